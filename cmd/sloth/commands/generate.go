@@ -1,23 +1,33 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/alecthomas/kingpin/v2"
 	prometheusmodel "github.com/prometheus/common/model"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/slok/sloth/internal/log"
 	"github.com/slok/sloth/internal/plugin"
 	k8stransformpromopv1 "github.com/slok/sloth/internal/plugin/k8stransform/prom_operator_prometheus_rule_v1"
+	storagefs "github.com/slok/sloth/internal/storage/fs"
+	storageio "github.com/slok/sloth/internal/storage/io"
 	"github.com/slok/sloth/pkg/common/model"
 	utilsdata "github.com/slok/sloth/pkg/common/utils/data"
 	slothlib "github.com/slok/sloth/pkg/lib"
@@ -32,11 +42,22 @@ type generateCommand struct {
 	disableAlerts            bool
 	extraLabels              map[string]string
 	pluginsPaths             []string
+	pluginsCacheDir          string
 	sloPeriodWindowsPath     string
+	sloTemplatesPath         string
 	sloPeriod                string
 	sloPlugins               []string
 	disableDefaultSLOPlugins bool
 	k8sTransformPluginID     string
+	workers                  int
+	sloWorkers               int
+	shareSLIRules            bool
+	watch                    bool
+	watchInterval            time.Duration
+	watchExec                string
+	tracePlugins             bool
+	tracePluginsOut          string
+	tracePluginsFormat       string
 }
 
 // NewGenerateCommand returns the generate command.
@@ -52,15 +73,31 @@ func NewGenerateCommand(app *kingpin.Application) Command {
 	cmd.Flag("disable-recordings", "Disables recording rules generation.").BoolVar(&c.disableRecordings)
 	cmd.Flag("disable-alerts", "Disables alert rules generation.").BoolVar(&c.disableAlerts)
 	cmd.Flag("plugins-path", "The path to any of the sloth compatible plugin types (can be repeated).").Short('p').StringsVar(&c.pluginsPaths)
+	cmd.Flag("plugins-cache-dir", "The directory used to cache the plugins discovery (kind and ID) across executions, so the plugins that can't be discovered statically are not loaded on start, the plugins are still loaded on their first use (disabled if empty).").StringVar(&c.pluginsCacheDir)
 	cmd.Flag("slo-period-windows-path", "The directory path to custom SLO period windows catalog (replaces default ones).").StringVar(&c.sloPeriodWindowsPath)
+	cmd.Flag("slo-templates-path", "The directory path to the PrometheusServiceLevelTemplate manifests used by the Kubernetes SLO specs SLO templates.").StringVar(&c.sloTemplatesPath)
 	cmd.Flag("default-slo-period", "The default SLO period windows to be used for the SLOs.").Default("30d").StringVar(&c.sloPeriod)
 	cmd.Flag("slo-plugins", `SLO plugins chain declaration in JSON format '{"id": "foo","priority": 0,"config": "{}"}' (Can be repeated).`).Short('s').StringsVar(&c.sloPlugins)
 	cmd.Flag("disable-default-slo-plugins", `Disables the default SLO plugins, normally used along with custom SLO plugins to fully customize Sloth behavior`).BoolVar(&c.disableDefaultSLOPlugins)
 	cmd.Flag("k8s-transform-plugin-id", "The ID of the plugin that will transform generated SLOs into k8s objects.").Default(k8stransformpromopv1.PluginID).StringVar(&c.k8sTransformPluginID)
+	cmd.Flag("workers", "The number of SLO spec files generated concurrently (used with directory based input/output).").Default("1").IntVar(&c.workers)
+	cmd.Flag("slo-workers", "The number of SLOs of the same SLO spec generated concurrently.").Default("1").IntVar(&c.sloWorkers)
+	cmd.Flag("share-sli-rules", "De-duplicates the SLI recording rules of the SLOs with identical SLIs across all the generated specs, generating them once under a shared ID (not supported in watch mode).").BoolVar(&c.shareSLIRules)
+	cmd.Flag("watch", "Keeps running and regenerates the affected outputs when the input specs, plugins, SLO period windows or SLO templates change.").Short('w').BoolVar(&c.watch)
+	cmd.Flag("watch-interval", "The interval used to check for changes in watch mode.").Default("1s").DurationVar(&c.watchInterval)
+	cmd.Flag("watch-exec", "Shell command executed after each successful regeneration in watch mode (e.g: 'promtool check rules ./out/*.yml').").StringVar(&c.watchExec)
+	cmd.Flag("trace-plugins", "Traces the SLO plugin chain of each SLO, reporting the executed plugins, their duration and the changes each plugin made on the SLO rules and the SLO.").BoolVar(&c.tracePlugins)
+	cmd.Flag("trace-plugins-out", "The SLO plugin traces output file path. If `-` it will use stderr.").Default("-").StringVar(&c.tracePluginsOut)
+	cmd.Flag("trace-plugins-format", "The SLO plugin traces output format.").Default(tracePluginsFormatText).EnumVar(&c.tracePluginsFormat, tracePluginsFormatText, tracePluginsFormatJSON)
 
 	return c
 }
 
+const (
+	tracePluginsFormatText = "text"
+	tracePluginsFormatJSON = "json"
+)
+
 func (g generateCommand) Name() string { return "generate" }
 func (g generateCommand) Run(ctx context.Context, config RootConfig) error {
 	logger := config.Logger.WithValues(log.Kv{"window": g.sloPeriod})
@@ -94,6 +131,18 @@ func (g generateCommand) Run(ctx context.Context, config RootConfig) error {
 		}
 	}
 
+	if g.workers < 1 || g.sloWorkers < 1 {
+		return fmt.Errorf("workers must be greater than 0")
+	}
+
+	if g.watch && g.watchInterval <= 0 {
+		return fmt.Errorf("watch interval must be greater than 0")
+	}
+
+	if g.watch && g.shareSLIRules {
+		return fmt.Errorf("shared SLI rules can't be used in watch mode")
+	}
+
 	// SLO period.
 	sp, err := prometheusmodel.ParseDuration(g.sloPeriod)
 	if err != nil {
@@ -111,112 +160,119 @@ func (g generateCommand) Run(ctx context.Context, config RootConfig) error {
 		return fmt.Errorf("could not load slo plugin declarations: %w", err)
 	}
 
-	// Get SLO targets.
-	genTargets := []generateTarget{}
-
-	// FIle based input/outputs.
-	if !inputInfo.IsDir() {
-		// Get SLO spec data.
-		f, err := os.Open(g.slosInput)
+	// Directory based input filters.
+	var excludeRegex *regexp.Regexp
+	var includeRegex *regexp.Regexp
+	if g.slosExcludeRegex != "" {
+		r, err := regexp.Compile(g.slosExcludeRegex)
 		if err != nil {
-			return fmt.Errorf("could not open SLOs spec file: %w", err)
+			return fmt.Errorf("invalid exclude regex: %w", err)
 		}
-		defer f.Close()
-
-		slxData, err := io.ReadAll(f)
+		excludeRegex = r
+	}
+	if g.slosIncludeRegex != "" {
+		r, err := regexp.Compile(g.slosIncludeRegex)
 		if err != nil {
-			return fmt.Errorf("could not read SLOs spec file data: %w", err)
+			return fmt.Errorf("invalid include regex: %w", err)
 		}
+		includeRegex = r
+	}
 
-		// Split YAMLs in case we have multiple yaml files in a single file.
-		splittedSLOsData := utilsdata.SplitYAML(slxData)
+	newGenerator := func() (*slothlib.PrometheusSLOGenerator, error) {
+		return g.newGenerator(logger, sloPeriod, cmdLevelSLOPlugins)
+	}
+	discoverFiles := func() ([]generateFile, error) {
+		return g.discoverGenerateFiles(logger, inputInfo.IsDir(), excludeRegex, includeRegex)
+	}
 
-		// Prepare store output.
-		var out = config.Stdout
-		if g.slosOut != "-" {
-			outFile, err := os.Create(g.slosOut)
-			if err != nil {
-				return fmt.Errorf("could not create out file: %w", err)
-			}
-			defer f.Close()
-			out = outFile
-		}
-		for _, s := range splittedSLOsData {
-			genTargets = append(genTargets, generateTarget{
-				SLOData: s,
-				Out:     out,
-			})
-		}
-	} else {
-		// Directory based input/outpus.
-		var excludeRegex *regexp.Regexp
-		var includeRegex *regexp.Regexp
-		if g.slosExcludeRegex != "" {
-			r, err := regexp.Compile(g.slosExcludeRegex)
-			if err != nil {
-				return fmt.Errorf("invalid exclude regex: %w", err)
-			}
-			excludeRegex = r
-		}
-		if g.slosIncludeRegex != "" {
-			r, err := regexp.Compile(g.slosIncludeRegex)
-			if err != nil {
-				return fmt.Errorf("invalid include regex: %w", err)
-			}
-			includeRegex = r
-		}
+	if g.watch {
+		return g.runWatch(ctx, config, logger, newGenerator, discoverFiles)
+	}
 
-		sloPaths, err := discoverSLOManifests(logger, excludeRegex, includeRegex, g.slosInput)
-		if err != nil {
-			return fmt.Errorf("could not discover files: %w", err)
+	genService, err := newGenerator()
+	if err != nil {
+		return fmt.Errorf("could not create Prometheus SLO generator: %w", err)
+	}
+
+	genFiles, err := discoverFiles()
+	if err != nil {
+		return err
+	}
+	if len(genFiles) == 0 {
+		return fmt.Errorf("0 slo specs have been discovered")
+	}
+
+	// Generate the files concurrently, the first error will cancel the rest of the generations.
+	traces := &pluginTraces{}
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(g.workers)
+	if !g.shareSLIRules {
+		for _, genFile := range genFiles {
+			eg.Go(func() error {
+				if err := egCtx.Err(); err != nil {
+					return err
+				}
+
+				return g.generateFile(egCtx, logger, genService, genFile, config.Stdout, traces)
+			})
 		}
-		if len(sloPaths) == 0 {
-			return fmt.Errorf("0 slo specs have been discovered")
+		if err := eg.Wait(); err != nil {
+			return err
 		}
 
-		for _, sloPath := range sloPaths {
-			f, err := os.Open(sloPath)
-			if err != nil {
-				return fmt.Errorf("could not open SLOs spec file: %w", err)
-			}
-			defer f.Close()
+		return g.writePluginTraces(ctx, config.Stderr, traces)
+	}
 
-			slxData, err := io.ReadAll(f)
-			if err != nil {
-				return fmt.Errorf("could not read SLOs spec file data: %w", err)
+	// Shared SLI rules need all the results before writing them, so we can de-duplicate across specs.
+	filesResults := make([][]*model.PromSLOGroupResult, len(genFiles))
+	for i, genFile := range genFiles {
+		eg.Go(func() error {
+			if err := egCtx.Err(); err != nil {
+				return err
 			}
 
-			// Infer output path.
-			outputPath := strings.TrimPrefix(path.Clean(sloPath), strings.TrimPrefix(g.slosInput, "./"))
-			outputPath = path.Join(g.slosOut, outputPath)
-
-			// Ensure the file path is ready.
-			err = os.MkdirAll(path.Dir(outputPath), os.ModePerm)
+			results, err := g.generateFileResults(egCtx, genService, genFile, traces)
 			if err != nil {
 				return err
 			}
+			filesResults[i] = results
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
 
-			// Create the target file.
-			outFile, err := os.Create(outputPath)
-			if err != nil {
-				return fmt.Errorf("could not create out file: %w", err)
-			}
-			defer outFile.Close()
-
-			// Split YAMLs in case we have multiple yaml files in a single file.
-			splittedSLOsData := utilsdata.SplitYAML(slxData)
-			for _, s := range splittedSLOsData {
-				genTargets = append(genTargets, generateTarget{
-					SLOData: s,
-					Out:     outFile,
-				})
-			}
+	allResults := []*model.PromSLOGroupResult{}
+	for _, results := range filesResults {
+		allResults = append(allResults, results...)
+	}
+	err = genService.ShareSLIRules(ctx, allResults)
+	if err != nil {
+		return err
+	}
+
+	for i, genFile := range genFiles {
+		err := g.writeFileResults(ctx, logger, genService, genFile, filesResults[i], config.Stdout)
+		if err != nil {
+			return err
 		}
 	}
 
+	return g.writePluginTraces(ctx, config.Stderr, traces)
+}
+
+// generateFile represents an SLO spec input file and the place where its generated rules will be written.
+type generateFile struct {
+	InputPath string
+	// OutputPath is the generated rules file path, `-` means stdout.
+	OutputPath string
+}
+
+func (g generateCommand) newGenerator(logger log.Logger, sloPeriod time.Duration, cmdLevelSLOPlugins []model.PromSLOPluginMetadata) (*slothlib.PrometheusSLOGenerator, error) {
 	pluginsFSs := []fs.FS{plugin.EmbeddedDefaultSLOPlugins, plugin.EmbeddedDefaultK8sTransformPlugins}
 	for _, p := range g.pluginsPaths {
-		pluginsFSs = append(pluginsFSs, os.DirFS(p))
+		pluginsFSs = append(pluginsFSs, storagefs.NewOSDirFS(p))
 	}
 
 	var wfs fs.FS
@@ -224,27 +280,83 @@ func (g generateCommand) Run(ctx context.Context, config RootConfig) error {
 		wfs = os.DirFS(g.sloPeriodWindowsPath)
 	}
 
-	genService, err := slothlib.NewPrometheusSLOGenerator(slothlib.PrometheusSLOGeneratorConfig{
+	var tfs fs.FS
+	if g.sloTemplatesPath != "" {
+		tfs = os.DirFS(g.sloTemplatesPath)
+	}
+
+	return slothlib.NewPrometheusSLOGenerator(slothlib.PrometheusSLOGeneratorConfig{
 		WindowsFS:             wfs,
 		PluginsFS:             pluginsFSs,
+		SLOTemplatesFS:        tfs,
+		PluginsCacheDir:       g.pluginsCacheDir,
 		DefaultSLOPeriod:      sloPeriod,
 		DisableDefaultPlugins: g.disableDefaultSLOPlugins,
 		CMDSLOPlugins:         cmdLevelSLOPlugins,
 		ExtraLabels:           g.extraLabels,
 		CallerAgent:           slothlib.CallerAgentCLI,
+		Workers:               g.sloWorkers,
+		TracePlugins:          g.tracePlugins,
 		Logger:                logger,
 	})
+}
+
+func (g generateCommand) discoverGenerateFiles(logger log.Logger, inputIsDir bool, excludeRegex, includeRegex *regexp.Regexp) ([]generateFile, error) {
+	// File based input/outputs.
+	if !inputIsDir {
+		return []generateFile{{InputPath: g.slosInput, OutputPath: g.slosOut}}, nil
+	}
+
+	// Directory based input/outpus.
+	sloPaths, err := discoverSLOManifests(logger, excludeRegex, includeRegex, g.slosInput)
 	if err != nil {
-		return fmt.Errorf("could not create Prometheus SLO generator: %w", err)
+		return nil, fmt.Errorf("could not discover files: %w", err)
 	}
 
-	for _, genTarget := range genTargets {
+	files := make([]generateFile, 0, len(sloPaths))
+	for _, sloPath := range sloPaths {
+		// Infer output path.
+		outputPath := strings.TrimPrefix(path.Clean(sloPath), strings.TrimPrefix(g.slosInput, "./"))
+		outputPath = path.Join(g.slosOut, outputPath)
+
+		files = append(files, generateFile{InputPath: sloPath, OutputPath: outputPath})
+	}
+
+	return files, nil
+}
+
+// generateFile generates all the SLO specs of a file and writes the result on the file output.
+// The output is only written once all the specs of the file have been generated, so a failed generation
+// doesn't leave half written outputs. The SLO plugin traces (if any) are added to the traces.
+func (g generateCommand) generateFile(ctx context.Context, logger log.Logger, genService *slothlib.PrometheusSLOGenerator, genFile generateFile, stdout io.Writer, traces *pluginTraces) error {
+	results, err := g.generateFileResults(ctx, genService, genFile, traces)
+	if err != nil {
+		return err
+	}
+
+	return g.writeFileResults(ctx, logger, genService, genFile, results, stdout)
+}
+
+// generateFileResults generates all the SLO specs of a file. The SLO plugin traces (if any) are added to the traces.
+func (g generateCommand) generateFileResults(ctx context.Context, genService *slothlib.PrometheusSLOGenerator, genFile generateFile, traces *pluginTraces) ([]*model.PromSLOGroupResult, error) {
+	slxData, err := os.ReadFile(genFile.InputPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read SLOs spec file data: %w", err)
+	}
+
+	// Split YAMLs in case we have multiple yaml files in a single file.
+	splittedSLOsData := utilsdata.SplitYAML(slxData)
+
+	results := make([]*model.PromSLOGroupResult, 0, len(splittedSLOsData))
+	for _, sloData := range splittedSLOsData {
 		// Generate SLOs.
-		genResult, err := genService.GenerateFromRaw(ctx, []byte(genTarget.SLOData))
+		genResult, err := genService.GenerateFromRaw(ctx, []byte(sloData))
 		if err != nil {
-			return fmt.Errorf("could not generate SLOs: %w", err)
+			return nil, fmt.Errorf("could not generate SLOs: %w", err)
 		}
 
+		traces.add(genFile.InputPath, genResult.SLOResults)
+
 		// Disable data if required.
 		for i := range genResult.SLOResults {
 			if g.disableAlerts {
@@ -256,18 +368,40 @@ func (g generateCommand) Run(ctx context.Context, config RootConfig) error {
 			}
 		}
 
-		err = g.storeSLOs(ctx, logger, genService, *genResult, genTarget.Out)
+		results = append(results, genResult)
+	}
+
+	return results, nil
+}
+
+// writeFileResults writes the generated results of a file on the file output.
+func (g generateCommand) writeFileResults(ctx context.Context, logger log.Logger, genService *slothlib.PrometheusSLOGenerator, genFile generateFile, results []*model.PromSLOGroupResult, stdout io.Writer) error {
+	var b bytes.Buffer
+	for _, genResult := range results {
+		err := g.storeSLOs(ctx, logger, genService, *genResult, &b)
 		if err != nil {
 			return fmt.Errorf("could not store SLOs: %w", err)
 		}
 	}
 
-	return nil
-}
+	// Write the results.
+	if genFile.OutputPath == "-" {
+		_, err := b.WriteTo(stdout)
+		return err
+	}
+
+	// Ensure the file path is ready.
+	err := os.MkdirAll(path.Dir(genFile.OutputPath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(genFile.OutputPath, b.Bytes(), 0o644)
+	if err != nil {
+		return fmt.Errorf("could not write out file: %w", err)
+	}
 
-type generateTarget struct {
-	Out     io.Writer
-	SLOData string
+	return nil
 }
 
 func (g generateCommand) storeSLOs(ctx context.Context, logger log.Logger, generator *slothlib.PrometheusSLOGenerator, genResult model.PromSLOGroupResult, out io.Writer) error {
@@ -296,3 +430,317 @@ func (g generateCommand) storeSLOs(ctx context.Context, logger log.Logger, gener
 		return fmt.Errorf("invalid spec, could not load with any of the supported spec types")
 	}
 }
+
+// runWatch generates the SLOs and keeps watching the inputs, plugins, SLO period windows and SLO templates for changes
+// until the context is cancelled or the process receives a termination signal.
+// The changes are detected by polling the file system, when something changes only the affected outputs are
+// regenerated:
+//
+// - SLO period windows change: The generator is recreated and all the outputs regenerated.
+// - SLO templates change: The generator is recreated and all the outputs regenerated.
+// - Plugins change: The plugins are reloaded and all the outputs regenerated.
+// - SLO specs change: Only the changed specs are regenerated (deleted specs will delete their outputs).
+//
+// The spec files are generated concurrently using the configured workers. Regeneration errors are printed without
+// stopping the watch, and the failed regenerations are retried on the next poll.
+func (g generateCommand) runWatch(ctx context.Context, config RootConfig, logger log.Logger, newGenerator func() (*slothlib.PrometheusSLOGenerator, error), discoverFiles func() ([]generateFile, error)) error {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	logger = logger.WithValues(log.Kv{"svc": "generate.Watcher"})
+	printErr := func(err error) {
+		fmt.Fprintf(config.Stderr, "error: %s\n", err)
+	}
+
+	var windowsPaths []string
+	if g.sloPeriodWindowsPath != "" {
+		windowsPaths = []string{g.sloPeriodWindowsPath}
+	}
+	var templatesPaths []string
+	if g.sloTemplatesPath != "" {
+		templatesPaths = []string{g.sloTemplatesPath}
+	}
+
+	// Take the initial state and generate everything.
+	specsSnap := snapshotFiles(logger, g.slosInput)
+	pluginsSnap := snapshotFiles(logger, g.pluginsPaths...)
+	windowsSnap := snapshotFiles(logger, windowsPaths...)
+	templatesSnap := snapshotFiles(logger, templatesPaths...)
+
+	var genService *slothlib.PrometheusSLOGenerator
+	generatedFiles := map[string]generateFile{}
+
+	// The pending state is what still needs to be regenerated, it's only cleared when the regeneration succeeds,
+	// so the failed regenerations are retried on the next poll even if their files didn't change again.
+	pendingAll := true
+	pendingReload := false
+	pendingSpecs := map[string]struct{}{}
+
+	// generate regenerates the pending files, if all are pending, it will regenerate everything.
+	generate := func() error {
+		if genService == nil {
+			gs, err := newGenerator()
+			if err != nil {
+				return fmt.Errorf("could not create Prometheus SLO generator: %w", err)
+			}
+			genService = gs
+			pendingReload = false
+		}
+
+		if pendingReload {
+			err := genService.ReloadPlugins(ctx)
+			if err != nil {
+				return fmt.Errorf("could not reload plugins: %w", err)
+			}
+			pendingReload = false
+		}
+
+		genFiles, err := discoverFiles()
+		if err != nil {
+			return err
+		}
+
+		// Clean outputs from removed specs.
+		currentFiles := map[string]generateFile{}
+		for _, f := range genFiles {
+			currentFiles[f.InputPath] = f
+		}
+		for input, f := range generatedFiles {
+			if _, ok := currentFiles[input]; ok || f.OutputPath == "-" {
+				continue
+			}
+			err := os.Remove(f.OutputPath)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("could not remove %q output: %w", f.OutputPath, err)
+			}
+			delete(generatedFiles, input)
+			logger.Infof("Spec %q removed, %q output deleted", input, f.OutputPath)
+		}
+
+		// Generate the files concurrently, the failed files are kept as pending.
+		traces := &pluginTraces{}
+		var mu sync.Mutex
+		var errs []error
+		failedSpecs := map[string]struct{}{}
+		var eg errgroup.Group
+		eg.SetLimit(g.workers)
+		for _, f := range genFiles {
+			if !pendingAll {
+				if _, ok := pendingSpecs[f.InputPath]; !ok {
+					continue
+				}
+			}
+
+			eg.Go(func() error {
+				err := g.generateFile(ctx, logger, genService, f, config.Stdout, traces)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%q: %w", f.InputPath, err))
+					failedSpecs[f.InputPath] = struct{}{}
+					return nil
+				}
+				generatedFiles[f.InputPath] = f
+				logger.Debugf("Spec %q generated into %q", f.InputPath, f.OutputPath)
+				return nil
+			})
+		}
+		_ = eg.Wait()
+		pendingAll = false
+		pendingSpecs = failedSpecs
+		if len(errs) > 0 {
+			return errors.Join(errs...)
+		}
+
+		return g.writePluginTraces(ctx, config.Stderr, traces)
+	}
+
+	// onSuccess is executed after each successful regeneration.
+	onSuccess := func() {
+		logger.Infof("SLOs generated")
+		if g.watchExec == "" {
+			return
+		}
+
+		cmd := exec.CommandContext(ctx, "sh", "-c", g.watchExec)
+		cmd.Stdout = config.Stdout
+		cmd.Stderr = config.Stderr
+		err := cmd.Run()
+		if err != nil {
+			printErr(fmt.Errorf("watch exec command failed: %w", err))
+		}
+	}
+
+	if err := generate(); err != nil {
+		printErr(err)
+	} else {
+		onSuccess()
+	}
+
+	logger.Infof("Watching for changes")
+	t := time.NewTicker(g.watchInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Infof("Stopping watch")
+			return nil
+		case <-t.C:
+		}
+
+		newSpecsSnap := snapshotFiles(logger, g.slosInput)
+		newPluginsSnap := snapshotFiles(logger, g.pluginsPaths...)
+		newWindowsSnap := snapshotFiles(logger, windowsPaths...)
+		newTemplatesSnap := snapshotFiles(logger, templatesPaths...)
+
+		changedSpecs := specsSnap.changed(newSpecsSnap)
+		pluginsChanged := len(pluginsSnap.changed(newPluginsSnap)) > 0
+		windowsChanged := len(windowsSnap.changed(newWindowsSnap)) > 0
+		templatesChanged := len(templatesSnap.changed(newTemplatesSnap)) > 0
+		specsSnap, pluginsSnap, windowsSnap, templatesSnap = newSpecsSnap, newPluginsSnap, newWindowsSnap, newTemplatesSnap
+
+		switch {
+		case windowsChanged:
+			logger.Infof("SLO period windows changed, regenerating all SLOs")
+			genService = nil
+			pendingAll = true
+		case templatesChanged:
+			logger.Infof("SLO templates changed, regenerating all SLOs")
+			genService = nil
+			pendingAll = true
+		case pluginsChanged:
+			logger.Infof("Plugins changed, reloading plugins and regenerating all SLOs")
+			pendingReload = genService != nil
+			pendingAll = true
+		case len(changedSpecs) > 0:
+			logger.Infof("%d SLO spec files changed, regenerating them", len(changedSpecs))
+		}
+		for p := range changedSpecs {
+			pendingSpecs[p] = struct{}{}
+		}
+
+		if !pendingAll && len(pendingSpecs) == 0 {
+			continue
+		}
+
+		if err := generate(); err != nil {
+			printErr(err)
+			continue
+		}
+		onSuccess()
+	}
+}
+
+type fileState struct {
+	modTime int64
+	size    int64
+}
+
+// filesSnapshot is the state of a group of files at a point in time.
+type filesSnapshot map[string]fileState
+
+// snapshotFiles gets the state of all the files on the paths recursively, missing
+// or unreadable paths are ignored, so they will be detected as changes once they are ready.
+func snapshotFiles(logger log.Logger, paths ...string) filesSnapshot {
+	snap := filesSnapshot{}
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			snap[path] = fileState{modTime: info.ModTime().UnixNano(), size: info.Size()}
+
+			return nil
+		})
+		if err != nil {
+			logger.Debugf("Could not snapshot %q: %s", p, err)
+		}
+	}
+
+	return snap
+}
+
+// changed returns the file paths that have been created, modified or deleted on the new snapshot.
+func (f filesSnapshot) changed(new filesSnapshot) map[string]struct{} {
+	changed := map[string]struct{}{}
+	for path, state := range new {
+		old, ok := f[path]
+		if !ok || old != state {
+			changed[path] = struct{}{}
+		}
+	}
+	for path := range f {
+		if _, ok := new[path]; !ok {
+			changed[path] = struct{}{}
+		}
+	}
+
+	return changed
+}
+
+// pluginTraces are the SLO plugin traces of a generation, safe to use concurrently.
+type pluginTraces struct {
+	mu     sync.Mutex
+	traces []storageio.SLOPluginTrace
+}
+
+func (p *pluginTraces) add(source string, results []model.PromSLOResult) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, r := range results {
+		if r.PluginTrace == nil {
+			continue
+		}
+		p.traces = append(p.traces, storageio.SLOPluginTrace{Source: source, SLOID: r.SLO.ID, Trace: *r.PluginTrace})
+	}
+}
+
+// writePluginTraces writes the SLO plugin traces if plugin tracing is enabled, the traces are sorted
+// by source, so concurrent generations have a deterministic output.
+func (g generateCommand) writePluginTraces(ctx context.Context, stderr io.Writer, traces *pluginTraces) error {
+	if !g.tracePlugins {
+		return nil
+	}
+
+	traces.mu.Lock()
+	defer traces.mu.Unlock()
+	slices.SortStableFunc(traces.traces, func(a, b storageio.SLOPluginTrace) int {
+		return strings.Compare(a.Source, b.Source)
+	})
+
+	var b bytes.Buffer
+	var repo interface {
+		StoreSLOPluginTraces(ctx context.Context, traces []storageio.SLOPluginTrace) error
+	}
+	switch g.tracePluginsFormat {
+	case tracePluginsFormatJSON:
+		repo = storageio.NewJSONSLOPluginTraceRepo(&b)
+	default:
+		repo = storageio.NewTextSLOPluginTraceRepo(&b)
+	}
+	err := repo.StoreSLOPluginTraces(ctx, traces.traces)
+	if err != nil {
+		return err
+	}
+
+	if g.tracePluginsOut == "-" {
+		_, err = b.WriteTo(stderr)
+		return err
+	}
+
+	err = os.WriteFile(g.tracePluginsOut, b.Bytes(), 0o644)
+	if err != nil {
+		return fmt.Errorf("could not write SLO plugin traces file: %w", err)
+	}
+
+	return nil
+}
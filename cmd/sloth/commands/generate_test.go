@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/slok/sloth/internal/log"
+)
+
+const watchTestSpec = `
+version: "prometheus/v1"
+service: "myservice"
+slos:
+  - name: "requests-availability"
+    objective: 99.9
+    sli:
+      events:
+        error_query: sum(rate(http_request_duration_seconds_count{job="myservice",code=~"(5..|429)"}[{{.window}}]))
+        total_query: sum(rate(http_request_duration_seconds_count{job="myservice"}[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`
+
+func TestFilesSnapshotChanged(t *testing.T) {
+	tests := map[string]struct {
+		old        filesSnapshot
+		new        filesSnapshot
+		expChanged map[string]struct{}
+	}{
+		"Having no files, nothing should change.": {
+			old:        filesSnapshot{},
+			new:        filesSnapshot{},
+			expChanged: map[string]struct{}{},
+		},
+
+		"Having the same files, nothing should change.": {
+			old: filesSnapshot{
+				"a.yml": {modTime: 1, size: 10},
+				"b.yml": {modTime: 2, size: 20},
+			},
+			new: filesSnapshot{
+				"a.yml": {modTime: 1, size: 10},
+				"b.yml": {modTime: 2, size: 20},
+			},
+			expChanged: map[string]struct{}{},
+		},
+
+		"Having new files, they should be changed.": {
+			old: filesSnapshot{
+				"a.yml": {modTime: 1, size: 10},
+			},
+			new: filesSnapshot{
+				"a.yml": {modTime: 1, size: 10},
+				"b.yml": {modTime: 2, size: 20},
+			},
+			expChanged: map[string]struct{}{"b.yml": {}},
+		},
+
+		"Having deleted files, they should be changed.": {
+			old: filesSnapshot{
+				"a.yml": {modTime: 1, size: 10},
+				"b.yml": {modTime: 2, size: 20},
+			},
+			new: filesSnapshot{
+				"a.yml": {modTime: 1, size: 10},
+			},
+			expChanged: map[string]struct{}{"b.yml": {}},
+		},
+
+		"Having modified files, they should be changed.": {
+			old: filesSnapshot{
+				"a.yml": {modTime: 1, size: 10},
+				"b.yml": {modTime: 2, size: 20},
+				"c.yml": {modTime: 3, size: 30},
+			},
+			new: filesSnapshot{
+				"a.yml": {modTime: 1, size: 10},
+				"b.yml": {modTime: 4, size: 20},
+				"c.yml": {modTime: 3, size: 31},
+			},
+			expChanged: map[string]struct{}{"b.yml": {}, "c.yml": {}},
+		},
+
+		"Having all the files deleted, all should be changed.": {
+			old: filesSnapshot{
+				"a.yml": {modTime: 1, size: 10},
+				"b.yml": {modTime: 2, size: 20},
+			},
+			new:        filesSnapshot{},
+			expChanged: map[string]struct{}{"a.yml": {}, "b.yml": {}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gotChanged := test.old.changed(test.new)
+			assert.Equal(test.expChanged, gotChanged)
+		})
+	}
+}
+
+func TestSnapshotFiles(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+	require.NoError(os.WriteFile(filepath.Join(dir, "a.yml"), []byte("a"), 0o644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "sub", "b.yml"), []byte("bb"), 0o644))
+
+	// Missing paths should be ignored.
+	snap := snapshotFiles(log.Noop, dir, filepath.Join(dir, "missing"))
+	require.Len(snap, 2)
+	assert.Equal(int64(1), snap[filepath.Join(dir, "a.yml")].size)
+	assert.Equal(int64(2), snap[filepath.Join(dir, "sub", "b.yml")].size)
+
+	// Modifying a file should be detected.
+	require.NoError(os.WriteFile(filepath.Join(dir, "sub", "b.yml"), []byte("bbb"), 0o644))
+	newSnap := snapshotFiles(log.Noop, dir)
+	assert.Equal(map[string]struct{}{filepath.Join(dir, "sub", "b.yml"): {}}, snap.changed(newSnap))
+}
+
+func TestGenerateCommandRunWatch(t *testing.T) {
+	require := require.New(t)
+
+	inDir := t.TempDir()
+	outDir := t.TempDir()
+	inFile := filepath.Join(inDir, "a.yml")
+	outFile := filepath.Join(outDir, "a.yml")
+	require.NoError(os.WriteFile(inFile, []byte(watchTestSpec), 0o644))
+
+	// Make the output write fail by having a directory on the output file path.
+	require.NoError(os.MkdirAll(filepath.Join(outFile, "blocker"), 0o755))
+
+	g := generateCommand{
+		slosInput:     inDir,
+		slosOut:       outDir,
+		sloPeriod:     "30d",
+		workers:       2,
+		sloWorkers:    1,
+		watch:         true,
+		watchInterval: 10 * time.Millisecond,
+	}
+	config := RootConfig{Stdout: io.Discard, Stderr: io.Discard, Logger: log.Noop}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() { runErr <- g.Run(ctx, config) }()
+
+	isRegularFile := func(path string) bool {
+		info, err := os.Stat(path)
+		return err == nil && info.Mode().IsRegular()
+	}
+
+	// The failed spec should be retried without changing it once the output can be written.
+	time.Sleep(50 * time.Millisecond)
+	require.False(isRegularFile(outFile))
+	require.NoError(os.RemoveAll(outFile))
+	require.Eventually(func() bool { return isRegularFile(outFile) }, 5*time.Second, 10*time.Millisecond)
+
+	// Deleting all the specs should remove their outputs.
+	require.NoError(os.Remove(inFile))
+	require.Eventually(func() bool {
+		_, err := os.Stat(outFile)
+		return os.IsNotExist(err)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-runErr:
+		require.NoError(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch didn't stop")
+	}
+}
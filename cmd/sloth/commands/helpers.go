@@ -97,7 +97,7 @@ func createDefaultSLOPlugins(logger log.Logger, disableRecordings, disableAlerts
 		if err != nil {
 			return nil, fmt.Errorf("could not create SLI rules plugin: %w", err)
 		}
-		sliRuleGen = sliPlugin
+		sliRuleGen = generate.NewIDSLOProcessor(plugincoreslirulesv1.PluginID, sliPlugin)
 
 		metadataPlugin, err := generate.NewSLOProcessorFromSLOPluginV1(
 			plugincoremetadatarulesv1.NewPlugin,
@@ -107,7 +107,7 @@ func createDefaultSLOPlugins(logger log.Logger, disableRecordings, disableAlerts
 		if err != nil {
 			return nil, fmt.Errorf("could not create metadata rules plugin: %w", err)
 		}
-		metaRuleGen = metadataPlugin
+		metaRuleGen = generate.NewIDSLOProcessor(plugincoremetadatarulesv1.PluginID, metadataPlugin)
 	}
 
 	validatePlugin, err := generate.NewSLOProcessorFromSLOPluginV1(
@@ -130,11 +130,11 @@ func createDefaultSLOPlugins(logger log.Logger, disableRecordings, disableAlerts
 		if err != nil {
 			return nil, fmt.Errorf("could not create alert rules plugin: %w", err)
 		}
-		alertRuleGen = plugin
+		alertRuleGen = generate.NewIDSLOProcessor(plugincorealertrulesv1.PluginID, plugin)
 	}
 
 	return []generate.SLOProcessor{
-		validatePlugin,
+		generate.NewIDSLOProcessor(plugincorevalidatev1.PluginID, validatePlugin),
 		sliRuleGen,
 		metaRuleGen,
 		alertRuleGen,
@@ -9,30 +9,45 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/oklog/run"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	prometheusmodel "github.com/prometheus/common/model"
 	"github.com/slok/reload"
 	koopercontroller "github.com/spotahome/kooper/v2/controller"
 	kooperlog "github.com/spotahome/kooper/v2/log"
 	kooperprometheus "github.com/spotahome/kooper/v2/metrics/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
 	_ "k8s.io/client-go/plugin/pkg/client/auth" // Init all available Kube client auth systems.
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/client-go/util/homedir"
 
 	"github.com/slok/sloth/internal/alert"
 	"github.com/slok/sloth/internal/app/generate"
 	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/http/admission"
+	httpbackendmetrics "github.com/slok/sloth/internal/http/backend/metrics"
+	httpbackendmetricsprometheus "github.com/slok/sloth/internal/http/backend/metrics/prometheus"
+	storageprometheus "github.com/slok/sloth/internal/http/backend/storage/prometheus"
 	"github.com/slok/sloth/internal/log"
 	"github.com/slok/sloth/internal/plugin"
 	k8stransformpromopv1 "github.com/slok/sloth/internal/plugin/k8stransform/prom_operator_prometheus_rule_v1"
@@ -47,7 +62,7 @@ import (
 	slothclientset "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned"
 )
 
-var controllerModes = []string{controllerModeDefault, controllerModeDryRun, controllerModeFake}
+var controllerModes = []string{controllerModeDefault, controllerModeDryRun, controllerModeFake, controllerModeGitOps}
 
 const (
 	// default mode will run using real Kubernetes clients.
@@ -56,8 +71,14 @@ const (
 	controllerModeDryRun = "dry-run"
 	// fake mode fakes all the kubernetes client calls, a Kubernetes cluster is not required.
 	controllerModeFake = "fake"
+	// gitops mode uses real kubernetes clients, but writes the generated rules as Prometheus rule files on a
+	// directory instead of storing them as Kubernetes objects.
+	controllerModeGitOps = "gitops"
 )
 
+// inClusterNamespacePath is the file with the namespace of the Pod when running inside Kubernetes.
+const inClusterNamespacePath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
 type kubeControllerCommand struct {
 	extraLabels              map[string]string
 	workers                  int
@@ -66,6 +87,9 @@ type kubeControllerCommand struct {
 	resyncInterval           time.Duration
 	namespace                string
 	labelSelector            string
+	namespaceLabelSelector   string
+	disableClusterSLOs       bool
+	autoSLOs                 bool
 	kubeLocal                bool
 	runMode                  string
 	metricsPath              string
@@ -73,11 +97,26 @@ type kubeControllerCommand struct {
 	hotReloadAddr            string
 	metricsListenAddr        string
 	pluginsPaths             []string
+	pluginsCacheDir          string
 	sloPeriodWindowsPath     string
 	sloPeriod                string
 	sloPlugins               []string
 	disableDefaultSLOPlugins bool
 	k8sTransformPluginID     string
+	webhookListenAddr        string
+	webhookPath              string
+	webhookTLSCertFile       string
+	webhookTLSKeyFile        string
+	leaderElection           bool
+	leaderElectionLeaseName  string
+	leaderElectionNamespace  string
+	leaseDuration            time.Duration
+	renewDeadline            time.Duration
+	retryPeriod              time.Duration
+	prometheus               prometheusClientConfig
+	gitOpsRulesDir           string
+	gitOpsPromReloadURL      string
+	gitOpsCleanInterval      time.Duration
 }
 
 // NewKubeControllerCommand returns the Kubernetes controller command.
@@ -96,17 +135,35 @@ func NewKubeControllerCommand(app *kingpin.Application) Command {
 	cmd.Flag("resync-interval", "The duration between all resources resync.").Default("15m").DurationVar(&c.resyncInterval)
 	cmd.Flag("namespace", "Run the controller targeting specific namespace, by default all.").StringVar(&c.namespace)
 	cmd.Flag("label-selector", "Kubernetes label selector that will make the controller filter resources by this selector.").StringVar(&c.labelSelector)
+	cmd.Flag("namespace-label-selector", "Kubernetes label selector that will make the controller only handle the namespaced resources of the namespaces that match this selector.").StringVar(&c.namespaceLabelSelector)
+	cmd.Flag("disable-cluster-slos", "Disables the handling of the cluster scoped ClusterPrometheusServiceLevel resources.").BoolVar(&c.disableClusterSLOs)
+	cmd.Flag("auto-slos", "Enables the auto SLOs, generates a PrometheusServiceLevel for each Service and Deployment with the SLO template annotation.").BoolVar(&c.autoSLOs)
 	cmd.Flag("metrics-path", "The path for Prometheus metrics.").Default("/metrics").StringVar(&c.metricsPath)
 	cmd.Flag("metrics-listen-addr", "The listen address for Prometheus metrics and pprof.").Default(":8081").StringVar(&c.metricsListenAddr)
 	cmd.Flag("hot-reload-addr", "The listen address for hot-reloading components that allow it.").Default(":8082").StringVar(&c.hotReloadAddr)
 	cmd.Flag("hot-reload-path", "The webhook path for hot-reloading components that allow it.").Default("/-/reload").StringVar(&c.hotReloadPath)
 	cmd.Flag("extra-labels", "Extra labels that will be added to all the generated Prometheus rules ('key=value' form, can be repeated).").Short('l').StringMapVar(&c.extraLabels)
 	cmd.Flag("plugins-path", "The path to SLI and SLO plugins (can be repeated).").Short('p').StringsVar(&c.pluginsPaths)
+	cmd.Flag("plugins-cache-dir", "The directory used to cache the plugins discovery (kind and ID) across executions, so the plugins that can't be discovered statically are not loaded on start, the plugins are still loaded on their first use (disabled if empty).").StringVar(&c.pluginsCacheDir)
 	cmd.Flag("slo-period-windows-path", "The directory path to custom SLO period windows catalog (replaces default ones).").StringVar(&c.sloPeriodWindowsPath)
 	cmd.Flag("default-slo-period", "The default SLO period windows to be used for the SLOs.").Default("30d").StringVar(&c.sloPeriod)
 	cmd.Flag("slo-plugins", `SLO plugins chain declaration in JSON format '{"id": "foo","priority": 0,"config": "{}"}' (Can be repeated).`).Short('s').StringsVar(&c.sloPlugins)
 	cmd.Flag("disable-default-slo-plugins", `Disables the default SLO plugins, normally used along with custom SLO plugins to fully customize Sloth behavior`).BoolVar(&c.disableDefaultSLOPlugins)
 	cmd.Flag("k8s-transform-plugin-id", "The ID of the plugin that will transform generated SLOs into k8s objects.").Default(k8stransformpromopv1.PluginID).StringVar(&c.k8sTransformPluginID)
+	cmd.Flag("admission-webhook-listen-addr", "The listen address for the PrometheusServiceLevel validating admission webhook (disabled if empty).").StringVar(&c.webhookListenAddr)
+	cmd.Flag("admission-webhook-path", "The path for the PrometheusServiceLevel validating admission webhook.").Default("/validate").StringVar(&c.webhookPath)
+	cmd.Flag("admission-webhook-tls-cert-file", "The TLS certificate file path for the validating admission webhook server.").StringVar(&c.webhookTLSCertFile)
+	cmd.Flag("admission-webhook-tls-key-file", "The TLS key file path for the validating admission webhook server.").StringVar(&c.webhookTLSKeyFile)
+	cmd.Flag("leader-election", "Enables Lease based leader election, only the leader will run the controller and the hot-reload manager.").BoolVar(&c.leaderElection)
+	cmd.Flag("leader-election-lease-name", "The name of the leader election Lease.").Default("sloth").StringVar(&c.leaderElectionLeaseName)
+	cmd.Flag("leader-election-namespace", "The namespace of the leader election Lease, by default the namespace where the controller is running.").StringVar(&c.leaderElectionNamespace)
+	cmd.Flag("leader-election-lease-duration", "The duration that non-leader candidates will wait to force acquire the leadership.").Default("15s").DurationVar(&c.leaseDuration)
+	cmd.Flag("leader-election-renew-deadline", "The duration that the leader will retry refreshing the leadership before giving up.").Default("10s").DurationVar(&c.renewDeadline)
+	cmd.Flag("leader-election-retry-period", "The duration the leader election candidates wait between tries of actions.").Default("2s").DurationVar(&c.retryPeriod)
+	cmd.Flag("gitops-rules-dir", "The directory where the Prometheus rule files are written on gitops mode.").StringVar(&c.gitOpsRulesDir)
+	cmd.Flag("gitops-prometheus-reload-url", "The Prometheus reload URL called after the rule files change on gitops mode, e.g: 'http://prometheus:9090/-/reload' (disabled if empty).").StringVar(&c.gitOpsPromReloadURL)
+	cmd.Flag("gitops-clean-interval", "The duration between the removals of the stale rule files on gitops mode (the deleted service levels rule files are also removed on deletion).").Default("1m").DurationVar(&c.gitOpsCleanInterval)
+	c.prometheus.registerFlags(cmd, "Prometheus compatible API address used to set the SLOs live error budget on the service levels status (disabled if empty).", "")
 
 	return c
 }
@@ -122,8 +179,12 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 	}
 	sloPeriod := time.Duration(sp)
 
+	if k.webhookListenAddr != "" && (k.webhookTLSCertFile == "" || k.webhookTLSKeyFile == "") {
+		return fmt.Errorf("admission webhook TLS certificate and key files are required")
+	}
+
 	// Plugins.
-	pluginsRepo, err := createPluginLoader(ctx, logger, k.pluginsPaths)
+	pluginsRepo, err := createPluginLoader(ctx, logger, k.pluginsPaths, k.pluginsCacheDir)
 	if err != nil {
 		return err
 	}
@@ -153,11 +214,33 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 		return fmt.Errorf("invalid default slo period: %w", err)
 	}
 
+	// Create the generate app service (the one that the CLIs use).
+	defSLOPlugins := []generate.SLOProcessor{}
+	if !k.disableDefaultSLOPlugins {
+		defSLOPlugins, err = createDefaultSLOPlugins(logger, false, false)
+		if err != nil {
+			return fmt.Errorf("could not create default SLO plugins: %w", err)
+		}
+	}
+
+	generator, err := generate.NewService(generate.ServiceConfig{
+		AlertGenerator:  alert.NewGenerator(windowsRepo),
+		DefaultPlugins:  defSLOPlugins,
+		SLOPluginGetter: pluginsRepo,
+		ExtraPlugins:    cmdLevelSLOPlugins,
+		Logger:          generatorLogger{Logger: logger},
+	})
+	if err != nil {
+		return fmt.Errorf("could not create Prometheus rules generator: %w", err)
+	}
+
 	// Kubernetes services.
-	kuberepo, err := k.newKubernetesService(ctx, config, pluginsRepo)
+	kubeSvcs, err := k.newKubernetesServices(ctx, config, pluginsRepo)
 	if err != nil {
 		return fmt.Errorf("could not create Kubernetes service: %w", err)
 	}
+	kuberepo := kubeSvcs.repo
+	defer kubeSvcs.eventRecorder.Shutdown()
 
 	// Check we can get Sloth CRs without problem before starting everything. This is a hard
 	// dependency, if we can't, we must fail.
@@ -167,29 +250,47 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 	}
 	logger.Debugf("PrometheusServiceLevel CRD ready")
 
+	// The ClusterPrometheusServiceLevel CRD is optional, the CRDs are not upgraded by some installation
+	// methods (e.g: Helm), so if missing, we don't fail and disable the cluster SLOs handling.
+	clusterSLOsEnabled := !k.disableClusterSLOs
+	if clusterSLOsEnabled {
+		_, err = kuberepo.ListClusterPrometheusServiceLevels(ctx, metav1.ListOptions{})
+		if err != nil {
+			if !kubeerrors.IsNotFound(err) {
+				return fmt.Errorf("check for ClusterPrometheusServiceLevel CRD failed: could not list: %w", err)
+			}
+			logger.Warningf("ClusterPrometheusServiceLevel CRD missing, cluster SLOs handling disabled")
+			clusterSLOsEnabled = false
+		} else {
+			logger.Debugf("ClusterPrometheusServiceLevel CRD ready")
+		}
+	}
+
+	// The PrometheusServiceLevelTemplate CRD is optional for the same reason, if missing, the SLO template
+	// changes are not watched (the SLO templates instances will fail to render).
+	templatesEnabled := true
+	_, err = kuberepo.ListPrometheusServiceLevelTemplates(ctx, k.namespace, metav1.ListOptions{})
+	if err != nil {
+		if !kubeerrors.IsNotFound(err) {
+			return fmt.Errorf("check for PrometheusServiceLevelTemplate CRD failed: could not list: %w", err)
+		}
+		if k.autoSLOs {
+			return fmt.Errorf("auto SLOs require the PrometheusServiceLevelTemplate CRD")
+		}
+		logger.Warningf("PrometheusServiceLevelTemplate CRD missing, SLO templates handling disabled")
+		templatesEnabled = false
+	} else {
+		logger.Debugf("PrometheusServiceLevelTemplate CRD ready")
+	}
+
 	// Prepare our run and reload entrypoints.
 	var g run.Group
 	reloadManager := reload.NewManager()
 
-	// Run hot-reload.
-	{
-		// Set SLI plugin repository reloader.
-		reloadManager.Add(1000, reload.ReloaderFunc(func(ctx context.Context, id string) error {
-			return pluginsRepo.Reload(ctx)
-		}))
-
-		ctx, cancel := context.WithCancel(ctx)
-		g.Add(
-			func() error {
-				logger.Infof("Hot-reload manager running")
-				defer logger.Infof("Hot-reload manager stopped")
-				return reloadManager.Run(ctx)
-			},
-			func(_ error) {
-				cancel()
-			},
-		)
-	}
+	// Set SLI plugin repository reloader.
+	reloadManager.Add(1000, reload.ReloaderFunc(func(ctx context.Context, id string) error {
+		return pluginsRepo.Reload(ctx)
+	}))
 
 	// OS signals.
 	{
@@ -215,7 +316,11 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 						logger.Infof("Signal %s received", s)
 						// Don't stop if SIGHUP, only reload.
 						if s == syscall.SIGHUP {
-							reloadC <- struct{}{}
+							select {
+							case reloadC <- struct{}{}:
+							default:
+								logger.Warningf("Hot-reload ignored, hot-reload manager is not running (not the leader)")
+							}
 							continue
 						}
 
@@ -249,7 +354,12 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 				w.WriteHeader(http.StatusMethodNotAllowed)
 				return
 			}
-			hotReloadC <- struct{}{}
+			select {
+			case hotReloadC <- struct{}{}:
+			default:
+				logger.Warningf("Hot-reload ignored, hot-reload manager is not running (not the leader)")
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
 		}))
 
 		server := &http.Server{
@@ -281,6 +391,11 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 		// Metrics.
 		mux.Handle(k.metricsPath, promhttp.Handler())
 
+		// Health check.
+		mux.Handle("/healthz", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}))
+
 		// Pprof.
 		mux.HandleFunc("/debug/pprof/", pprof.Index)
 		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
@@ -310,39 +425,62 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 		)
 	}
 
-	// Main controller.
-	{
-		ctx, cancel := context.WithCancel(ctx)
-		defer cancel()
+	// Validating admission webhook HTTP server.
+	if k.webhookListenAddr != "" {
+		webhook, err := admission.NewWebhook(admission.WebhookConfig{
+			SpecLoader:  storageio.NewK8sSlothPrometheusYAMLSpecLoader(pluginsRepo, sloPeriod),
+			Generator:   generator,
+			ExtraLabels: k.extraLabels,
+			Logger:      logger,
+		})
+		if err != nil {
+			return fmt.Errorf("could not create admission webhook: %w", err)
+		}
 
-		defSLOPlugins := []generate.SLOProcessor{}
-		if !k.disableDefaultSLOPlugins {
-			defSLOPlugins, err = createDefaultSLOPlugins(logger, false, false)
-			if err != nil {
-				return fmt.Errorf("could not create default SLO plugins: %w", err)
-			}
+		mux := http.NewServeMux()
+		mux.Handle(k.webhookPath, webhook)
+
+		server := &http.Server{
+			Addr:    k.webhookListenAddr,
+			Handler: mux,
 		}
 
-		// Create the generate app service (the one that the CLIs use).
-		generator, err := generate.NewService(generate.ServiceConfig{
-			AlertGenerator:  alert.NewGenerator(windowsRepo),
-			DefaultPlugins:  defSLOPlugins,
-			SLOPluginGetter: pluginsRepo,
-			ExtraPlugins:    cmdLevelSLOPlugins,
-			Logger:          generatorLogger{Logger: logger},
-		})
+		g.Add(
+			func() error {
+				logger.WithValues(log.Kv{"addr": k.webhookListenAddr}).Infof("Admission webhook https server listening")
+				defer logger.WithValues(log.Kv{"addr": k.webhookListenAddr}).Infof("Admission webhook https server stopped")
+				return server.ListenAndServeTLS(k.webhookTLSCertFile, k.webhookTLSKeyFile)
+			},
+			func(_ error) {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				err := server.Shutdown(ctx)
+				if err != nil {
+					logger.Errorf("Error shutting down admission webhook server: %w", err)
+				}
+			},
+		)
+	}
+
+	// Main controllers.
+	{
+		nsSelector, err := labels.Parse(k.namespaceLabelSelector)
 		if err != nil {
-			return fmt.Errorf("could not create Prometheus rules generator: %w", err)
+			return fmt.Errorf("invalid namespace label selector %q: %w", k.namespaceLabelSelector, err)
 		}
 
-		// Create handler.
+		// Create handler, shared by the namespaced and the cluster scoped controllers.
 		controllerConfig := kubecontroller.HandlerConfig{
-			Generator:        generator,
-			SpecLoader:       storageio.NewK8sSlothPrometheusCRSpecLoader(pluginsRepo, sloPeriod),
-			Repository:       kuberepo,
-			KubeStatusStorer: kuberepo,
-			ExtraLabels:      k.extraLabels,
-			Logger:           logger,
+			Generator:              generator,
+			SpecLoader:             storageio.NewK8sSlothPrometheusCRSpecLoader(pluginsRepo, sloPeriod),
+			Repository:             kuberepo,
+			KubeStatusStorer:       kuberepo,
+			EventRecorder:          kubeSvcs.eventRecorder,
+			NamespaceGetter:        kuberepo,
+			TemplateGetter:         kuberepo,
+			NamespaceLabelSelector: nsSelector,
+			ExtraLabels:            k.extraLabels,
+			Logger:                 logger,
 		}
 		handler, err := kubecontroller.NewHandler(controllerConfig)
 		if err != nil {
@@ -357,6 +495,7 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 
 		ret := kubecontroller.NewPrometheusServiceLevelsRetriver(k.namespace, lSelector, kuberepo)
 
+		metricsRecorder := kooperprometheus.New(kooperprometheus.Config{})
 		ctrl, err := koopercontroller.New(&koopercontroller.Config{
 			Handler:              handler,
 			Retriever:            ret,
@@ -365,17 +504,209 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 			ConcurrentWorkers:    k.workers,
 			ProcessingJobRetries: 2,
 			ResyncInterval:       k.resyncInterval,
-			MetricsRecorder:      kooperprometheus.New(kooperprometheus.Config{}),
+			MetricsRecorder:      metricsRecorder,
 		})
 		if err != nil {
 			return fmt.Errorf("could not create namespace controller: %w", err)
 		}
 
+		ctrls := []koopercontroller.Controller{ctrl}
+		if clusterSLOsEnabled {
+			clusterCtrl, err := koopercontroller.New(&koopercontroller.Config{
+				Handler:              handler,
+				Retriever:            kubecontroller.NewClusterPrometheusServiceLevelsRetriver(lSelector, kuberepo),
+				Logger:               kooperlogger{Logger: logger.WithValues(log.Kv{"lib": "kooper", "controller": "cluster"})},
+				Name:                 "sloth-cluster",
+				ConcurrentWorkers:    k.workers,
+				ProcessingJobRetries: 2,
+				ResyncInterval:       k.resyncInterval,
+				MetricsRecorder:      metricsRecorder,
+			})
+			if err != nil {
+				return fmt.Errorf("could not create cluster controller: %w", err)
+			}
+			ctrls = append(ctrls, clusterCtrl)
+		}
+
+		if templatesEnabled {
+			tplHandler, err := kubecontroller.NewTemplateHandler(kubecontroller.TemplateHandlerConfig{
+				ServiceLevelHandler:         handler,
+				ServiceLevelLister:          kuberepo,
+				Namespace:                   k.namespace,
+				LabelSelector:               lSelector,
+				DisableClusterServiceLevels: !clusterSLOsEnabled,
+				Logger:                      logger,
+			})
+			if err != nil {
+				return fmt.Errorf("could not create SLO templates controller handler: %w", err)
+			}
+
+			tplCtrl, err := koopercontroller.New(&koopercontroller.Config{
+				Handler:              tplHandler,
+				Retriever:            kubecontroller.NewPrometheusServiceLevelTemplatesRetriver(k.namespace, kuberepo),
+				Logger:               kooperlogger{Logger: logger.WithValues(log.Kv{"lib": "kooper", "controller": "templates"})},
+				Name:                 "sloth-templates",
+				ConcurrentWorkers:    k.workers,
+				ProcessingJobRetries: 2,
+				ResyncInterval:       k.resyncInterval,
+				MetricsRecorder:      metricsRecorder,
+			})
+			if err != nil {
+				return fmt.Errorf("could not create SLO templates controller: %w", err)
+			}
+			ctrls = append(ctrls, tplCtrl)
+		}
+
+		if k.autoSLOs {
+			autoSLOHandler, err := kubecontroller.NewAutoSLOHandler(kubecontroller.AutoSLOHandlerConfig{
+				Repository:             kuberepo,
+				NamespaceGetter:        kuberepo,
+				NamespaceLabelSelector: nsSelector,
+				Logger:                 logger,
+			})
+			if err != nil {
+				return fmt.Errorf("could not create auto SLOs controller handler: %w", err)
+			}
+
+			autoSLORetrievers := map[string]koopercontroller.Retriever{
+				"services":    kubecontroller.NewServicesRetriver(k.namespace, lSelector, kuberepo),
+				"deployments": kubecontroller.NewDeploymentsRetriver(k.namespace, lSelector, kuberepo),
+			}
+			for _, kind := range []string{"services", "deployments"} {
+				autoSLOCtrl, err := koopercontroller.New(&koopercontroller.Config{
+					Handler:              autoSLOHandler,
+					Retriever:            autoSLORetrievers[kind],
+					Logger:               kooperlogger{Logger: logger.WithValues(log.Kv{"lib": "kooper", "controller": "auto-slos-" + kind})},
+					Name:                 "sloth-auto-slos-" + kind,
+					ConcurrentWorkers:    k.workers,
+					ProcessingJobRetries: 2,
+					ResyncInterval:       k.resyncInterval,
+					MetricsRecorder:      metricsRecorder,
+				})
+				if err != nil {
+					return fmt.Errorf("could not create %s auto SLOs controller: %w", kind, err)
+				}
+				ctrls = append(ctrls, autoSLOCtrl)
+			}
+		}
+
+		// Live SLO error budgets status, uses the same Prometheus storage as the server command.
+		var (
+			budgetPromClient      storageprometheus.PrometheusAPIClient
+			budgetMetricsRecorder httpbackendmetrics.Recorder
+		)
+		if k.prometheus.promAddress != "" {
+			client, err := k.prometheus.newAPIClient()
+			if err != nil {
+				return err
+			}
+			budgetMetricsRecorder = httpbackendmetricsprometheus.NewRecorder(prometheus.DefaultRegisterer)
+			budgetPromClient = storageprometheus.NewMeasuredPrometheusAPIClient(budgetMetricsRecorder, client)
+		}
+
+		// The leader components, only the leader (if leader election enabled) runs the controller, the
+		// hot-reload manager and the SLO budgets status updater.
+		runLeader := func(ctx context.Context) error {
+			var g run.Group
+
+			// SLO budgets status updater.
+			if budgetPromClient != nil {
+				ctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				logger := logger.WithValues(log.Kv{"prometheus": k.prometheus.promAddress})
+
+				promRepo, err := storageprometheus.NewRepository(ctx, storageprometheus.RepositoryConfig{
+					PrometheusClient:     budgetPromClient,
+					CacheRefreshInterval: k.prometheus.cacheInstantRefreshInterval,
+					MetricsRecorder:      budgetMetricsRecorder,
+					Logger:               logger,
+				})
+				if err != nil {
+					return fmt.Errorf("could not create prometheus storage repository: %w", err)
+				}
+
+				budgetUpdater, err := kubecontroller.NewBudgetStatusUpdater(kubecontroller.BudgetStatusUpdaterConfig{
+					SLOBudgetGetter:             promRepo,
+					ServiceLevelLister:          kuberepo,
+					KubeStatusStorer:            kuberepo,
+					Namespace:                   k.namespace,
+					LabelSelector:               lSelector,
+					DisableClusterServiceLevels: !clusterSLOsEnabled,
+					RefreshInterval:             k.prometheus.cacheInstantRefreshInterval,
+					Logger:                      logger,
+				})
+				if err != nil {
+					return fmt.Errorf("could not create SLO budgets status updater: %w", err)
+				}
+
+				g.Add(
+					func() error {
+						logger.Infof("SLO budgets status updater running")
+						defer logger.Infof("SLO budgets status updater stopped")
+						return budgetUpdater.Run(ctx)
+					},
+					func(_ error) {
+						cancel()
+					},
+				)
+			}
+
+			// GitOps stale rule files cleaner.
+			if kubeSvcs.gitOpsRepo != nil {
+				ctx, cancel := context.WithCancel(ctx)
+				g.Add(
+					func() error {
+						logger.Infof("GitOps rule files cleaner running")
+						defer logger.Infof("GitOps rule files cleaner stopped")
+						return kubeSvcs.gitOpsRepo.Run(ctx)
+					},
+					func(_ error) {
+						cancel()
+					},
+				)
+			}
+
+			// Hot-reload manager.
+			{
+				ctx, cancel := context.WithCancel(ctx)
+				g.Add(
+					func() error {
+						logger.Infof("Hot-reload manager running")
+						defer logger.Infof("Hot-reload manager stopped")
+						return reloadManager.Run(ctx)
+					},
+					func(_ error) {
+						cancel()
+					},
+				)
+			}
+
+			// Controllers.
+			for _, ctrl := range ctrls {
+				ctx, cancel := context.WithCancel(ctx)
+				g.Add(
+					func() error {
+						logger.Infof("Kubernetes controller running")
+						defer logger.Infof("Kubernetes controller stopped")
+						return ctrl.Run(ctx)
+					},
+					func(_ error) {
+						cancel()
+					},
+				)
+			}
+
+			return g.Run()
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
 		g.Add(
 			func() error {
-				logger.Infof("Kubernetes controller running")
-				defer logger.Infof("Kubernetes controller stopped")
-				return ctrl.Run(ctx)
+				if !k.leaderElection {
+					return runLeader(ctx)
+				}
+				return k.runWithLeaderElection(ctx, logger, kubeSvcs.kubeCli, runLeader)
 			},
 			func(_ error) {
 				cancel()
@@ -386,16 +717,128 @@ func (k kubeControllerCommand) Run(ctx context.Context, config RootConfig) error
 	return g.Run()
 }
 
+// runWithLeaderElection runs the function only when the Lease based leader election leadership is acquired.
+// If the leadership is lost, it will stop the function and end with an error, the same as if the function
+// ends.
+func (k kubeControllerCommand) runWithLeaderElection(ctx context.Context, logger log.Logger, kubeCli kubernetes.Interface, f func(ctx context.Context) error) error {
+	ns := k.leaderElectionNamespace
+	if ns == "" {
+		data, err := os.ReadFile(inClusterNamespacePath)
+		if err != nil {
+			return fmt.Errorf("leader election namespace is required when not running in a Kubernetes cluster: %w", err)
+		}
+		ns = strings.TrimSpace(string(data))
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("could not get hostname: %w", err)
+	}
+	id := hostname + "_" + string(uuid.NewUUID())
+	logger = logger.WithValues(log.Kv{"lease": ns + "/" + k.leaderElectionLeaseName, "id": id})
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	leading := make(chan struct{})
+	leadershipLost := false
+	errC := make(chan error, 1)
+	le, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta:  metav1.ObjectMeta{Name: k.leaderElectionLeaseName, Namespace: ns},
+			Client:     kubeCli.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: id},
+		},
+		LeaseDuration:   k.leaseDuration,
+		RenewDeadline:   k.renewDeadline,
+		RetryPeriod:     k.retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            k.leaderElectionLeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				logger.Infof("Leadership acquired")
+				close(leading)
+				errC <- f(ctx)
+				// Stop leading if the leader components end by themselves.
+				cancel()
+			},
+			OnStoppedLeading: func() {
+				// Our context is only cancelled when stopping or when the leader components end by themselves,
+				// so if it's not cancelled, the lease has been lost. This is called before the elector returns.
+				if ctx.Err() == nil {
+					leadershipLost = true
+					logger.Warningf("Leadership lost")
+					return
+				}
+				logger.Infof("Leadership released")
+			},
+			OnNewLeader: func(identity string) {
+				logger.Infof("Current leader: %s", identity)
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not create leader elector: %w", err)
+	}
+
+	logger.Infof("Waiting to acquire leadership...")
+	le.Run(ctx)
+
+	// Stopped without acquiring the leadership.
+	select {
+	case <-leading:
+	default:
+		return nil
+	}
+
+	// Wait until the leader components stop.
+	err = <-errC
+	if leadershipLost {
+		return fmt.Errorf("leadership lost")
+	}
+
+	return err
+}
+
 // kubernetesService is an internal interface so we can return all the Kubernetes service specific implemententations from the
 // same function (e.g: regular, dry-run, fake...).
 type kubernetesService interface {
 	ListPrometheusServiceLevels(ctx context.Context, ns string, opts metav1.ListOptions) (*slothv1.PrometheusServiceLevelList, error)
 	WatchPrometheusServiceLevels(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error)
-	EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, err error) error
+	EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, status slothv1.PrometheusServiceLevelStatus) error
+	ListClusterPrometheusServiceLevels(ctx context.Context, opts metav1.ListOptions) (*slothv1.ClusterPrometheusServiceLevelList, error)
+	WatchClusterPrometheusServiceLevels(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	EnsureClusterPrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.ClusterPrometheusServiceLevel, status slothv1.PrometheusServiceLevelStatus) error
+	GetPrometheusServiceLevelTemplate(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevelTemplate, error)
+	ListPrometheusServiceLevelTemplates(ctx context.Context, ns string, opts metav1.ListOptions) (*slothv1.PrometheusServiceLevelTemplateList, error)
+	WatchPrometheusServiceLevelTemplates(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error)
+	GetPrometheusServiceLevel(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevel, error)
+	EnsurePrometheusServiceLevel(ctx context.Context, psl *slothv1.PrometheusServiceLevel) error
+	DeletePrometheusServiceLevel(ctx context.Context, ns, name string) error
+	ListServices(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.ServiceList, error)
+	WatchServices(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error)
+	ListDeployments(ctx context.Context, ns string, opts metav1.ListOptions) (*appsv1.DeploymentList, error)
+	WatchDeployments(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error)
+	GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error)
 	StoreSLOs(ctx context.Context, kmeta model.K8sMeta, slos model.PromSLOGroupResult) error
 }
 
-func (k kubeControllerCommand) newKubernetesService(ctx context.Context, config RootConfig, pluginsRepo *storagefs.FilePluginRepo) (kubernetesService, error) {
+// kubernetesEventRecorder is an internal interface so we can return all the Kubernetes event recorder specific
+// implementations from the same function (e.g: regular, dry-run, fake...).
+type kubernetesEventRecorder interface {
+	Eventf(obj runtime.Object, eventType, reason, messageFmt string, args ...interface{})
+	Shutdown()
+}
+
+// kubernetesServices are the Kubernetes services used by the controller.
+type kubernetesServices struct {
+	repo          kubernetesService
+	eventRecorder kubernetesEventRecorder
+	kubeCli       kubernetes.Interface
+	// gitOpsRepo is only set on gitops mode, used to clean the deleted service levels rule files.
+	gitOpsRepo *storagek8s.GitOpsRepository
+}
+
+func (k kubeControllerCommand) newKubernetesServices(ctx context.Context, config RootConfig, pluginsRepo *storagefs.FilePluginRepo) (*kubernetesServices, error) {
 	config.Logger.Infof("Loading Kubernetes configuration...")
 
 	// Get k8s transform plugin.
@@ -410,7 +853,15 @@ func (k kubeControllerCommand) newKubernetesService(ctx context.Context, config
 
 	// Fake mode.
 	if k.runMode == controllerModeFake {
-		return storagek8s.NewFakeApiserverRepository(config.Logger, plugin)
+		kuberepo, err := storagek8s.NewFakeApiserverRepository(config.Logger, plugin)
+		if err != nil {
+			return nil, err
+		}
+		return &kubernetesServices{
+			repo:          kuberepo,
+			eventRecorder: storagek8s.NewFakeEventRecorder(config.Logger),
+			kubeCli:       kubernetesfake.NewClientset(),
+		}, nil
 	}
 
 	// Load Kubernetes clients.
@@ -433,8 +884,13 @@ func (k kubeControllerCommand) newKubernetesService(ctx context.Context, config
 	if err != nil {
 		return nil, fmt.Errorf("could not create Kubernetes discovery client: %w", err)
 	}
+	kubeCli, err := kubernetes.NewForConfig(kubeCfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create Kubernetes client: %w", err)
+	}
 
 	apiserverRepoConfig := storagek8s.ApiserverRepositoryConfig{
+		KubeCli:            kubeCli,
 		SlothCli:           kubeSlothcli,
 		Logger:             config.Logger,
 		DynamicCli:         dynamicCli,
@@ -451,11 +907,64 @@ func (k kubeControllerCommand) newKubernetesService(ctx context.Context, config
 	// Dry run mode.
 	if k.runMode == controllerModeDryRun {
 		config.Logger.Warningf("Kubernetes in dry run mode")
-		return storagek8s.NewDryRunApiserverRepository(*kuberepo, config.Logger), nil
+		return &kubernetesServices{
+			repo:          storagek8s.NewDryRunApiserverRepository(*kuberepo, config.Logger),
+			eventRecorder: storagek8s.NewDryRunEventRecorder(config.Logger),
+			kubeCli:       kubeCli,
+		}, nil
+	}
+
+	// Create Kubernetes event recorder.
+	eventRecorder, err := storagek8s.NewEventRecorder(storagek8s.EventRecorderConfig{
+		KubeCli:   kubeCli,
+		Component: "sloth",
+		Logger:    config.Logger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create Kubernetes event recorder: %w", err)
+	}
+
+	// GitOps mode.
+	if k.runMode == controllerModeGitOps {
+		lSelector, err := labels.Parse(k.labelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %w", k.labelSelector, err)
+		}
+		nsSelector, err := labels.Parse(k.namespaceLabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace label selector %q: %w", k.namespaceLabelSelector, err)
+		}
+
+		gitOpsRepo, err := storagek8s.NewGitOpsRepository(storagek8s.GitOpsRepositoryConfig{
+			Repository:                  *kuberepo,
+			RulesDir:                    k.gitOpsRulesDir,
+			PrometheusReloadURL:         k.gitOpsPromReloadURL,
+			Namespace:                   k.namespace,
+			LabelSelector:               lSelector,
+			NamespaceLabelSelector:      nsSelector,
+			DisableClusterServiceLevels: k.disableClusterSLOs,
+			CleanInterval:               k.gitOpsCleanInterval,
+			Logger:                      config.Logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not create Kubernetes GitOps repository: %w", err)
+		}
+
+		config.Logger.WithValues(log.Kv{"rules-dir": k.gitOpsRulesDir}).Infof("Kubernetes in gitops mode")
+		return &kubernetesServices{
+			repo:          gitOpsRepo,
+			eventRecorder: eventRecorder,
+			kubeCli:       kubeCli,
+			gitOpsRepo:    gitOpsRepo,
+		}, nil
 	}
 
 	// Default mode.
-	return kuberepo, nil
+	return &kubernetesServices{
+		repo:          kuberepo,
+		eventRecorder: eventRecorder,
+		kubeCli:       kubeCli,
+	}, nil
 }
 
 // loadKubernetesConfig loads kubernetes configuration based on flags.
@@ -519,16 +1028,16 @@ func (g generatorLogger) WithCtxValues(ctx context.Context) log.Logger {
 	return generatorLogger{Logger: g.Logger.WithCtxValues(ctx)}
 }
 
-func createPluginLoader(ctx context.Context, logger log.Logger, paths []string) (*storagefs.FilePluginRepo, error) {
+func createPluginLoader(ctx context.Context, logger log.Logger, paths []string, cacheDir string) (*storagefs.FilePluginRepo, error) {
 	fss := []fs.FS{
 		plugin.EmbeddedDefaultSLOPlugins,
 		plugin.EmbeddedDefaultK8sTransformPlugins,
 	}
 	for _, p := range paths {
-		fss = append(fss, os.DirFS(p))
+		fss = append(fss, storagefs.NewOSDirFS(p))
 	}
 
-	pluginsRepo, err := storagefs.NewFilePluginRepo(logger, false, pluginenginesli.PluginLoader, pluginengineslo.PluginLoader, pluginenginesk8stransform.PluginLoader, fss...)
+	pluginsRepo, err := storagefs.NewFilePluginRepo(logger, false, cacheDir, pluginenginesli.PluginLoader, pluginengineslo.PluginLoader, pluginenginesk8stransform.PluginLoader, fss...)
 	if err != nil {
 		return nil, fmt.Errorf("could not create file SLO and SLI plugins repository: %w", err)
 	}
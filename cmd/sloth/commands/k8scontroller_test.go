@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/slok/sloth/internal/log"
+)
+
+func TestKubeControllerCommandRunWithLeaderElection(t *testing.T) {
+	tests := map[string]struct {
+		run    func(ctx context.Context, stop context.CancelFunc, loseLease func()) error
+		expErr error
+	}{
+		"Stopping while leading should end without error.": {
+			run: func(ctx context.Context, stop context.CancelFunc, loseLease func()) error {
+				stop()
+				<-ctx.Done()
+				return nil
+			},
+		},
+
+		"The leader components ending with an error should end with the error.": {
+			run: func(ctx context.Context, stop context.CancelFunc, loseLease func()) error {
+				return fmt.Errorf("something")
+			},
+			expErr: fmt.Errorf("something"),
+		},
+
+		"Losing the lease should stop the leader components and end with an error.": {
+			run: func(ctx context.Context, stop context.CancelFunc, loseLease func()) error {
+				loseLease()
+				<-ctx.Done()
+				return nil
+			},
+			expErr: fmt.Errorf("leadership lost"),
+		},
+
+		"Losing the lease while the leader components end with an error should end with the leadership lost error.": {
+			run: func(ctx context.Context, stop context.CancelFunc, loseLease func()) error {
+				loseLease()
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			expErr: fmt.Errorf("leadership lost"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			// Lose the lease by failing the lease renewals.
+			var leaseLost atomic.Bool
+			kubeCli := kubernetesfake.NewClientset()
+			kubeCli.PrependReactor("update", "leases", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if leaseLost.Load() {
+					return true, nil, fmt.Errorf("lease lost")
+				}
+				return false, nil, nil
+			})
+
+			k := kubeControllerCommand{
+				leaderElectionNamespace: "test-ns",
+				leaderElectionLeaseName: "test-lease",
+				leaseDuration:           300 * time.Millisecond,
+				renewDeadline:           200 * time.Millisecond,
+				retryPeriod:             20 * time.Millisecond,
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			err := k.runWithLeaderElection(ctx, log.Noop, kubeCli, func(lctx context.Context) error {
+				return test.run(lctx, cancel, func() { leaseLost.Store(true) })
+			})
+
+			assert.NotErrorIs(ctx.Err(), context.DeadlineExceeded, "timed out")
+			assert.Equal(test.expErr, err)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/alecthomas/kingpin/v2"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/pluginengine"
+	pluginengineslo "github.com/slok/sloth/internal/pluginengine/slo"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+	pluginslov2 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v2"
+)
+
+const (
+	pluginsSubcommandList           = "list"
+	pluginsSubcommandDescribe       = "describe"
+	pluginsSubcommandValidateConfig = "validate-config"
+)
+
+type pluginsCommand struct {
+	subcommand      string
+	pluginsPaths    []string
+	pluginsCacheDir string
+	pluginID        string
+	config          string
+	configFile      string
+}
+
+// NewPluginsCommand returns the plugins command.
+func NewPluginsCommand(app *kingpin.Application) Command {
+	c := &pluginsCommand{}
+	cmd := app.Command("plugins", "Lists, describes and validates the configuration of the built-in and discovered plugins.")
+	cmd.Flag("plugins-path", "The path to any of the sloth compatible plugin types (can be repeated).").Short('p').StringsVar(&c.pluginsPaths)
+	cmd.Flag("plugins-cache-dir", "The directory used to cache the plugins discovery (kind and ID) across executions, so the plugins that can't be discovered statically are not loaded on start, the plugins are still loaded on their first use (disabled if empty).").StringVar(&c.pluginsCacheDir)
+
+	cmd.Command(pluginsSubcommandList, "Lists all the plugins.").Action(c.setSubcommand(pluginsSubcommandList))
+
+	describe := cmd.Command(pluginsSubcommandDescribe, "Shows the plugin description, supported modes, config schema and config example.").Action(c.setSubcommand(pluginsSubcommandDescribe))
+	describe.Arg("id", "The plugin ID.").Required().StringVar(&c.pluginID)
+
+	validateConfig := cmd.Command(pluginsSubcommandValidateConfig, "Validates a plugin config against the plugin config schema.").Action(c.setSubcommand(pluginsSubcommandValidateConfig))
+	validateConfig.Arg("id", "The plugin ID.").Required().StringVar(&c.pluginID)
+	validateConfig.Flag("config", "The plugin config in JSON or YAML.").Short('c').StringVar(&c.config)
+	validateConfig.Flag("config-file", "The plugin config file in JSON or YAML, use '-' to read it from stdin.").Short('f').StringVar(&c.configFile)
+
+	return c
+}
+
+func (p *pluginsCommand) setSubcommand(name string) kingpin.Action {
+	return func(*kingpin.ParseContext) error {
+		p.subcommand = name
+		return nil
+	}
+}
+
+func (p pluginsCommand) Name() string { return "plugins" }
+func (p pluginsCommand) Run(ctx context.Context, config RootConfig) error {
+	logger := config.Logger.WithValues(log.Kv{"cmd": "plugins"})
+
+	pluginsRepo, err := createPluginLoader(ctx, logger, p.pluginsPaths, p.pluginsCacheDir)
+	if err != nil {
+		return err
+	}
+
+	// Get all the plugins.
+	plugins := []pluginInfo{}
+	sloPlugins, err := pluginsRepo.ListSLOPlugins(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list SLO plugins: %w", err)
+	}
+	for _, pl := range sloPlugins {
+		plugins = append(plugins, pluginInfo{Kind: "slo", ID: pl.ID, Metadata: pl.Metadata, sloPlugin: &pl})
+	}
+
+	sliPlugins, err := pluginsRepo.ListSLIPlugins(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list SLI plugins: %w", err)
+	}
+	for _, pl := range sliPlugins {
+		plugins = append(plugins, pluginInfo{Kind: "sli", ID: pl.ID, Metadata: pl.Metadata})
+	}
+
+	k8sTransformPlugins, err := pluginsRepo.ListK8sTransformPlugins(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list K8s transform plugins: %w", err)
+	}
+	for _, pl := range k8sTransformPlugins {
+		plugins = append(plugins, pluginInfo{Kind: "k8s-transform", ID: pl.ID, Metadata: pl.Metadata})
+	}
+
+	sort.SliceStable(plugins, func(i, j int) bool {
+		if plugins[i].Kind != plugins[j].Kind {
+			return plugins[i].Kind > plugins[j].Kind // SLO first.
+		}
+		return plugins[i].ID < plugins[j].ID
+	})
+
+	switch p.subcommand {
+	case pluginsSubcommandList:
+		return p.list(config.Stdout, plugins)
+	case pluginsSubcommandDescribe:
+		return p.describe(config.Stdout, plugins)
+	case pluginsSubcommandValidateConfig:
+		return p.validateConfig(ctx, logger, config.Stdin, config.Stdout, plugins)
+	}
+
+	return fmt.Errorf("unknown %q plugins subcommand", p.subcommand)
+}
+
+type pluginInfo struct {
+	Kind      string
+	ID        string
+	Metadata  pluginengine.Metadata
+	sloPlugin *pluginengineslo.Plugin
+}
+
+func (p pluginsCommand) list(out io.Writer, plugins []pluginInfo) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tID\tCONFIG SCHEMA\tDESCRIPTION")
+	for _, pl := range plugins {
+		schema := "no"
+		if pl.Metadata.ConfigSchema != "" {
+			schema = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", pl.Kind, pl.ID, schema, pl.Metadata.Description)
+	}
+
+	return w.Flush()
+}
+
+func (p pluginsCommand) describe(out io.Writer, plugins []pluginInfo) error {
+	found := false
+	for _, pl := range filterPluginInfos(plugins, p.pluginID) {
+		if found {
+			fmt.Fprintln(out, "---")
+		}
+		found = true
+
+		modes := "all"
+		if len(pl.Metadata.Modes) > 0 {
+			ms := []string{}
+			for _, m := range pl.Metadata.Modes {
+				ms = append(ms, string(m))
+			}
+			modes = strings.Join(ms, ", ")
+		}
+
+		fmt.Fprintf(out, "ID: %s\n", pl.ID)
+		fmt.Fprintf(out, "Kind: %s\n", pl.Kind)
+		fmt.Fprintf(out, "Description: %s\n", pl.Metadata.Description)
+		fmt.Fprintf(out, "Modes: %s\n", modes)
+		if pl.Metadata.ConfigSchema != "" {
+			fmt.Fprintf(out, "Config schema:\n%s\n", indentJSON(pl.Metadata.ConfigSchema))
+		}
+		if pl.Metadata.ConfigExample != "" {
+			fmt.Fprintf(out, "Config example:\n%s\n", indentJSON(pl.Metadata.ConfigExample))
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("plugin %q not found", p.pluginID)
+	}
+
+	return nil
+}
+
+func (p pluginsCommand) validateConfig(ctx context.Context, logger log.Logger, in io.Reader, out io.Writer, plugins []pluginInfo) error {
+	var configData []byte
+	switch {
+	case p.config != "" && p.configFile != "":
+		return fmt.Errorf("config and config file can't be used at the same time")
+	case p.config != "":
+		configData = []byte(p.config)
+	case p.configFile == "-":
+		d, err := io.ReadAll(in)
+		if err != nil {
+			return fmt.Errorf("could not read config from stdin: %w", err)
+		}
+		configData = d
+	case p.configFile != "":
+		d, err := os.ReadFile(p.configFile)
+		if err != nil {
+			return fmt.Errorf("could not read config file: %w", err)
+		}
+		configData = d
+	}
+
+	// Configs are written in YAML on the specs, so support both.
+	configJSON, err := yaml.ToJSON(configData)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	pls := filterPluginInfos(plugins, p.pluginID)
+	if len(pls) == 0 {
+		return fmt.Errorf("plugin %q not found", p.pluginID)
+	}
+
+	for _, pl := range pls {
+		if pl.Metadata.ConfigSchema == "" {
+			logger.Warningf("%s plugin %q doesn't have a config schema, the config can't be validated by the schema", pl.Kind, pl.ID)
+		}
+
+		err := pl.Metadata.ValidateConfig(configJSON)
+		if err != nil {
+			return fmt.Errorf("invalid %s plugin %q config: %w", pl.Kind, pl.ID, err)
+		}
+
+		// SLO plugins can also validate the config on creation.
+		switch {
+		case pl.sloPlugin != nil && pl.sloPlugin.PluginV1Factory != nil:
+			_, err = pl.sloPlugin.PluginV1Factory(configJSON, pluginslov1.AppUtils{Logger: logger})
+		case pl.sloPlugin != nil && pl.sloPlugin.PluginV2Factory != nil:
+			_, err = pl.sloPlugin.PluginV2Factory(configJSON, pluginslov2.AppUtils{Logger: logger})
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s plugin %q config: %w", pl.Kind, pl.ID, err)
+		}
+
+		fmt.Fprintf(out, "%s plugin %q config is valid\n", pl.Kind, pl.ID)
+	}
+
+	return nil
+}
+
+func filterPluginInfos(plugins []pluginInfo, id string) []pluginInfo {
+	filtered := []pluginInfo{}
+	for _, pl := range plugins {
+		if pl.ID == id {
+			filtered = append(filtered, pl)
+		}
+	}
+
+	return filtered
+}
+
+func indentJSON(data string) string {
+	var b bytes.Buffer
+	err := json.Indent(&b, []byte(data), "  ", "  ")
+	if err != nil {
+		return data
+	}
+
+	return "  " + b.String()
+}
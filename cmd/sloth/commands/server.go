@@ -43,20 +43,8 @@ type serverCommand struct {
 	}
 
 	prometheus struct {
-		fake                        bool
-		promAddress                 string
-		cacheInstantRefreshInterval time.Duration
-		auth                        struct {
-			basicUser     string
-			basicPassword string
-		}
-		tls struct {
-			insecureSkipVerify bool
-			caFile             string
-			certFile           string
-			keyFile            string
-		}
-		headers map[string]string
+		fake bool
+		prometheusClientConfig
 	}
 }
 
@@ -71,15 +59,7 @@ func NewServerCommand(app *kingpin.Application) Command {
 	cmd.Flag("pprof-path", "PProf path where debug tool is available.").Default("/debug/pprof").StringVar(&c.statusServer.pprofPath)
 
 	cmd.Flag("fake-prometheus", "Enable fake Prometheus server.").BoolVar(&c.prometheus.fake)
-	cmd.Flag("prometheus-address", "Prometheus server address.").Default("http://localhost:9090").StringVar(&c.prometheus.promAddress)
-	cmd.Flag("prometheus-cache-refresh-interval", "The interval for Prometheus cache instant data refresh refresh.").Default("1m").DurationVar(&c.prometheus.cacheInstantRefreshInterval)
-	cmd.Flag("prometheus-auth-basic-user", "Basic auth user for Prometheus.").StringVar(&c.prometheus.auth.basicUser)
-	cmd.Flag("prometheus-auth-basic-password", "Basic auth password for Prometheus.").StringVar(&c.prometheus.auth.basicPassword)
-	cmd.Flag("prometheus-tls-insecure-skip-verify", "Skip TLS certificate verification for Prometheus client.").BoolVar(&c.prometheus.tls.insecureSkipVerify)
-	cmd.Flag("prometheus-tls-ca-file", "CA certificate file for Prometheus client TLS.").StringVar(&c.prometheus.tls.caFile)
-	cmd.Flag("prometheus-tls-cert-file", "Client certificate file for Prometheus client mTLS.").StringVar(&c.prometheus.tls.certFile)
-	cmd.Flag("prometheus-tls-key-file", "Client key file for Prometheus client mTLS.").StringVar(&c.prometheus.tls.keyFile)
-	cmd.Flag("prometheus-header", "Custom header for Prometheus client (format: 'key=value'). Can be repeated for multiple headers.").Short('h').StringMapVar(&c.prometheus.headers)
+	c.prometheus.registerFlags(cmd, "Prometheus server address.", "http://localhost:9090")
 
 	return c
 }
@@ -179,47 +159,15 @@ func (c serverCommand) Run(ctx context.Context, config RootConfig) error {
 			logger.Warningf("Using fake Prometheus storage backend")
 			repo = storagefake.NewFakeRepository()
 		case c.prometheus.promAddress != "":
-			// Create HTTP transport with optional TLS configuration.
-			transport := http.DefaultTransport.(*http.Transport).Clone()
-
-			// Configure TLS if any TLS options are set.
-			if c.prometheus.tls.insecureSkipVerify || c.prometheus.tls.caFile != "" || c.prometheus.tls.certFile != "" {
-				tlsConfig, err := c.buildPrometheusTLSConfig()
-				if err != nil {
-					return fmt.Errorf("could not build TLS config: %w", err)
-				}
-				transport.TLSClientConfig = tlsConfig
-			}
-
-			var roundTripper http.RoundTripper = transport
-
-			// Add auth and custom headers if configured.
-			if c.prometheus.auth.basicUser != "" || c.prometheus.auth.basicPassword != "" || len(c.prometheus.headers) > 0 {
-				roundTripper = &authHeadersRoundTripper{
-					basicAuthUser: c.prometheus.auth.basicUser,
-					basicAuthPass: c.prometheus.auth.basicPassword,
-					headers:       c.prometheus.headers,
-					next:          roundTripper,
-				}
-			}
-
-			httpClient := &http.Client{
-				Timeout:   1 * time.Minute, // At least we end at some point.
-				Transport: roundTripper,
-			}
-
 			logger.Infof("Using Prometheus storage backend at %s", c.prometheus.promAddress)
 
-			client, err := promapi.NewClient(promapi.Config{
-				Address: c.prometheus.promAddress,
-				Client:  httpClient,
-			})
+			client, err := c.prometheus.newAPIClient()
 			if err != nil {
-				return fmt.Errorf("could not create prometheus api client: %w", err)
+				return err
 			}
 
 			repo, err = storageprometheus.NewRepository(ctx, storageprometheus.RepositoryConfig{
-				PrometheusClient:     storageprometheus.NewMeasuredPrometheusAPIClient(uiBackendMetricsRecorder, promv1.NewAPI(client)),
+				PrometheusClient:     storageprometheus.NewMeasuredPrometheusAPIClient(uiBackendMetricsRecorder, client),
 				CacheRefreshInterval: c.prometheus.cacheInstantRefreshInterval,
 				MetricsRecorder:      uiBackendMetricsRecorder,
 				Logger:               logger,
@@ -300,14 +248,85 @@ func (c serverCommand) Run(ctx context.Context, config RootConfig) error {
 	return nil
 }
 
-func (c *serverCommand) buildPrometheusTLSConfig() (*tls.Config, error) {
+// prometheusClientConfig is the Prometheus API client configuration shared by the commands that
+// query Prometheus.
+type prometheusClientConfig struct {
+	promAddress                 string
+	cacheInstantRefreshInterval time.Duration
+	auth                        struct {
+		basicUser     string
+		basicPassword string
+	}
+	tls struct {
+		insecureSkipVerify bool
+		caFile             string
+		certFile           string
+		keyFile            string
+	}
+	headers map[string]string
+}
+
+func (c *prometheusClientConfig) registerFlags(cmd *kingpin.CmdClause, addressHelp, defaultAddress string) {
+	cmd.Flag("prometheus-address", addressHelp).Default(defaultAddress).StringVar(&c.promAddress)
+	cmd.Flag("prometheus-cache-refresh-interval", "The interval for Prometheus cache instant data refresh refresh.").Default("1m").DurationVar(&c.cacheInstantRefreshInterval)
+	cmd.Flag("prometheus-auth-basic-user", "Basic auth user for Prometheus.").StringVar(&c.auth.basicUser)
+	cmd.Flag("prometheus-auth-basic-password", "Basic auth password for Prometheus.").StringVar(&c.auth.basicPassword)
+	cmd.Flag("prometheus-tls-insecure-skip-verify", "Skip TLS certificate verification for Prometheus client.").BoolVar(&c.tls.insecureSkipVerify)
+	cmd.Flag("prometheus-tls-ca-file", "CA certificate file for Prometheus client TLS.").StringVar(&c.tls.caFile)
+	cmd.Flag("prometheus-tls-cert-file", "Client certificate file for Prometheus client mTLS.").StringVar(&c.tls.certFile)
+	cmd.Flag("prometheus-tls-key-file", "Client key file for Prometheus client mTLS.").StringVar(&c.tls.keyFile)
+	cmd.Flag("prometheus-header", "Custom header for Prometheus client (format: 'key=value'). Can be repeated for multiple headers.").Short('h').StringMapVar(&c.headers)
+}
+
+func (c prometheusClientConfig) newAPIClient() (promv1.API, error) {
+	// Create HTTP transport with optional TLS configuration.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	// Configure TLS if any TLS options are set.
+	if c.tls.insecureSkipVerify || c.tls.caFile != "" || c.tls.certFile != "" {
+		tlsConfig, err := c.buildTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("could not build TLS config: %w", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	var roundTripper http.RoundTripper = transport
+
+	// Add auth and custom headers if configured.
+	if c.auth.basicUser != "" || c.auth.basicPassword != "" || len(c.headers) > 0 {
+		roundTripper = &authHeadersRoundTripper{
+			basicAuthUser: c.auth.basicUser,
+			basicAuthPass: c.auth.basicPassword,
+			headers:       c.headers,
+			next:          roundTripper,
+		}
+	}
+
+	httpClient := &http.Client{
+		Timeout:   1 * time.Minute, // At least we end at some point.
+		Transport: roundTripper,
+	}
+
+	client, err := promapi.NewClient(promapi.Config{
+		Address: c.promAddress,
+		Client:  httpClient,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create prometheus api client: %w", err)
+	}
+
+	return promv1.NewAPI(client), nil
+}
+
+func (c prometheusClientConfig) buildTLSConfig() (*tls.Config, error) {
 	tlsConfig := &tls.Config{
-		InsecureSkipVerify: c.prometheus.tls.insecureSkipVerify,
+		InsecureSkipVerify: c.tls.insecureSkipVerify,
 	}
 
 	// Load CA certificate if provided.
-	if c.prometheus.tls.caFile != "" {
-		caCert, err := os.ReadFile(c.prometheus.tls.caFile)
+	if c.tls.caFile != "" {
+		caCert, err := os.ReadFile(c.tls.caFile)
 		if err != nil {
 			return nil, fmt.Errorf("could not read CA file: %w", err)
 		}
@@ -320,13 +339,13 @@ func (c *serverCommand) buildPrometheusTLSConfig() (*tls.Config, error) {
 	}
 
 	// Load client certificate and key for mTLS if provided.
-	if c.prometheus.tls.certFile != "" && c.prometheus.tls.keyFile != "" {
-		cert, err := tls.LoadX509KeyPair(c.prometheus.tls.certFile, c.prometheus.tls.keyFile)
+	if c.tls.certFile != "" && c.tls.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.tls.certFile, c.tls.keyFile)
 		if err != nil {
 			return nil, fmt.Errorf("could not load client certificate: %w", err)
 		}
 		tlsConfig.Certificates = []tls.Certificate{cert}
-	} else if c.prometheus.tls.certFile != "" || c.prometheus.tls.keyFile != "" {
+	} else if c.tls.certFile != "" || c.tls.keyFile != "" {
 		return nil, fmt.Errorf("both cert-file and key-file must be provided for mTLS")
 	}
 
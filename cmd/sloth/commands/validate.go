@@ -3,7 +3,6 @@ package commands
 import (
 	"context"
 	"fmt"
-	"io"
 	"io/fs"
 	"os"
 	"regexp"
@@ -14,6 +13,7 @@ import (
 
 	"github.com/slok/sloth/internal/log"
 	"github.com/slok/sloth/internal/plugin"
+	storagefs "github.com/slok/sloth/internal/storage/fs"
 	commonerrors "github.com/slok/sloth/pkg/common/errors"
 	utilsdata "github.com/slok/sloth/pkg/common/utils/data"
 	slothlib "github.com/slok/sloth/pkg/lib"
@@ -25,7 +25,9 @@ type validateCommand struct {
 	slosIncludeRegex         string
 	extraLabels              map[string]string
 	pluginsPaths             []string
+	pluginsCacheDir          string
 	sloPeriodWindowsPath     string
+	sloTemplatesPath         string
 	sloPeriod                string
 	sloPlugins               []string
 	disableDefaultSLOPlugins bool
@@ -41,7 +43,9 @@ func NewValidateCommand(app *kingpin.Application) Command {
 	cmd.Flag("fs-include", "Filter regex to include matched discovered SLO file paths, everything else will be ignored. Exclude has preference.").Short('n').StringVar(&c.slosIncludeRegex)
 	cmd.Flag("extra-labels", "Extra labels that will be added to all the generated Prometheus rules ('key=value' form, can be repeated).").Short('l').StringMapVar(&c.extraLabels)
 	cmd.Flag("plugins-path", "The path to SLI and SLO plugins (can be repeated).").Short('p').StringsVar(&c.pluginsPaths)
+	cmd.Flag("plugins-cache-dir", "The directory used to cache the plugins discovery (kind and ID) across executions, so the plugins that can't be discovered statically are not loaded on start, the plugins are still loaded on their first use (disabled if empty).").StringVar(&c.pluginsCacheDir)
 	cmd.Flag("slo-period-windows-path", "The directory path to custom SLO period windows catalog (replaces default ones).").StringVar(&c.sloPeriodWindowsPath)
+	cmd.Flag("slo-templates-path", "The directory path to the PrometheusServiceLevelTemplate manifests used by the Kubernetes SLO specs SLO templates.").StringVar(&c.sloTemplatesPath)
 	cmd.Flag("default-slo-period", "The default SLO period windows to be used for the SLOs.").Default("30d").StringVar(&c.sloPeriod)
 	cmd.Flag("slo-plugins", `SLO plugins chain declaration in JSON format '{"id": "foo","priority": 0,"config": "{}"}' (Can be repeated).`).Short('s').StringsVar(&c.sloPlugins)
 	cmd.Flag("disable-default-slo-plugins", `Disables the default SLO plugins, normally used along with custom SLO plugins to fully customize Sloth behavior`).BoolVar(&c.disableDefaultSLOPlugins)
@@ -96,7 +100,7 @@ func (v validateCommand) Run(ctx context.Context, config RootConfig) error {
 
 	pluginsFSs := []fs.FS{plugin.EmbeddedDefaultSLOPlugins}
 	for _, p := range v.pluginsPaths {
-		pluginsFSs = append(pluginsFSs, os.DirFS(p))
+		pluginsFSs = append(pluginsFSs, storagefs.NewOSDirFS(p))
 	}
 
 	var wfs fs.FS
@@ -104,9 +108,16 @@ func (v validateCommand) Run(ctx context.Context, config RootConfig) error {
 		wfs = os.DirFS(v.sloPeriodWindowsPath)
 	}
 
+	var tfs fs.FS
+	if v.sloTemplatesPath != "" {
+		tfs = os.DirFS(v.sloTemplatesPath)
+	}
+
 	genService, err := slothlib.NewPrometheusSLOGenerator(slothlib.PrometheusSLOGeneratorConfig{
 		WindowsFS:             wfs,
 		PluginsFS:             pluginsFSs,
+		SLOTemplatesFS:        tfs,
+		PluginsCacheDir:       v.pluginsCacheDir,
 		DefaultSLOPeriod:      sloPeriod,
 		DisableDefaultPlugins: v.disableDefaultSLOPlugins,
 		CMDSLOPlugins:         cmdLevelSLOPlugins,
@@ -137,14 +148,8 @@ func (v validateCommand) Run(ctx context.Context, config RootConfig) error {
 		validations = append(validations, validation)
 		for _, data := range splittedSLOsData {
 			totalValidations++
-			_ = data
-			genTarget := generateTarget{
-				SLOData: data,
-				Out:     io.Discard,
-			}
-
 			// Generate SLOs.
-			sloGroupResult, err := genService.GenerateFromRaw(ctx, []byte(genTarget.SLOData))
+			sloGroupResult, err := genService.GenerateFromRaw(ctx, []byte(data))
 			if err != nil {
 				validation.Errs = append(validation.Errs, fmt.Errorf("invalid SLO: %w", err))
 			} else {
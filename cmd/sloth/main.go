@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/sirupsen/logrus"
@@ -24,6 +25,7 @@ func Run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.
 	// Setup commands (registers flags).
 	generateCmd := commands.NewGenerateCommand(app)
 	kubeCtrlCmd := commands.NewKubeControllerCommand(app)
+	pluginsCmd := commands.NewPluginsCommand(app)
 	serverCmd := commands.NewServerCommand(app)
 	validateCmd := commands.NewValidateCommand(app)
 	versionCmd := commands.NewVersionCommand(app)
@@ -31,6 +33,7 @@ func Run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.
 	cmds := map[string]commands.Command{
 		generateCmd.Name(): generateCmd,
 		kubeCtrlCmd.Name(): kubeCtrlCmd,
+		pluginsCmd.Name():  pluginsCmd,
 		serverCmd.Name():   serverCmd,
 		validateCmd.Name(): validateCmd,
 		versionCmd.Name():  versionCmd,
@@ -48,8 +51,8 @@ func Run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.
 	config.Stderr = stderr
 	config.Logger = getLogger(*config)
 
-	// Execute command.
-	err = cmds[cmdName].Run(ctx, *config)
+	// Execute command (subcommands are handled by their root command).
+	err = cmds[strings.Fields(cmdName)[0]].Run(ctx, *config)
 	if err != nil {
 		return fmt.Errorf("%q command failed: %w", cmdName, err)
 	}
@@ -23,10 +23,13 @@ func customValues() msi {
 		},
 
 		"sloth": msi{
-			"resyncInterval": "17m",
-			"workers":        99,
-			"labelSelector":  `x=y,z!=y`,
-			"namespace":      "somens",
+			"resyncInterval":         "17m",
+			"workers":                99,
+			"labelSelector":          `x=y,z!=y`,
+			"namespace":              "somens",
+			"namespaceLabelSelector": "sloth=enabled",
+			"autoSLOs":               true,
+			"prometheusAddress":      "http://prometheus:9090",
 			"extraLabels": msi{
 				"k1": "v1",
 				"k2": "v2",
@@ -3,8 +3,13 @@ package generate
 import (
 	"cmp"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
+	"time"
+
+	"golang.org/x/sync/errgroup"
 
 	"github.com/slok/sloth/internal/alert"
 	"github.com/slok/sloth/internal/log"
@@ -22,7 +27,8 @@ import (
 
 // Default plugins.
 var (
-	NoopPlugin, _ = NewSLOProcessorFromSLOPluginV1(plugincorenoopv1.NewPlugin, log.Noop, nil)
+	NoopPlugin    = NewIDSLOProcessor(plugincorenoopv1.PluginID, noopPlugin)
+	noopPlugin, _ = NewSLOProcessorFromSLOPluginV1(plugincorenoopv1.NewPlugin, log.Noop, nil)
 )
 
 type noopSLOPluginGetter bool
@@ -41,7 +47,12 @@ type ServiceConfig struct {
 	DefaultPlugins  []SLOProcessor
 	ExtraPlugins    []model.PromSLOPluginMetadata
 	SLOPluginGetter SLOPluginGetter
-	Logger          log.Logger
+	// Workers is the number of SLOs of the same group that will be generated concurrently (by default 1).
+	Workers int
+	// TracePlugins will trace the SLO plugin chain execution of each SLO (executed plugins, durations and
+	// the changes each plugin made on the SLO rules and the SLO).
+	TracePlugins bool
+	Logger       log.Logger
 }
 
 func (c *ServiceConfig) defaults() error {
@@ -49,6 +60,14 @@ func (c *ServiceConfig) defaults() error {
 		return fmt.Errorf("alert generator is required")
 	}
 
+	if c.Workers < 0 {
+		return fmt.Errorf("workers can't be negative")
+	}
+
+	if c.Workers == 0 {
+		c.Workers = 1
+	}
+
 	if c.SLOPluginGetter == nil {
 		c.SLOPluginGetter = noopSLOPluginGetter(false)
 	}
@@ -97,10 +116,10 @@ func (c *ServiceConfig) defaults() error {
 		}
 
 		c.DefaultPlugins = []SLOProcessor{
-			pluginValidate,
-			pluginSLI,
-			pluginMeta,
-			pluginAlert,
+			NewIDSLOProcessor(plugincorevalidatev1.PluginID, pluginValidate),
+			NewIDSLOProcessor(plugincoreslirulesv1.PluginID, pluginSLI),
+			NewIDSLOProcessor(plugincoremetadatarulesv1.PluginID, pluginMeta),
+			NewIDSLOProcessor(plugincorealertrulesv1.PluginID, pluginAlert),
 		}
 	}
 
@@ -118,6 +137,8 @@ type Service struct {
 	sloPluginGetter SLOPluginGetter
 	defaultPlugins  []SLOProcessor
 	extraPlugins    []model.PromSLOPluginMetadata
+	workers         int
+	tracePlugins    bool
 	logger          log.Logger
 }
 
@@ -133,6 +154,8 @@ func NewService(config ServiceConfig) (*Service, error) {
 		sloPluginGetter: config.SLOPluginGetter,
 		defaultPlugins:  config.DefaultPlugins,
 		extraPlugins:    config.ExtraPlugins,
+		workers:         config.Workers,
+		tracePlugins:    config.TracePlugins,
 		logger:          config.Logger,
 	}, nil
 }
@@ -149,10 +172,14 @@ type Request struct {
 type SLOResult struct {
 	SLO      model.PromSLO
 	SLORules model.PromSLORules
+	// PluginTrace is the SLO plugin chain execution trace, only set when plugin tracing is enabled.
+	PluginTrace *model.PromSLOPluginTrace
 }
 
 type Response struct {
 	PrometheusSLOs []SLOResult
+	// ExtraRules are the SLO group level rule groups generated by the SLO group plugins.
+	ExtraRules []model.PromRuleGroup
 }
 
 func (s Service) Generate(ctx context.Context, r Request) (*Response, error) {
@@ -161,30 +188,168 @@ func (s Service) Generate(ctx context.Context, r Request) (*Response, error) {
 		return nil, fmt.Errorf("invalid SLO group: %w", err)
 	}
 
-	// Generate Prom rules.
-	results := make([]SLOResult, 0, len(r.SLOGroup.SLOs))
+	// Get the SLO group plugins once, checking they can be used before running anything.
+	plugins, declarations, err := s.getSLOGroupPlugins(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pre process the SLO group with the SLO group plugins.
+	groupProcessors, err := s.newSLOGroupProcessors(ctx, plugins, declarations)
+	if err != nil {
+		return nil, err
+	}
+	groupReq := &SLOGroupProcessorRequest{Info: r.Info, SLOGroup: r.SLOGroup}
+	groupReq.SLOGroup.SLOs = make([]model.PromSLO, 0, len(r.SLOGroup.SLOs))
 	for _, slo := range r.SLOGroup.SLOs {
-		// Add extra labels.
 		slo.Labels = utilsdata.MergeLabels(slo.Labels, r.ExtraLabels)
-
-		// Generate SLO result.
-		result, err := s.generateSLO(ctx, r.Info, r.SLOGroup, slo)
+		groupReq.SLOGroup.SLOs = append(groupReq.SLOGroup.SLOs, slo)
+	}
+	groupRes := &SLOGroupProcessorResult{}
+	for _, p := range groupProcessors {
+		err := p.processor.PreProcessSLOGroup(ctx, groupReq, groupRes)
 		if err != nil {
-			return nil, fmt.Errorf("could not generate %q slo: %w", slo.ID, err)
+			return nil, fmt.Errorf("slo group pre processor %q failed: %w", p.id, err)
 		}
+	}
+
+	// Generate Prom rules concurrently, each SLO result is stored in the same index
+	// as the SLO, so the result order is deterministic regardless of the concurrency.
+	// The first error will cancel the rest of the SLO generations.
+	results := make([]SLOResult, len(r.SLOGroup.SLOs))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.workers)
+	for i, slo := range r.SLOGroup.SLOs {
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
 
-		// Set safe defaults on rules result.
-		setDefaultsPromSLORulesResult(slo, result)
+			// Add extra labels.
+			slo.Labels = utilsdata.MergeLabels(slo.Labels, r.ExtraLabels)
 
-		results = append(results, SLOResult{SLO: slo, SLORules: *result})
+			// Generate SLO result.
+			result, trace, err := s.generateSLO(gctx, r.Info, r.SLOGroup, slo, plugins)
+			if err != nil {
+				sloErr := &SLOError{SLOID: slo.ID, Err: err}
+				var perr sloProcessorError
+				if errors.As(err, &perr) {
+					sloErr.PluginID = perr.pluginID
+					sloErr.Err = perr.err
+				}
+				return sloErr
+			}
+
+			// Set safe defaults on rules result.
+			SetDefaultsPromSLORulesResult(slo, result)
+
+			results[i] = SLOResult{SLO: slo, SLORules: *result, PluginTrace: trace}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Post process the SLO group with the SLO group plugins.
+	groupReq.SLOResults = results
+	for _, p := range groupProcessors {
+		err := p.processor.PostProcessSLOGroup(ctx, groupReq, groupRes)
+		if err != nil {
+			return nil, fmt.Errorf("slo group post processor %q failed: %w", p.id, err)
+		}
+	}
+
+	// Group rule groups don't belong to an SLO, so we can't generate a default name.
+	for i, rg := range groupRes.ExtraRules {
+		if rg.Name == "" {
+			return nil, fmt.Errorf("SLO group extra rule group %d name is required", i)
+		}
 	}
 
 	return &Response{
 		PrometheusSLOs: results,
+		ExtraRules:     groupRes.ExtraRules,
 	}, nil
 }
 
-func (s Service) generateSLO(ctx context.Context, info model.Info, sloGroup model.PromSLOGroup, slo model.PromSLO) (*model.PromSLORules, error) {
+// getSLOGroupPlugins returns the SLO plugins declared on the SLO group by ID and the distinct plugin
+// declarations (ID and config) sorted by priority. The plugins are checked so they can be used.
+func (s Service) getSLOGroupPlugins(ctx context.Context, r Request) (map[string]*pluginengineslo.Plugin, []model.PromSLOPluginMetadata, error) {
+	plugins := map[string]*pluginengineslo.Plugin{}
+	declarations := []model.PromSLOPluginMetadata{}
+	seen := map[string]struct{}{}
+	for _, slo := range r.SLOGroup.SLOs {
+		sloPluginMetadata := []model.PromSLOPluginMetadata{}
+		if !slo.Plugins.OverridePlugins {
+			sloPluginMetadata = append(sloPluginMetadata, s.extraPlugins...)
+		}
+		sloPluginMetadata = append(sloPluginMetadata, slo.Plugins.Plugins...)
+
+		for _, p := range sloPluginMetadata {
+			configData, err := json.Marshal(p.Config)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not marshal SLO plugin %q config: %w", p.ID, err)
+			}
+			key := p.ID + "\x00" + string(configData)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			declarations = append(declarations, p)
+
+			pf, ok := plugins[p.ID]
+			if !ok {
+				pf, err = s.sloPluginGetter.GetSLOPlugin(ctx, p.ID)
+				if err != nil {
+					return nil, nil, fmt.Errorf("could not get SLO plugin %q: %w", p.ID, err)
+				}
+				if !pf.Metadata.SupportsMode(r.Info.Mode) {
+					return nil, nil, fmt.Errorf("SLO plugin %q doesn't support %q mode", p.ID, r.Info.Mode)
+				}
+				plugins[p.ID] = pf
+			}
+
+			err = pf.Metadata.ValidateConfig(configData)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid SLO plugin %q config: %w", p.ID, err)
+			}
+		}
+	}
+	slices.SortStableFunc(declarations, func(a, b model.PromSLOPluginMetadata) int {
+		return cmp.Compare(a.Priority, b.Priority)
+	})
+
+	return plugins, declarations, nil
+}
+
+// newSLOGroupProcessors returns the SLO group processors of the SLO group plugins, one per
+// plugin declaration. Only SLO plugins v2 have SLO group processing.
+func (s Service) newSLOGroupProcessors(ctx context.Context, plugins map[string]*pluginengineslo.Plugin, declarations []model.PromSLOPluginMetadata) ([]chainSLOGroupProcessor, error) {
+	processors := []chainSLOGroupProcessor{}
+	for _, p := range declarations {
+		pf := plugins[p.ID]
+		if pf.PluginV2Factory == nil {
+			continue
+		}
+
+		processor, err := NewSLOGroupProcessorFromSLOPluginV2(pf.PluginV2Factory, s.logger.WithCtxValues(ctx).WithValues(log.Kv{"plugin": pf.ID}), p.Config)
+		if err != nil {
+			return nil, fmt.Errorf("could create SLO plugin %q: %w", p.ID, err)
+		}
+		processors = append(processors, chainSLOGroupProcessor{id: p.ID, processor: processor})
+	}
+
+	return processors, nil
+}
+
+// chainSLOGroupProcessor is an SLO group processor of the SLO plugin chain.
+type chainSLOGroupProcessor struct {
+	id        string
+	processor SLOGroupProcessor
+}
+
+func (s Service) generateSLO(ctx context.Context, info model.Info, sloGroup model.PromSLOGroup, slo model.PromSLO, plugins map[string]*pluginengineslo.Plugin) (*model.PromSLORules, *model.PromSLOPluginTrace, error) {
 	logger := s.logger.WithCtxValues(ctx).WithValues(log.Kv{"slo": slo.ID})
 
 	// Generate the MWMB alerts.
@@ -195,15 +360,15 @@ func (s Service) generateSLO(ctx context.Context, info model.Info, sloGroup mode
 	}
 	as, err := s.alertGen.GenerateMWMBAlerts(ctx, alertSLO)
 	if err != nil {
-		return nil, fmt.Errorf("could not generate SLO alerts: %w", err)
+		return nil, nil, fmt.Errorf("could not generate SLO alerts: %w", err)
 	}
 	logger.Debugf("Multiwindow-multiburn alerts generated")
 
 	// Get SLO plugins based on the priority, default plugins are `0` priority
 	// so, we split the plugins in two slices, pre default (<0) and post default (>=0).
 	// That way we create the final processor list: pre-default + default + post-default.
-	preDefault := []SLOProcessor{}
-	postDefault := []SLOProcessor{}
+	preDefault := []chainSLOProcessor{}
+	postDefault := []chainSLOProcessor{}
 	sloPluginMetadata := []model.PromSLOPluginMetadata{}
 	if !slo.Plugins.OverridePlugins {
 		sloPluginMetadata = append(sloPluginMetadata, s.extraPlugins...) // App level SLO plugins.
@@ -214,23 +379,27 @@ func (s Service) generateSLO(ctx context.Context, info model.Info, sloGroup mode
 	})
 
 	for _, p := range sloPluginMetadata {
-		pf, err := s.sloPluginGetter.GetSLOPlugin(ctx, p.ID)
-		if err != nil {
-			return nil, fmt.Errorf("could not get SLO plugin %q: %w", p.ID, err)
-		}
+		pf := plugins[p.ID]
 		var processor SLOProcessor
+		var err error
 		switch {
 		case pf.PluginV1Factory != nil:
 			processor, err = NewSLOProcessorFromSLOPluginV1(pf.PluginV1Factory, logger.WithValues(log.Kv{"plugin": pf.ID}), p.Config)
 			if err != nil {
-				return nil, fmt.Errorf("could create SLO plugin %q: %w", p.ID, err)
+				return nil, nil, fmt.Errorf("could create SLO plugin %q: %w", p.ID, err)
+			}
+		case pf.PluginV2Factory != nil:
+			processor, err = NewSLOProcessorFromSLOPluginV2(pf.PluginV2Factory, logger.WithValues(log.Kv{"plugin": pf.ID}), p.Config)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could create SLO plugin %q: %w", p.ID, err)
 			}
 		}
 
+		cp := chainSLOProcessor{id: p.ID, priority: p.Priority, processor: processor}
 		if p.Priority < 0 {
-			preDefault = append(preDefault, processor)
+			preDefault = append(preDefault, cp)
 		} else {
-			postDefault = append(postDefault, processor)
+			postDefault = append(postDefault, cp)
 		}
 	}
 
@@ -238,7 +407,9 @@ func (s Service) generateSLO(ctx context.Context, info model.Info, sloGroup mode
 	sloProcessors := preDefault
 	// Add default plugins if we don't want to override the plugins.
 	if !slo.Plugins.OverridePlugins {
-		sloProcessors = append(sloProcessors, s.defaultPlugins...)
+		for _, p := range s.defaultPlugins {
+			sloProcessors = append(sloProcessors, chainSLOProcessor{id: sloProcessorID(p), processor: p})
+		}
 	}
 	sloProcessors = append(sloProcessors, postDefault...)
 
@@ -249,14 +420,79 @@ func (s Service) generateSLO(ctx context.Context, info model.Info, sloGroup mode
 		SLOGroup:       sloGroup,
 	}
 	res := &SLOProcessorResult{}
+	if !s.tracePlugins {
+		for _, p := range sloProcessors {
+			err := p.processor.ProcessSLO(ctx, req, res)
+			if err != nil {
+				return nil, nil, sloProcessorError{pluginID: p.id, err: err}
+			}
+		}
+
+		return &res.SLORules, nil, nil
+	}
+
+	// Trace the chain, taking a snapshot after each plugin to know what changed.
+	trace := &model.PromSLOPluginTrace{}
+	snapshot := newSLOTraceSnapshot(req.SLO, res.SLORules)
 	for _, p := range sloProcessors {
-		err := p.ProcessSLO(ctx, req, res)
+		start := time.Now()
+		err := p.processor.ProcessSLO(ctx, req, res)
+		duration := time.Since(start)
 		if err != nil {
-			return nil, fmt.Errorf("slo processor failed: %w", err)
+			return nil, nil, sloProcessorError{pluginID: p.id, err: err}
 		}
+
+		newSnapshot := newSLOTraceSnapshot(req.SLO, res.SLORules)
+		ruleChanges, sloChanges := diffSLOTraceSnapshots(snapshot, newSnapshot)
+		snapshot = newSnapshot
+
+		trace.Steps = append(trace.Steps, model.PromSLOPluginTraceStep{
+			PluginID:    p.id,
+			Priority:    p.priority,
+			Duration:    duration,
+			RuleChanges: ruleChanges,
+			SLOChanges:  sloChanges,
+		})
 	}
 
-	return &res.SLORules, nil
+	return &res.SLORules, trace, nil
+}
+
+// SLOError is the error returned when the generation of an SLO fails.
+type SLOError struct {
+	// SLOID is the ID of the SLO that failed.
+	SLOID string
+	// PluginID is the ID of the SLO plugin that failed, empty if the
+	// failure was not on the SLO plugin chain.
+	PluginID string
+	// Err is the error of the SLO plugin or the generation.
+	Err error
+}
+
+func (e *SLOError) Error() string {
+	if e.PluginID != "" {
+		return fmt.Sprintf("could not generate %q slo: %s", e.SLOID, sloProcessorError{pluginID: e.PluginID, err: e.Err})
+	}
+	return fmt.Sprintf("could not generate %q slo: %s", e.SLOID, e.Err)
+}
+
+func (e *SLOError) Unwrap() error { return e.Err }
+
+// sloProcessorError is the error of an SLO processor of the SLO plugin chain.
+type sloProcessorError struct {
+	pluginID string
+	err      error
+}
+
+func (e sloProcessorError) Error() string { return fmt.Sprintf("slo processor failed: %s", e.err) }
+
+func (e sloProcessorError) Unwrap() error { return e.err }
+
+// chainSLOProcessor is an SLO processor of the SLO plugin chain.
+type chainSLOProcessor struct {
+	id        string
+	priority  int
+	processor SLOProcessor
 }
 
 func (s Service) validateSLOGroup(sloGroup model.PromSLOGroup) error {
@@ -277,8 +513,8 @@ func (s Service) validateSLOGroup(sloGroup model.PromSLOGroup) error {
 	return nil
 }
 
-// helper function to set the required safe defaults generated SLO prom rules.
-func setDefaultsPromSLORulesResult(slo model.PromSLO, rules *model.PromSLORules) {
+// SetDefaultsPromSLORulesResult sets the defaults of the SLO rules generated by the SLO plugin chain (e.g: rule group names).
+func SetDefaultsPromSLORulesResult(slo model.PromSLO, rules *model.PromSLORules) {
 	// Set rule result naming defaults.
 	if rules.SLIErrorRecRules.Name == "" {
 		rules.SLIErrorRecRules.Name = conventions.PromRuleGroupNameSLOSLIPrefix + slo.ID
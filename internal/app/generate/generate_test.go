@@ -3,6 +3,7 @@ package generate_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -14,9 +15,11 @@ import (
 	"github.com/slok/sloth/internal/alert"
 	"github.com/slok/sloth/internal/app/generate"
 	"github.com/slok/sloth/internal/app/generate/generatemock"
+	"github.com/slok/sloth/internal/pluginengine"
 	pluginengineslo "github.com/slok/sloth/internal/pluginengine/slo"
 	"github.com/slok/sloth/pkg/common/model"
 	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+	pluginslov2 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v2"
 )
 
 type testPluginAlertInterval struct {
@@ -930,6 +933,84 @@ or
 				},
 			},
 		},
+
+		"Having SLO plugins with a config that doesn't match the plugin config schema should error.": {
+			mocks: func(mspg *generatemock.SLOPluginGetter) {
+				mspg.On("GetSLOPlugin", mock.Anything, "test-plugin1").Once().Return(&pluginengineslo.Plugin{
+					ID:       "test-plugin1",
+					Metadata: pluginengine.Metadata{ConfigSchema: `{"type": "object", "required": ["name"]}`},
+					PluginV1Factory: func(config json.RawMessage, appUtils pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+						return testPluginAlertRuleAppender{rule: rulefmt.Rule{Expr: "test1"}}, nil
+					},
+				}, nil)
+			},
+			req: generate.Request{
+				Info: model.Info{Mode: model.ModeCLIGenPrometheus},
+				SLOGroup: model.PromSLOGroup{SLOs: []model.PromSLO{
+					{
+						ID:      "test-id",
+						Name:    "test-name",
+						Service: "test-svc",
+						SLI: model.PromSLI{
+							Events: &model.PromSLIEvents{
+								ErrorQuery: `rate(my_metric{error="true"}[{{.window}}])`,
+								TotalQuery: `rate(my_metric[{{.window}}])`,
+							},
+						},
+						TimeWindow:      30 * 24 * time.Hour,
+						Objective:       99.9,
+						PageAlertMeta:   model.PromAlertMeta{Disable: true},
+						TicketAlertMeta: model.PromAlertMeta{Disable: true},
+						Plugins: model.SLOPlugins{
+							OverridePlugins: true,
+							Plugins: []model.PromSLOPluginMetadata{
+								{ID: "test-plugin1", Config: json.RawMessage(`{"other": "test"}`)},
+							},
+						},
+					},
+				}},
+			},
+			expErr: true,
+		},
+
+		"Having SLO plugins that don't support the generation mode should error.": {
+			mocks: func(mspg *generatemock.SLOPluginGetter) {
+				mspg.On("GetSLOPlugin", mock.Anything, "test-plugin1").Once().Return(&pluginengineslo.Plugin{
+					ID:       "test-plugin1",
+					Metadata: pluginengine.Metadata{Modes: []model.Mode{model.ModeCLIGenKubernetes}},
+					PluginV1Factory: func(config json.RawMessage, appUtils pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+						return testPluginAlertRuleAppender{rule: rulefmt.Rule{Expr: "test1"}}, nil
+					},
+				}, nil)
+			},
+			req: generate.Request{
+				Info: model.Info{Mode: model.ModeCLIGenPrometheus},
+				SLOGroup: model.PromSLOGroup{SLOs: []model.PromSLO{
+					{
+						ID:      "test-id",
+						Name:    "test-name",
+						Service: "test-svc",
+						SLI: model.PromSLI{
+							Events: &model.PromSLIEvents{
+								ErrorQuery: `rate(my_metric{error="true"}[{{.window}}])`,
+								TotalQuery: `rate(my_metric[{{.window}}])`,
+							},
+						},
+						TimeWindow:      30 * 24 * time.Hour,
+						Objective:       99.9,
+						PageAlertMeta:   model.PromAlertMeta{Disable: true},
+						TicketAlertMeta: model.PromAlertMeta{Disable: true},
+						Plugins: model.SLOPlugins{
+							OverridePlugins: true,
+							Plugins: []model.PromSLOPluginMetadata{
+								{ID: "test-plugin1", Config: nil},
+							},
+						},
+					},
+				}},
+			},
+			expErr: true,
+		},
 	}
 
 	for name, test := range tests {
@@ -960,3 +1041,520 @@ or
 		})
 	}
 }
+
+func TestAppServiceGenerateConcurrency(t *testing.T) {
+	newSLOs := func(n int) []model.PromSLO {
+		slos := []model.PromSLO{}
+		for i := range n {
+			slos = append(slos, model.PromSLO{
+				ID:         fmt.Sprintf("test-id-%03d", i),
+				Name:       fmt.Sprintf("test-name-%03d", i),
+				Service:    "test-svc",
+				TimeWindow: 30 * 24 * time.Hour,
+				Objective:  99.9,
+			})
+		}
+		return slos
+	}
+
+	// The Yaegi plugins are interpreted, so they share the interpreter state between all the concurrent SLO generations.
+	yaegiPluginSrc := `package test
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+const (
+	PluginVersion = "prometheus/slo/v1"
+	PluginID      = "test.sloth.dev/yaegi/v1"
+)
+
+func NewPlugin(_ json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+	return plugin{}, nil
+}
+
+type plugin struct{}
+
+func (plugin) ProcessSLO(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) error {
+	result.SLORules.SLIErrorRecRules.Rules = []rulefmt.Rule{{Record: request.SLO.ID}}
+	return nil
+}
+`
+	newYaegiSLOs := func(n int) []model.PromSLO {
+		slos := newSLOs(n)
+		for i := range slos {
+			slos[i].Plugins.Plugins = []model.PromSLOPluginMetadata{{ID: "test.sloth.dev/yaegi/v1"}}
+		}
+		return slos
+	}
+
+	tests := map[string]struct {
+		workers        int
+		slos           []model.PromSLO
+		processor      generate.SLOProcessorFunc
+		yaegiPluginSrc string
+		expErr         bool
+	}{
+		"Generating SLOs concurrently should return the results in the same order as the SLOs.": {
+			workers: 8,
+			slos:    newSLOs(100),
+			processor: func(ctx context.Context, req *generate.SLOProcessorRequest, res *generate.SLOProcessorResult) error {
+				res.SLORules.SLIErrorRecRules.Rules = []rulefmt.Rule{{Record: req.SLO.ID}}
+				return nil
+			},
+		},
+
+		"Generating SLOs concurrently with an SLO failing should fail.": {
+			workers: 8,
+			slos:    newSLOs(100),
+			processor: func(ctx context.Context, req *generate.SLOProcessorRequest, res *generate.SLOProcessorResult) error {
+				if req.SLO.ID == "test-id-042" {
+					return fmt.Errorf("something")
+				}
+				res.SLORules.SLIErrorRecRules.Rules = []rulefmt.Rule{{Record: req.SLO.ID}}
+				return nil
+			},
+			expErr: true,
+		},
+
+		"Generating SLOs concurrently with a Yaegi plugin should return the results in the same order as the SLOs.": {
+			workers: 8,
+			slos:    newYaegiSLOs(100),
+			processor: func(ctx context.Context, req *generate.SLOProcessorRequest, res *generate.SLOProcessorResult) error {
+				return nil
+			},
+			yaegiPluginSrc: yaegiPluginSrc,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			windowsRepo, err := alert.NewFSWindowsRepo(alert.FSWindowsRepoConfig{})
+			require.NoError(err)
+
+			mspg := generatemock.NewSLOPluginGetter(t)
+			if test.yaegiPluginSrc != "" {
+				plugin, err := pluginengineslo.PluginLoader.LoadRawPlugin(t.Context(), test.yaegiPluginSrc)
+				require.NoError(err)
+				mspg.On("GetSLOPlugin", mock.Anything, plugin.ID).Once().Return(plugin, nil)
+			}
+
+			svc, err := generate.NewService(generate.ServiceConfig{
+				AlertGenerator:  alert.NewGenerator(windowsRepo),
+				DefaultPlugins:  []generate.SLOProcessor{test.processor},
+				SLOPluginGetter: mspg,
+				Workers:         test.workers,
+			})
+			require.NoError(err)
+
+			gotResp, err := svc.Generate(context.TODO(), generate.Request{SLOGroup: model.PromSLOGroup{SLOs: test.slos}})
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				require.Len(gotResp.PrometheusSLOs, len(test.slos))
+				for i, slo := range test.slos {
+					assert.Equal(slo.ID, gotResp.PrometheusSLOs[i].SLO.ID)
+					assert.Equal(slo.ID, gotResp.PrometheusSLOs[i].SLORules.SLIErrorRecRules.Rules[0].Record)
+				}
+			}
+		})
+	}
+}
+
+func TestAppServiceGeneratePluginTrace(t *testing.T) {
+	tests := map[string]struct {
+		tracePlugins   bool
+		defaultPlugins []generate.SLOProcessor
+		mocks          func(mspg *generatemock.SLOPluginGetter)
+		slo            model.PromSLO
+		expTrace       *model.PromSLOPluginTrace
+		expErr         bool
+	}{
+		"Without plugin tracing the trace should not be returned.": {
+			tracePlugins: false,
+			defaultPlugins: []generate.SLOProcessor{
+				generate.NewIDSLOProcessor("test-default", generate.SLOProcessorFunc(func(ctx context.Context, req *generate.SLOProcessorRequest, res *generate.SLOProcessorResult) error {
+					return nil
+				})),
+			},
+			mocks:    func(mspg *generatemock.SLOPluginGetter) {},
+			slo:      model.PromSLO{ID: "test-id", TimeWindow: 30 * 24 * time.Hour, Objective: 99.9},
+			expTrace: nil,
+		},
+
+		"With plugin tracing the executed plugins and their changes should be traced in execution order.": {
+			tracePlugins: true,
+			defaultPlugins: []generate.SLOProcessor{
+				generate.NewIDSLOProcessor("test-default", generate.SLOProcessorFunc(func(ctx context.Context, req *generate.SLOProcessorRequest, res *generate.SLOProcessorResult) error {
+					res.SLORules.AlertRules.Rules = []rulefmt.Rule{
+						{Alert: "test-alert", Expr: "up == 0", Labels: map[string]string{"severity": "page"}},
+						{Alert: "test-alert2", Expr: "up == 1"},
+					}
+					return nil
+				})),
+				generate.SLOProcessorFunc(func(ctx context.Context, req *generate.SLOProcessorRequest, res *generate.SLOProcessorResult) error {
+					return nil
+				}),
+			},
+			mocks: func(mspg *generatemock.SLOPluginGetter) {
+				mspg.On("GetSLOPlugin", mock.Anything, "test-plugin1").Once().Return(&pluginengineslo.Plugin{
+					ID: "test-plugin1",
+					PluginV1Factory: func(config json.RawMessage, appUtils pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+						return testPluginFunc(func(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) error {
+							request.SLO.Labels["team"] = "b"
+							result.SLORules.AlertRules.Interval = 2 * time.Minute
+							result.SLORules.AlertRules.Rules = []rulefmt.Rule{
+								{Alert: "test-alert", Expr: "up == 0", Labels: map[string]string{"severity": "ticket"}},
+							}
+							return nil
+						}), nil
+					},
+				}, nil)
+			},
+			slo: model.PromSLO{
+				ID:         "test-id",
+				TimeWindow: 30 * 24 * time.Hour,
+				Objective:  99.9,
+				Labels:     map[string]string{"team": "a"},
+				Plugins: model.SLOPlugins{Plugins: []model.PromSLOPluginMetadata{
+					{ID: "test-plugin1", Priority: 10},
+				}},
+			},
+			expTrace: &model.PromSLOPluginTrace{
+				Steps: []model.PromSLOPluginTraceStep{
+					{
+						PluginID: "test-default",
+						RuleChanges: []model.PromRuleChange{
+							{Group: "alerts", Rule: "test-alert", Type: model.ChangeTypeAdded, Fields: []model.FieldChange{
+								{Path: "Alert", Type: model.ChangeTypeAdded, New: "test-alert"},
+								{Path: "Expr", Type: model.ChangeTypeAdded, New: "up == 0"},
+								{Path: "Labels.severity", Type: model.ChangeTypeAdded, New: "page"},
+							}},
+							{Group: "alerts", Rule: "test-alert2", Type: model.ChangeTypeAdded, Fields: []model.FieldChange{
+								{Path: "Alert", Type: model.ChangeTypeAdded, New: "test-alert2"},
+								{Path: "Expr", Type: model.ChangeTypeAdded, New: "up == 1"},
+							}},
+						},
+					},
+					{
+						PluginID: "",
+					},
+					{
+						PluginID: "test-plugin1",
+						Priority: 10,
+						RuleChanges: []model.PromRuleChange{
+							{Group: "alerts", Type: model.ChangeTypeModified, Fields: []model.FieldChange{
+								{Path: "Interval", Type: model.ChangeTypeAdded, New: "2m0s"},
+							}},
+							{Group: "alerts", Rule: "test-alert2", Type: model.ChangeTypeRemoved, Fields: []model.FieldChange{
+								{Path: "Alert", Type: model.ChangeTypeRemoved, Old: "test-alert2"},
+								{Path: "Expr", Type: model.ChangeTypeRemoved, Old: "up == 1"},
+							}},
+							{Group: "alerts", Rule: "test-alert", Type: model.ChangeTypeModified, Fields: []model.FieldChange{
+								{Path: "Labels.severity", Type: model.ChangeTypeModified, Old: "page", New: "ticket"},
+							}},
+						},
+						SLOChanges: []model.FieldChange{
+							{Path: "Labels.team", Type: model.ChangeTypeModified, Old: "a", New: "b"},
+						},
+					},
+				},
+			},
+		},
+
+		"With plugin tracing a failing plugin should fail.": {
+			tracePlugins: true,
+			defaultPlugins: []generate.SLOProcessor{
+				generate.NewIDSLOProcessor("test-default", generate.SLOProcessorFunc(func(ctx context.Context, req *generate.SLOProcessorRequest, res *generate.SLOProcessorResult) error {
+					return fmt.Errorf("something")
+				})),
+			},
+			mocks:  func(mspg *generatemock.SLOPluginGetter) {},
+			slo:    model.PromSLO{ID: "test-id", TimeWindow: 30 * 24 * time.Hour, Objective: 99.9},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			mspg := generatemock.NewSLOPluginGetter(t)
+			test.mocks(mspg)
+
+			windowsRepo, err := alert.NewFSWindowsRepo(alert.FSWindowsRepoConfig{})
+			require.NoError(err)
+
+			svc, err := generate.NewService(generate.ServiceConfig{
+				AlertGenerator:  alert.NewGenerator(windowsRepo),
+				DefaultPlugins:  test.defaultPlugins,
+				SLOPluginGetter: mspg,
+				TracePlugins:    test.tracePlugins,
+			})
+			require.NoError(err)
+
+			gotResp, err := svc.Generate(context.TODO(), generate.Request{SLOGroup: model.PromSLOGroup{SLOs: []model.PromSLO{test.slo}}})
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				gotTrace := gotResp.PrometheusSLOs[0].PluginTrace
+				// Durations are not deterministic.
+				if gotTrace != nil {
+					for i := range gotTrace.Steps {
+						gotTrace.Steps[i].Duration = 0
+					}
+				}
+				assert.Equal(test.expTrace, gotTrace)
+			}
+		})
+	}
+}
+
+type testPluginFunc func(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) error
+
+func (p testPluginFunc) ProcessSLO(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) error {
+	return p(ctx, request, result)
+}
+
+func TestAppServiceGenerateSLOError(t *testing.T) {
+	tests := map[string]struct {
+		defaultPlugins []generate.SLOProcessor
+		slo            model.PromSLO
+		expPluginID    string
+		expSLOErrMsg   string
+		expErrMsg      string
+	}{
+		"An invalid SLO should fail on the default validation plugin.": {
+			slo: model.PromSLO{
+				ID:         "test-id",
+				Name:       "test-name",
+				Service:    "test-svc",
+				TimeWindow: 30 * 24 * time.Hour,
+				Objective:  101,
+				SLI:        model.PromSLI{Raw: &model.PromSLIRaw{ErrorRatioQuery: `rate(errors[{{.window}}])`}},
+			},
+			expPluginID:  "sloth.dev/core/validate/v1",
+			expSLOErrMsg: `invalid slo "test-id": `,
+			expErrMsg:    `could not generate "test-id" slo: slo processor failed: invalid slo "test-id": `,
+		},
+
+		"A failing SLO plugin should return the failed plugin.": {
+			defaultPlugins: []generate.SLOProcessor{
+				generate.NewIDSLOProcessor("test-default", generate.SLOProcessorFunc(func(ctx context.Context, req *generate.SLOProcessorRequest, res *generate.SLOProcessorResult) error {
+					return fmt.Errorf("something")
+				})),
+			},
+			slo:          model.PromSLO{ID: "test-id", TimeWindow: 30 * 24 * time.Hour, Objective: 99.9},
+			expPluginID:  "test-default",
+			expSLOErrMsg: "something",
+			expErrMsg:    `could not generate "test-id" slo: slo processor failed: something`,
+		},
+
+		"A failure outside the SLO plugin chain should not return a plugin.": {
+			defaultPlugins: []generate.SLOProcessor{},
+			slo:            model.PromSLO{ID: "test-id", TimeWindow: 42 * time.Hour, Objective: 99.9},
+			expPluginID:    "",
+			expSLOErrMsg:   "could not generate SLO alerts: ",
+			expErrMsg:      `could not generate "test-id" slo: could not generate SLO alerts: `,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			windowsRepo, err := alert.NewFSWindowsRepo(alert.FSWindowsRepoConfig{})
+			require.NoError(err)
+
+			svc, err := generate.NewService(generate.ServiceConfig{
+				AlertGenerator: alert.NewGenerator(windowsRepo),
+				DefaultPlugins: test.defaultPlugins,
+			})
+			require.NoError(err)
+
+			_, err = svc.Generate(context.TODO(), generate.Request{SLOGroup: model.PromSLOGroup{SLOs: []model.PromSLO{test.slo}}})
+
+			var sloErr *generate.SLOError
+			require.ErrorAs(err, &sloErr)
+			assert.Equal(test.slo.ID, sloErr.SLOID)
+			assert.Equal(test.expPluginID, sloErr.PluginID)
+			assert.Contains(sloErr.Err.Error(), test.expSLOErrMsg)
+			assert.Contains(err.Error(), test.expErrMsg)
+		})
+	}
+}
+
+func TestAppServiceGenerateSLOGroupPlugins(t *testing.T) {
+	newSLO := func(id string, plugins ...model.PromSLOPluginMetadata) model.PromSLO {
+		return model.PromSLO{
+			ID:         id,
+			Service:    "test-svc",
+			TimeWindow: 30 * 24 * time.Hour,
+			Objective:  99.9,
+			Plugins:    model.SLOPlugins{Plugins: plugins},
+		}
+	}
+
+	tests := map[string]struct {
+		plugin   func(calls *[]string) *testPluginV2
+		slos     []model.PromSLO
+		expCalls []string
+		expRules []model.PromRuleGroup
+		expErr   bool
+	}{
+		"A v2 plugin should process the SLO group once before and after the SLOs.": {
+			plugin: func(calls *[]string) *testPluginV2 {
+				return &testPluginV2{
+					pre: func(req *pluginslov2.GroupRequest, res *pluginslov2.GroupResult) error {
+						*calls = append(*calls, fmt.Sprintf("pre:%d", len(req.SLOs)))
+						return nil
+					},
+					process: func(req *pluginslov2.Request, res *pluginslov2.Result) error {
+						*calls = append(*calls, "slo:"+req.SLO.ID)
+						return nil
+					},
+					post: func(req *pluginslov2.GroupRequest, res *pluginslov2.GroupResult) error {
+						*calls = append(*calls, fmt.Sprintf("post:%d", len(req.SLOResults)))
+						for _, r := range req.SLOResults {
+							res.ExtraRules = append(res.ExtraRules, model.PromRuleGroup{
+								Name:  "test-group-" + r.SLO.ID,
+								Rules: []rulefmt.Rule{{Record: "test:record", Expr: "vector(1)"}},
+							})
+						}
+						return nil
+					},
+				}
+			},
+			slos: []model.PromSLO{
+				newSLO("test-id1", model.PromSLOPluginMetadata{ID: "test-plugin1"}),
+				newSLO("test-id2", model.PromSLOPluginMetadata{ID: "test-plugin1"}),
+			},
+			expCalls: []string{"pre:2", "slo:test-id1", "slo:test-id2", "post:2"},
+			expRules: []model.PromRuleGroup{
+				{Name: "test-group-test-id1", Rules: []rulefmt.Rule{{Record: "test:record", Expr: "vector(1)"}}},
+				{Name: "test-group-test-id2", Rules: []rulefmt.Rule{{Record: "test:record", Expr: "vector(1)"}}},
+			},
+		},
+
+		"A v2 plugin declared with different configs should process the SLO group once per config.": {
+			plugin: func(calls *[]string) *testPluginV2 {
+				return &testPluginV2{
+					pre: func(req *pluginslov2.GroupRequest, res *pluginslov2.GroupResult) error {
+						*calls = append(*calls, "pre")
+						return nil
+					},
+				}
+			},
+			slos: []model.PromSLO{
+				newSLO("test-id1", model.PromSLOPluginMetadata{ID: "test-plugin1", Config: json.RawMessage(`{"a":1}`)}),
+				newSLO("test-id2", model.PromSLOPluginMetadata{ID: "test-plugin1", Config: json.RawMessage(`{"a":2}`)}),
+				newSLO("test-id3", model.PromSLOPluginMetadata{ID: "test-plugin1", Config: json.RawMessage(`{"a":1}`)}),
+			},
+			expCalls: []string{"pre", "pre"},
+		},
+
+		"A v2 plugin SLO group extra rule group without name should fail.": {
+			plugin: func(calls *[]string) *testPluginV2 {
+				return &testPluginV2{
+					post: func(req *pluginslov2.GroupRequest, res *pluginslov2.GroupResult) error {
+						res.ExtraRules = append(res.ExtraRules, model.PromRuleGroup{Rules: []rulefmt.Rule{{Record: "test:record", Expr: "vector(1)"}}})
+						return nil
+					},
+				}
+			},
+			slos:   []model.PromSLO{newSLO("test-id1", model.PromSLOPluginMetadata{ID: "test-plugin1"})},
+			expErr: true,
+		},
+
+		"A v2 plugin failing on the SLO group pre processing should fail without processing the SLOs.": {
+			plugin: func(calls *[]string) *testPluginV2 {
+				return &testPluginV2{
+					pre: func(req *pluginslov2.GroupRequest, res *pluginslov2.GroupResult) error {
+						return fmt.Errorf("something")
+					},
+					process: func(req *pluginslov2.Request, res *pluginslov2.Result) error {
+						*calls = append(*calls, "slo:"+req.SLO.ID)
+						return nil
+					},
+				}
+			},
+			slos:   []model.PromSLO{newSLO("test-id1", model.PromSLOPluginMetadata{ID: "test-plugin1"})},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			calls := []string{}
+			mspg := generatemock.NewSLOPluginGetter(t)
+			mspg.On("GetSLOPlugin", mock.Anything, "test-plugin1").Once().Return(&pluginengineslo.Plugin{
+				ID: "test-plugin1",
+				PluginV2Factory: func(config json.RawMessage, appUtils pluginslov2.AppUtils) (pluginslov2.Plugin, error) {
+					return test.plugin(&calls), nil
+				},
+			}, nil)
+
+			windowsRepo, err := alert.NewFSWindowsRepo(alert.FSWindowsRepoConfig{})
+			require.NoError(err)
+
+			svc, err := generate.NewService(generate.ServiceConfig{
+				AlertGenerator:  alert.NewGenerator(windowsRepo),
+				DefaultPlugins:  []generate.SLOProcessor{generate.NoopPlugin},
+				SLOPluginGetter: mspg,
+			})
+			require.NoError(err)
+
+			gotResp, err := svc.Generate(context.TODO(), generate.Request{SLOGroup: model.PromSLOGroup{SLOs: test.slos}})
+
+			if test.expErr {
+				assert.Error(err)
+				assert.Empty(calls)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expCalls, calls)
+				assert.Equal(test.expRules, gotResp.ExtraRules)
+			}
+		})
+	}
+}
+
+// testPluginV2 is a test SLO plugin v2, not set phases are no-op.
+type testPluginV2 struct {
+	pre     func(req *pluginslov2.GroupRequest, res *pluginslov2.GroupResult) error
+	process func(req *pluginslov2.Request, res *pluginslov2.Result) error
+	post    func(req *pluginslov2.GroupRequest, res *pluginslov2.GroupResult) error
+}
+
+func (p *testPluginV2) PreProcessSLOGroup(ctx context.Context, request *pluginslov2.GroupRequest, result *pluginslov2.GroupResult) error {
+	if p.pre == nil {
+		return nil
+	}
+	return p.pre(request, result)
+}
+
+func (p *testPluginV2) ProcessSLO(ctx context.Context, request *pluginslov2.Request, result *pluginslov2.Result) error {
+	if p.process == nil {
+		return nil
+	}
+	return p.process(request, result)
+}
+
+func (p *testPluginV2) PostProcessSLOGroup(ctx context.Context, request *pluginslov2.GroupRequest, result *pluginslov2.GroupResult) error {
+	if p.post == nil {
+		return nil
+	}
+	return p.post(request, result)
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/slok/sloth/internal/log"
 	"github.com/slok/sloth/pkg/common/model"
 	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+	pluginslov2 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v2"
 )
 
 type SLOProcessorRequest struct {
@@ -34,6 +36,27 @@ func (s SLOProcessorFunc) ProcessSLO(ctx context.Context, req *SLOProcessorReque
 	return s(ctx, req, res)
 }
 
+// NewIDSLOProcessor wraps a processor with the ID of the plugin it runs, so the processor can be
+// identified (e.g: on the plugin chain traces).
+func NewIDSLOProcessor(id string, p SLOProcessor) SLOProcessor {
+	return idSLOProcessor{id: id, SLOProcessor: p}
+}
+
+type idSLOProcessor struct {
+	id string
+	SLOProcessor
+}
+
+func (i idSLOProcessor) ID() string { return i.id }
+
+// sloProcessorID returns the ID of a processor if it has one.
+func sloProcessorID(p SLOProcessor) string {
+	if i, ok := p.(interface{ ID() string }); ok {
+		return i.ID()
+	}
+	return ""
+}
+
 // NewSLOProcessorFromSLOPluginV1 will be able to map a SLO plugin v1 to the SLOProcessor interface.
 func NewSLOProcessorFromSLOPluginV1(pluginFactory pluginslov1.PluginFactory, logger log.Logger, config any) (SLOProcessor, error) {
 	configData, err := json.Marshal(config)
@@ -73,3 +96,119 @@ func NewSLOProcessorFromSLOPluginV1(pluginFactory pluginslov1.PluginFactory, log
 		return nil
 	}), nil
 }
+
+// NewSLOProcessorFromSLOPluginV2 will be able to map the SLO processing of a SLO plugin v2 to the SLOProcessor interface.
+func NewSLOProcessorFromSLOPluginV2(pluginFactory pluginslov2.PluginFactory, logger log.Logger, config any) (SLOProcessor, error) {
+	plugin, err := newSLOPluginV2(pluginFactory, logger, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return SLOProcessorFunc(func(ctx context.Context, req *SLOProcessorRequest, res *SLOProcessorResult) error {
+		// Map models for slo plugin V2 version.
+		r := &pluginslov2.Request{
+			Info:           req.Info,
+			SLO:            req.SLO,
+			MWMBAlertGroup: req.MWMBAlertGroup,
+			OriginalSource: req.SLOGroup.OriginalSource,
+		}
+		rs := &pluginslov2.Result{
+			SLORules: res.SLORules,
+		}
+
+		// Process plugin.
+		err := plugin.ProcessSLO(ctx, r, rs)
+		if err != nil {
+			return err
+		}
+
+		// Unmap models for slo plugin V2 version.
+		req.Info = r.Info
+		req.SLO = r.SLO
+		req.MWMBAlertGroup = r.MWMBAlertGroup
+		res.SLORules = rs.SLORules
+
+		return nil
+	}), nil
+}
+
+func newSLOPluginV2(pluginFactory pluginslov2.PluginFactory, logger log.Logger, config any) (pluginslov2.Plugin, error) {
+	configData, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal config: %w", err)
+	}
+
+	plugin, err := pluginFactory(configData, pluginslov2.AppUtils{Logger: logger})
+	if err != nil {
+		return nil, fmt.Errorf("could not create plugin: %w", err)
+	}
+
+	return plugin, nil
+}
+
+type SLOGroupProcessorRequest struct {
+	Info     model.Info
+	SLOGroup model.PromSLOGroup
+	// SLOResults are the SLO results of the group, only set on the post processing.
+	SLOResults []SLOResult
+}
+
+type SLOGroupProcessorResult struct {
+	ExtraRules []model.PromRuleGroup
+}
+
+// SLOGroupProcessor is the interface that will be used to process the SLO group before and after
+// processing the SLOs of the group.
+type SLOGroupProcessor interface {
+	PreProcessSLOGroup(ctx context.Context, req *SLOGroupProcessorRequest, res *SLOGroupProcessorResult) error
+	PostProcessSLOGroup(ctx context.Context, req *SLOGroupProcessorRequest, res *SLOGroupProcessorResult) error
+}
+
+// NewSLOGroupProcessorFromSLOPluginV2 will be able to map the SLO group processing of a SLO plugin v2 to the SLOGroupProcessor interface.
+func NewSLOGroupProcessorFromSLOPluginV2(pluginFactory pluginslov2.PluginFactory, logger log.Logger, config any) (SLOGroupProcessor, error) {
+	plugin, err := newSLOPluginV2(pluginFactory, logger, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return sloGroupProcessorPluginV2{plugin: plugin}, nil
+}
+
+type sloGroupProcessorPluginV2 struct {
+	plugin pluginslov2.Plugin
+}
+
+func (s sloGroupProcessorPluginV2) PreProcessSLOGroup(ctx context.Context, req *SLOGroupProcessorRequest, res *SLOGroupProcessorResult) error {
+	return s.process(ctx, req, res, s.plugin.PreProcessSLOGroup)
+}
+
+func (s sloGroupProcessorPluginV2) PostProcessSLOGroup(ctx context.Context, req *SLOGroupProcessorRequest, res *SLOGroupProcessorResult) error {
+	return s.process(ctx, req, res, s.plugin.PostProcessSLOGroup)
+}
+
+type sloGroupPluginV2Func = func(ctx context.Context, request *pluginslov2.GroupRequest, result *pluginslov2.GroupResult) error
+
+func (s sloGroupProcessorPluginV2) process(ctx context.Context, req *SLOGroupProcessorRequest, res *SLOGroupProcessorResult, process sloGroupPluginV2Func) error {
+	// Map models for slo plugin V2 version, the SLOs and the results are read only, so we pass copies.
+	r := &pluginslov2.GroupRequest{
+		Info:           req.Info,
+		OriginalSource: req.SLOGroup.OriginalSource,
+		SLOs:           slices.Clone(req.SLOGroup.SLOs),
+	}
+	for _, sr := range req.SLOResults {
+		r.SLOResults = append(r.SLOResults, model.PromSLOResult{SLO: sr.SLO, PrometheusRules: sr.SLORules})
+	}
+	rs := &pluginslov2.GroupResult{
+		ExtraRules: res.ExtraRules,
+	}
+
+	err := process(ctx, r, rs)
+	if err != nil {
+		return err
+	}
+
+	// Unmap models for slo plugin V2 version.
+	res.ExtraRules = rs.ExtraRules
+
+	return nil
+}
@@ -0,0 +1,360 @@
+package generate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/slok/sloth/pkg/common/conventions"
+	"github.com/slok/sloth/pkg/common/model"
+	promutils "github.com/slok/sloth/pkg/common/utils/prometheus"
+)
+
+const (
+	// SharedSLIIDPrefix is the prefix of the `sloth_id` label used by the shared SLI recording rules.
+	SharedSLIIDPrefix = "shared-sli-"
+
+	sharedSLIFilterPlaceholder = `{sloth_id="__sloth_shared_sli__"}`
+)
+
+// ShareSLIRules de-duplicates the SLI error recording rules of the SLOs that have identical SLI
+// recording rules across all the generation results.
+//
+// The SLI recording rules of these SLOs are generated once under a shared ID (on the first result
+// that uses them) and the rest of the SLO rules (metadata, alerts and extra rules) are rewritten to
+// use the shared SLI recordings, restoring the SLO labels, so the resulting series are the same.
+//
+// SLOs whose SLI recordings are used in ways that can't be rewritten (e.g: range selectors on the
+// metadata or alert rules) keep their own SLI recording rules.
+//
+// The SLOs are identified by their result and ID, so SLOs with the same ID on different results (e.g: the
+// same service and SLO name on multiple spec files) are never mixed.
+func ShareSLIRules(results []*model.PromSLOGroupResult) error {
+	// The SLOs of each result indexed by ID.
+	slos := make([]map[string]model.PromSLO, len(results))
+	for i, res := range results {
+		slos[i] = map[string]model.PromSLO{}
+		for _, sloRes := range res.SLOResults {
+			slos[i][sloRes.SLO.ID] = sloRes.SLO
+		}
+	}
+
+	// Get the SLOs whose SLI recordings can't be replaced with the shared ones.
+	ineligible := map[resultSLOKey]bool{}
+	err := walkSLIConsumerRules(results, func(resultIdx int, expr string) (string, error) {
+		return expr, inspectSLISelectors(expr, slos[resultIdx], func(slo model.PromSLO, vs *parser.VectorSelector, path []parser.Node) {
+			if !isSimpleSLISelector(slo, vs, path) {
+				ineligible[resultSLOKey{resultIdx: resultIdx, sloID: slo.ID}] = true
+			}
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	// Group the SLOs by their SLI recording rules fingerprint.
+	type sharedSLI struct {
+		id        string
+		group     model.PromRuleGroup
+		resultIdx int
+		slos      []resultSLOKey
+	}
+	shared := map[string]*sharedSLI{}
+	fingerprints := []string{}
+	for i, res := range results {
+		for _, sloRes := range res.SLOResults {
+			slo := sloRes.SLO
+			key := resultSLOKey{resultIdx: i, sloID: slo.ID}
+			if ineligible[key] || len(sloRes.PrometheusRules.SLIErrorRecRules.Rules) == 0 {
+				continue
+			}
+
+			group := normalizeSLIRuleGroup(slo, sloRes.PrometheusRules.SLIErrorRecRules)
+			fp, err := json.Marshal(group)
+			if err != nil {
+				return fmt.Errorf("could not fingerprint %q SLO SLI recording rules: %w", slo.ID, err)
+			}
+
+			s, ok := shared[string(fp)]
+			if !ok {
+				s = &sharedSLI{group: group, resultIdx: i}
+				shared[string(fp)] = s
+				fingerprints = append(fingerprints, string(fp))
+			}
+			s.slos = append(s.slos, key)
+		}
+	}
+
+	// The shared SLI IDs of each result indexed by SLO ID.
+	sharedIDs := make([]map[string]string, len(results))
+	for i := range results {
+		sharedIDs[i] = map[string]string{}
+	}
+	sharing := false
+	for _, fp := range fingerprints {
+		s := shared[fp]
+		if len(s.slos) < 2 {
+			continue
+		}
+
+		sharing = true
+		sum := sha256.Sum256([]byte(fp))
+		s.id = SharedSLIIDPrefix + hex.EncodeToString(sum[:])[:12]
+		for _, key := range s.slos {
+			sharedIDs[key.resultIdx][key.sloID] = s.id
+		}
+
+		// Set the shared ID on the normalized SLI recording rules.
+		sharedFilter := promutils.LabelsToPromFilter(map[string]string{conventions.PromSLOIDLabelName: s.id})
+		rules := make([]rulefmt.Rule, 0, len(s.group.Rules))
+		for _, r := range s.group.Rules {
+			r.Expr = strings.ReplaceAll(r.Expr, sharedSLIFilterPlaceholder, sharedFilter)
+			r.Labels[conventions.PromSLOIDLabelName] = s.id
+			rules = append(rules, r)
+		}
+		results[s.resultIdx].ExtraRules = append(results[s.resultIdx].ExtraRules, model.PromRuleGroup{
+			Name:     conventions.PromRuleGroupNameSLOSLIPrefix + s.id,
+			Interval: s.group.Interval,
+			Rules:    rules,
+		})
+	}
+	if !sharing {
+		return nil
+	}
+
+	// Remove the SLO own SLI recordings and use the shared ones.
+	for ri, res := range results {
+		for i, sloRes := range res.SLOResults {
+			if _, ok := sharedIDs[ri][sloRes.SLO.ID]; ok {
+				res.SLOResults[i].PrometheusRules.SLIErrorRecRules = model.PromRuleGroup{}
+			}
+		}
+	}
+
+	return walkSLIConsumerRules(results, func(resultIdx int, expr string) (string, error) {
+		return rewriteSLISelectors(expr, slos[resultIdx], sharedIDs[resultIdx])
+	})
+}
+
+// resultSLOKey identifies an SLO across all the generation results.
+type resultSLOKey struct {
+	resultIdx int
+	sloID     string
+}
+
+// normalizeSLIRuleGroup returns the SLI recording rules without the SLO identity (ID filter, ID labels and
+// SLO labels), so SLOs with the same SLI recordings have the same normalized rules.
+func normalizeSLIRuleGroup(slo model.PromSLO, group model.PromRuleGroup) model.PromRuleGroup {
+	idLabels := conventions.GetSLOIDPromLabels(slo)
+	filter := promutils.LabelsToPromFilter(idLabels)
+
+	rules := make([]rulefmt.Rule, 0, len(group.Rules))
+	for _, r := range group.Rules {
+		lbls := map[string]string{}
+		for k, v := range r.Labels {
+			if idv, ok := idLabels[k]; ok && idv == v {
+				continue
+			}
+			if sv, ok := slo.Labels[k]; ok && sv == v {
+				continue
+			}
+			lbls[k] = v
+		}
+
+		rules = append(rules, rulefmt.Rule{
+			Record:      r.Record,
+			Alert:       r.Alert,
+			Expr:        strings.ReplaceAll(r.Expr, filter, sharedSLIFilterPlaceholder),
+			For:         r.For,
+			Labels:      lbls,
+			Annotations: r.Annotations,
+		})
+	}
+
+	return model.PromRuleGroup{Interval: group.Interval, Rules: rules}
+}
+
+// walkSLIConsumerRules calls the function with all the rule expressions that could use the SLI recordings
+// (everything except the SLI recording rules) and their result index, and sets the returned expression.
+func walkSLIConsumerRules(results []*model.PromSLOGroupResult, f func(resultIdx int, expr string) (string, error)) error {
+	walkGroup := func(resultIdx int, g *model.PromRuleGroup) error {
+		for i, r := range g.Rules {
+			expr, err := f(resultIdx, r.Expr)
+			if err != nil {
+				return fmt.Errorf("invalid %q rule group %q rule expression: %w", g.Name, ruleName(r), err)
+			}
+			g.Rules[i].Expr = expr
+		}
+		return nil
+	}
+
+	for ri, res := range results {
+		for i := range res.SLOResults {
+			rules := &res.SLOResults[i].PrometheusRules
+			groups := []*model.PromRuleGroup{&rules.MetadataRecRules, &rules.AlertRules}
+			for j := range rules.ExtraRules {
+				groups = append(groups, &rules.ExtraRules[j])
+			}
+			for _, g := range groups {
+				if err := walkGroup(ri, g); err != nil {
+					return err
+				}
+			}
+		}
+
+		for i := range res.ExtraRules {
+			if err := walkGroup(ri, &res.ExtraRules[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// inspectSLISelectors calls the function with the SLI recording vector selectors of the expression that
+// select an SLO by its ID.
+func inspectSLISelectors(expr string, slos map[string]model.PromSLO, f func(slo model.PromSLO, vs *parser.VectorSelector, path []parser.Node)) error {
+	e, err := parser.ParseExpr(expr)
+	if err != nil {
+		return err
+	}
+
+	parser.Inspect(e, func(node parser.Node, path []parser.Node) error {
+		vs, ok := node.(*parser.VectorSelector)
+		if !ok || !strings.HasPrefix(vs.Name, conventions.PromSLIErrorMetric) {
+			return nil
+		}
+
+		for _, m := range vs.LabelMatchers {
+			if m.Name != conventions.PromSLOIDLabelName || m.Type != labels.MatchEqual {
+				continue
+			}
+			if slo, ok := slos[m.Value]; ok {
+				f(slo, vs, path)
+			}
+		}
+
+		return nil
+	})
+
+	return nil
+}
+
+// isSimpleSLISelector returns true if the selector selects the SLO SLI recordings only by the SLO ID labels,
+// and the selector can be replaced by another instant vector expression.
+func isSimpleSLISelector(slo model.PromSLO, vs *parser.VectorSelector, path []parser.Node) bool {
+	if len(path) > 0 {
+		if _, ok := path[len(path)-1].(*parser.MatrixSelector); ok {
+			return false
+		}
+	}
+
+	idLabels := conventions.GetSLOIDPromLabels(slo)
+	matched := 0
+	for _, m := range vs.LabelMatchers {
+		if m.Name == labels.MetricName {
+			continue
+		}
+		if m.Type != labels.MatchEqual || idLabels[m.Name] != m.Value {
+			return false
+		}
+		matched++
+	}
+
+	return matched == len(idLabels)
+}
+
+// rewriteSLISelectors replaces the SLI recording selectors of the shared SLOs with the shared SLI recordings,
+// restoring the SLO labels.
+func rewriteSLISelectors(expr string, slos map[string]model.PromSLO, sharedIDs map[string]string) (string, error) {
+	type replacement struct {
+		start, end int
+		text       string
+	}
+	replacements := []replacement{}
+	err := inspectSLISelectors(expr, slos, func(slo model.PromSLO, vs *parser.VectorSelector, _ []parser.Node) {
+		sharedID, ok := sharedIDs[slo.ID]
+		if !ok {
+			return
+		}
+
+		// Keep the selector modifiers (e.g: offset), they are part of the selector position.
+		start, end := int(vs.PosRange.Start), int(vs.PosRange.End)
+		selector := vs.Name + promutils.LabelsToPromFilter(map[string]string{conventions.PromSLOIDLabelName: sharedID})
+		text := selector + expr[start+len(vs.Name)+len(selectorMatchersText(expr[start+len(vs.Name):end])):end]
+		replacements = append(replacements, replacement{start: start, end: end, text: restoreSLOLabels(slo, text)})
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(replacements) == 0 {
+		return expr, nil
+	}
+
+	sort.Slice(replacements, func(i, j int) bool { return replacements[i].start > replacements[j].start })
+	for _, r := range replacements {
+		expr = expr[:r.start] + r.text + expr[r.end:]
+	}
+
+	// Make sure we don't generate broken expressions.
+	if _, err := parser.ParseExpr(expr); err != nil {
+		return "", fmt.Errorf("invalid shared SLI expression: %w", err)
+	}
+
+	return expr, nil
+}
+
+// selectorMatchersText returns the `{...}` label matchers text at the start of the selector text (after the
+// metric name).
+func selectorMatchersText(s string) string {
+	if !strings.HasPrefix(s, "{") {
+		return ""
+	}
+
+	inQuote := false
+	for i := 1; i < len(s); i++ {
+		switch {
+		case inQuote && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			inQuote = !inQuote
+		case !inQuote && s[i] == '}':
+			return s[:i+1]
+		}
+	}
+
+	return s
+}
+
+// restoreSLOLabels wraps the expression with the SLO ID labels and SLO labels, like the ones the SLO
+// SLI recordings would have.
+func restoreSLOLabels(slo model.PromSLO, expr string) string {
+	lbls := map[string]string{}
+	for k, v := range slo.Labels {
+		lbls[k] = v
+	}
+	for k, v := range conventions.GetSLOIDPromLabels(slo) {
+		lbls[k] = v
+	}
+
+	names := make([]string, 0, len(lbls))
+	for k := range lbls {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		expr = fmt.Sprintf(`label_replace(%s, %s, %s, "", "")`, expr, strconv.Quote(k), strconv.Quote(lbls[k]))
+	}
+
+	return expr
+}
@@ -0,0 +1,197 @@
+package generate_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/slok/sloth/internal/app/generate"
+	"github.com/slok/sloth/pkg/common/model"
+)
+
+func newSharedTestResult(slo model.PromSLO, sliQuery, alertExpr string) model.PromSLOResult {
+	filter := `{sloth_id="` + slo.ID + `", sloth_service="` + slo.Service + `", sloth_slo="` + slo.Name + `"}`
+	sliLabels := func(window string) map[string]string {
+		return map[string]string{"sloth_id": slo.ID, "sloth_service": slo.Service, "sloth_slo": slo.Name, "sloth_window": window, "owner": slo.Labels["owner"]}
+	}
+
+	return model.PromSLOResult{
+		SLO: slo,
+		PrometheusRules: model.PromSLORules{
+			SLIErrorRecRules: model.PromRuleGroup{
+				Name: "sloth-slo-sli-recordings-" + slo.ID,
+				Rules: []rulefmt.Rule{
+					{Record: "slo:sli_error:ratio_rate5m", Expr: sliQuery, Labels: sliLabels("5m")},
+					{Record: "slo:sli_error:ratio_rate30d", Expr: "sum_over_time(slo:sli_error:ratio_rate5m" + filter + "[30d])", Labels: sliLabels("30d")},
+				},
+			},
+			MetadataRecRules: model.PromRuleGroup{
+				Name: "sloth-slo-meta-recordings-" + slo.ID,
+				Rules: []rulefmt.Rule{
+					{Record: "slo:current_burn_rate:ratio", Expr: "slo:sli_error:ratio_rate5m" + filter + " / on(sloth_id, sloth_slo, sloth_service) group_left slo:error_budget:ratio" + filter},
+				},
+			},
+			AlertRules: model.PromRuleGroup{
+				Name: "sloth-slo-alerts-" + slo.ID,
+				Rules: []rulefmt.Rule{
+					{Alert: "Test", Expr: alertExpr},
+				},
+			},
+		},
+	}
+}
+
+func TestShareSLIRules(t *testing.T) {
+	slo1 := model.PromSLO{ID: "svc1-slo1", Service: "svc1", Name: "slo1", Labels: map[string]string{"owner": "team1"}}
+	slo2 := model.PromSLO{ID: "svc2-slo1", Service: "svc2", Name: "slo1", Labels: map[string]string{"owner": "team2"}}
+	slo3 := model.PromSLO{ID: "svc3-slo1", Service: "svc3", Name: "slo1", Labels: map[string]string{"owner": "team3"}}
+	slo1Dup := model.PromSLO{ID: "svc1-slo1", Service: "svc1", Name: "slo1", Labels: map[string]string{"owner": "team9"}}
+
+	const sharedID = "shared-sli-6c49a1945e30"
+	restore := func(slo model.PromSLO, selector string) string {
+		return `label_replace(label_replace(label_replace(label_replace(` + selector + `, "owner", "` + slo.Labels["owner"] + `", "", ""), "sloth_id", "` + slo.ID + `", "", ""), "sloth_service", "` + slo.Service + `", "", ""), "sloth_slo", "` + slo.Name + `", "", "")`
+	}
+	sharedRules := []rulefmt.Rule{
+		{Record: "slo:sli_error:ratio_rate5m", Expr: "rate(errors[5m])", Labels: map[string]string{"sloth_id": sharedID, "sloth_window": "5m"}},
+		{Record: "slo:sli_error:ratio_rate30d", Expr: `sum_over_time(slo:sli_error:ratio_rate5m{sloth_id="` + sharedID + `"}[30d])`, Labels: map[string]string{"sloth_id": sharedID, "sloth_window": "30d"}},
+	}
+	sharedResult := func(slo model.PromSLO, alertExpr string) model.PromSLOResult {
+		r := newSharedTestResult(slo, "", alertExpr)
+		r.PrometheusRules.SLIErrorRecRules = model.PromRuleGroup{}
+		r.PrometheusRules.MetadataRecRules.Rules[0].Expr = restore(slo, `slo:sli_error:ratio_rate5m{sloth_id="`+sharedID+`"}`) + ` / on(sloth_id, sloth_slo, sloth_service) group_left slo:error_budget:ratio{sloth_id="` + slo.ID + `", sloth_service="` + slo.Service + `", sloth_slo="` + slo.Name + `"}`
+		return r
+	}
+
+	tests := map[string]struct {
+		results    func() []*model.PromSLOGroupResult
+		expResults func() []*model.PromSLOGroupResult
+		expErr     bool
+	}{
+		"SLOs with different SLIs should not be shared.": {
+			results: func() []*model.PromSLOGroupResult {
+				return []*model.PromSLOGroupResult{
+					{SLOResults: []model.PromSLOResult{
+						newSharedTestResult(slo1, "rate(errors[5m])", "vector(1)"),
+						newSharedTestResult(slo2, "rate(other_errors[5m])", "vector(1)"),
+					}},
+				}
+			},
+			expResults: func() []*model.PromSLOGroupResult {
+				return []*model.PromSLOGroupResult{
+					{SLOResults: []model.PromSLOResult{
+						newSharedTestResult(slo1, "rate(errors[5m])", "vector(1)"),
+						newSharedTestResult(slo2, "rate(other_errors[5m])", "vector(1)"),
+					}},
+				}
+			},
+		},
+
+		"SLOs with identical SLIs across results should be shared on the first result that uses them.": {
+			results: func() []*model.PromSLOGroupResult {
+				return []*model.PromSLOGroupResult{
+					{SLOResults: []model.PromSLOResult{
+						newSharedTestResult(slo1, "rate(errors[5m])", `slo:sli_error:ratio_rate5m{sloth_id="svc1-slo1", sloth_service="svc1", sloth_slo="slo1"} > 0.1`),
+						newSharedTestResult(slo3, "rate(other_errors[5m])", "vector(1)"),
+					}},
+					{SLOResults: []model.PromSLOResult{
+						newSharedTestResult(slo2, "rate(errors[5m])", `slo:sli_error:ratio_rate5m{sloth_slo="slo1",sloth_service="svc2",sloth_id="svc2-slo1"} offset 5m > 0.1`),
+					}},
+				}
+			},
+			expResults: func() []*model.PromSLOGroupResult {
+				return []*model.PromSLOGroupResult{
+					{
+						SLOResults: []model.PromSLOResult{
+							sharedResult(slo1, restore(slo1, `slo:sli_error:ratio_rate5m{sloth_id="`+sharedID+`"}`)+` > 0.1`),
+							newSharedTestResult(slo3, "rate(other_errors[5m])", "vector(1)"),
+						},
+						ExtraRules: []model.PromRuleGroup{
+							{Name: "sloth-slo-sli-recordings-" + sharedID, Rules: sharedRules},
+						},
+					},
+					{SLOResults: []model.PromSLOResult{
+						sharedResult(slo2, restore(slo2, `slo:sli_error:ratio_rate5m{sloth_id="`+sharedID+`"} offset 5m`)+` > 0.1`),
+					}},
+				}
+			},
+		},
+
+		"SLOs with the same ID on different results should not be mixed.": {
+			results: func() []*model.PromSLOGroupResult {
+				return []*model.PromSLOGroupResult{
+					{SLOResults: []model.PromSLOResult{
+						newSharedTestResult(slo1, "rate(errors[5m])", "vector(1)"),
+						newSharedTestResult(slo2, "rate(errors[5m])", "vector(1)"),
+					}},
+					{SLOResults: []model.PromSLOResult{
+						newSharedTestResult(slo1Dup, "rate(other_errors[5m])", `slo:sli_error:ratio_rate5m{sloth_id="svc1-slo1", sloth_service="svc1", sloth_slo="slo1"} > 0.1`),
+					}},
+				}
+			},
+			expResults: func() []*model.PromSLOGroupResult {
+				return []*model.PromSLOGroupResult{
+					{
+						SLOResults: []model.PromSLOResult{
+							sharedResult(slo1, "vector(1)"),
+							sharedResult(slo2, "vector(1)"),
+						},
+						ExtraRules: []model.PromRuleGroup{
+							{Name: "sloth-slo-sli-recordings-" + sharedID, Rules: sharedRules},
+						},
+					},
+					{SLOResults: []model.PromSLOResult{
+						newSharedTestResult(slo1Dup, "rate(other_errors[5m])", `slo:sli_error:ratio_rate5m{sloth_id="svc1-slo1", sloth_service="svc1", sloth_slo="slo1"} > 0.1`),
+					}},
+				}
+			},
+		},
+
+		"SLOs with their SLI recordings used with range selectors should not be shared.": {
+			results: func() []*model.PromSLOGroupResult {
+				return []*model.PromSLOGroupResult{
+					{SLOResults: []model.PromSLOResult{
+						newSharedTestResult(slo1, "rate(errors[5m])", `absent_over_time(slo:sli_error:ratio_rate5m{sloth_id="svc1-slo1", sloth_service="svc1", sloth_slo="slo1"}[1h])`),
+						newSharedTestResult(slo2, "rate(errors[5m])", "vector(1)"),
+					}},
+				}
+			},
+			expResults: func() []*model.PromSLOGroupResult {
+				return []*model.PromSLOGroupResult{
+					{SLOResults: []model.PromSLOResult{
+						newSharedTestResult(slo1, "rate(errors[5m])", `absent_over_time(slo:sli_error:ratio_rate5m{sloth_id="svc1-slo1", sloth_service="svc1", sloth_slo="slo1"}[1h])`),
+						newSharedTestResult(slo2, "rate(errors[5m])", "vector(1)"),
+					}},
+				}
+			},
+		},
+
+		"Invalid rule expressions should fail.": {
+			results: func() []*model.PromSLOGroupResult {
+				return []*model.PromSLOGroupResult{
+					{SLOResults: []model.PromSLOResult{
+						newSharedTestResult(slo1, "rate(errors[5m])", "sum("),
+					}},
+				}
+			},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			results := test.results()
+			err := generate.ShareSLIRules(results)
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				require.Equal(test.expResults(), results)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package generate
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+
+	"github.com/slok/sloth/pkg/common/model"
+)
+
+// sloTraceSnapshot is the state of the SLO and its rules at a point of the SLO plugin chain execution.
+type sloTraceSnapshot struct {
+	slo   map[string]string
+	rules []ruleGroupSnapshot
+}
+
+type ruleGroupSnapshot struct {
+	group  string
+	fields map[string]string
+	rules  []ruleSnapshot
+}
+
+type ruleSnapshot struct {
+	key    string
+	fields map[string]string
+}
+
+func newSLOTraceSnapshot(slo model.PromSLO, rules model.PromSLORules) sloTraceSnapshot {
+	groups := []ruleGroupSnapshot{
+		newRuleGroupSnapshot("sli_error_recordings", rules.SLIErrorRecRules),
+		newRuleGroupSnapshot("metadata_recordings", rules.MetadataRecRules),
+		newRuleGroupSnapshot("alerts", rules.AlertRules),
+	}
+	for i, g := range rules.ExtraRules {
+		groups = append(groups, newRuleGroupSnapshot(fmt.Sprintf("extra[%d]", i), g))
+	}
+
+	return sloTraceSnapshot{
+		slo:   flattenFields(slo),
+		rules: groups,
+	}
+}
+
+func newRuleGroupSnapshot(name string, g model.PromRuleGroup) ruleGroupSnapshot {
+	// Rules are identified by their name, repeated names are identified by their occurrence.
+	seen := map[string]int{}
+	rules := make([]ruleSnapshot, 0, len(g.Rules))
+	for _, r := range g.Rules {
+		key := ruleName(r)
+		seen[key]++
+		if seen[key] > 1 || key == "" {
+			key = fmt.Sprintf("%s#%d", key, seen[key])
+		}
+		rules = append(rules, ruleSnapshot{key: key, fields: flattenFields(r)})
+	}
+
+	return ruleGroupSnapshot{
+		group:  name,
+		fields: flattenFields(model.PromRuleGroup{Name: g.Name, Interval: g.Interval}),
+		rules:  rules,
+	}
+}
+
+func ruleName(r rulefmt.Rule) string {
+	if r.Alert != "" {
+		return r.Alert
+	}
+	return r.Record
+}
+
+// diffSLOTraceSnapshots returns the rule and SLO changes between two snapshots.
+func diffSLOTraceSnapshots(before, after sloTraceSnapshot) ([]model.PromRuleChange, []model.FieldChange) {
+	beforeGroups := map[string]ruleGroupSnapshot{}
+	for _, g := range before.rules {
+		beforeGroups[g.group] = g
+	}
+	afterGroups := map[string]ruleGroupSnapshot{}
+	for _, g := range after.rules {
+		afterGroups[g.group] = g
+	}
+
+	// Keep the groups order, removed extra groups are at the end.
+	groups := slices.Clone(after.rules)
+	for _, g := range before.rules {
+		if _, ok := afterGroups[g.group]; !ok {
+			groups = append(groups, ruleGroupSnapshot{group: g.group})
+		}
+	}
+
+	var ruleChanges []model.PromRuleChange
+	for _, g := range groups {
+		ruleChanges = append(ruleChanges, diffRuleGroupSnapshots(beforeGroups[g.group], afterGroups[g.group], g.group)...)
+	}
+
+	return ruleChanges, diffFields(before.slo, after.slo)
+}
+
+func diffRuleGroupSnapshots(before, after ruleGroupSnapshot, group string) []model.PromRuleChange {
+	var changes []model.PromRuleChange
+	if fields := diffFields(before.fields, after.fields); len(fields) > 0 {
+		changes = append(changes, model.PromRuleChange{Group: group, Type: model.ChangeTypeModified, Fields: fields})
+	}
+
+	beforeRules := map[string]map[string]string{}
+	for _, r := range before.rules {
+		beforeRules[r.key] = r.fields
+	}
+	afterRules := map[string]map[string]string{}
+	for _, r := range after.rules {
+		afterRules[r.key] = r.fields
+	}
+
+	for _, r := range before.rules {
+		if _, ok := afterRules[r.key]; !ok {
+			changes = append(changes, model.PromRuleChange{Group: group, Rule: r.key, Type: model.ChangeTypeRemoved, Fields: diffFields(r.fields, nil)})
+		}
+	}
+
+	for _, r := range after.rules {
+		beforeFields, ok := beforeRules[r.key]
+		if !ok {
+			changes = append(changes, model.PromRuleChange{Group: group, Rule: r.key, Type: model.ChangeTypeAdded, Fields: diffFields(nil, r.fields)})
+			continue
+		}
+
+		if fields := diffFields(beforeFields, r.fields); len(fields) > 0 {
+			changes = append(changes, model.PromRuleChange{Group: group, Rule: r.key, Type: model.ChangeTypeModified, Fields: fields})
+		}
+	}
+
+	return changes
+}
+
+// diffFields returns the changes between two flattened field sets sorted by path.
+func diffFields(before, after map[string]string) []model.FieldChange {
+	paths := map[string]struct{}{}
+	for k := range before {
+		paths[k] = struct{}{}
+	}
+	for k := range after {
+		paths[k] = struct{}{}
+	}
+
+	var changes []model.FieldChange
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		o, inBefore := before[path]
+		n, inAfter := after[path]
+		switch {
+		case !inBefore:
+			changes = append(changes, model.FieldChange{Path: path, Type: model.ChangeTypeAdded, New: n})
+		case !inAfter:
+			changes = append(changes, model.FieldChange{Path: path, Type: model.ChangeTypeRemoved, Old: o})
+		case o != n:
+			changes = append(changes, model.FieldChange{Path: path, Type: model.ChangeTypeModified, Old: o, New: n})
+		}
+	}
+
+	return changes
+}
+
+// flattenFields returns the non zero leaf values of a value by their path (e.g: `Labels.team`, `Plugins.Plugins[0].ID`),
+// this way we can diff any model without knowing its structure.
+func flattenFields(v any) map[string]string {
+	fields := map[string]string{}
+	flattenValue("", reflect.ValueOf(v), fields)
+	return fields
+}
+
+var stringerType = reflect.TypeFor[fmt.Stringer]()
+
+func flattenValue(path string, v reflect.Value, fields map[string]string) {
+	if !v.IsValid() {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		flattenValue(path, v.Elem(), fields)
+		return
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			flattenValue(joinPath(path, t.Field(i).Name), v.Field(i), fields)
+		}
+		return
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		for _, k := range keys {
+			flattenValue(joinPath(path, fmt.Sprint(k.Interface())), v.MapIndex(k), fields)
+		}
+		return
+
+	case reflect.Slice, reflect.Array:
+		// Raw data (e.g: JSON raw messages) is used as a single value.
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			if v.Len() > 0 {
+				fields[path] = string(v.Bytes())
+			}
+			return
+		}
+		for i := range v.Len() {
+			flattenValue(fmt.Sprintf("%s[%d]", path, i), v.Index(i), fields)
+		}
+		return
+	}
+
+	if v.IsZero() {
+		return
+	}
+
+	// Use the human readable representation if available (e.g: durations).
+	if v.Type().Implements(stringerType) {
+		fields[path] = v.Interface().(fmt.Stringer).String()
+		return
+	}
+	fields[path] = fmt.Sprint(v.Interface())
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,264 @@
+package kubecontroller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/spotahome/kooper/v2/controller"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// Auto SLOs workload annotations.
+const (
+	// AutoSLOAnnotationTemplate is the PrometheusServiceLevelTemplate used to generate the workload SLOs,
+	// in `name` (workload namespace template) or `namespace/name` format.
+	AutoSLOAnnotationTemplate = "sloth.dev/slo-template"
+	// AutoSLOAnnotationObjective overrides the objective of the template SLOs.
+	AutoSLOAnnotationObjective = "sloth.dev/objective"
+	// AutoSLOAnnotationService is the service of the generated SLOs, by default the workload name.
+	AutoSLOAnnotationService = "sloth.dev/service"
+	// AutoSLOAnnotationParamPrefix is the prefix of the annotations used as template params
+	// (e.g: `sloth.dev/slo-param-job: myapp` sets the `job` param).
+	AutoSLOAnnotationParamPrefix = "sloth.dev/slo-param-"
+)
+
+// AutoSLOLabel is the label set on the auto generated PrometheusServiceLevels.
+const AutoSLOLabel = "sloth.dev/auto-slo"
+
+// AutoSLORepository knows how to manage the auto generated PrometheusServiceLevels.
+type AutoSLORepository interface {
+	GetPrometheusServiceLevel(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevel, error)
+	EnsurePrometheusServiceLevel(ctx context.Context, psl *slothv1.PrometheusServiceLevel) error
+	DeletePrometheusServiceLevel(ctx context.Context, ns, name string) error
+}
+
+// AutoSLOHandlerConfig is the auto SLOs controller handler configuration.
+type AutoSLOHandlerConfig struct {
+	Repository      AutoSLORepository
+	NamespaceGetter NamespaceGetter
+	// NamespaceLabelSelector makes the handler only generate the auto SLOs of the workloads of the
+	// namespaces that match the selector.
+	NamespaceLabelSelector labels.Selector
+	Logger                 log.Logger
+}
+
+func (c *AutoSLOHandlerConfig) defaults() error {
+	if c.Repository == nil {
+		return fmt.Errorf("repository is required")
+	}
+
+	if c.NamespaceLabelSelector == nil {
+		c.NamespaceLabelSelector = labels.Everything()
+	}
+
+	if !c.NamespaceLabelSelector.Empty() && c.NamespaceGetter == nil {
+		return fmt.Errorf("namespace getter is required when using a namespace label selector")
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "kubecontroller.AutoSLOHandler"})
+
+	return nil
+}
+
+type autoSLOHandler struct {
+	repo              AutoSLORepository
+	namespaceGetter   NamespaceGetter
+	namespaceSelector labels.Selector
+	logger            log.Logger
+}
+
+// NewAutoSLOHandler returns the auto SLOs controller handler. The handler generates a PrometheusServiceLevel
+// that instances an SLO template for each Service and Deployment with the SLO template annotation.
+//
+// The generated PrometheusServiceLevels are owned by their workload, so they are garbage collected by
+// Kubernetes when the workload is deleted. When the workload annotation is removed, the handler deletes them.
+func NewAutoSLOHandler(config AutoSLOHandlerConfig) (controller.Handler, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &autoSLOHandler{
+		repo:              config.Repository,
+		namespaceGetter:   config.NamespaceGetter,
+		namespaceSelector: config.NamespaceLabelSelector,
+		logger:            config.Logger,
+	}, nil
+}
+
+func (h autoSLOHandler) Handle(ctx context.Context, obj runtime.Object) error {
+	var (
+		objMeta metav1.ObjectMeta
+		ownRef  metav1.OwnerReference
+	)
+	switch v := obj.(type) {
+	case *corev1.Service:
+		objMeta = v.ObjectMeta
+		ownRef = metav1.OwnerReference{APIVersion: "v1", Kind: "Service"}
+	case *appsv1.Deployment:
+		objMeta = v.ObjectMeta
+		ownRef = metav1.OwnerReference{APIVersion: "apps/v1", Kind: "Deployment"}
+	default:
+		h.logger.Warningf("Unsuported Kubernetes object type: %s", obj.GetObjectKind())
+		return nil
+	}
+	ownRef.Name = objMeta.Name
+	ownRef.UID = objMeta.UID
+	ownRef.Controller = ptrTo(true)
+
+	name := autoSLOName(ownRef.Kind, objMeta.Name)
+	ctx = h.logger.SetValuesOnCtx(ctx, log.Kv{"ns": objMeta.Namespace, "kind": ownRef.Kind, "name": objMeta.Name})
+	logger := h.logger.WithCtxValues(ctx)
+
+	selected, err := namespaceSelected(ctx, h.namespaceGetter, h.namespaceSelector, objMeta.Namespace)
+	if err != nil {
+		return fmt.Errorf("could not check if the namespace is selected: %w", err)
+	}
+	if !selected {
+		logger.Debugf("Ignoring object due to %q", "namespace not selected")
+		return nil
+	}
+
+	stored, err := h.repo.GetPrometheusServiceLevel(ctx, objMeta.Namespace, name)
+	if err != nil {
+		if !kubeerrors.IsNotFound(err) {
+			return fmt.Errorf("could not get auto SLOs PrometheusServiceLevel: %w", err)
+		}
+		stored = nil
+	}
+
+	// Only manage the PrometheusServiceLevels owned by the workload.
+	_, autoSLOs := objMeta.Annotations[AutoSLOAnnotationTemplate]
+	if stored != nil && !ownedBy(stored.OwnerReferences, objMeta.UID) {
+		if !autoSLOs {
+			return nil
+		}
+		return fmt.Errorf("%s/%s PrometheusServiceLevel already exists and is not managed by the %s", objMeta.Namespace, name, ownRef.Kind)
+	}
+
+	// Garbage collect the auto SLOs of the workloads without the annotation.
+	if !autoSLOs {
+		if stored == nil {
+			return nil
+		}
+
+		err := h.repo.DeletePrometheusServiceLevel(ctx, objMeta.Namespace, name)
+		if err != nil && !kubeerrors.IsNotFound(err) {
+			return fmt.Errorf("could not delete auto SLOs PrometheusServiceLevel: %w", err)
+		}
+		logger.Infof("Auto SLOs PrometheusServiceLevel deleted")
+
+		return nil
+	}
+
+	psl, err := newAutoSLOPrometheusServiceLevel(name, objMeta, ownRef)
+	if err != nil {
+		return fmt.Errorf("invalid auto SLOs annotations: %w", err)
+	}
+
+	// Don't update if nothing changed, this way we don't update the objects on every resync.
+	if stored != nil &&
+		reflect.DeepEqual(stored.Spec, psl.Spec) &&
+		maps.Equal(stored.Labels, psl.Labels) &&
+		reflect.DeepEqual(stored.OwnerReferences, psl.OwnerReferences) {
+		return nil
+	}
+
+	err = h.repo.EnsurePrometheusServiceLevel(ctx, psl)
+	if err != nil {
+		return fmt.Errorf("could not ensure auto SLOs PrometheusServiceLevel: %w", err)
+	}
+	logger.Infof("Auto SLOs PrometheusServiceLevel ensured")
+
+	return nil
+}
+
+// autoSLOName returns the name of the auto generated PrometheusServiceLevel of a workload.
+func autoSLOName(kind, name string) string {
+	return fmt.Sprintf("%s-%s", strings.ToLower(kind), name)
+}
+
+func ownedBy(refs []metav1.OwnerReference, uid types.UID) bool {
+	for _, ref := range refs {
+		if ref.Controller != nil && *ref.Controller && ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func newAutoSLOPrometheusServiceLevel(name string, objMeta metav1.ObjectMeta, ownRef metav1.OwnerReference) (*slothv1.PrometheusServiceLevel, error) {
+	instance := slothv1.SLOTemplateInstance{Name: objMeta.Annotations[AutoSLOAnnotationTemplate]}
+	if ns, tplName, ok := strings.Cut(instance.Name, "/"); ok {
+		instance.Namespace = ns
+		instance.Name = tplName
+	}
+	if instance.Name == "" {
+		return nil, fmt.Errorf("%q annotation template name is required", AutoSLOAnnotationTemplate)
+	}
+
+	if objective, ok := objMeta.Annotations[AutoSLOAnnotationObjective]; ok {
+		o, err := strconv.ParseFloat(objective, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q annotation: %w", AutoSLOAnnotationObjective, err)
+		}
+		if o <= 0 || o > 100 {
+			return nil, fmt.Errorf("invalid %q annotation: objective must be in the (0, 100] range", AutoSLOAnnotationObjective)
+		}
+		instance.Objective = &o
+	}
+
+	for k, v := range objMeta.Annotations {
+		param, ok := strings.CutPrefix(k, AutoSLOAnnotationParamPrefix)
+		if !ok {
+			continue
+		}
+		if instance.Params == nil {
+			instance.Params = map[string]string{}
+		}
+		instance.Params[param] = v
+	}
+
+	service := objMeta.Annotations[AutoSLOAnnotationService]
+	if service == "" {
+		service = objMeta.Name
+	}
+
+	// Use the workload labels so the controller label selector also applies to the auto SLOs.
+	labels := maps.Clone(objMeta.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[AutoSLOLabel] = "true"
+
+	return &slothv1.PrometheusServiceLevel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       objMeta.Namespace,
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{ownRef},
+		},
+		Spec: slothv1.PrometheusServiceLevelSpec{
+			Service:   service,
+			Templates: []slothv1.SLOTemplateInstance{instance},
+		},
+	}, nil
+}
+
+func ptrTo[T any](v T) *T { return &v }
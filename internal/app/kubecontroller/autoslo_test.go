@@ -0,0 +1,244 @@
+package kubecontroller_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/app/kubecontroller/kubecontrollermock"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+func getTestAutoSLOService(annotations map[string]string) *corev1.Service {
+	return &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:        "app1",
+		Namespace:   "test-ns",
+		UID:         "test-uid",
+		Labels:      map[string]string{"team": "team1"},
+		Annotations: annotations,
+	}}
+}
+
+func getTestAutoSLOPSL(kind, apiVersion string, instance slothv1.SLOTemplateInstance) *slothv1.PrometheusServiceLevel {
+	controller := true
+	return &slothv1.PrometheusServiceLevel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "service-app1",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "team1", "sloth.dev/auto-slo": "true"},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: apiVersion, Kind: kind, Name: "app1", UID: "test-uid", Controller: &controller},
+			},
+		},
+		Spec: slothv1.PrometheusServiceLevelSpec{
+			Service:   "app1",
+			Templates: []slothv1.SLOTemplateInstance{instance},
+		},
+	}
+}
+
+func TestAutoSLOHandler(t *testing.T) {
+	notFoundErr := kubeerrors.NewNotFound(schema.GroupResource{Group: "sloth.slok.dev", Resource: "prometheusservicelevels"}, "service-app1")
+	objective := 99.5
+
+	tests := map[string]struct {
+		obj      runtime.Object
+		selector labels.Selector
+		mock     func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter)
+		expErr   bool
+	}{
+		"An annotated service should generate its PrometheusServiceLevel.": {
+			obj: getTestAutoSLOService(map[string]string{
+				"sloth.dev/slo-template":      "monitoring/http",
+				"sloth.dev/objective":         "99.5",
+				"sloth.dev/slo-param-job":     "app1",
+				"sloth.dev/slo-param-handler": "/api",
+				"other":                       "annotation",
+			}),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				m.On("GetPrometheusServiceLevel", mock.Anything, "test-ns", "service-app1").Once().Return(nil, notFoundErr)
+				exp := getTestAutoSLOPSL("Service", "v1", slothv1.SLOTemplateInstance{
+					Name:      "http",
+					Namespace: "monitoring",
+					Objective: &objective,
+					Params:    map[string]string{"job": "app1", "handler": "/api"},
+				})
+				m.On("EnsurePrometheusServiceLevel", mock.Anything, exp).Once().Return(nil)
+			},
+		},
+
+		"An annotated deployment should generate its PrometheusServiceLevel.": {
+			obj: &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+				Name:        "app1",
+				Namespace:   "test-ns",
+				UID:         "test-uid",
+				Annotations: map[string]string{"sloth.dev/slo-template": "http", "sloth.dev/service": "svc1"},
+			}},
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				m.On("GetPrometheusServiceLevel", mock.Anything, "test-ns", "deployment-app1").Once().Return(nil, notFoundErr)
+				exp := getTestAutoSLOPSL("Deployment", "apps/v1", slothv1.SLOTemplateInstance{Name: "http"})
+				exp.Name = "deployment-app1"
+				exp.Labels = map[string]string{"sloth.dev/auto-slo": "true"}
+				exp.Spec.Service = "svc1"
+				m.On("EnsurePrometheusServiceLevel", mock.Anything, exp).Once().Return(nil)
+			},
+		},
+
+		"An annotated service with an already updated PrometheusServiceLevel should not update it.": {
+			obj: getTestAutoSLOService(map[string]string{"sloth.dev/slo-template": "http"}),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				stored := getTestAutoSLOPSL("Service", "v1", slothv1.SLOTemplateInstance{Name: "http"})
+				stored.ResourceVersion = "42"
+				m.On("GetPrometheusServiceLevel", mock.Anything, "test-ns", "service-app1").Once().Return(stored, nil)
+			},
+		},
+
+		"An annotated service with a changed PrometheusServiceLevel should update it.": {
+			obj: getTestAutoSLOService(map[string]string{"sloth.dev/slo-template": "http"}),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				stored := getTestAutoSLOPSL("Service", "v1", slothv1.SLOTemplateInstance{Name: "grpc"})
+				m.On("GetPrometheusServiceLevel", mock.Anything, "test-ns", "service-app1").Once().Return(stored, nil)
+				exp := getTestAutoSLOPSL("Service", "v1", slothv1.SLOTemplateInstance{Name: "http"})
+				m.On("EnsurePrometheusServiceLevel", mock.Anything, exp).Once().Return(nil)
+			},
+		},
+
+		"A service without annotations should delete its generated PrometheusServiceLevel.": {
+			obj: getTestAutoSLOService(nil),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				stored := getTestAutoSLOPSL("Service", "v1", slothv1.SLOTemplateInstance{Name: "http"})
+				m.On("GetPrometheusServiceLevel", mock.Anything, "test-ns", "service-app1").Once().Return(stored, nil)
+				m.On("DeletePrometheusServiceLevel", mock.Anything, "test-ns", "service-app1").Once().Return(nil)
+			},
+		},
+
+		"A service without annotations nor generated PrometheusServiceLevel should be ignored.": {
+			obj: getTestAutoSLOService(nil),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				m.On("GetPrometheusServiceLevel", mock.Anything, "test-ns", "service-app1").Once().Return(nil, notFoundErr)
+			},
+		},
+
+		"A service without annotations should not delete PrometheusServiceLevels that are not owned by the service.": {
+			obj: getTestAutoSLOService(nil),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				stored := getTestAutoSLOPSL("Service", "v1", slothv1.SLOTemplateInstance{Name: "http"})
+				stored.OwnerReferences = nil
+				m.On("GetPrometheusServiceLevel", mock.Anything, "test-ns", "service-app1").Once().Return(stored, nil)
+			},
+		},
+
+		"An annotated service should fail if the PrometheusServiceLevel is not owned by the service.": {
+			obj: getTestAutoSLOService(map[string]string{"sloth.dev/slo-template": "http"}),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				stored := getTestAutoSLOPSL("Service", "v1", slothv1.SLOTemplateInstance{Name: "http"})
+				stored.OwnerReferences[0].UID = "other-uid"
+				m.On("GetPrometheusServiceLevel", mock.Anything, "test-ns", "service-app1").Once().Return(stored, nil)
+			},
+			expErr: true,
+		},
+
+		"An annotated service with an invalid objective should fail.": {
+			obj: getTestAutoSLOService(map[string]string{"sloth.dev/slo-template": "http", "sloth.dev/objective": "150"}),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				m.On("GetPrometheusServiceLevel", mock.Anything, "test-ns", "service-app1").Once().Return(nil, notFoundErr)
+			},
+			expErr: true,
+		},
+
+		"An annotated service without template name should fail.": {
+			obj: getTestAutoSLOService(map[string]string{"sloth.dev/slo-template": "monitoring/"}),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				m.On("GetPrometheusServiceLevel", mock.Anything, "test-ns", "service-app1").Once().Return(nil, notFoundErr)
+			},
+			expErr: true,
+		},
+
+		"Failing getting the PrometheusServiceLevel should fail.": {
+			obj: getTestAutoSLOService(map[string]string{"sloth.dev/slo-template": "http"}),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				m.On("GetPrometheusServiceLevel", mock.Anything, "test-ns", "service-app1").Once().Return(nil, fmt.Errorf("something"))
+			},
+			expErr: true,
+		},
+
+		"Failing ensuring the PrometheusServiceLevel should fail.": {
+			obj: getTestAutoSLOService(map[string]string{"sloth.dev/slo-template": "http"}),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				m.On("GetPrometheusServiceLevel", mock.Anything, "test-ns", "service-app1").Once().Return(nil, notFoundErr)
+				m.On("EnsurePrometheusServiceLevel", mock.Anything, mock.Anything).Once().Return(fmt.Errorf("something"))
+			},
+			expErr: true,
+		},
+
+		"Annotated workloads on namespaces that match the namespace selector should generate their PrometheusServiceLevel.": {
+			obj:      getTestAutoSLOService(map[string]string{"sloth.dev/slo-template": "http"}),
+			selector: labels.SelectorFromSet(labels.Set{"sloth": "enabled"}),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns", Labels: map[string]string{"sloth": "enabled"}}}
+				mng.On("GetNamespace", mock.Anything, "test-ns").Once().Return(ns, nil)
+				m.On("GetPrometheusServiceLevel", mock.Anything, "test-ns", "service-app1").Once().Return(nil, notFoundErr)
+				exp := getTestAutoSLOPSL("Service", "v1", slothv1.SLOTemplateInstance{Name: "http"})
+				m.On("EnsurePrometheusServiceLevel", mock.Anything, exp).Once().Return(nil)
+			},
+		},
+
+		"Annotated workloads on namespaces that don't match the namespace selector should be ignored.": {
+			obj:      getTestAutoSLOService(map[string]string{"sloth.dev/slo-template": "http"}),
+			selector: labels.SelectorFromSet(labels.Set{"sloth": "enabled"}),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns", Labels: map[string]string{"sloth": "disabled"}}}
+				mng.On("GetNamespace", mock.Anything, "test-ns").Once().Return(ns, nil)
+			},
+		},
+
+		"Failing getting the workload namespace should fail.": {
+			obj:      getTestAutoSLOService(map[string]string{"sloth.dev/slo-template": "http"}),
+			selector: labels.SelectorFromSet(labels.Set{"sloth": "enabled"}),
+			mock: func(m *kubecontrollermock.AutoSLORepository, mng *kubecontrollermock.NamespaceGetter) {
+				mng.On("GetNamespace", mock.Anything, "test-ns").Once().Return(nil, fmt.Errorf("something"))
+			},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			// Mocks.
+			mr := kubecontrollermock.NewAutoSLORepository(t)
+			mng := kubecontrollermock.NewNamespaceGetter(t)
+			test.mock(mr, mng)
+
+			// Execute.
+			h, err := kubecontroller.NewAutoSLOHandler(kubecontroller.AutoSLOHandlerConfig{
+				Repository:             mr,
+				NamespaceGetter:        mng,
+				NamespaceLabelSelector: test.selector,
+			})
+			require.NoError(err)
+			err = h.Handle(context.TODO(), test.obj)
+
+			// Check.
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package kubecontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/slok/sloth/internal/http/backend/model"
+	"github.com/slok/sloth/internal/http/backend/storage"
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// SLOBudgetGetter knows how to get the live SLO error budget details (e.g: from Prometheus).
+type SLOBudgetGetter interface {
+	ListSLOInstantDetails(ctx context.Context) ([]storage.SLOInstantDetails, error)
+}
+
+// BudgetStatusUpdaterConfig is the budget status updater configuration.
+type BudgetStatusUpdaterConfig struct {
+	SLOBudgetGetter    SLOBudgetGetter
+	ServiceLevelLister ServiceLevelLister
+	KubeStatusStorer   KubeStatusStorer
+	// Namespace is the namespace of the updated PrometheusServiceLevels, by default all.
+	Namespace string
+	// LabelSelector is the label selector of the updated service levels.
+	LabelSelector labels.Selector
+	// DisableClusterServiceLevels disables the update of the ClusterPrometheusServiceLevels.
+	DisableClusterServiceLevels bool
+	// RefreshInterval is the interval between the status updates.
+	RefreshInterval time.Duration
+	TimeNowFunc     func() time.Time // Used for faking time in testing.
+	Logger          log.Logger
+}
+
+func (c *BudgetStatusUpdaterConfig) defaults() error {
+	if c.SLOBudgetGetter == nil {
+		return fmt.Errorf("SLO budget getter is required")
+	}
+
+	if c.ServiceLevelLister == nil {
+		return fmt.Errorf("service level lister is required")
+	}
+
+	if c.KubeStatusStorer == nil {
+		return fmt.Errorf("kubernetes status storer is required")
+	}
+
+	if c.LabelSelector == nil {
+		c.LabelSelector = labels.Everything()
+	}
+
+	if c.RefreshInterval < 1*time.Minute {
+		c.RefreshInterval = 1 * time.Minute
+	}
+
+	if c.TimeNowFunc == nil {
+		c.TimeNowFunc = time.Now
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "kubecontroller.BudgetStatusUpdater"})
+
+	return nil
+}
+
+// BudgetStatusUpdater sets the live error budget of the SLOs on the service levels status, so the
+// SLOs health can be checked with kubectl.
+type BudgetStatusUpdater struct {
+	budgetGetter    SLOBudgetGetter
+	slLister        ServiceLevelLister
+	statusStorer    KubeStatusStorer
+	namespace       string
+	labelSelector   labels.Selector
+	disableCluster  bool
+	refreshInterval time.Duration
+	timeNowFunc     func() time.Time
+	logger          log.Logger
+}
+
+// NewBudgetStatusUpdater returns a new budget status updater.
+func NewBudgetStatusUpdater(config BudgetStatusUpdaterConfig) (*BudgetStatusUpdater, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &BudgetStatusUpdater{
+		budgetGetter:    config.SLOBudgetGetter,
+		slLister:        config.ServiceLevelLister,
+		statusStorer:    config.KubeStatusStorer,
+		namespace:       config.Namespace,
+		labelSelector:   config.LabelSelector,
+		disableCluster:  config.DisableClusterServiceLevels,
+		refreshInterval: config.RefreshInterval,
+		timeNowFunc:     config.TimeNowFunc,
+		logger:          config.Logger,
+	}, nil
+}
+
+// Run updates the service levels status on every refresh interval until the context is done.
+func (u *BudgetStatusUpdater) Run(ctx context.Context) error {
+	for {
+		err := u.UpdateStatuses(ctx)
+		if err != nil {
+			u.logger.Errorf("Could not update SLO budgets status: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(u.refreshInterval):
+		}
+	}
+}
+
+// UpdateStatuses sets the current SLO error budgets on the status of the service levels, the service
+// levels that fail to update don't stop the update of the rest.
+//
+// Be aware that updating the status will trigger a watch update event on the service levels controller, the
+// service levels handler ignores these budgets only status updates.
+func (u *BudgetStatusUpdater) UpdateStatuses(ctx context.Context) error {
+	details, err := u.budgetGetter.ListSLOInstantDetails(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list SLO budget details: %w", err)
+	}
+	budgets := newSLOBudgetsIndex(details)
+	now := u.timeNowFunc().UTC()
+	opts := metav1.ListOptions{LabelSelector: u.labelSelector.String()}
+
+	var errs []error
+
+	psls, err := u.slLister.ListPrometheusServiceLevels(ctx, u.namespace, opts)
+	if err != nil {
+		return fmt.Errorf("could not list PrometheusServiceLevels: %w", err)
+	}
+	for i := range psls.Items {
+		psl := &psls.Items[i]
+		status, ok := newBudgetStatus(psl.Spec.Service, psl.Status, budgets, now)
+		if !ok {
+			continue
+		}
+		err := u.statusStorer.EnsurePrometheusServiceLevelStatus(ctx, psl, status)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not update %s/%s PrometheusServiceLevel status: %w", psl.Namespace, psl.Name, err))
+		}
+	}
+
+	if !u.disableCluster {
+		cpsls, err := u.slLister.ListClusterPrometheusServiceLevels(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("could not list ClusterPrometheusServiceLevels: %w", err)
+		}
+		for i := range cpsls.Items {
+			cpsl := &cpsls.Items[i]
+			status, ok := newBudgetStatus(cpsl.Spec.Service, cpsl.Status, budgets, now)
+			if !ok {
+				continue
+			}
+			err := u.statusStorer.EnsureClusterPrometheusServiceLevelStatus(ctx, cpsl, status)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("could not update %s ClusterPrometheusServiceLevel status: %w", cpsl.Name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sloBudget is the live error budget of an SLO, grouped SLOs are merged using the worst values.
+type sloBudget struct {
+	burnRate        float64
+	remainingBudget float64
+	firingAlerts    []string
+}
+
+// newSLOBudgetsIndex returns the SLO budgets indexed by service and SLO name.
+func newSLOBudgetsIndex(details []storage.SLOInstantDetails) map[[2]string]sloBudget {
+	budgets := map[[2]string]sloBudget{}
+	for _, d := range details {
+		burnRate := d.BudgetDetails.BurningBudgetPercent / 100
+		remaining := 100 - d.BudgetDetails.BurnedBudgetWindowPercent
+		if math.IsNaN(burnRate) || math.IsInf(burnRate, 0) || math.IsNaN(remaining) || math.IsInf(remaining, 0) {
+			continue
+		}
+
+		key := [2]string{d.SLO.ServiceID, d.SLO.Name}
+		b, ok := budgets[key]
+		if !ok {
+			b = sloBudget{burnRate: burnRate, remainingBudget: remaining}
+		}
+		b.burnRate = math.Max(b.burnRate, burnRate)
+		b.remainingBudget = math.Min(b.remainingBudget, remaining)
+		for _, a := range []string{alertName(d.Alerts.FiringPage), alertName(d.Alerts.FiringWarning)} {
+			if a != "" && !slices.Contains(b.firingAlerts, a) {
+				b.firingAlerts = append(b.firingAlerts, a)
+			}
+		}
+		slices.Sort(b.firingAlerts)
+		budgets[key] = b
+	}
+
+	return budgets
+}
+
+// newBudgetStatus returns the service level status with the live SLO error budgets, it returns false
+// if the status doesn't need to be updated.
+func newBudgetStatus(service string, current slothv1.PrometheusServiceLevelStatus, budgets map[[2]string]sloBudget, now time.Time) (slothv1.PrometheusServiceLevelStatus, bool) {
+	status := *current.DeepCopy()
+	burning, alerting, found := 0, 0, 0
+	for i, s := range status.SLOs {
+		b, ok := budgets[[2]string{service, s.Name}]
+		if !ok {
+			status.SLOs[i].BurnRate = nil
+			status.SLOs[i].RemainingBudgetPercent = nil
+			status.SLOs[i].FiringAlerts = nil
+			continue
+		}
+
+		found++
+		status.SLOs[i].BurnRate = ptrTo(roundBudget(b.burnRate))
+		status.SLOs[i].RemainingBudgetPercent = ptrTo(roundBudget(b.remainingBudget))
+		status.SLOs[i].FiringAlerts = b.firingAlerts
+		if b.burnRate > 1 {
+			burning++
+		}
+		if len(b.firingAlerts) > 0 {
+			alerting++
+		}
+	}
+
+	// Clean the stale budgets of the service levels without budget details, ignore if already clean.
+	if found == 0 {
+		status.SLOsBurningOverBudget = nil
+		status.SLOsWithFiringAlerts = nil
+		status.LastBudgetsUpdate = nil
+		return status, current.LastBudgetsUpdate != nil
+	}
+
+	status.SLOsBurningOverBudget = &burning
+	status.SLOsWithFiringAlerts = &alerting
+	status.LastBudgetsUpdate = &metav1.Time{Time: now}
+
+	return status, true
+}
+
+func alertName(a *model.Alert) string {
+	if a == nil {
+		return ""
+	}
+	return a.Name
+}
+
+// roundBudget rounds the budget values to 2 decimals, enough to check the SLOs health.
+func roundBudget(v float64) float64 {
+	return math.Round(v*100) / 100
+}
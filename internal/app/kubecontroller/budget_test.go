@@ -0,0 +1,199 @@
+package kubecontroller_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/app/kubecontroller/kubecontrollermock"
+	"github.com/slok/sloth/internal/http/backend/model"
+	"github.com/slok/sloth/internal/http/backend/storage"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+func ptrTo[T any](v T) *T { return &v }
+
+func TestBudgetStatusUpdater(t *testing.T) {
+	psl := func(name, service string, status slothv1.PrometheusServiceLevelStatus) slothv1.PrometheusServiceLevel {
+		return slothv1.PrometheusServiceLevel{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns"},
+			Spec:       slothv1.PrometheusServiceLevelSpec{Service: service},
+			Status:     status,
+		}
+	}
+	details := func(service, slo string, burning, burned float64, alerts model.SLOAlerts) storage.SLOInstantDetails {
+		return storage.SLOInstantDetails{
+			SLO:           model.SLO{ServiceID: service, Name: slo},
+			BudgetDetails: model.SLOBudgetDetails{BurningBudgetPercent: burning, BurnedBudgetWindowPercent: burned},
+			Alerts:        alerts,
+		}
+	}
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tNow := metav1.NewTime(now)
+
+	tests := map[string]struct {
+		disableCluster bool
+		mock           func(mb *kubecontrollermock.SLOBudgetGetter, ml *kubecontrollermock.ServiceLevelLister, mss *kubecontrollermock.KubeStatusStorer)
+		expErr         bool
+	}{
+		"The service levels status should be updated with the SLOs live error budgets.": {
+			mock: func(mb *kubecontrollermock.SLOBudgetGetter, ml *kubecontrollermock.ServiceLevelLister, mss *kubecontrollermock.KubeStatusStorer) {
+				mb.On("ListSLOInstantDetails", mock.Anything).Once().Return([]storage.SLOInstantDetails{
+					details("svc1", "slo1", 250, 40.123, model.SLOAlerts{FiringWarning: &model.Alert{Name: "Slo1Ticket"}}),
+					details("svc1", "slo2", 50, 10, model.SLOAlerts{}),
+					details("svc2", "slo1", 300, 110, model.SLOAlerts{FiringPage: &model.Alert{Name: "Slo1Page"}}),
+				}, nil)
+
+				status := slothv1.PrometheusServiceLevelStatus{
+					ProcessedSLOs: 3,
+					SLOs:          []slothv1.SLOStatus{{Name: "slo1"}, {Name: "slo2"}, {Name: "slo3", BurnRate: ptrTo(1.0)}},
+				}
+				ml.On("ListPrometheusServiceLevels", mock.Anything, "", metav1.ListOptions{}).Once().Return(&slothv1.PrometheusServiceLevelList{
+					Items: []slothv1.PrometheusServiceLevel{
+						psl("psl1", "svc1", status),
+						psl("psl2", "svc3", slothv1.PrometheusServiceLevelStatus{SLOs: []slothv1.SLOStatus{{Name: "slo1"}}}),
+					},
+				}, nil)
+				ml.On("ListClusterPrometheusServiceLevels", mock.Anything, metav1.ListOptions{}).Once().Return(&slothv1.ClusterPrometheusServiceLevelList{
+					Items: []slothv1.ClusterPrometheusServiceLevel{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "cpsl1"},
+							Spec:       slothv1.ClusterPrometheusServiceLevelSpec{PrometheusServiceLevelSpec: slothv1.PrometheusServiceLevelSpec{Service: "svc2"}},
+							Status:     slothv1.PrometheusServiceLevelStatus{SLOs: []slothv1.SLOStatus{{Name: "slo1"}}},
+						},
+					},
+				}, nil)
+
+				expPSL := psl("psl1", "svc1", status)
+				expStatus := slothv1.PrometheusServiceLevelStatus{
+					ProcessedSLOs: 3,
+					SLOs: []slothv1.SLOStatus{
+						{Name: "slo1", BurnRate: ptrTo(2.5), RemainingBudgetPercent: ptrTo(59.88), FiringAlerts: []string{"Slo1Ticket"}},
+						{Name: "slo2", BurnRate: ptrTo(0.5), RemainingBudgetPercent: ptrTo(90.0)},
+						{Name: "slo3"},
+					},
+					SLOsBurningOverBudget: ptrTo(1),
+					SLOsWithFiringAlerts:  ptrTo(1),
+					LastBudgetsUpdate:     &tNow,
+				}
+				mss.On("EnsurePrometheusServiceLevelStatus", mock.Anything, &expPSL, expStatus).Once().Return(nil)
+
+				expCStatus := slothv1.PrometheusServiceLevelStatus{
+					SLOs:                  []slothv1.SLOStatus{{Name: "slo1", BurnRate: ptrTo(3.0), RemainingBudgetPercent: ptrTo(-10.0), FiringAlerts: []string{"Slo1Page"}}},
+					SLOsBurningOverBudget: ptrTo(1),
+					SLOsWithFiringAlerts:  ptrTo(1),
+					LastBudgetsUpdate:     &tNow,
+				}
+				mss.On("EnsureClusterPrometheusServiceLevelStatus", mock.Anything, mock.Anything, expCStatus).Once().Return(nil)
+			},
+		},
+
+		"Grouped SLOs should set the worst error budget values.": {
+			disableCluster: true,
+			mock: func(mb *kubecontrollermock.SLOBudgetGetter, ml *kubecontrollermock.ServiceLevelLister, mss *kubecontrollermock.KubeStatusStorer) {
+				mb.On("ListSLOInstantDetails", mock.Anything).Once().Return([]storage.SLOInstantDetails{
+					details("svc1", "slo1", 50, 90, model.SLOAlerts{FiringPage: &model.Alert{Name: "Slo1"}}),
+					details("svc1", "slo1", 200, 10, model.SLOAlerts{FiringPage: &model.Alert{Name: "Slo1"}, FiringWarning: &model.Alert{Name: "Slo1"}}),
+					details("svc1", "slo1", math.NaN(), 20, model.SLOAlerts{}),
+				}, nil)
+
+				status := slothv1.PrometheusServiceLevelStatus{SLOs: []slothv1.SLOStatus{{Name: "slo1"}}}
+				ml.On("ListPrometheusServiceLevels", mock.Anything, "", metav1.ListOptions{}).Once().Return(&slothv1.PrometheusServiceLevelList{
+					Items: []slothv1.PrometheusServiceLevel{psl("psl1", "svc1", status)},
+				}, nil)
+
+				expStatus := slothv1.PrometheusServiceLevelStatus{
+					SLOs:                  []slothv1.SLOStatus{{Name: "slo1", BurnRate: ptrTo(2.0), RemainingBudgetPercent: ptrTo(10.0), FiringAlerts: []string{"Slo1"}}},
+					SLOsBurningOverBudget: ptrTo(1),
+					SLOsWithFiringAlerts:  ptrTo(1),
+					LastBudgetsUpdate:     &tNow,
+				}
+				mss.On("EnsurePrometheusServiceLevelStatus", mock.Anything, mock.Anything, expStatus).Once().Return(nil)
+			},
+		},
+
+		"Service levels that don't have SLO error budgets anymore should be cleaned.": {
+			disableCluster: true,
+			mock: func(mb *kubecontrollermock.SLOBudgetGetter, ml *kubecontrollermock.ServiceLevelLister, mss *kubecontrollermock.KubeStatusStorer) {
+				mb.On("ListSLOInstantDetails", mock.Anything).Once().Return([]storage.SLOInstantDetails{}, nil)
+
+				status := slothv1.PrometheusServiceLevelStatus{
+					SLOs:                  []slothv1.SLOStatus{{Name: "slo1", BurnRate: ptrTo(2.0), RemainingBudgetPercent: ptrTo(10.0)}},
+					SLOsBurningOverBudget: ptrTo(1),
+					SLOsWithFiringAlerts:  ptrTo(0),
+					LastBudgetsUpdate:     &tNow,
+				}
+				ml.On("ListPrometheusServiceLevels", mock.Anything, "", metav1.ListOptions{}).Once().Return(&slothv1.PrometheusServiceLevelList{
+					Items: []slothv1.PrometheusServiceLevel{psl("psl1", "svc1", status)},
+				}, nil)
+
+				expStatus := slothv1.PrometheusServiceLevelStatus{SLOs: []slothv1.SLOStatus{{Name: "slo1"}}}
+				mss.On("EnsurePrometheusServiceLevelStatus", mock.Anything, mock.Anything, expStatus).Once().Return(nil)
+			},
+		},
+
+		"Failing getting the SLO error budgets should fail.": {
+			mock: func(mb *kubecontrollermock.SLOBudgetGetter, ml *kubecontrollermock.ServiceLevelLister, mss *kubecontrollermock.KubeStatusStorer) {
+				mb.On("ListSLOInstantDetails", mock.Anything).Once().Return(nil, fmt.Errorf("something"))
+			},
+			expErr: true,
+		},
+
+		"Failing updating a service level status should update the rest and fail.": {
+			disableCluster: true,
+			mock: func(mb *kubecontrollermock.SLOBudgetGetter, ml *kubecontrollermock.ServiceLevelLister, mss *kubecontrollermock.KubeStatusStorer) {
+				mb.On("ListSLOInstantDetails", mock.Anything).Once().Return([]storage.SLOInstantDetails{
+					details("svc1", "slo1", 50, 10, model.SLOAlerts{}),
+				}, nil)
+
+				status := slothv1.PrometheusServiceLevelStatus{SLOs: []slothv1.SLOStatus{{Name: "slo1"}}}
+				ml.On("ListPrometheusServiceLevels", mock.Anything, "", metav1.ListOptions{}).Once().Return(&slothv1.PrometheusServiceLevelList{
+					Items: []slothv1.PrometheusServiceLevel{psl("psl1", "svc1", status), psl("psl2", "svc1", status)},
+				}, nil)
+
+				mss.On("EnsurePrometheusServiceLevelStatus", mock.Anything, mock.Anything, mock.Anything).Once().Return(fmt.Errorf("something"))
+				mss.On("EnsurePrometheusServiceLevelStatus", mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+			},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			// Mocks.
+			mb := kubecontrollermock.NewSLOBudgetGetter(t)
+			ml := kubecontrollermock.NewServiceLevelLister(t)
+			mss := kubecontrollermock.NewKubeStatusStorer(t)
+			test.mock(mb, ml, mss)
+
+			// Execute.
+			u, err := kubecontroller.NewBudgetStatusUpdater(kubecontroller.BudgetStatusUpdaterConfig{
+				SLOBudgetGetter:             mb,
+				ServiceLevelLister:          ml,
+				KubeStatusStorer:            mss,
+				DisableClusterServiceLevels: test.disableCluster,
+				TimeNowFunc:                 func() time.Time { return now },
+			})
+			require.NoError(err)
+			err = u.UpdateStatuses(context.TODO())
+
+			// Check.
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
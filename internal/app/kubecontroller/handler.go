@@ -2,15 +2,25 @@ package kubecontroller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/spotahome/kooper/v2/controller"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/slok/sloth/internal/app/generate"
 	"github.com/slok/sloth/internal/info"
 	"github.com/slok/sloth/internal/log"
+	plugincorevalidatev1 "github.com/slok/sloth/internal/plugin/slo/core/validate_v1"
+	"github.com/slok/sloth/internal/slotemplate"
 	commonmodel "github.com/slok/sloth/pkg/common/model"
 
 	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
@@ -19,6 +29,7 @@ import (
 // SpecLoader Knows how to load a Kubernetes Spec into an app model.
 type SpecLoader interface {
 	LoadSpec(ctx context.Context, spec *slothv1.PrometheusServiceLevel) (*commonmodel.PromSLOGroup, error)
+	LoadClusterSpec(ctx context.Context, spec *slothv1.ClusterPrometheusServiceLevel) (*commonmodel.PromSLOGroup, error)
 }
 
 // Generator Knows how to generate SLO prometheus rules from app SLO model.
@@ -33,16 +44,50 @@ type Repository interface {
 
 // KubeStatusStorer knows how to set the status of Prometheus service levels Kubernetes CRD.
 type KubeStatusStorer interface {
-	EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, err error) error
+	EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, status slothv1.PrometheusServiceLevelStatus) error
+	EnsureClusterPrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.ClusterPrometheusServiceLevel, status slothv1.PrometheusServiceLevelStatus) error
 }
 
+// NamespaceGetter knows how to get Kubernetes namespaces.
+type NamespaceGetter interface {
+	GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error)
+}
+
+// TemplateGetter knows how to get the PrometheusServiceLevelTemplates used by the service levels.
+type TemplateGetter interface {
+	GetPrometheusServiceLevelTemplate(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevelTemplate, error)
+}
+
+// EventRecorder knows how to record Kubernetes events on the handled Kubernetes objects.
+type EventRecorder interface {
+	Eventf(obj runtime.Object, eventType, reason, messageFmt string, args ...interface{})
+}
+
+// Kubernetes event reasons recorded on the handled PrometheusServiceLevels.
+const (
+	EventReasonRulesGenerated     = "RulesGenerated"
+	EventReasonInvalidSpec        = "InvalidSpec"
+	EventReasonInvalidSLO         = "InvalidSLO"
+	EventReasonInvalidTemplate    = "InvalidTemplate"
+	EventReasonPluginFailed       = "PluginFailed"
+	EventReasonGenerationFailed   = "GenerationFailed"
+	EventReasonRulesApplyConflict = "RulesApplyConflict"
+	EventReasonRulesApplyFailed   = "RulesApplyFailed"
+)
+
 // HandlerConfig is the controller handler configuration.
 type HandlerConfig struct {
 	Generator        Generator
 	SpecLoader       SpecLoader
 	Repository       Repository
 	KubeStatusStorer KubeStatusStorer
-	ExtraLabels      map[string]string
+	EventRecorder    EventRecorder
+	NamespaceGetter  NamespaceGetter
+	TemplateGetter   TemplateGetter
+	// NamespaceLabelSelector makes the handler only handle the PrometheusServiceLevels of the namespaces
+	// that match the selector. ClusterPrometheusServiceLevels are not affected by this selector.
+	NamespaceLabelSelector labels.Selector
+	ExtraLabels            map[string]string
 	// IgnoreHandleBefore makes the handles of objects with a success state and no spec change,
 	// be ignored if the last success is less than this setting.
 	// Be aware that this setting should be less than the controller resync interval.
@@ -63,6 +108,22 @@ func (c *HandlerConfig) defaults() error {
 		return fmt.Errorf("kubernetes status storer is required")
 	}
 
+	if c.EventRecorder == nil {
+		return fmt.Errorf("kubernetes event recorder is required")
+	}
+
+	if c.TemplateGetter == nil {
+		return fmt.Errorf("template getter is required")
+	}
+
+	if c.NamespaceLabelSelector == nil {
+		c.NamespaceLabelSelector = labels.Everything()
+	}
+
+	if !c.NamespaceLabelSelector.Empty() && c.NamespaceGetter == nil {
+		return fmt.Errorf("namespace getter is required when using a namespace label selector")
+	}
+
 	if c.ExtraLabels == nil {
 		c.ExtraLabels = map[string]string{}
 	}
@@ -88,8 +149,15 @@ type handler struct {
 	generator          Generator
 	repository         Repository
 	kubeStatusStorer   KubeStatusStorer
+	eventRecorder      EventRecorder
+	namespaceGetter    NamespaceGetter
+	namespaceSelector  labels.Selector
+	templateRenderer   slotemplate.Renderer
 	extraLabels        map[string]string
 	ignoreHandleBefore time.Duration
+	// lastBudgetsUpdates are the last seen budgets update time of the service levels, used to
+	// detect the status updates made by the budget status updater.
+	lastBudgetsUpdates *sync.Map
 	logger             log.Logger
 }
 
@@ -103,8 +171,13 @@ func NewHandler(config HandlerConfig) (controller.Handler, error) {
 		generator:          config.Generator,
 		repository:         config.Repository,
 		kubeStatusStorer:   config.KubeStatusStorer,
+		eventRecorder:      config.EventRecorder,
+		namespaceGetter:    config.NamespaceGetter,
+		namespaceSelector:  config.NamespaceLabelSelector,
+		templateRenderer:   slotemplate.NewRenderer(config.TemplateGetter),
 		extraLabels:        config.ExtraLabels,
 		ignoreHandleBefore: config.IgnoreHandleBefore,
+		lastBudgetsUpdates: &sync.Map{},
 		logger:             config.Logger,
 	}, nil
 }
@@ -113,6 +186,8 @@ func (h handler) Handle(ctx context.Context, obj runtime.Object) error {
 	switch v := obj.(type) {
 	case *slothv1.PrometheusServiceLevel:
 		return h.handlePrometheusServiceLevelV1(ctx, v)
+	case *slothv1.ClusterPrometheusServiceLevel:
+		return h.handleClusterPrometheusServiceLevelV1(ctx, v)
 	default:
 		h.logger.Warningf("Unsuported Kubernetes object type: %s", obj.GetObjectKind())
 	}
@@ -120,30 +195,146 @@ func (h handler) Handle(ctx context.Context, obj runtime.Object) error {
 	return nil
 }
 
-func (h handler) handlePrometheusServiceLevelV1(ctx context.Context, psl *slothv1.PrometheusServiceLevel) (err error) {
+// serviceLevel is the common view of the handled service level Kubernetes objects, the namespaced and the
+// cluster scoped ones share the same spec, status and handling process.
+type serviceLevel struct {
+	kind    string
+	obj     runtime.Object
+	objMeta metav1.ObjectMeta
+	spec    slothv1.PrometheusServiceLevelSpec
+	status  slothv1.PrometheusServiceLevelStatus
+	kmeta   commonmodel.K8sMeta
+	// templatesNamespace is the namespace of the SLO templates referenced without namespace.
+	templatesNamespace string
+	// templates are the SLO templates used to render the spec (set once rendered).
+	templates   []slothv1.SLOTemplateStatus
+	loadSpec    func(ctx context.Context, spec slothv1.PrometheusServiceLevelSpec) (*commonmodel.PromSLOGroup, error)
+	storeStatus func(ctx context.Context, status slothv1.PrometheusServiceLevelStatus) error
+}
+
+func (h handler) handlePrometheusServiceLevelV1(ctx context.Context, psl *slothv1.PrometheusServiceLevel) error {
 	ctx = h.logger.SetValuesOnCtx(ctx, log.Kv{"ns": psl.Namespace, "name": psl.Name})
+
+	selected, err := namespaceSelected(ctx, h.namespaceGetter, h.namespaceSelector, psl.Namespace)
+	if err != nil {
+		return fmt.Errorf("could not check if the namespace is selected: %w", err)
+	}
+	if !selected {
+		h.logger.WithCtxValues(ctx).Debugf("Ignoring object due to %q", "namespace not selected")
+		return nil
+	}
+
+	return h.handleServiceLevel(ctx, serviceLevel{
+		kind:    "PrometheusServiceLevel",
+		obj:     psl,
+		objMeta: psl.ObjectMeta,
+		spec:    psl.Spec,
+		status:  psl.Status,
+		kmeta: commonmodel.K8sMeta{
+			Name:        psl.Name,
+			Namespace:   psl.Namespace,
+			Labels:      psl.Labels,
+			Annotations: psl.Annotations,
+		},
+		templatesNamespace: psl.Namespace,
+		loadSpec: func(ctx context.Context, spec slothv1.PrometheusServiceLevelSpec) (*commonmodel.PromSLOGroup, error) {
+			rendered := psl.DeepCopy()
+			rendered.Spec = spec
+			return h.specLoader.LoadSpec(ctx, rendered)
+		},
+		storeStatus: func(ctx context.Context, status slothv1.PrometheusServiceLevelStatus) error {
+			return h.kubeStatusStorer.EnsurePrometheusServiceLevelStatus(ctx, psl, status)
+		},
+	})
+}
+
+func (h handler) handleClusterPrometheusServiceLevelV1(ctx context.Context, cpsl *slothv1.ClusterPrometheusServiceLevel) error {
+	ctx = h.logger.SetValuesOnCtx(ctx, log.Kv{"name": cpsl.Name, "target-ns": cpsl.Spec.TargetNamespace})
+
+	return h.handleServiceLevel(ctx, serviceLevel{
+		kind:    "ClusterPrometheusServiceLevel",
+		obj:     cpsl,
+		objMeta: cpsl.ObjectMeta,
+		spec:    cpsl.Spec.PrometheusServiceLevelSpec,
+		status:  cpsl.Status,
+		// The generated rules are stored on the target namespace.
+		kmeta: commonmodel.K8sMeta{
+			Name:        cpsl.Name,
+			Namespace:   cpsl.Spec.TargetNamespace,
+			Labels:      cpsl.Labels,
+			Annotations: cpsl.Annotations,
+		},
+		templatesNamespace: cpsl.Spec.TargetNamespace,
+		loadSpec: func(ctx context.Context, spec slothv1.PrometheusServiceLevelSpec) (*commonmodel.PromSLOGroup, error) {
+			rendered := cpsl.DeepCopy()
+			rendered.Spec.PrometheusServiceLevelSpec = spec
+			return h.specLoader.LoadClusterSpec(ctx, rendered)
+		},
+		storeStatus: func(ctx context.Context, status slothv1.PrometheusServiceLevelStatus) error {
+			return h.kubeStatusStorer.EnsureClusterPrometheusServiceLevelStatus(ctx, cpsl, status)
+		},
+	})
+}
+
+// namespaceSelected returns if the namespace labels match the namespace label selector.
+func namespaceSelected(ctx context.Context, getter NamespaceGetter, selector labels.Selector, name string) (bool, error) {
+	if selector.Empty() {
+		return true, nil
+	}
+
+	ns, err := getter.GetNamespace(ctx, name)
+	if err != nil {
+		// If the namespace is gone, its objects will be gone soon.
+		if kubeerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+func (h handler) handleServiceLevel(ctx context.Context, sl serviceLevel) (err error) {
 	logger := h.logger.WithCtxValues(ctx)
 
-	ignoreReason, ignore := h.ignoreHandlePrometheusServiceLevelV1(ctx, psl)
+	// Render the SLO templates before checking if we need to handle the object, the template
+	// changes need to be handled although the object didn't change.
+	renderErr := h.renderTemplates(ctx, &sl)
+
+	ignoreReason, ignore := h.ignoreHandleServiceLevel(ctx, sl, renderErr)
 	if ignore {
 		logger.Debugf("Ignoring object due to %q", ignoreReason)
 		return nil
 	}
 
-	// Store the status with the result of the handling process every time we
-	// process a CR.
+	// Store the status and record the event with the result of the handling process
+	// every time we process a CR.
+	hr := handleResult{}
 	defer func() {
-		storedErr := h.kubeStatusStorer.EnsurePrometheusServiceLevelStatus(ctx, psl, err)
+		hr.err = err
+		status := newPrometheusServiceLevelStatus(sl, hr, time.Now().UTC())
+		storedErr := sl.storeStatus(ctx, status)
 		if storedErr != nil {
-			logger.Errorf("Could not set PrometheusServiceLevel CRD status: %s", storedErr)
+			logger.Errorf("Could not set %s CRD status: %s", sl.kind, storedErr)
 		}
+		h.recordServiceLevelEvent(sl.obj, hr)
 	}()
 
+	if renderErr != nil {
+		hr.failedReason = slothv1.ConditionReasonInvalidTemplate
+		return fmt.Errorf("could not render SLO templates: %w", renderErr)
+	}
+
 	// Load From CRD to model.
-	model, err := h.specLoader.LoadSpec(ctx, psl)
+	model, err := sl.loadSpec(ctx, sl.spec)
 	if err != nil {
+		hr.failedReason = slothv1.ConditionReasonInvalidSpec
 		return fmt.Errorf("could not load CR spec into model: %w", err)
 	}
+	hr.sloNames = map[string]string{}
+	for _, s := range model.SLOs {
+		hr.sloNames[s.ID] = s.Name
+	}
 
 	// Generate rules.
 	req := generate.Request{
@@ -157,12 +348,19 @@ func (h handler) handlePrometheusServiceLevelV1(ctx context.Context, psl *slothv
 	}
 	resp, err := h.generator.Generate(ctx, req)
 	if err != nil {
+		hr.failedReason = slothv1.ConditionReasonGenerationFailed
+		if errors.As(err, &hr.sloErr) && hr.sloErr.PluginID == plugincorevalidatev1.PluginID {
+			hr.failedReason = slothv1.ConditionReasonInvalidSLO
+		}
 		return fmt.Errorf("could not generate SLOs: %w", err)
 	}
+	hr.sloResults = resp.PrometheusSLOs
+	hr.extraRules = resp.ExtraRules
 
 	// Store on k8s as Prometheus operator Rules.
 	sloResult := commonmodel.PromSLOGroupResult{
 		OriginalSource: model.OriginalSource,
+		ExtraRules:     resp.ExtraRules,
 	}
 	for _, s := range resp.PrometheusSLOs {
 		sloResult.SLOResults = append(sloResult.SLOResults, commonmodel.PromSLOResult{
@@ -171,28 +369,52 @@ func (h handler) handlePrometheusServiceLevelV1(ctx context.Context, psl *slothv
 		})
 	}
 
-	kmeta := commonmodel.K8sMeta{
-		Name:        model.OriginalSource.K8sSlothV1.Name,
-		Namespace:   model.OriginalSource.K8sSlothV1.Namespace,
-		Labels:      model.OriginalSource.K8sSlothV1.Labels,
-		Annotations: model.OriginalSource.K8sSlothV1.Annotations,
+	err = h.repository.StoreSLOs(ctx, sl.kmeta, sloResult)
+	if err != nil {
+		hr.failedReason = slothv1.ConditionReasonRulesApplyFailed
+		return fmt.Errorf("could not store SLOs: %w", err)
 	}
 
-	err = h.repository.StoreSLOs(ctx, kmeta, sloResult)
+	return nil
+}
+
+// renderTemplates renders the SLO templates of the service level spec, replacing the spec with the
+// rendered one and setting the used templates.
+func (h handler) renderTemplates(ctx context.Context, sl *serviceLevel) error {
+	res, err := h.templateRenderer.Render(ctx, sl.templatesNamespace, sl.spec)
 	if err != nil {
-		return fmt.Errorf("could not store SLOs: %w", err)
+		return err
+	}
+
+	sl.spec = res.Spec
+	sl.templates = nil
+	for _, tpl := range res.Templates {
+		tplStatus := slothv1.SLOTemplateStatus{Name: tpl.Name, Namespace: tpl.Namespace, ObservedGeneration: tpl.Generation}
+		if !slices.Contains(sl.templates, tplStatus) {
+			sl.templates = append(sl.templates, tplStatus)
+		}
 	}
 
 	return nil
 }
 
-func (h handler) ignoreHandlePrometheusServiceLevelV1(ctx context.Context, psl *slothv1.PrometheusServiceLevel) (reason string, ignore bool) {
+func (h handler) ignoreHandleServiceLevel(ctx context.Context, sl serviceLevel, renderErr error) (reason string, ignore bool) {
 	// If the received object is being deleted, ignore.
-	deleteInProgress := !psl.DeletionTimestamp.IsZero()
+	deleteInProgress := !sl.objMeta.DeletionTimestamp.IsZero()
 	if deleteInProgress {
+		h.lastBudgetsUpdates.Delete(serviceLevelKey(sl))
 		return "deletion in progress", true
 	}
 
+	// Track the budgets update on every event, so we know if the next event is a budgets status update.
+	budgetsUpdated := h.trackBudgetsUpdate(sl)
+
+	// The SLO template errors need to be handled, the templates can be missing or invalid
+	// although the object didn't change.
+	if renderErr != nil {
+		return "", false
+	}
+
 	// If we received an update event not because of an spec change but because of an status change
 	// we need to break the loop because if we continue with the handling most likely that will update
 	// the status (and we will end here again on the next controller event).
@@ -200,13 +422,181 @@ func (h handler) ignoreHandlePrometheusServiceLevelV1(ctx context.Context, psl *
 	// however if we are in a success state we have a changing field (a timestamp), so to break these loops
 	// we will check some conditions so we can ignore it:
 	// - The generation of the status is the same as the one in the metadata: Means the spec didn't change.
+	// - The generation of the used SLO templates is the same as the one in the status: Means the templates didn't change.
 	// - The status is ok: Means is not a retry because of an error.
 	// - The status success TS is less than a duration: Means that if we just updated the success state we break the inmediate loop.
-	if psl.Generation == psl.Status.ObservedGeneration &&
-		psl.Status.PromOpRulesGenerated &&
-		time.Since(psl.Status.LastPromOpRulesSuccessfulGenerated.Time) < h.ignoreHandleBefore {
-		return "no spec change in correct state object", true
+	// - Or the budgets update TS changed since the last event: Means that the budget status updater updated the status (this happens
+	//   periodically, and without this the rules would be regenerated on every budgets update).
+	if sl.objMeta.Generation == sl.status.ObservedGeneration &&
+		slices.Equal(sl.templates, sl.status.Templates) &&
+		sl.status.PromOpRulesGenerated {
+		if time.Since(sl.status.LastPromOpRulesSuccessfulGenerated.Time) < h.ignoreHandleBefore {
+			return "no spec change in correct state object", true
+		}
+		if budgetsUpdated {
+			return "budgets status update in correct state object", true
+		}
 	}
 
 	return "", false
 }
+
+// trackBudgetsUpdate stores the budgets update time of the service level and returns if it changed
+// since the last handled event of the same service level.
+func (h handler) trackBudgetsUpdate(sl serviceLevel) (changed bool) {
+	var current time.Time
+	if sl.status.LastBudgetsUpdate != nil {
+		current = sl.status.LastBudgetsUpdate.Time
+	}
+
+	prev, ok := h.lastBudgetsUpdates.Swap(serviceLevelKey(sl), current)
+	return ok && !prev.(time.Time).Equal(current)
+}
+
+func serviceLevelKey(sl serviceLevel) string {
+	return sl.kind + "/" + sl.objMeta.Namespace + "/" + sl.objMeta.Name
+}
+
+// handleResult is the result of a service level handling, used to set its status.
+type handleResult struct {
+	// failedReason is the condition reason of the handling failure.
+	failedReason string
+	// sloNames are the SLO names by their ID.
+	sloNames   map[string]string
+	sloErr     *generate.SLOError
+	sloResults []generate.SLOResult
+	extraRules []commonmodel.PromRuleGroup
+	err        error
+}
+
+// recordServiceLevelEvent records the Kubernetes event of a service level object based on the
+// result of its handling. Repeated events are deduplicated by the event recorder.
+func (h handler) recordServiceLevelEvent(obj runtime.Object, hr handleResult) {
+	if hr.err == nil {
+		rules := 0
+		for _, r := range hr.sloResults {
+			rules += len(r.SLORules.SLIErrorRecRules.Rules) + len(r.SLORules.MetadataRecRules.Rules) + len(r.SLORules.AlertRules.Rules)
+			for _, rg := range r.SLORules.ExtraRules {
+				rules += len(rg.Rules)
+			}
+		}
+		for _, rg := range hr.extraRules {
+			rules += len(rg.Rules)
+		}
+		h.eventRecorder.Eventf(obj, corev1.EventTypeNormal, EventReasonRulesGenerated, "Generated %d Prometheus rules for %d SLOs", rules, len(hr.sloResults))
+		return
+	}
+
+	switch {
+	case hr.failedReason == slothv1.ConditionReasonInvalidSpec:
+		h.eventRecorder.Eventf(obj, corev1.EventTypeWarning, EventReasonInvalidSpec, "Invalid spec: %s", hr.err)
+	case hr.failedReason == slothv1.ConditionReasonInvalidTemplate:
+		h.eventRecorder.Eventf(obj, corev1.EventTypeWarning, EventReasonInvalidTemplate, "Invalid SLO template: %s", hr.err)
+	case hr.failedReason == slothv1.ConditionReasonInvalidSLO:
+		h.eventRecorder.Eventf(obj, corev1.EventTypeWarning, EventReasonInvalidSLO, "Invalid %q SLO: %s", hr.sloNames[hr.sloErr.SLOID], hr.sloErr.Err)
+	case hr.sloErr != nil && hr.sloErr.PluginID != "":
+		h.eventRecorder.Eventf(obj, corev1.EventTypeWarning, EventReasonPluginFailed, "SLO %q plugin %q failed: %s", hr.sloNames[hr.sloErr.SLOID], hr.sloErr.PluginID, hr.sloErr.Err)
+	case hr.failedReason == slothv1.ConditionReasonRulesApplyFailed && kubeerrors.IsConflict(hr.err):
+		h.eventRecorder.Eventf(obj, corev1.EventTypeWarning, EventReasonRulesApplyConflict, "Conflict applying the Prometheus rules: %s", hr.err)
+	case hr.failedReason == slothv1.ConditionReasonRulesApplyFailed:
+		h.eventRecorder.Eventf(obj, corev1.EventTypeWarning, EventReasonRulesApplyFailed, "Could not apply the Prometheus rules: %s", hr.err)
+	default:
+		h.eventRecorder.Eventf(obj, corev1.EventTypeWarning, EventReasonGenerationFailed, "Could not generate the Prometheus rules: %s", hr.err)
+	}
+}
+
+// newPrometheusServiceLevelStatus returns the status of a service level object based on the result of its
+// handling. The conditions keep their last transition time if their status didn't change.
+// In case of no error we will update "last correct Prometheus operation rules generated" TS, this will trigger
+// a new handling, the handler should break this loop somehow (e.g: if ok and last generated < 5m, ignore).
+func newPrometheusServiceLevelStatus(sl serviceLevel, hr handleResult, now time.Time) slothv1.PrometheusServiceLevelStatus {
+	status := *sl.status.DeepCopy()
+	status.PromOpRulesGenerated = false
+	status.PromOpRulesGeneratedSLOs = 0
+	status.ProcessedSLOs = len(sl.spec.SLOs)
+	status.ObservedGeneration = sl.objMeta.Generation
+	// Keep the last used templates if they couldn't be rendered.
+	if hr.failedReason != slothv1.ConditionReasonInvalidTemplate {
+		status.Templates = sl.templates
+	}
+
+	if hr.err == nil {
+		status.PromOpRulesGenerated = true
+		status.PromOpRulesGeneratedSLOs = len(sl.spec.SLOs)
+		status.LastPromOpRulesSuccessfulGenerated = &metav1.Time{Time: now}
+		status.TargetNamespace = sl.kmeta.Namespace
+	}
+
+	// Set the SLO statuses.
+	sloRuleGroups := map[string][]string{}
+	for _, r := range hr.sloResults {
+		rgs := []string{}
+		for _, rg := range append([]commonmodel.PromRuleGroup{r.SLORules.SLIErrorRecRules, r.SLORules.MetadataRecRules, r.SLORules.AlertRules}, r.SLORules.ExtraRules...) {
+			if len(rg.Rules) > 0 {
+				rgs = append(rgs, rg.Name)
+			}
+		}
+		sloRuleGroups[r.SLO.Name] = rgs
+	}
+	sloErrName := ""
+	if hr.sloErr != nil {
+		sloErrName = hr.sloNames[hr.sloErr.SLOID]
+	}
+	// Keep the live error budgets, these are set by the budget status updater and not by the handler.
+	budgetsBySLO := map[string]slothv1.SLOStatus{}
+	for _, s := range status.SLOs {
+		budgetsBySLO[s.Name] = s
+	}
+	status.SLOs = make([]slothv1.SLOStatus, 0, len(sl.spec.SLOs))
+	for _, s := range sl.spec.SLOs {
+		sloStatus := slothv1.SLOStatus{Name: s.Name, RuleGroups: sloRuleGroups[s.Name]}
+		if sloErrName != "" && sloErrName == s.Name {
+			sloStatus.Error = hr.sloErr.Err.Error()
+		}
+		if b, ok := budgetsBySLO[s.Name]; ok {
+			sloStatus.BurnRate = b.BurnRate
+			sloStatus.RemainingBudgetPercent = b.RemainingBudgetPercent
+			sloStatus.FiringAlerts = b.FiringAlerts
+		}
+		status.SLOs = append(status.SLOs, sloStatus)
+	}
+
+	// Set the conditions.
+	setCondition := func(condType string, ok bool, reason, message string) {
+		condStatus := metav1.ConditionTrue
+		if !ok {
+			condStatus = metav1.ConditionFalse
+		}
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               condType,
+			Status:             condStatus,
+			ObservedGeneration: sl.objMeta.Generation,
+			LastTransitionTime: metav1.Time{Time: now},
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+
+	if hr.err == nil {
+		setCondition(slothv1.ConditionTypeSpecValid, true, slothv1.ConditionReasonValidSpec, "The spec is valid")
+		setCondition(slothv1.ConditionTypeRulesApplied, true, slothv1.ConditionReasonRulesApplied, fmt.Sprintf("The rules of %d SLOs have been applied", len(sl.spec.SLOs)))
+		setCondition(slothv1.ConditionTypeReady, true, slothv1.ConditionReasonReady, "All the SLOs are ready")
+		return status
+	}
+
+	reason := hr.failedReason
+	if reason == "" {
+		reason = slothv1.ConditionReasonGenerationFailed
+	}
+	message := hr.err.Error()
+	specValid := reason != slothv1.ConditionReasonInvalidSpec && reason != slothv1.ConditionReasonInvalidSLO && reason != slothv1.ConditionReasonInvalidTemplate
+	if specValid {
+		setCondition(slothv1.ConditionTypeSpecValid, true, slothv1.ConditionReasonValidSpec, "The spec is valid")
+	} else {
+		setCondition(slothv1.ConditionTypeSpecValid, false, reason, message)
+	}
+	setCondition(slothv1.ConditionTypeRulesApplied, false, reason, message)
+	setCondition(slothv1.ConditionTypeReady, false, reason, message)
+
+	return status
+}
@@ -0,0 +1,751 @@
+package kubecontroller_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/slok/sloth/internal/app/generate"
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/app/kubecontroller/kubecontrollermock"
+	storagek8s "github.com/slok/sloth/internal/storage/k8s"
+	"github.com/slok/sloth/pkg/common/model"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+func getTestPSL() *slothv1.PrometheusServiceLevel {
+	return &slothv1.PrometheusServiceLevel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Generation: 2},
+		Spec: slothv1.PrometheusServiceLevelSpec{
+			Service: "svc",
+			SLOs: []slothv1.SLO{
+				{Name: "slo1"},
+				{Name: "slo2"},
+			},
+		},
+	}
+}
+
+func getTestSLOGroup() *model.PromSLOGroup {
+	return &model.PromSLOGroup{
+		SLOs: []model.PromSLO{
+			{ID: "svc-slo1", Name: "slo1", Service: "svc"},
+			{ID: "svc-slo2", Name: "slo2", Service: "svc"},
+		},
+		OriginalSource: model.PromSLOGroupSource{K8sSlothV1: getTestPSL()},
+	}
+}
+
+func getTestGenResponse() *generate.Response {
+	rules := func(id string) model.PromSLORules {
+		return model.PromSLORules{
+			SLIErrorRecRules: model.PromRuleGroup{Name: "sloth-slo-sli-recordings-" + id, Rules: []rulefmt.Rule{{Record: "test"}}},
+			MetadataRecRules: model.PromRuleGroup{Name: "sloth-slo-meta-recordings-" + id, Rules: []rulefmt.Rule{{Record: "test"}}},
+			AlertRules:       model.PromRuleGroup{Name: "sloth-slo-alerts-" + id},
+		}
+	}
+	return &generate.Response{
+		PrometheusSLOs: []generate.SLOResult{
+			{SLO: model.PromSLO{ID: "svc-slo1", Name: "slo1", Service: "svc"}, SLORules: rules("svc-slo1")},
+			{SLO: model.PromSLO{ID: "svc-slo2", Name: "slo2", Service: "svc"}, SLORules: rules("svc-slo2")},
+		},
+	}
+}
+
+var t0 = metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+func TestHandlerPrometheusServiceLevelStatus(t *testing.T) {
+	tests := map[string]struct {
+		psl       func() *slothv1.PrometheusServiceLevel
+		mock      func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository)
+		expStatus *slothv1.PrometheusServiceLevelStatus
+		expEvents []storagek8s.FakeEvent
+		expErr    bool
+	}{
+		"Objects being deleted should be ignored.": {
+			psl: func() *slothv1.PrometheusServiceLevel {
+				psl := getTestPSL()
+				psl.DeletionTimestamp = &t0
+				return psl
+			},
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository) {
+			},
+			expEvents: []storagek8s.FakeEvent{},
+		},
+
+		"A correct handling should set the ready status with the SLOs rule groups.": {
+			psl: getTestPSL,
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository) {
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(getTestSLOGroup(), nil)
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(getTestGenResponse(), nil)
+				mr.On("StoreSLOs", mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+			},
+			expStatus: &slothv1.PrometheusServiceLevelStatus{
+				PromOpRulesGeneratedSLOs:           2,
+				ProcessedSLOs:                      2,
+				PromOpRulesGenerated:               true,
+				LastPromOpRulesSuccessfulGenerated: &metav1.Time{},
+				ObservedGeneration:                 2,
+				TargetNamespace:                    "test-ns",
+				Conditions: []metav1.Condition{
+					{Type: "SpecValid", Status: "True", ObservedGeneration: 2, Reason: "ValidSpec", Message: "The spec is valid"},
+					{Type: "RulesApplied", Status: "True", ObservedGeneration: 2, Reason: "RulesApplied", Message: "The rules of 2 SLOs have been applied"},
+					{Type: "Ready", Status: "True", ObservedGeneration: 2, Reason: "Ready", Message: "All the SLOs are ready"},
+				},
+				SLOs: []slothv1.SLOStatus{
+					{Name: "slo1", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo1", "sloth-slo-meta-recordings-svc-slo1"}},
+					{Name: "slo2", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo2", "sloth-slo-meta-recordings-svc-slo2"}},
+				},
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "test-ns/test", Type: "Normal", Reason: "RulesGenerated", Message: "Generated 4 Prometheus rules for 2 SLOs", Count: 1},
+			},
+		},
+
+		"Conditions that don't change their status should keep their transition time.": {
+			psl: func() *slothv1.PrometheusServiceLevel {
+				psl := getTestPSL()
+				psl.Status.Conditions = []metav1.Condition{
+					{Type: "SpecValid", Status: "True", ObservedGeneration: 1, LastTransitionTime: t0, Reason: "ValidSpec", Message: "The spec is valid"},
+					{Type: "RulesApplied", Status: "False", ObservedGeneration: 1, LastTransitionTime: t0, Reason: "RulesApplyFailed", Message: "something"},
+				}
+				return psl
+			},
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository) {
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(getTestSLOGroup(), nil)
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(getTestGenResponse(), nil)
+				mr.On("StoreSLOs", mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+			},
+			expStatus: &slothv1.PrometheusServiceLevelStatus{
+				PromOpRulesGeneratedSLOs:           2,
+				ProcessedSLOs:                      2,
+				PromOpRulesGenerated:               true,
+				LastPromOpRulesSuccessfulGenerated: &metav1.Time{},
+				ObservedGeneration:                 2,
+				TargetNamespace:                    "test-ns",
+				Conditions: []metav1.Condition{
+					{Type: "SpecValid", Status: "True", ObservedGeneration: 2, LastTransitionTime: t0, Reason: "ValidSpec", Message: "The spec is valid"},
+					{Type: "RulesApplied", Status: "True", ObservedGeneration: 2, Reason: "RulesApplied", Message: "The rules of 2 SLOs have been applied"},
+					{Type: "Ready", Status: "True", ObservedGeneration: 2, Reason: "Ready", Message: "All the SLOs are ready"},
+				},
+				SLOs: []slothv1.SLOStatus{
+					{Name: "slo1", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo1", "sloth-slo-meta-recordings-svc-slo1"}},
+					{Name: "slo2", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo2", "sloth-slo-meta-recordings-svc-slo2"}},
+				},
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "test-ns/test", Type: "Normal", Reason: "RulesGenerated", Message: "Generated 4 Prometheus rules for 2 SLOs", Count: 1},
+			},
+		},
+
+		"The SLOs live error budgets should be kept on the status.": {
+			psl: func() *slothv1.PrometheusServiceLevel {
+				psl := getTestPSL()
+				burning := 1
+				psl.Status.SLOsBurningOverBudget = &burning
+				psl.Status.LastBudgetsUpdate = &t0
+				psl.Status.SLOs = []slothv1.SLOStatus{
+					{Name: "slo1", BurnRate: ptrTo(1.5), RemainingBudgetPercent: ptrTo(42.0), FiringAlerts: []string{"alert1"}},
+					{Name: "slo3", BurnRate: ptrTo(0.5)},
+				}
+				return psl
+			},
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository) {
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(getTestSLOGroup(), nil)
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(getTestGenResponse(), nil)
+				mr.On("StoreSLOs", mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+			},
+			expStatus: &slothv1.PrometheusServiceLevelStatus{
+				PromOpRulesGeneratedSLOs:           2,
+				ProcessedSLOs:                      2,
+				PromOpRulesGenerated:               true,
+				LastPromOpRulesSuccessfulGenerated: &metav1.Time{},
+				ObservedGeneration:                 2,
+				TargetNamespace:                    "test-ns",
+				Conditions: []metav1.Condition{
+					{Type: "SpecValid", Status: "True", ObservedGeneration: 2, Reason: "ValidSpec", Message: "The spec is valid"},
+					{Type: "RulesApplied", Status: "True", ObservedGeneration: 2, Reason: "RulesApplied", Message: "The rules of 2 SLOs have been applied"},
+					{Type: "Ready", Status: "True", ObservedGeneration: 2, Reason: "Ready", Message: "All the SLOs are ready"},
+				},
+				SLOs: []slothv1.SLOStatus{
+					{Name: "slo1", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo1", "sloth-slo-meta-recordings-svc-slo1"}, BurnRate: ptrTo(1.5), RemainingBudgetPercent: ptrTo(42.0), FiringAlerts: []string{"alert1"}},
+					{Name: "slo2", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo2", "sloth-slo-meta-recordings-svc-slo2"}},
+				},
+				SLOsBurningOverBudget: ptrTo(1),
+				LastBudgetsUpdate:     &t0,
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "test-ns/test", Type: "Normal", Reason: "RulesGenerated", Message: "Generated 4 Prometheus rules for 2 SLOs", Count: 1},
+			},
+		},
+
+		"An invalid spec should set the invalid spec status.": {
+			psl: getTestPSL,
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository) {
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(nil, fmt.Errorf("something"))
+			},
+			expStatus: &slothv1.PrometheusServiceLevelStatus{
+				ProcessedSLOs:      2,
+				ObservedGeneration: 2,
+				Conditions: []metav1.Condition{
+					{Type: "SpecValid", Status: "False", ObservedGeneration: 2, Reason: "InvalidSpec", Message: "could not load CR spec into model: something"},
+					{Type: "RulesApplied", Status: "False", ObservedGeneration: 2, Reason: "InvalidSpec", Message: "could not load CR spec into model: something"},
+					{Type: "Ready", Status: "False", ObservedGeneration: 2, Reason: "InvalidSpec", Message: "could not load CR spec into model: something"},
+				},
+				SLOs: []slothv1.SLOStatus{{Name: "slo1"}, {Name: "slo2"}},
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "test-ns/test", Type: "Warning", Reason: "InvalidSpec", Message: "Invalid spec: could not load CR spec into model: something", Count: 1},
+			},
+			expErr: true,
+		},
+
+		"An invalid SLO should set the invalid SLO status with the SLO error.": {
+			psl: getTestPSL,
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository) {
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(getTestSLOGroup(), nil)
+				err := &generate.SLOError{SLOID: "svc-slo2", PluginID: "sloth.dev/core/validate/v1", Err: fmt.Errorf("invalid objective")}
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(nil, err)
+			},
+			expStatus: &slothv1.PrometheusServiceLevelStatus{
+				ProcessedSLOs:      2,
+				ObservedGeneration: 2,
+				Conditions: []metav1.Condition{
+					{Type: "SpecValid", Status: "False", ObservedGeneration: 2, Reason: "InvalidSLO", Message: `could not generate SLOs: could not generate "svc-slo2" slo: slo processor failed: invalid objective`},
+					{Type: "RulesApplied", Status: "False", ObservedGeneration: 2, Reason: "InvalidSLO", Message: `could not generate SLOs: could not generate "svc-slo2" slo: slo processor failed: invalid objective`},
+					{Type: "Ready", Status: "False", ObservedGeneration: 2, Reason: "InvalidSLO", Message: `could not generate SLOs: could not generate "svc-slo2" slo: slo processor failed: invalid objective`},
+				},
+				SLOs: []slothv1.SLOStatus{{Name: "slo1"}, {Name: "slo2", Error: "invalid objective"}},
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "test-ns/test", Type: "Warning", Reason: "InvalidSLO", Message: `Invalid "slo2" SLO: invalid objective`, Count: 1},
+			},
+			expErr: true,
+		},
+
+		"A failed generation should set the generation failed status with the SLO error.": {
+			psl: getTestPSL,
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository) {
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(getTestSLOGroup(), nil)
+				err := &generate.SLOError{SLOID: "svc-slo1", PluginID: "test-plugin", Err: fmt.Errorf("something")}
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(nil, err)
+			},
+			expStatus: &slothv1.PrometheusServiceLevelStatus{
+				ProcessedSLOs:      2,
+				ObservedGeneration: 2,
+				Conditions: []metav1.Condition{
+					{Type: "SpecValid", Status: "True", ObservedGeneration: 2, Reason: "ValidSpec", Message: "The spec is valid"},
+					{Type: "RulesApplied", Status: "False", ObservedGeneration: 2, Reason: "GenerationFailed", Message: `could not generate SLOs: could not generate "svc-slo1" slo: slo processor failed: something`},
+					{Type: "Ready", Status: "False", ObservedGeneration: 2, Reason: "GenerationFailed", Message: `could not generate SLOs: could not generate "svc-slo1" slo: slo processor failed: something`},
+				},
+				SLOs: []slothv1.SLOStatus{{Name: "slo1", Error: "something"}, {Name: "slo2"}},
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "test-ns/test", Type: "Warning", Reason: "PluginFailed", Message: `SLO "slo1" plugin "test-plugin" failed: something`, Count: 1},
+			},
+			expErr: true,
+		},
+
+		"A failed rules store should set the rules apply failed status.": {
+			psl: getTestPSL,
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository) {
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(getTestSLOGroup(), nil)
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(getTestGenResponse(), nil)
+				mr.On("StoreSLOs", mock.Anything, mock.Anything, mock.Anything).Once().Return(fmt.Errorf("something"))
+			},
+			expStatus: &slothv1.PrometheusServiceLevelStatus{
+				ProcessedSLOs:      2,
+				ObservedGeneration: 2,
+				Conditions: []metav1.Condition{
+					{Type: "SpecValid", Status: "True", ObservedGeneration: 2, Reason: "ValidSpec", Message: "The spec is valid"},
+					{Type: "RulesApplied", Status: "False", ObservedGeneration: 2, Reason: "RulesApplyFailed", Message: "could not store SLOs: something"},
+					{Type: "Ready", Status: "False", ObservedGeneration: 2, Reason: "RulesApplyFailed", Message: "could not store SLOs: something"},
+				},
+				SLOs: []slothv1.SLOStatus{
+					{Name: "slo1", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo1", "sloth-slo-meta-recordings-svc-slo1"}},
+					{Name: "slo2", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo2", "sloth-slo-meta-recordings-svc-slo2"}},
+				},
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "test-ns/test", Type: "Warning", Reason: "RulesApplyFailed", Message: "Could not apply the Prometheus rules: could not store SLOs: something", Count: 1},
+			},
+			expErr: true,
+		},
+		"A conflict on the rules store should set the rules apply failed status and record the conflict.": {
+			psl: getTestPSL,
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository) {
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(getTestSLOGroup(), nil)
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(getTestGenResponse(), nil)
+				err := kubeerrors.NewConflict(schema.GroupResource{Group: "monitoring.coreos.com", Resource: "prometheusrules"}, "test", fmt.Errorf("something"))
+				mr.On("StoreSLOs", mock.Anything, mock.Anything, mock.Anything).Once().Return(fmt.Errorf("could not apply: %w", err))
+			},
+			expStatus: &slothv1.PrometheusServiceLevelStatus{
+				ProcessedSLOs:      2,
+				ObservedGeneration: 2,
+				Conditions: []metav1.Condition{
+					{Type: "SpecValid", Status: "True", ObservedGeneration: 2, Reason: "ValidSpec", Message: "The spec is valid"},
+					{Type: "RulesApplied", Status: "False", ObservedGeneration: 2, Reason: "RulesApplyFailed", Message: `could not store SLOs: could not apply: Operation cannot be fulfilled on prometheusrules.monitoring.coreos.com "test": something`},
+					{Type: "Ready", Status: "False", ObservedGeneration: 2, Reason: "RulesApplyFailed", Message: `could not store SLOs: could not apply: Operation cannot be fulfilled on prometheusrules.monitoring.coreos.com "test": something`},
+				},
+				SLOs: []slothv1.SLOStatus{
+					{Name: "slo1", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo1", "sloth-slo-meta-recordings-svc-slo1"}},
+					{Name: "slo2", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo2", "sloth-slo-meta-recordings-svc-slo2"}},
+				},
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "test-ns/test", Type: "Warning", Reason: "RulesApplyConflict", Message: `Conflict applying the Prometheus rules: could not store SLOs: could not apply: Operation cannot be fulfilled on prometheusrules.monitoring.coreos.com "test": something`, Count: 1},
+			},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			// Mocks.
+			msl := kubecontrollermock.NewSpecLoader(t)
+			mg := kubecontrollermock.NewGenerator(t)
+			mr := kubecontrollermock.NewRepository(t)
+			mss := kubecontrollermock.NewKubeStatusStorer(t)
+			mer := storagek8s.NewFakeEventRecorder(nil)
+			test.mock(msl, mg, mr)
+
+			var gotStatus *slothv1.PrometheusServiceLevelStatus
+			if test.expStatus != nil {
+				mss.On("EnsurePrometheusServiceLevelStatus", mock.Anything, mock.Anything, mock.Anything).Once().Run(func(args mock.Arguments) {
+					s := args.Get(2).(slothv1.PrometheusServiceLevelStatus)
+					gotStatus = &s
+				}).Return(nil)
+			}
+
+			// Execute.
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				SpecLoader:       msl,
+				Generator:        mg,
+				Repository:       mr,
+				KubeStatusStorer: mss,
+				EventRecorder:    mer,
+				TemplateGetter:   kubecontrollermock.NewTemplateGetter(t),
+			})
+			require.NoError(err)
+			start := time.Now()
+			err = h.Handle(context.TODO(), test.psl())
+
+			// Check.
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			// Times set on the handling are not deterministic.
+			if gotStatus != nil {
+				if gotStatus.LastPromOpRulesSuccessfulGenerated != nil && !gotStatus.LastPromOpRulesSuccessfulGenerated.Time.Before(start) {
+					gotStatus.LastPromOpRulesSuccessfulGenerated = &metav1.Time{}
+				}
+				for i, c := range gotStatus.Conditions {
+					if !c.LastTransitionTime.Time.Before(start) {
+						gotStatus.Conditions[i].LastTransitionTime = metav1.Time{}
+					}
+				}
+			}
+			assert.Equal(test.expStatus, gotStatus)
+			assert.Equal(test.expEvents, mer.Events())
+		})
+	}
+}
+
+func getTestCPSL() *slothv1.ClusterPrometheusServiceLevel {
+	psl := getTestPSL()
+	return &slothv1.ClusterPrometheusServiceLevel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 2, Labels: map[string]string{"k1": "v1"}},
+		Spec: slothv1.ClusterPrometheusServiceLevelSpec{
+			TargetNamespace:            "target-ns",
+			PrometheusServiceLevelSpec: psl.Spec,
+		},
+	}
+}
+
+func TestHandlerServiceLevelSelection(t *testing.T) {
+	tests := map[string]struct {
+		obj       func() runtime.Object
+		selector  labels.Selector
+		mock      func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository, mss *kubecontrollermock.KubeStatusStorer, mng *kubecontrollermock.NamespaceGetter)
+		expEvents []storagek8s.FakeEvent
+		expErr    bool
+	}{
+		"Cluster service levels should store the rules on the target namespace and set their status.": {
+			obj:      func() runtime.Object { return getTestCPSL() },
+			selector: labels.Everything(),
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository, mss *kubecontrollermock.KubeStatusStorer, mng *kubecontrollermock.NamespaceGetter) {
+				msl.On("LoadClusterSpec", mock.Anything, getTestCPSL()).Once().Return(getTestSLOGroup(), nil)
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(getTestGenResponse(), nil)
+				expKmeta := model.K8sMeta{Name: "test", Namespace: "target-ns", Labels: map[string]string{"k1": "v1"}}
+				mr.On("StoreSLOs", mock.Anything, expKmeta, mock.Anything).Once().Return(nil)
+				mss.On("EnsureClusterPrometheusServiceLevelStatus", mock.Anything, getTestCPSL(), mock.Anything).Once().Return(nil)
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "/test", Type: "Normal", Reason: "RulesGenerated", Message: "Generated 4 Prometheus rules for 2 SLOs", Count: 1},
+			},
+		},
+
+		"Service levels on namespaces that match the namespace selector should be handled.": {
+			obj:      func() runtime.Object { return getTestPSL() },
+			selector: labels.SelectorFromSet(labels.Set{"sloth": "enabled"}),
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository, mss *kubecontrollermock.KubeStatusStorer, mng *kubecontrollermock.NamespaceGetter) {
+				ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns", Labels: map[string]string{"sloth": "enabled"}}}
+				mng.On("GetNamespace", mock.Anything, "test-ns").Once().Return(ns, nil)
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(getTestSLOGroup(), nil)
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(getTestGenResponse(), nil)
+				mr.On("StoreSLOs", mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+				mss.On("EnsurePrometheusServiceLevelStatus", mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "test-ns/test", Type: "Normal", Reason: "RulesGenerated", Message: "Generated 4 Prometheus rules for 2 SLOs", Count: 1},
+			},
+		},
+
+		"Service levels on namespaces that don't match the namespace selector should be ignored.": {
+			obj:      func() runtime.Object { return getTestPSL() },
+			selector: labels.SelectorFromSet(labels.Set{"sloth": "enabled"}),
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository, mss *kubecontrollermock.KubeStatusStorer, mng *kubecontrollermock.NamespaceGetter) {
+				ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns", Labels: map[string]string{"sloth": "disabled"}}}
+				mng.On("GetNamespace", mock.Anything, "test-ns").Once().Return(ns, nil)
+			},
+			expEvents: []storagek8s.FakeEvent{},
+		},
+
+		"Service levels on missing namespaces should be ignored.": {
+			obj:      func() runtime.Object { return getTestPSL() },
+			selector: labels.SelectorFromSet(labels.Set{"sloth": "enabled"}),
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository, mss *kubecontrollermock.KubeStatusStorer, mng *kubecontrollermock.NamespaceGetter) {
+				err := kubeerrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "test-ns")
+				mng.On("GetNamespace", mock.Anything, "test-ns").Once().Return(nil, err)
+			},
+			expEvents: []storagek8s.FakeEvent{},
+		},
+
+		"Failing getting the service level namespace should fail.": {
+			obj:      func() runtime.Object { return getTestPSL() },
+			selector: labels.SelectorFromSet(labels.Set{"sloth": "enabled"}),
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository, mss *kubecontrollermock.KubeStatusStorer, mng *kubecontrollermock.NamespaceGetter) {
+				mng.On("GetNamespace", mock.Anything, "test-ns").Once().Return(nil, fmt.Errorf("something"))
+			},
+			expEvents: []storagek8s.FakeEvent{},
+			expErr:    true,
+		},
+
+		"Cluster service levels should not be affected by the namespace selector.": {
+			obj:      func() runtime.Object { return getTestCPSL() },
+			selector: labels.SelectorFromSet(labels.Set{"sloth": "enabled"}),
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository, mss *kubecontrollermock.KubeStatusStorer, mng *kubecontrollermock.NamespaceGetter) {
+				msl.On("LoadClusterSpec", mock.Anything, mock.Anything).Once().Return(getTestSLOGroup(), nil)
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(getTestGenResponse(), nil)
+				mr.On("StoreSLOs", mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+				mss.On("EnsureClusterPrometheusServiceLevelStatus", mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "/test", Type: "Normal", Reason: "RulesGenerated", Message: "Generated 4 Prometheus rules for 2 SLOs", Count: 1},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			// Mocks.
+			msl := kubecontrollermock.NewSpecLoader(t)
+			mg := kubecontrollermock.NewGenerator(t)
+			mr := kubecontrollermock.NewRepository(t)
+			mss := kubecontrollermock.NewKubeStatusStorer(t)
+			mng := kubecontrollermock.NewNamespaceGetter(t)
+			mer := storagek8s.NewFakeEventRecorder(nil)
+			test.mock(msl, mg, mr, mss, mng)
+
+			// Execute.
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				SpecLoader:             msl,
+				Generator:              mg,
+				Repository:             mr,
+				KubeStatusStorer:       mss,
+				EventRecorder:          mer,
+				NamespaceGetter:        mng,
+				TemplateGetter:         kubecontrollermock.NewTemplateGetter(t),
+				NamespaceLabelSelector: test.selector,
+			})
+			require.NoError(err)
+			err = h.Handle(context.TODO(), test.obj())
+
+			// Check.
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(test.expEvents, mer.Events())
+		})
+	}
+}
+
+func TestHandlerBudgetsStatusUpdates(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// A service level successfully generated a while ago, so it's not on the ignored after success window.
+	newPSL := func(lastBudgetsUpdate metav1.Time) *slothv1.PrometheusServiceLevel {
+		psl := getTestPSL()
+		psl.Status = slothv1.PrometheusServiceLevelStatus{
+			ObservedGeneration:                 psl.Generation,
+			PromOpRulesGenerated:               true,
+			LastPromOpRulesSuccessfulGenerated: &metav1.Time{Time: time.Now().Add(-1 * time.Hour)},
+			LastBudgetsUpdate:                  &lastBudgetsUpdate,
+		}
+		return psl
+	}
+	t1 := metav1.NewTime(t0.Add(1 * time.Minute))
+
+	// Mocks, only the first handle and the resync handle should generate the rules.
+	msl := kubecontrollermock.NewSpecLoader(t)
+	mg := kubecontrollermock.NewGenerator(t)
+	mr := kubecontrollermock.NewRepository(t)
+	mss := kubecontrollermock.NewKubeStatusStorer(t)
+	msl.On("LoadSpec", mock.Anything, mock.Anything).Twice().Return(getTestSLOGroup(), nil)
+	mg.On("Generate", mock.Anything, mock.Anything).Twice().Return(getTestGenResponse(), nil)
+	mr.On("StoreSLOs", mock.Anything, mock.Anything, mock.Anything).Twice().Return(nil)
+	mss.On("EnsurePrometheusServiceLevelStatus", mock.Anything, mock.Anything, mock.Anything).Twice().Return(nil)
+
+	h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+		SpecLoader:       msl,
+		Generator:        mg,
+		Repository:       mr,
+		KubeStatusStorer: mss,
+		EventRecorder:    storagek8s.NewFakeEventRecorder(nil),
+		TemplateGetter:   kubecontrollermock.NewTemplateGetter(t),
+	})
+	require.NoError(err)
+
+	// First handle generates the rules.
+	err = h.Handle(context.TODO(), newPSL(t0))
+	assert.NoError(err)
+
+	// The budget status updater updates the status, it should be ignored.
+	err = h.Handle(context.TODO(), newPSL(t1))
+	assert.NoError(err)
+
+	// A resync without status changes should generate the rules.
+	err = h.Handle(context.TODO(), newPSL(t1))
+	assert.NoError(err)
+}
+
+func getTestTemplatedPSL() *slothv1.PrometheusServiceLevel {
+	psl := getTestPSL()
+	psl.Spec.SLOs = psl.Spec.SLOs[:1]
+	psl.Spec.Templates = []slothv1.SLOTemplateInstance{{Name: "test-tpl", Params: map[string]string{"name": "slo2"}}}
+	return psl
+}
+
+func getTestSLOTemplate() *slothv1.PrometheusServiceLevelTemplate {
+	return &slothv1.PrometheusServiceLevelTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-tpl", Namespace: "test-ns", Generation: 3},
+		Spec: slothv1.PrometheusServiceLevelTemplateSpec{
+			Params: []slothv1.SLOTemplateParam{{Name: "name"}},
+			SLOs:   []slothv1.SLO{{Name: "${name}"}},
+		},
+	}
+}
+
+func TestHandlerSLOTemplates(t *testing.T) {
+	tests := map[string]struct {
+		psl       func() *slothv1.PrometheusServiceLevel
+		mock      func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository, mtg *kubecontrollermock.TemplateGetter)
+		expStatus *slothv1.PrometheusServiceLevelStatus
+		expEvents []storagek8s.FakeEvent
+		expErr    bool
+	}{
+		"The template SLOs should be rendered and handled along with the spec SLOs.": {
+			psl: getTestTemplatedPSL,
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository, mtg *kubecontrollermock.TemplateGetter) {
+				mtg.On("GetPrometheusServiceLevelTemplate", mock.Anything, "test-ns", "test-tpl").Once().Return(getTestSLOTemplate(), nil)
+				expPSL := getTestTemplatedPSL()
+				expPSL.Spec.SLOs = getTestPSL().Spec.SLOs
+				msl.On("LoadSpec", mock.Anything, expPSL).Once().Return(getTestSLOGroup(), nil)
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(getTestGenResponse(), nil)
+				mr.On("StoreSLOs", mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+			},
+			expStatus: &slothv1.PrometheusServiceLevelStatus{
+				PromOpRulesGeneratedSLOs:           2,
+				ProcessedSLOs:                      2,
+				PromOpRulesGenerated:               true,
+				LastPromOpRulesSuccessfulGenerated: &metav1.Time{},
+				ObservedGeneration:                 2,
+				TargetNamespace:                    "test-ns",
+				Conditions: []metav1.Condition{
+					{Type: "SpecValid", Status: "True", ObservedGeneration: 2, Reason: "ValidSpec", Message: "The spec is valid"},
+					{Type: "RulesApplied", Status: "True", ObservedGeneration: 2, Reason: "RulesApplied", Message: "The rules of 2 SLOs have been applied"},
+					{Type: "Ready", Status: "True", ObservedGeneration: 2, Reason: "Ready", Message: "All the SLOs are ready"},
+				},
+				SLOs: []slothv1.SLOStatus{
+					{Name: "slo1", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo1", "sloth-slo-meta-recordings-svc-slo1"}},
+					{Name: "slo2", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo2", "sloth-slo-meta-recordings-svc-slo2"}},
+				},
+				Templates: []slothv1.SLOTemplateStatus{{Name: "test-tpl", Namespace: "test-ns", ObservedGeneration: 3}},
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "test-ns/test", Type: "Normal", Reason: "RulesGenerated", Message: "Generated 4 Prometheus rules for 2 SLOs", Count: 1},
+			},
+		},
+
+		"A missing template should set the invalid template status.": {
+			psl: func() *slothv1.PrometheusServiceLevel {
+				psl := getTestTemplatedPSL()
+				psl.Status.Templates = []slothv1.SLOTemplateStatus{{Name: "test-tpl", Namespace: "test-ns", ObservedGeneration: 2}}
+				return psl
+			},
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository, mtg *kubecontrollermock.TemplateGetter) {
+				mtg.On("GetPrometheusServiceLevelTemplate", mock.Anything, "test-ns", "test-tpl").Once().Return(nil, fmt.Errorf("something"))
+			},
+			expStatus: &slothv1.PrometheusServiceLevelStatus{
+				ProcessedSLOs:      1,
+				ObservedGeneration: 2,
+				Conditions: []metav1.Condition{
+					{Type: "SpecValid", Status: "False", ObservedGeneration: 2, Reason: "InvalidTemplate", Message: "could not render SLO templates: SLO template test-ns/test-tpl: could not get template: something"},
+					{Type: "RulesApplied", Status: "False", ObservedGeneration: 2, Reason: "InvalidTemplate", Message: "could not render SLO templates: SLO template test-ns/test-tpl: could not get template: something"},
+					{Type: "Ready", Status: "False", ObservedGeneration: 2, Reason: "InvalidTemplate", Message: "could not render SLO templates: SLO template test-ns/test-tpl: could not get template: something"},
+				},
+				SLOs:      []slothv1.SLOStatus{{Name: "slo1"}},
+				Templates: []slothv1.SLOTemplateStatus{{Name: "test-tpl", Namespace: "test-ns", ObservedGeneration: 2}},
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "test-ns/test", Type: "Warning", Reason: "InvalidTemplate", Message: "Invalid SLO template: could not render SLO templates: SLO template test-ns/test-tpl: could not get template: something", Count: 1},
+			},
+			expErr: true,
+		},
+
+		"Correct objects without spec and template changes should be ignored.": {
+			psl: func() *slothv1.PrometheusServiceLevel {
+				psl := getTestTemplatedPSL()
+				psl.Status.ObservedGeneration = 2
+				psl.Status.PromOpRulesGenerated = true
+				psl.Status.LastPromOpRulesSuccessfulGenerated = &metav1.Time{Time: time.Now()}
+				psl.Status.Templates = []slothv1.SLOTemplateStatus{{Name: "test-tpl", Namespace: "test-ns", ObservedGeneration: 3}}
+				return psl
+			},
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository, mtg *kubecontrollermock.TemplateGetter) {
+				mtg.On("GetPrometheusServiceLevelTemplate", mock.Anything, "test-ns", "test-tpl").Once().Return(getTestSLOTemplate(), nil)
+			},
+			expEvents: []storagek8s.FakeEvent{},
+		},
+
+		"Correct objects without spec changes should be handled if the templates changed.": {
+			psl: func() *slothv1.PrometheusServiceLevel {
+				psl := getTestTemplatedPSL()
+				psl.Status.ObservedGeneration = 2
+				psl.Status.PromOpRulesGenerated = true
+				psl.Status.LastPromOpRulesSuccessfulGenerated = &metav1.Time{Time: time.Now()}
+				psl.Status.Templates = []slothv1.SLOTemplateStatus{{Name: "test-tpl", Namespace: "test-ns", ObservedGeneration: 2}}
+				return psl
+			},
+			mock: func(msl *kubecontrollermock.SpecLoader, mg *kubecontrollermock.Generator, mr *kubecontrollermock.Repository, mtg *kubecontrollermock.TemplateGetter) {
+				mtg.On("GetPrometheusServiceLevelTemplate", mock.Anything, "test-ns", "test-tpl").Once().Return(getTestSLOTemplate(), nil)
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(getTestSLOGroup(), nil)
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(getTestGenResponse(), nil)
+				mr.On("StoreSLOs", mock.Anything, mock.Anything, mock.Anything).Once().Return(nil)
+			},
+			expStatus: &slothv1.PrometheusServiceLevelStatus{
+				PromOpRulesGeneratedSLOs:           2,
+				ProcessedSLOs:                      2,
+				PromOpRulesGenerated:               true,
+				LastPromOpRulesSuccessfulGenerated: &metav1.Time{},
+				ObservedGeneration:                 2,
+				TargetNamespace:                    "test-ns",
+				Conditions: []metav1.Condition{
+					{Type: "SpecValid", Status: "True", ObservedGeneration: 2, Reason: "ValidSpec", Message: "The spec is valid"},
+					{Type: "RulesApplied", Status: "True", ObservedGeneration: 2, Reason: "RulesApplied", Message: "The rules of 2 SLOs have been applied"},
+					{Type: "Ready", Status: "True", ObservedGeneration: 2, Reason: "Ready", Message: "All the SLOs are ready"},
+				},
+				SLOs: []slothv1.SLOStatus{
+					{Name: "slo1", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo1", "sloth-slo-meta-recordings-svc-slo1"}},
+					{Name: "slo2", RuleGroups: []string{"sloth-slo-sli-recordings-svc-slo2", "sloth-slo-meta-recordings-svc-slo2"}},
+				},
+				Templates: []slothv1.SLOTemplateStatus{{Name: "test-tpl", Namespace: "test-ns", ObservedGeneration: 3}},
+			},
+			expEvents: []storagek8s.FakeEvent{
+				{Object: "test-ns/test", Type: "Normal", Reason: "RulesGenerated", Message: "Generated 4 Prometheus rules for 2 SLOs", Count: 1},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			// Mocks.
+			msl := kubecontrollermock.NewSpecLoader(t)
+			mg := kubecontrollermock.NewGenerator(t)
+			mr := kubecontrollermock.NewRepository(t)
+			mss := kubecontrollermock.NewKubeStatusStorer(t)
+			mtg := kubecontrollermock.NewTemplateGetter(t)
+			mer := storagek8s.NewFakeEventRecorder(nil)
+			test.mock(msl, mg, mr, mtg)
+
+			var gotStatus *slothv1.PrometheusServiceLevelStatus
+			if test.expStatus != nil {
+				mss.On("EnsurePrometheusServiceLevelStatus", mock.Anything, mock.Anything, mock.Anything).Once().Run(func(args mock.Arguments) {
+					s := args.Get(2).(slothv1.PrometheusServiceLevelStatus)
+					gotStatus = &s
+				}).Return(nil)
+			}
+
+			// Execute.
+			h, err := kubecontroller.NewHandler(kubecontroller.HandlerConfig{
+				SpecLoader:       msl,
+				Generator:        mg,
+				Repository:       mr,
+				KubeStatusStorer: mss,
+				EventRecorder:    mer,
+				TemplateGetter:   mtg,
+			})
+			require.NoError(err)
+			start := time.Now()
+			err = h.Handle(context.TODO(), test.psl())
+
+			// Check.
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			// Times set on the handling are not deterministic.
+			if gotStatus != nil {
+				if gotStatus.LastPromOpRulesSuccessfulGenerated != nil && !gotStatus.LastPromOpRulesSuccessfulGenerated.Time.Before(start) {
+					gotStatus.LastPromOpRulesSuccessfulGenerated = &metav1.Time{}
+				}
+				for i, c := range gotStatus.Conditions {
+					if !c.LastTransitionTime.Time.Before(start) {
+						gotStatus.Conditions[i].LastTransitionTime = metav1.Time{}
+					}
+				}
+			}
+			assert.Equal(test.expStatus, gotStatus)
+			assert.Equal(test.expEvents, mer.Events())
+		})
+	}
+}
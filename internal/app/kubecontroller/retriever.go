@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/spotahome/kooper/v2/controller"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -19,6 +21,26 @@ type RetrieverKubernetesRepository interface {
 	WatchPrometheusServiceLevels(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error)
 }
 
+// ClusterRetrieverKubernetesRepository is the service to manage cluster scoped k8s resources by the Kubernetes controller retrievers.
+type ClusterRetrieverKubernetesRepository interface {
+	ListClusterPrometheusServiceLevels(ctx context.Context, opts metav1.ListOptions) (*slothv1.ClusterPrometheusServiceLevelList, error)
+	WatchClusterPrometheusServiceLevels(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// TemplateRetrieverKubernetesRepository is the service to manage SLO template k8s resources by the Kubernetes controller retrievers.
+type TemplateRetrieverKubernetesRepository interface {
+	ListPrometheusServiceLevelTemplates(ctx context.Context, ns string, opts metav1.ListOptions) (*slothv1.PrometheusServiceLevelTemplateList, error)
+	WatchPrometheusServiceLevelTemplates(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// AutoSLORetrieverKubernetesRepository is the service to manage the auto SLOs workload k8s resources by the Kubernetes controller retrievers.
+type AutoSLORetrieverKubernetesRepository interface {
+	ListServices(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.ServiceList, error)
+	WatchServices(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error)
+	ListDeployments(ctx context.Context, ns string, opts metav1.ListOptions) (*appsv1.DeploymentList, error)
+	WatchDeployments(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error)
+}
+
 // NewPrometheusServiceLevelsRetriver returns the retriever for Prometheus service levels events.
 func NewPrometheusServiceLevelsRetriver(ns string, labelSelector labels.Selector, repo RetrieverKubernetesRepository) controller.Retriever {
 	return controller.MustRetrieverFromListerWatcher(&cache.ListWatch{
@@ -32,3 +54,57 @@ func NewPrometheusServiceLevelsRetriver(ns string, labelSelector labels.Selector
 		},
 	})
 }
+
+// NewClusterPrometheusServiceLevelsRetriver returns the retriever for cluster Prometheus service levels events.
+func NewClusterPrometheusServiceLevelsRetriver(labelSelector labels.Selector, repo ClusterRetrieverKubernetesRepository) controller.Retriever {
+	return controller.MustRetrieverFromListerWatcher(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = labelSelector.String()
+			return repo.ListClusterPrometheusServiceLevels(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = labelSelector.String()
+			return repo.WatchClusterPrometheusServiceLevels(context.Background(), options)
+		},
+	})
+}
+
+// NewPrometheusServiceLevelTemplatesRetriver returns the retriever for Prometheus service level templates events.
+func NewPrometheusServiceLevelTemplatesRetriver(ns string, repo TemplateRetrieverKubernetesRepository) controller.Retriever {
+	return controller.MustRetrieverFromListerWatcher(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return repo.ListPrometheusServiceLevelTemplates(context.Background(), ns, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return repo.WatchPrometheusServiceLevelTemplates(context.Background(), ns, options)
+		},
+	})
+}
+
+// NewServicesRetriver returns the retriever for Kubernetes services events.
+func NewServicesRetriver(ns string, labelSelector labels.Selector, repo AutoSLORetrieverKubernetesRepository) controller.Retriever {
+	return controller.MustRetrieverFromListerWatcher(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = labelSelector.String()
+			return repo.ListServices(context.Background(), ns, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = labelSelector.String()
+			return repo.WatchServices(context.Background(), ns, options)
+		},
+	})
+}
+
+// NewDeploymentsRetriver returns the retriever for Kubernetes deployments events.
+func NewDeploymentsRetriver(ns string, labelSelector labels.Selector, repo AutoSLORetrieverKubernetesRepository) controller.Retriever {
+	return controller.MustRetrieverFromListerWatcher(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = labelSelector.String()
+			return repo.ListDeployments(context.Background(), ns, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = labelSelector.String()
+			return repo.WatchDeployments(context.Background(), ns, options)
+		},
+	})
+}
@@ -0,0 +1,156 @@
+package kubecontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spotahome/kooper/v2/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/slok/sloth/internal/log"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// ServiceLevelLister knows how to list the service levels that can instantiate SLO templates.
+type ServiceLevelLister interface {
+	ListPrometheusServiceLevels(ctx context.Context, ns string, opts metav1.ListOptions) (*slothv1.PrometheusServiceLevelList, error)
+	ListClusterPrometheusServiceLevels(ctx context.Context, opts metav1.ListOptions) (*slothv1.ClusterPrometheusServiceLevelList, error)
+}
+
+// TemplateHandlerConfig is the SLO templates controller handler configuration.
+type TemplateHandlerConfig struct {
+	// ServiceLevelHandler is the service levels handler, used to handle the template instances again.
+	ServiceLevelHandler controller.Handler
+	ServiceLevelLister  ServiceLevelLister
+	// Namespace is the namespace of the handled PrometheusServiceLevels, by default all.
+	Namespace string
+	// LabelSelector is the label selector of the handled service levels.
+	LabelSelector labels.Selector
+	// DisableClusterServiceLevels disables the handling of the ClusterPrometheusServiceLevel template instances.
+	DisableClusterServiceLevels bool
+	Logger                      log.Logger
+}
+
+func (c *TemplateHandlerConfig) defaults() error {
+	if c.ServiceLevelHandler == nil {
+		return fmt.Errorf("service level handler is required")
+	}
+
+	if c.ServiceLevelLister == nil {
+		return fmt.Errorf("service level lister is required")
+	}
+
+	if c.LabelSelector == nil {
+		c.LabelSelector = labels.Everything()
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "kubecontroller.TemplateHandler"})
+
+	return nil
+}
+
+type templateHandler struct {
+	slHandler      controller.Handler
+	slLister       ServiceLevelLister
+	namespace      string
+	labelSelector  labels.Selector
+	disableCluster bool
+	logger         log.Logger
+}
+
+// NewTemplateHandler returns the SLO templates controller handler. When an SLO template changes, the service
+// levels that instantiate the template are handled again, so their SLOs are rendered with the new template.
+//
+// Deleted templates are not handled, their instances will report the missing template on their next handling.
+func NewTemplateHandler(config TemplateHandlerConfig) (controller.Handler, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &templateHandler{
+		slHandler:      config.ServiceLevelHandler,
+		slLister:       config.ServiceLevelLister,
+		namespace:      config.Namespace,
+		labelSelector:  config.LabelSelector,
+		disableCluster: config.DisableClusterServiceLevels,
+		logger:         config.Logger,
+	}, nil
+}
+
+func (h templateHandler) Handle(ctx context.Context, obj runtime.Object) error {
+	tpl, ok := obj.(*slothv1.PrometheusServiceLevelTemplate)
+	if !ok {
+		h.logger.Warningf("Unsuported Kubernetes object type: %s", obj.GetObjectKind())
+		return nil
+	}
+
+	ctx = h.logger.SetValuesOnCtx(ctx, log.Kv{"template-ns": tpl.Namespace, "template": tpl.Name})
+	logger := h.logger.WithCtxValues(ctx)
+	opts := metav1.ListOptions{LabelSelector: h.labelSelector.String()}
+
+	var errs []error
+	instances := 0
+
+	psls, err := h.slLister.ListPrometheusServiceLevels(ctx, h.namespace, opts)
+	if err != nil {
+		return fmt.Errorf("could not list PrometheusServiceLevels: %w", err)
+	}
+	for i := range psls.Items {
+		psl := &psls.Items[i]
+		if !instancesTemplate(psl.Spec.Templates, psl.Namespace, tpl) {
+			continue
+		}
+
+		instances++
+		err := h.slHandler.Handle(ctx, psl)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not handle %s/%s PrometheusServiceLevel: %w", psl.Namespace, psl.Name, err))
+		}
+	}
+
+	if !h.disableCluster {
+		cpsls, err := h.slLister.ListClusterPrometheusServiceLevels(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("could not list ClusterPrometheusServiceLevels: %w", err)
+		}
+		for i := range cpsls.Items {
+			cpsl := &cpsls.Items[i]
+			if !instancesTemplate(cpsl.Spec.Templates, cpsl.Spec.TargetNamespace, tpl) {
+				continue
+			}
+
+			instances++
+			err := h.slHandler.Handle(ctx, cpsl)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("could not handle %s ClusterPrometheusServiceLevel: %w", cpsl.Name, err))
+			}
+		}
+	}
+
+	logger.WithValues(log.Kv{"instances": instances}).Debugf("SLO template instances handled")
+
+	return errors.Join(errs...)
+}
+
+// instancesTemplate returns if any of the template instances is an instance of the template.
+func instancesTemplate(instances []slothv1.SLOTemplateInstance, defaultNamespace string, tpl *slothv1.PrometheusServiceLevelTemplate) bool {
+	for _, instance := range instances {
+		ns := instance.Namespace
+		if ns == "" {
+			ns = defaultNamespace
+		}
+
+		if instance.Name == tpl.Name && ns == tpl.Namespace {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,136 @@
+package kubecontroller_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/spotahome/kooper/v2/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/slok/sloth/internal/app/kubecontroller"
+	"github.com/slok/sloth/internal/app/kubecontroller/kubecontrollermock"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+func TestTemplateHandler(t *testing.T) {
+	psl := func(ns, name string, instances ...slothv1.SLOTemplateInstance) slothv1.PrometheusServiceLevel {
+		return slothv1.PrometheusServiceLevel{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			Spec:       slothv1.PrometheusServiceLevelSpec{Templates: instances},
+		}
+	}
+	cpsl := func(targetNS, name string, instances ...slothv1.SLOTemplateInstance) slothv1.ClusterPrometheusServiceLevel {
+		return slothv1.ClusterPrometheusServiceLevel{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: slothv1.ClusterPrometheusServiceLevelSpec{
+				TargetNamespace:            targetNS,
+				PrometheusServiceLevelSpec: slothv1.PrometheusServiceLevelSpec{Templates: instances},
+			},
+		}
+	}
+	tpl := &slothv1.PrometheusServiceLevelTemplate{ObjectMeta: metav1.ObjectMeta{Name: "http", Namespace: "tpl-ns"}}
+
+	tests := map[string]struct {
+		disableCluster bool
+		mock           func(ml *kubecontrollermock.ServiceLevelLister)
+		handleErr      error
+		expHandled     []string
+		expErr         bool
+	}{
+		"The service levels that instance the template should be handled.": {
+			mock: func(ml *kubecontrollermock.ServiceLevelLister) {
+				ml.On("ListPrometheusServiceLevels", mock.Anything, "", metav1.ListOptions{}).Once().Return(&slothv1.PrometheusServiceLevelList{
+					Items: []slothv1.PrometheusServiceLevel{
+						psl("tpl-ns", "psl1", slothv1.SLOTemplateInstance{Name: "http"}),
+						psl("other-ns", "psl2", slothv1.SLOTemplateInstance{Name: "http"}),
+						psl("other-ns", "psl3", slothv1.SLOTemplateInstance{Name: "grpc", Namespace: "tpl-ns"}, slothv1.SLOTemplateInstance{Name: "http", Namespace: "tpl-ns"}),
+						psl("tpl-ns", "psl4"),
+					},
+				}, nil)
+				ml.On("ListClusterPrometheusServiceLevels", mock.Anything, metav1.ListOptions{}).Once().Return(&slothv1.ClusterPrometheusServiceLevelList{
+					Items: []slothv1.ClusterPrometheusServiceLevel{
+						cpsl("tpl-ns", "cpsl1", slothv1.SLOTemplateInstance{Name: "http"}),
+						cpsl("other-ns", "cpsl2", slothv1.SLOTemplateInstance{Name: "http"}),
+					},
+				}, nil)
+			},
+			expHandled: []string{"tpl-ns/psl1", "other-ns/psl3", "/cpsl1"},
+		},
+
+		"Having the cluster service levels disabled should only handle the PrometheusServiceLevels.": {
+			disableCluster: true,
+			mock: func(ml *kubecontrollermock.ServiceLevelLister) {
+				ml.On("ListPrometheusServiceLevels", mock.Anything, "", metav1.ListOptions{}).Once().Return(&slothv1.PrometheusServiceLevelList{
+					Items: []slothv1.PrometheusServiceLevel{
+						psl("tpl-ns", "psl1", slothv1.SLOTemplateInstance{Name: "http"}),
+					},
+				}, nil)
+			},
+			expHandled: []string{"tpl-ns/psl1"},
+		},
+
+		"Failing listing the service levels should fail.": {
+			mock: func(ml *kubecontrollermock.ServiceLevelLister) {
+				ml.On("ListPrometheusServiceLevels", mock.Anything, mock.Anything, mock.Anything).Once().Return(nil, fmt.Errorf("something"))
+			},
+			expErr: true,
+		},
+
+		"Failing handling a service level should handle the rest and fail.": {
+			mock: func(ml *kubecontrollermock.ServiceLevelLister) {
+				ml.On("ListPrometheusServiceLevels", mock.Anything, "", metav1.ListOptions{}).Once().Return(&slothv1.PrometheusServiceLevelList{
+					Items: []slothv1.PrometheusServiceLevel{
+						psl("tpl-ns", "psl1", slothv1.SLOTemplateInstance{Name: "http"}),
+						psl("tpl-ns", "psl2", slothv1.SLOTemplateInstance{Name: "http"}),
+					},
+				}, nil)
+				ml.On("ListClusterPrometheusServiceLevels", mock.Anything, metav1.ListOptions{}).Once().Return(&slothv1.ClusterPrometheusServiceLevelList{}, nil)
+			},
+			handleErr:  fmt.Errorf("something"),
+			expHandled: []string{"tpl-ns/psl1", "tpl-ns/psl2"},
+			expErr:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			// Mocks.
+			ml := kubecontrollermock.NewServiceLevelLister(t)
+			test.mock(ml)
+
+			gotHandled := []string{}
+			slHandler := controller.HandlerFunc(func(_ context.Context, obj runtime.Object) error {
+				mobj := obj.(metav1.Object)
+				gotHandled = append(gotHandled, mobj.GetNamespace()+"/"+mobj.GetName())
+				return test.handleErr
+			})
+
+			// Execute.
+			h, err := kubecontroller.NewTemplateHandler(kubecontroller.TemplateHandlerConfig{
+				ServiceLevelHandler:         slHandler,
+				ServiceLevelLister:          ml,
+				DisableClusterServiceLevels: test.disableCluster,
+			})
+			require.NoError(err)
+			err = h.Handle(context.TODO(), tpl)
+
+			// Check.
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			if test.expHandled != nil {
+				assert.Equal(test.expHandled, gotHandled)
+			}
+		})
+	}
+}
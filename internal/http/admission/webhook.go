@@ -0,0 +1,194 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/slok/sloth/internal/app/generate"
+	"github.com/slok/sloth/internal/info"
+	"github.com/slok/sloth/internal/log"
+	commonmodel "github.com/slok/sloth/pkg/common/model"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// SpecLoader knows how to load raw Kubernetes PrometheusServiceLevel objects into an app model.
+type SpecLoader interface {
+	LoadSpec(ctx context.Context, data []byte) (*commonmodel.PromSLOGroup, error)
+}
+
+// Generator knows how to generate SLO prometheus rules from app SLO model.
+type Generator interface {
+	Generate(ctx context.Context, r generate.Request) (*generate.Response, error)
+}
+
+// WebhookConfig is the validating admission webhook configuration.
+type WebhookConfig struct {
+	SpecLoader  SpecLoader
+	Generator   Generator
+	ExtraLabels map[string]string
+	Logger      log.Logger
+}
+
+func (c *WebhookConfig) defaults() error {
+	if c.SpecLoader == nil {
+		return fmt.Errorf("spec loader is required")
+	}
+
+	if c.Generator == nil {
+		return fmt.Errorf("generator is required")
+	}
+
+	if c.ExtraLabels == nil {
+		c.ExtraLabels = map[string]string{}
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "admission.Webhook"})
+
+	return nil
+}
+
+type webhook struct {
+	specLoader  SpecLoader
+	generator   Generator
+	extraLabels map[string]string
+	logger      log.Logger
+}
+
+// NewWebhook returns the PrometheusServiceLevel validating admission webhook HTTP handler.
+//
+// The webhook loads the admitted objects and generates their SLOs (without storing anything),
+// the same way the controller would do, rejecting the invalid objects with the SLO that failed.
+func NewWebhook(config WebhookConfig) (http.Handler, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return webhook{
+		specLoader:  config.SpecLoader,
+		generator:   config.Generator,
+		extraLabels: config.ExtraLabels,
+		logger:      config.Logger,
+	}, nil
+}
+
+func (w webhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	review := admissionv1.AdmissionReview{}
+	err := json.NewDecoder(r.Body).Decode(&review)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("could not decode admission review: %s", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(rw, "admission review request is required", http.StatusBadRequest)
+		return
+	}
+
+	resp := w.review(r.Context(), review.Request)
+	resp.UID = review.Request.UID
+
+	// Use the same admission review version the apiserver sent us.
+	review.Request = nil
+	review.Response = resp
+	if review.APIVersion == "" {
+		review.APIVersion = admissionv1.SchemeGroupVersion.String()
+		review.Kind = "AdmissionReview"
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(rw).Encode(review)
+	if err != nil {
+		w.logger.Errorf("Could not write admission review response: %s", err)
+	}
+}
+
+func (w webhook) review(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	ctx = w.logger.SetValuesOnCtx(ctx, log.Kv{"ns": req.Namespace, "name": req.Name, "op": req.Operation})
+	logger := w.logger.WithCtxValues(ctx)
+
+	// We only validate the spec of the created and updated objects.
+	if (req.Operation != admissionv1.Create && req.Operation != admissionv1.Update) || req.SubResource != "" {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	err := w.validate(ctx, req.Object.Raw)
+	if err != nil {
+		logger.Infof("Object rejected: %s", err)
+		gk := slothv1.SchemeGroupVersion.WithKind("PrometheusServiceLevel").GroupKind()
+		status := kubeerrors.NewInvalid(gk, req.Name, err.errs).ErrStatus
+		return &admissionv1.AdmissionResponse{Allowed: false, Result: &status}
+	}
+
+	logger.Debugf("Object allowed")
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+// validationError has the field errors of an invalid object.
+type validationError struct {
+	errs field.ErrorList
+}
+
+func (v *validationError) Error() string { return v.errs.ToAggregate().Error() }
+
+func (w webhook) validate(ctx context.Context, data []byte) *validationError {
+	specPath := field.NewPath("spec")
+
+	model, err := w.specLoader.LoadSpec(ctx, data)
+	if err != nil {
+		return &validationError{errs: field.ErrorList{field.Invalid(specPath, field.OmitValueType{}, err.Error())}}
+	}
+
+	// The specs with only SLO templates don't have SLOs to validate, the SLO templates are rendered
+	// and validated by the controller (the templates can be created after their instances).
+	if len(model.SLOs) == 0 {
+		return nil
+	}
+
+	// Generate the rules in dry-run, we only want to know if the generation process succeeds.
+	req := generate.Request{
+		Info: commonmodel.Info{
+			Version: info.Version,
+			Mode:    commonmodel.ModeControllerGenKubernetes,
+			Spec:    fmt.Sprintf("%s/%s", slothv1.SchemeGroupVersion.Group, slothv1.SchemeGroupVersion.Version),
+		},
+		ExtraLabels: w.extraLabels,
+		SLOGroup:    *model,
+	}
+	_, err = w.generator.Generate(ctx, req)
+	if err == nil {
+		return nil
+	}
+
+	// Point to the SLO that failed if we can.
+	var sloErr *generate.SLOError
+	if errors.As(err, &sloErr) {
+		for i, slo := range model.SLOs {
+			if slo.ID != sloErr.SLOID {
+				continue
+			}
+
+			msg := sloErr.Err.Error()
+			if sloErr.PluginID != "" {
+				msg = fmt.Sprintf("plugin %q failed: %s", sloErr.PluginID, msg)
+			}
+			return &validationError{errs: field.ErrorList{field.Invalid(specPath.Child("slos").Index(i), slo.Name, msg)}}
+		}
+	}
+
+	return &validationError{errs: field.ErrorList{field.Invalid(specPath, field.OmitValueType{}, err.Error())}}
+}
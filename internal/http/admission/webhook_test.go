@@ -0,0 +1,238 @@
+package admission_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/slok/sloth/internal/app/generate"
+	"github.com/slok/sloth/internal/http/admission"
+	"github.com/slok/sloth/internal/http/admission/admissionmock"
+	"github.com/slok/sloth/pkg/common/model"
+)
+
+func getTestSLOGroup() *model.PromSLOGroup {
+	return &model.PromSLOGroup{
+		SLOs: []model.PromSLO{
+			{ID: "svc-slo1", Name: "slo1", Service: "svc"},
+			{ID: "svc-slo2", Name: "slo2", Service: "svc"},
+		},
+	}
+}
+
+func getTestAdmissionReview(op admissionv1.Operation) admissionv1.AdmissionReview {
+	return admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       "test-uid",
+			Name:      "test",
+			Namespace: "test-ns",
+			Operation: op,
+			Object:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"sloth.slok.dev/v1","kind":"PrometheusServiceLevel"}`)},
+		},
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	tests := map[string]struct {
+		method  string
+		review  func() any
+		mock    func(msl *admissionmock.SpecLoader, mg *admissionmock.Generator)
+		expCode int
+		expResp *admissionv1.AdmissionResponse
+	}{
+		"Non POST requests should fail.": {
+			method:  http.MethodGet,
+			review:  func() any { return getTestAdmissionReview(admissionv1.Create) },
+			mock:    func(msl *admissionmock.SpecLoader, mg *admissionmock.Generator) {},
+			expCode: http.StatusMethodNotAllowed,
+		},
+
+		"Invalid admission reviews should fail.": {
+			method:  http.MethodPost,
+			review:  func() any { return "not-a-review" },
+			mock:    func(msl *admissionmock.SpecLoader, mg *admissionmock.Generator) {},
+			expCode: http.StatusBadRequest,
+		},
+
+		"Admission reviews without request should fail.": {
+			method:  http.MethodPost,
+			review:  func() any { return admissionv1.AdmissionReview{} },
+			mock:    func(msl *admissionmock.SpecLoader, mg *admissionmock.Generator) {},
+			expCode: http.StatusBadRequest,
+		},
+
+		"Deleted objects should be allowed without validation.": {
+			method:  http.MethodPost,
+			review:  func() any { return getTestAdmissionReview(admissionv1.Delete) },
+			mock:    func(msl *admissionmock.SpecLoader, mg *admissionmock.Generator) {},
+			expCode: http.StatusOK,
+			expResp: &admissionv1.AdmissionResponse{UID: "test-uid", Allowed: true},
+		},
+
+		"Subresource updates should be allowed without validation.": {
+			method: http.MethodPost,
+			review: func() any {
+				r := getTestAdmissionReview(admissionv1.Update)
+				r.Request.SubResource = "status"
+				return r
+			},
+			mock:    func(msl *admissionmock.SpecLoader, mg *admissionmock.Generator) {},
+			expCode: http.StatusOK,
+			expResp: &admissionv1.AdmissionResponse{UID: "test-uid", Allowed: true},
+		},
+
+		"Valid objects should be allowed.": {
+			method: http.MethodPost,
+			review: func() any { return getTestAdmissionReview(admissionv1.Create) },
+			mock: func(msl *admissionmock.SpecLoader, mg *admissionmock.Generator) {
+				expData := []byte(`{"apiVersion":"sloth.slok.dev/v1","kind":"PrometheusServiceLevel"}`)
+				msl.On("LoadSpec", mock.Anything, expData).Once().Return(getTestSLOGroup(), nil)
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(&generate.Response{}, nil)
+			},
+			expCode: http.StatusOK,
+			expResp: &admissionv1.AdmissionResponse{UID: "test-uid", Allowed: true},
+		},
+
+		"Objects with only SLO templates should be allowed without generating.": {
+			method: http.MethodPost,
+			review: func() any { return getTestAdmissionReview(admissionv1.Create) },
+			mock: func(msl *admissionmock.SpecLoader, mg *admissionmock.Generator) {
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(&model.PromSLOGroup{}, nil)
+			},
+			expCode: http.StatusOK,
+			expResp: &admissionv1.AdmissionResponse{UID: "test-uid", Allowed: true},
+		},
+
+		"Objects with an invalid spec should be rejected.": {
+			method: http.MethodPost,
+			review: func() any { return getTestAdmissionReview(admissionv1.Update) },
+			mock: func(msl *admissionmock.SpecLoader, mg *admissionmock.Generator) {
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(nil, fmt.Errorf("something"))
+			},
+			expCode: http.StatusOK,
+			expResp: &admissionv1.AdmissionResponse{
+				UID:     "test-uid",
+				Allowed: false,
+				Result: &metav1.Status{
+					Status:  "Failure",
+					Message: `PrometheusServiceLevel.sloth.slok.dev "test" is invalid: spec: Invalid value: something`,
+					Reason:  metav1.StatusReasonInvalid,
+					Details: &metav1.StatusDetails{
+						Name:   "test",
+						Group:  "sloth.slok.dev",
+						Kind:   "PrometheusServiceLevel",
+						Causes: []metav1.StatusCause{{Type: metav1.CauseTypeFieldValueInvalid, Message: "Invalid value: something", Field: "spec"}},
+					},
+					Code: http.StatusUnprocessableEntity,
+				},
+			},
+		},
+
+		"Objects with an invalid SLO should be rejected with the SLO and plugin that failed.": {
+			method: http.MethodPost,
+			review: func() any { return getTestAdmissionReview(admissionv1.Create) },
+			mock: func(msl *admissionmock.SpecLoader, mg *admissionmock.Generator) {
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(getTestSLOGroup(), nil)
+				err := &generate.SLOError{SLOID: "svc-slo2", PluginID: "sloth.dev/core/validate/v1", Err: fmt.Errorf("invalid objective")}
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(nil, fmt.Errorf("could not generate: %w", err))
+			},
+			expCode: http.StatusOK,
+			expResp: &admissionv1.AdmissionResponse{
+				UID:     "test-uid",
+				Allowed: false,
+				Result: &metav1.Status{
+					Status:  "Failure",
+					Message: `PrometheusServiceLevel.sloth.slok.dev "test" is invalid: spec.slos[1]: Invalid value: "slo2": plugin "sloth.dev/core/validate/v1" failed: invalid objective`,
+					Reason:  metav1.StatusReasonInvalid,
+					Details: &metav1.StatusDetails{
+						Name:   "test",
+						Group:  "sloth.slok.dev",
+						Kind:   "PrometheusServiceLevel",
+						Causes: []metav1.StatusCause{{Type: metav1.CauseTypeFieldValueInvalid, Message: `Invalid value: "slo2": plugin "sloth.dev/core/validate/v1" failed: invalid objective`, Field: "spec.slos[1]"}},
+					},
+					Code: http.StatusUnprocessableEntity,
+				},
+			},
+		},
+
+		"Objects that fail the generation without an SLO error should be rejected.": {
+			method: http.MethodPost,
+			review: func() any { return getTestAdmissionReview(admissionv1.Create) },
+			mock: func(msl *admissionmock.SpecLoader, mg *admissionmock.Generator) {
+				msl.On("LoadSpec", mock.Anything, mock.Anything).Once().Return(getTestSLOGroup(), nil)
+				mg.On("Generate", mock.Anything, mock.Anything).Once().Return(nil, fmt.Errorf("something"))
+			},
+			expCode: http.StatusOK,
+			expResp: &admissionv1.AdmissionResponse{
+				UID:     "test-uid",
+				Allowed: false,
+				Result: &metav1.Status{
+					Status:  "Failure",
+					Message: `PrometheusServiceLevel.sloth.slok.dev "test" is invalid: spec: Invalid value: something`,
+					Reason:  metav1.StatusReasonInvalid,
+					Details: &metav1.StatusDetails{
+						Name:   "test",
+						Group:  "sloth.slok.dev",
+						Kind:   "PrometheusServiceLevel",
+						Causes: []metav1.StatusCause{{Type: metav1.CauseTypeFieldValueInvalid, Message: "Invalid value: something", Field: "spec"}},
+					},
+					Code: http.StatusUnprocessableEntity,
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			// Mocks.
+			msl := admissionmock.NewSpecLoader(t)
+			mg := admissionmock.NewGenerator(t)
+			test.mock(msl, mg)
+
+			// Prepare.
+			h, err := admission.NewWebhook(admission.WebhookConfig{
+				SpecLoader: msl,
+				Generator:  mg,
+			})
+			require.NoError(err)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			body, err := json.Marshal(test.review())
+			require.NoError(err)
+			req, err := http.NewRequest(test.method, srv.URL, bytes.NewReader(body))
+			require.NoError(err)
+
+			// Execute.
+			resp, err := srv.Client().Do(req)
+			require.NoError(err)
+			defer resp.Body.Close()
+
+			// Check.
+			require.Equal(test.expCode, resp.StatusCode)
+			if test.expResp != nil {
+				gotReview := admissionv1.AdmissionReview{}
+				err = json.NewDecoder(resp.Body).Decode(&gotReview)
+				require.NoError(err)
+				assert.Equal("admission.k8s.io/v1", gotReview.APIVersion)
+				assert.Equal("AdmissionReview", gotReview.Kind)
+				assert.Nil(gotReview.Request)
+				assert.Equal(test.expResp, gotReview.Response)
+			}
+		})
+	}
+}
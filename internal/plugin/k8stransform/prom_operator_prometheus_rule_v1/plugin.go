@@ -2,6 +2,9 @@ package plugin
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
@@ -11,10 +14,20 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/k8stransform/v1"
-	PluginID      = "sloth.dev/k8stransform/prom-operator-prometheus-rule/v1"
+	PluginVersion     = "prometheus/k8stransform/v1"
+	PluginID          = "sloth.dev/k8stransform/prom-operator-prometheus-rule/v1"
+	PluginDescription = "Transforms the generated SLO rules into Prometheus operator PrometheusRules, sharded when the rules exceed the PrometheusRule max size."
 )
 
+const (
+	// MaxSizeAnnotation overrides the max size in bytes of the rule groups of each PrometheusRule.
+	MaxSizeAnnotation = "sloth.dev/prometheus-rule-max-size"
+	// defaultMaxSize keeps the PrometheusRules far from the etcd object size limit (1.5MiB by default).
+	defaultMaxSize = 1024 * 1024
+)
+
+var PluginModes = []string{"cli-gen-k8s", "api-gen-k8s", "ctrl-gen-k8s"}
+
 func NewPlugin() (plugink8stransformv1.Plugin, error) {
 	return plugin{}, nil
 }
@@ -22,13 +35,14 @@ func NewPlugin() (plugink8stransformv1.Plugin, error) {
 type plugin struct{}
 
 func (p plugin) TransformK8sObjects(ctx context.Context, kmeta model.K8sMeta, sloResult model.PromSLOGroupResult) (*plugink8stransformv1.K8sObjects, error) {
-	u := &unstructured.Unstructured{}
-	u.SetAPIVersion("monitoring.coreos.com/v1")
-	u.SetKind("PrometheusRule")
-	u.SetNamespace(kmeta.Namespace)
-	u.SetName(kmeta.Name)
-	u.SetLabels(kmeta.Labels)
-	u.SetAnnotations(kmeta.Annotations)
+	maxSize := defaultMaxSize
+	if v, ok := kmeta.Annotations[MaxSizeAnnotation]; ok {
+		s, err := strconv.Atoi(v)
+		if err != nil || s <= 0 {
+			return nil, fmt.Errorf("invalid %q annotation %q: must be a positive number of bytes", MaxSizeAnnotation, v)
+		}
+		maxSize = s
+	}
 
 	groups := []any{}
 	for _, slo := range sloResult.SLOResults {
@@ -53,11 +67,68 @@ func (p plugin) TransformK8sObjects(ctx context.Context, kmeta model.K8sMeta, sl
 		}
 	}
 
-	u.Object["spec"] = map[string]any{
-		"groups": groups,
+	// SLO group extra rules.
+	for _, extraRG := range sloResult.ExtraRules {
+		if len(extraRG.Rules) == 0 {
+			continue
+		}
+		groups = append(groups, k8sutils.PromRuleGroupToUnstructuredPromOperator(extraRG))
+	}
+
+	shards, err := shardGroups(groups, maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first shard keeps the original name, so the rules that don't need sharding are not renamed.
+	// A shard name can match another service level name, Sloth refuses to overwrite the objects owned
+	// by a different service level, so they don't override each other.
+	items := []*unstructured.Unstructured{}
+	for i, shard := range shards {
+		name := kmeta.Name
+		if i > 0 {
+			name = fmt.Sprintf("%s-shard-%d", kmeta.Name, i)
+		}
+
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("monitoring.coreos.com/v1")
+		u.SetKind("PrometheusRule")
+		u.SetNamespace(kmeta.Namespace)
+		u.SetName(name)
+		u.SetLabels(kmeta.Labels)
+		u.SetAnnotations(kmeta.Annotations)
+		u.Object["spec"] = map[string]any{
+			"groups": shard,
+		}
+		items = append(items, u)
 	}
 
 	return &plugink8stransformv1.K8sObjects{
-		Items: []*unstructured.Unstructured{u},
+		Items: items,
 	}, nil
 }
+
+// shardGroups splits the rule groups in shards whose size doesn't exceed the max size, keeping the
+// rule groups order. Rule groups are not split, so a rule group bigger than the max size will have
+// its own shard.
+func shardGroups(groups []any, maxSize int) ([][]any, error) {
+	shards := [][]any{{}}
+	size := 0
+	for _, g := range groups {
+		data, err := json.Marshal(g)
+		if err != nil {
+			return nil, fmt.Errorf("could not marshal rule group: %w", err)
+		}
+
+		last := len(shards) - 1
+		if len(shards[last]) > 0 && size+len(data) > maxSize {
+			shards = append(shards, []any{})
+			last++
+			size = 0
+		}
+		shards[last] = append(shards[last], g)
+		size += len(data)
+	}
+
+	return shards, nil
+}
@@ -141,6 +141,140 @@ spec:
     rules:
     - alert: alert3
       expr: expC
+`,
+		},
+
+		"Test plugin with SLO group extra rules": {
+			kmeta: model.K8sMeta{
+				Namespace: "test-ns",
+				Name:      "test01",
+			},
+			slos: model.PromSLOGroupResult{
+				SLOResults: []model.PromSLOResult{
+					{
+						SLO: model.PromSLO{Name: "test"},
+						PrometheusRules: model.PromSLORules{
+							AlertRules: model.PromRuleGroup{
+								Name:  "slo-test-alert-rules",
+								Rules: []rulefmt.Rule{{Alert: "alert1", Expr: "expA"}},
+							},
+						},
+					},
+				},
+				ExtraRules: []model.PromRuleGroup{
+					{
+						Name:     "slo-group-rollup-rules",
+						Interval: 5 * time.Minute,
+						Rules:    []rulefmt.Rule{{Record: "rollup1", Expr: "expR"}},
+					},
+					{Name: "slo-group-empty-rules"},
+				},
+			},
+			expYAML: `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# DO NOT EDIT.
+
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  labels:
+    app.kubernetes.io/component: SLO
+    app.kubernetes.io/managed-by: sloth
+  name: test01
+  namespace: test-ns
+spec:
+  groups:
+  - name: slo-test-alert-rules
+    rules:
+    - alert: alert1
+      expr: expA
+  - interval: 5m
+    name: slo-group-rollup-rules
+    rules:
+    - expr: expR
+      record: rollup1
+`,
+		},
+
+		"Test plugin with rules exceeding the max size should shard the PrometheusRules": {
+			kmeta: model.K8sMeta{
+				Namespace:   "test-ns",
+				Name:        "test01",
+				Labels:      map[string]string{"app": "sloth"},
+				Annotations: map[string]string{"sloth.dev/prometheus-rule-max-size": "160"},
+			},
+			slos: model.PromSLOGroupResult{
+				SLOResults: []model.PromSLOResult{
+					{
+						SLO: model.PromSLO{Name: "test"},
+						PrometheusRules: model.PromSLORules{
+							SLIErrorRecRules: model.PromRuleGroup{
+								Name:  "slo-test-sli-error-rules",
+								Rules: []rulefmt.Rule{{Record: "rec1", Expr: "exp1"}},
+							},
+							MetadataRecRules: model.PromRuleGroup{
+								Name:  "slo-test-metadata-rules",
+								Rules: []rulefmt.Rule{{Record: "rec2", Expr: "exp2"}},
+							},
+							AlertRules: model.PromRuleGroup{
+								Name:  "slo-test-alert-rules",
+								Rules: []rulefmt.Rule{{Alert: "alert1", Expr: "expA"}, {Alert: "alert2", Expr: "expB"}},
+							},
+						},
+					},
+				},
+			},
+			expYAML: `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# DO NOT EDIT.
+
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  annotations:
+    sloth.dev/prometheus-rule-max-size: "160"
+  labels:
+    app: sloth
+    app.kubernetes.io/component: SLO
+    app.kubernetes.io/managed-by: sloth
+  name: test01
+  namespace: test-ns
+spec:
+  groups:
+  - name: slo-test-sli-error-rules
+    rules:
+    - expr: exp1
+      record: rec1
+  - name: slo-test-metadata-rules
+    rules:
+    - expr: exp2
+      record: rec2
+
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# DO NOT EDIT.
+
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  annotations:
+    sloth.dev/prometheus-rule-max-size: "160"
+  labels:
+    app: sloth
+    app.kubernetes.io/component: SLO
+    app.kubernetes.io/managed-by: sloth
+  name: test01-shard-1
+  namespace: test-ns
+spec:
+  groups:
+  - name: slo-test-alert-rules
+    rules:
+    - alert: alert1
+      expr: expA
+    - alert: alert2
+      expr: expB
 `,
 		},
 	}
@@ -0,0 +1,377 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/slok/sloth/pkg/common/conventions"
+	"github.com/slok/sloth/pkg/common/model"
+	utilsdata "github.com/slok/sloth/pkg/common/utils/data"
+	promutils "github.com/slok/sloth/pkg/common/utils/prometheus"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+const (
+	PluginVersion      = "prometheus/slo/v1"
+	PluginID           = "sloth.dev/contrib/business_hours/v1"
+	PluginDescription  = "Pages only during business hours, outside of them the page alerts fire with the ticket severity labels."
+	PluginConfigSchema = `{
+  "type": "object",
+  "properties": {
+    "timezone": {
+      "type": "string",
+      "description": "IANA timezone of the business hours (default UTC)."
+    },
+    "weekdays": {
+      "type": "array",
+      "items": {
+        "type": "string",
+        "enum": ["monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"]
+      },
+      "description": "Business days (default monday to friday)."
+    },
+    "start": {
+      "type": "string",
+      "pattern": "^([01][0-9]|2[0-3]):[0-5][0-9]$",
+      "description": "Business hours start time, HH:MM (default 09:00)."
+    },
+    "end": {
+      "type": "string",
+      "pattern": "^([01][0-9]|2[0-4]):[0-5][0-9]$",
+      "description": "Business hours end time, HH:MM (default 17:00)."
+    },
+    "holidays": {
+      "type": "array",
+      "items": {
+        "type": "string",
+        "pattern": "^([0-9]{4}-)?[0-9]{2}-[0-9]{2}$"
+      },
+      "description": "Days out of business hours, YYYY-MM-DD or MM-DD for every year."
+    }
+  },
+  "additionalProperties": false
+}`
+	PluginConfigExample = `{"timezone": "Europe/Madrid", "start": "08:00", "end": "18:00", "holidays": ["12-25", "2026-04-03"]}`
+)
+
+const (
+	localTimeMetric = "slo:business_hours_local_time:seconds" // The SLO timezone local time metric name.
+	activeMetric    = "slo:business_hours:active"             // The business hours active (1) or not (0) metric name.
+
+	// PromQL time functions are UTC, so the timezone offset changes (e.g DST) are resolved in these years.
+	offsetFromYear = 2025
+	offsetToYear   = 2040
+)
+
+var weekdayNumbers = map[string]int{
+	"sunday":    0,
+	"monday":    1,
+	"tuesday":   2,
+	"wednesday": 3,
+	"thursday":  4,
+	"friday":    5,
+	"saturday":  6,
+}
+
+type Config struct {
+	Timezone string   `json:"timezone,omitempty"` // default "UTC".
+	Weekdays []string `json:"weekdays,omitempty"` // default monday to friday.
+	Start    string   `json:"start,omitempty"`    // default "09:00".
+	End      string   `json:"end,omitempty"`      // default "17:00".
+	Holidays []string `json:"holidays,omitempty"`
+}
+
+func NewPlugin(configData json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+	cfg := Config{}
+	if err := json.Unmarshal(configData, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid plugin config: %w", err)
+	}
+
+	if cfg.Timezone == "" {
+		cfg.Timezone = "UTC"
+	}
+
+	if len(cfg.Weekdays) == 0 {
+		cfg.Weekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday"}
+	}
+
+	if cfg.Start == "" {
+		cfg.Start = "09:00"
+	}
+
+	if cfg.End == "" {
+		cfg.End = "17:00"
+	}
+
+	start, err := dayMinute(cfg.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := dayMinute(cfg.End)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end: %w", err)
+	}
+	if start >= end {
+		return nil, fmt.Errorf("start (%s) must be before end (%s)", cfg.Start, cfg.End)
+	}
+
+	weekdays := []int{}
+	seen := map[string]bool{}
+	for _, wd := range cfg.Weekdays {
+		n, ok := weekdayNumbers[wd]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", wd)
+		}
+		if seen[wd] {
+			continue
+		}
+		seen[wd] = true
+		weekdays = append(weekdays, n)
+	}
+
+	holidays := []holiday{}
+	for _, h := range cfg.Holidays {
+		if seen[h] {
+			continue
+		}
+		seen[h] = true
+
+		hd, err := parseHoliday(h)
+		if err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, hd)
+	}
+
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", cfg.Timezone, err)
+	}
+
+	return plugin{
+		config:      cfg,
+		start:       start,
+		end:         end,
+		weekdays:    weekdays,
+		holidays:    holidays,
+		localTimeEx: localTimeExpr(loc),
+	}, nil
+}
+
+type plugin struct {
+	config      Config
+	start       int
+	end         int
+	weekdays    []int
+	holidays    []holiday
+	localTimeEx string
+}
+
+func (p plugin) ProcessSLO(_ context.Context, req *pluginslov1.Request, result *pluginslov1.Result) error {
+	slo := req.SLO
+	filter := promutils.LabelsToPromFilter(conventions.GetSLOIDPromLabels(slo))
+	activeSeries := activeMetric + filter
+
+	rules := []rulefmt.Rule{}
+	guarded := false
+	for _, rule := range result.SLORules.AlertRules.Rules {
+		if rule.Alert == "" || rule.Labels[conventions.PromSLOSeverityLabelName] != model.PageAlertSeverity.String() {
+			rules = append(rules, rule)
+			continue
+		}
+
+		pageExpr, err := businessHoursExpr(rule.Expr, "and", activeSeries)
+		if err != nil {
+			return fmt.Errorf("could not create %q business hours alert: %w", rule.Alert, err)
+		}
+		ticketExpr, err := businessHoursExpr(rule.Expr, "unless", activeSeries)
+		if err != nil {
+			return fmt.Errorf("could not create %q out of business hours alert: %w", rule.Alert, err)
+		}
+
+		page := rule
+		page.Expr = pageExpr
+
+		ticket := rule
+		ticket.Expr = ticketExpr
+		ticket.Labels = utilsdata.MergeLabels(
+			withoutKeys(rule.Labels, slo.PageAlertMeta.Labels),
+			map[string]string{conventions.PromSLOSeverityLabelName: model.TicketAlertSeverity.String()},
+			slo.TicketAlertMeta.Labels,
+		)
+		ticket.Annotations = utilsdata.MergeLabels(withoutKeys(rule.Annotations, slo.PageAlertMeta.Annotations), slo.TicketAlertMeta.Annotations)
+		if title, ok := ticket.Annotations["title"]; ok {
+			ticket.Annotations["title"] = strings.Replace(title, "(page)", "(ticket)", 1)
+		}
+
+		rules = append(rules, page, ticket)
+		guarded = true
+	}
+
+	// Nothing to route.
+	if !guarded {
+		return nil
+	}
+	result.SLORules.AlertRules.Rules = rules
+
+	labels := utilsdata.MergeLabels(conventions.GetSLOIDPromLabels(slo), slo.Labels)
+	result.SLORules.MetadataRecRules.Rules = append(result.SLORules.MetadataRecRules.Rules,
+		rulefmt.Rule{
+			Record: localTimeMetric,
+			Expr:   p.localTimeEx,
+			Labels: labels,
+		},
+		rulefmt.Rule{
+			Record: activeMetric,
+			Expr:   p.activeExpr(localTimeMetric + filter),
+			Labels: labels,
+		},
+	)
+
+	return nil
+}
+
+// activeExpr returns the expression that is 1 in business hours and 0 outside of them, using
+// the local time series.
+func (p plugin) activeExpr(localTime string) string {
+	minute := fmt.Sprintf("(hour(%s) * 60 + minute(%s))", localTime, localTime)
+
+	weekdays := []string{}
+	for _, wd := range p.weekdays {
+		weekdays = append(weekdays, fmt.Sprintf("(day_of_week(%s) == bool %d)", localTime, wd))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "(%s >= bool %d)\n", minute, p.start)
+	fmt.Fprintf(&b, "*\n(%s < bool %d)\n", minute, p.end)
+	fmt.Fprintf(&b, "*\n(\n  %s\n)\n", strings.Join(weekdays, "\n  +\n  "))
+
+	if len(p.holidays) > 0 {
+		holidays := []string{}
+		for _, h := range p.holidays {
+			conds := []string{}
+			if h.year != 0 {
+				conds = append(conds, fmt.Sprintf("(year(%s) == bool %d)", localTime, h.year))
+			}
+			conds = append(conds,
+				fmt.Sprintf("(month(%s) == bool %d)", localTime, h.month),
+				fmt.Sprintf("(day_of_month(%s) == bool %d)", localTime, h.day),
+			)
+			holidays = append(holidays, strings.Join(conds, " * "))
+		}
+		fmt.Fprintf(&b, "*\n(\n  1 -\n  (\n    %s\n  )\n)\n", strings.Join(holidays, "\n    +\n    "))
+	}
+
+	return b.String()
+}
+
+// businessHoursExpr returns the alert expression conditioned (`and`) or negatively conditioned (`unless`)
+// to the business hours.
+func businessHoursExpr(expr, op, activeSeries string) (string, error) {
+	expr = strings.ReplaceAll(strings.TrimSuffix(expr, "\n"), "\n", "\n    ")
+	e := fmt.Sprintf("(\n    %s\n)\n%s on ()\n(%s == 1)\n", expr, op, activeSeries)
+
+	// Make sure we don't generate broken expressions.
+	if _, err := parser.ParseExpr(e); err != nil {
+		return "", fmt.Errorf("invalid alert expression: %w", err)
+	}
+
+	return e, nil
+}
+
+// localTimeExpr returns the expression with the timestamp on the location, PromQL time functions
+// use UTC so we add the location offset, and its changes (e.g DST) as conditions over the time.
+func localTimeExpr(loc *time.Location) string {
+	from := time.Date(offsetFromYear, time.January, 1, 0, 0, 0, 0, loc)
+	to := time.Date(offsetToYear+1, time.January, 1, 0, 0, 0, 0, loc)
+
+	_, offset := from.Zone()
+	terms := []string{"time()"}
+	if offset > 0 {
+		terms = append(terms, fmt.Sprintf("+ %d", offset))
+	} else if offset < 0 {
+		terms = append(terms, fmt.Sprintf("- %d", -offset))
+	}
+	t := from
+	for {
+		_, end := t.ZoneBounds()
+		if end.IsZero() || !end.Before(to) {
+			break
+		}
+
+		// The zone bounds can be stuck on the same time at the end of the location rules, move forward.
+		if !end.After(t) {
+			t = t.Add(24 * time.Hour)
+			continue
+		}
+
+		_, newOffset := end.Zone()
+		delta := newOffset - offset
+		if delta > 0 {
+			terms = append(terms, fmt.Sprintf("+ %d * (time() >= bool %d)", delta, end.Unix()))
+		} else if delta < 0 {
+			terms = append(terms, fmt.Sprintf("- %d * (time() >= bool %d)", -delta, end.Unix()))
+		}
+		offset = newOffset
+		t = end
+	}
+
+	return fmt.Sprintf("vector(\n  %s\n)\n", strings.Join(terms, "\n  "))
+}
+
+type holiday struct {
+	year  int // 0 means every year.
+	month int
+	day   int
+}
+
+func parseHoliday(h string) (holiday, error) {
+	// Every year holidays use a leap year so February 29 is valid.
+	everyYear := len(h) == len("01-02")
+	date := h
+	if everyYear {
+		date = "2000-" + h
+	}
+
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return holiday{}, fmt.Errorf("invalid holiday %q: %w", h, err)
+	}
+
+	hd := holiday{month: int(t.Month()), day: t.Day()}
+	if !everyYear {
+		hd.year = t.Year()
+	}
+
+	return hd, nil
+}
+
+// dayMinute returns the minute of the day of a HH:MM time.
+func dayMinute(hm string) (int, error) {
+	var h, m int
+	if _, err := fmt.Sscanf(hm, "%d:%d", &h, &m); err != nil {
+		return 0, fmt.Errorf("invalid time %q: %w", hm, err)
+	}
+	if h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("invalid time %q", hm)
+	}
+
+	return h*60 + m, nil
+}
+
+// withoutKeys returns a copy of the map without the keys of the other map.
+func withoutKeys(m, keys map[string]string) map[string]string {
+	res := map[string]string{}
+	for k, v := range m {
+		if _, ok := keys[k]; !ok {
+			res[k] = v
+		}
+	}
+	return res
+}
@@ -0,0 +1,388 @@
+package plugin_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	plugin "github.com/slok/sloth/internal/plugin/slo/contrib/business_hours_v1"
+	alertrulesv1 "github.com/slok/sloth/internal/plugin/slo/core/alert_rules_v1"
+	"github.com/slok/sloth/pkg/common/model"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+	pluginslov1testing "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1/testing"
+)
+
+func baseSLO() model.PromSLO {
+	return model.PromSLO{
+		ID:      "svc01-slo01",
+		Name:    "slo01",
+		Service: "svc01",
+		Labels:  map[string]string{"owner": "team01"},
+		PageAlertMeta: model.PromAlertMeta{
+			Name:        "MyAlert",
+			Labels:      map[string]string{"routing_key": "oncall"},
+			Annotations: map[string]string{"runbook": "https://runbooks.test/page"},
+		},
+		TicketAlertMeta: model.PromAlertMeta{
+			Name:   "MyAlert",
+			Labels: map[string]string{"slack_channel": "#team01"},
+		},
+	}
+}
+
+func baseAlertRules() []rulefmt.Rule {
+	return []rulefmt.Rule{
+		{
+			Alert: "MyAlert",
+			Expr:  "max(slo:sli_error:ratio_rate5m{sloth_id=\"svc01-slo01\"} > 0.01) without (sloth_window)\n",
+			Labels: map[string]string{
+				"sloth_severity": "page",
+				"routing_key":    "oncall",
+				"team":           "team01",
+			},
+			Annotations: map[string]string{
+				"title":   "(page) SLO error budget burn rate is too fast.",
+				"runbook": "https://runbooks.test/page",
+			},
+		},
+		{
+			Alert:  "MyAlert",
+			Expr:   "max(slo:sli_error:ratio_rate2h{sloth_id=\"svc01-slo01\"} > 0.003) without (sloth_window)\n",
+			Labels: map[string]string{"sloth_severity": "ticket", "slack_channel": "#team01"},
+		},
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	tests := map[string]struct {
+		config     json.RawMessage
+		req        pluginslov1.Request
+		res        pluginslov1.Result
+		expRes     pluginslov1.Result
+		expLoadErr bool
+		expErr     bool
+	}{
+		"An invalid timezone should fail.": {
+			config:     json.RawMessage(`{"timezone": "Mars/Olympus"}`),
+			expLoadErr: true,
+		},
+
+		"A start after the end should fail.": {
+			config:     json.RawMessage(`{"start": "18:00", "end": "09:00"}`),
+			expLoadErr: true,
+		},
+
+		"An invalid holiday should fail.": {
+			config:     json.RawMessage(`{"holidays": ["2026-02-30"]}`),
+			expLoadErr: true,
+		},
+
+		"An invalid page alert expression should fail.": {
+			config: json.RawMessage(`{}`),
+			req:    pluginslov1.Request{SLO: baseSLO()},
+			res: pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+				{Alert: "MyAlert", Expr: "max(", Labels: map[string]string{"sloth_severity": "page"}},
+			}}}},
+			expErr: true,
+		},
+
+		"Without page alerts nothing should change.": {
+			config: json.RawMessage(`{}`),
+			req:    pluginslov1.Request{SLO: baseSLO()},
+			res: pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+				baseAlertRules()[1],
+			}}}},
+			expRes: pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+				baseAlertRules()[1],
+			}}}},
+		},
+
+		"The page alert should be split in business hours page and out of business hours ticket alerts with the defaults.": {
+			config: json.RawMessage(`{}`),
+			req:    pluginslov1.Request{SLO: baseSLO()},
+			res:    pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: baseAlertRules()}}},
+			expRes: pluginslov1.Result{SLORules: model.PromSLORules{
+				MetadataRecRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+					{
+						Record: "slo:business_hours_local_time:seconds",
+						Expr: `vector(
+  time()
+)
+`,
+						Labels: map[string]string{"owner": "team01", "sloth_id": "svc01-slo01", "sloth_service": "svc01", "sloth_slo": "slo01"},
+					},
+					{
+						Record: "slo:business_hours:active",
+						Expr: `((hour(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) * 60 + minute(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"})) >= bool 540)
+*
+((hour(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) * 60 + minute(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"})) < bool 1020)
+*
+(
+  (day_of_week(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) == bool 1)
+  +
+  (day_of_week(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) == bool 2)
+  +
+  (day_of_week(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) == bool 3)
+  +
+  (day_of_week(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) == bool 4)
+  +
+  (day_of_week(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) == bool 5)
+)
+`,
+						Labels: map[string]string{"owner": "team01", "sloth_id": "svc01-slo01", "sloth_service": "svc01", "sloth_slo": "slo01"},
+					},
+				}},
+				AlertRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+					{
+						Alert: "MyAlert",
+						Expr: `(
+    max(slo:sli_error:ratio_rate5m{sloth_id="svc01-slo01"} > 0.01) without (sloth_window)
+)
+and on ()
+(slo:business_hours:active{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"} == 1)
+`,
+						Labels: map[string]string{
+							"sloth_severity": "page",
+							"routing_key":    "oncall",
+							"team":           "team01",
+						},
+						Annotations: map[string]string{
+							"title":   "(page) SLO error budget burn rate is too fast.",
+							"runbook": "https://runbooks.test/page",
+						},
+					},
+					{
+						Alert: "MyAlert",
+						Expr: `(
+    max(slo:sli_error:ratio_rate5m{sloth_id="svc01-slo01"} > 0.01) without (sloth_window)
+)
+unless on ()
+(slo:business_hours:active{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"} == 1)
+`,
+						Labels: map[string]string{
+							"sloth_severity": "ticket",
+							"slack_channel":  "#team01",
+							"team":           "team01",
+						},
+						Annotations: map[string]string{
+							"title": "(ticket) SLO error budget burn rate is too fast.",
+						},
+					},
+					baseAlertRules()[1],
+				}},
+			}},
+		},
+
+		"A custom timezone, business hours and holidays should be used on the business hours rules.": {
+			config: json.RawMessage(`{"timezone": "Europe/Madrid", "weekdays": ["monday", "wednesday", "monday"], "start": "08:30", "end": "18:00", "holidays": ["12-25", "2026-04-03", "12-25"]}`),
+			req:    pluginslov1.Request{SLO: baseSLO()},
+			res: pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+				baseAlertRules()[0],
+			}}}},
+			expRes: pluginslov1.Result{SLORules: model.PromSLORules{
+				MetadataRecRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+					{
+						Record: "slo:business_hours_local_time:seconds",
+						Expr: `vector(
+  time()
+  + 3600
+  + 3600 * (time() >= bool 1743296400)
+  - 3600 * (time() >= bool 1761440400)
+  + 3600 * (time() >= bool 1774746000)
+  - 3600 * (time() >= bool 1792890000)
+  + 3600 * (time() >= bool 1806195600)
+  - 3600 * (time() >= bool 1824944400)
+  + 3600 * (time() >= bool 1837645200)
+  - 3600 * (time() >= bool 1856394000)
+  + 3600 * (time() >= bool 1869094800)
+  - 3600 * (time() >= bool 1887843600)
+  + 3600 * (time() >= bool 1901149200)
+  - 3600 * (time() >= bool 1919293200)
+  + 3600 * (time() >= bool 1932598800)
+  - 3600 * (time() >= bool 1950742800)
+  + 3600 * (time() >= bool 1964048400)
+  - 3600 * (time() >= bool 1982797200)
+  + 3600 * (time() >= bool 1995498000)
+  - 3600 * (time() >= bool 2014246800)
+  + 3600 * (time() >= bool 2026947600)
+  - 3600 * (time() >= bool 2045696400)
+  + 3600 * (time() >= bool 2058397200)
+  - 3600 * (time() >= bool 2077146000)
+  + 3600 * (time() >= bool 2090451600)
+  - 3600 * (time() >= bool 2108595600)
+  + 3600 * (time() >= bool 2121901200)
+  - 3600 * (time() >= bool 2140045200)
+  + 3600 * (time() >= bool 2153350800)
+  - 3600 * (time() >= bool 2172099600)
+  + 3600 * (time() >= bool 2184800400)
+  - 3600 * (time() >= bool 2203549200)
+  + 3600 * (time() >= bool 2216250000)
+  - 3600 * (time() >= bool 2234998800)
+)
+`,
+						Labels: map[string]string{"owner": "team01", "sloth_id": "svc01-slo01", "sloth_service": "svc01", "sloth_slo": "slo01"},
+					},
+					{
+						Record: "slo:business_hours:active",
+						Expr: `((hour(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) * 60 + minute(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"})) >= bool 510)
+*
+((hour(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) * 60 + minute(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"})) < bool 1080)
+*
+(
+  (day_of_week(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) == bool 1)
+  +
+  (day_of_week(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) == bool 3)
+)
+*
+(
+  1 -
+  (
+    (month(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) == bool 12) * (day_of_month(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) == bool 25)
+    +
+    (year(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) == bool 2026) * (month(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) == bool 4) * (day_of_month(slo:business_hours_local_time:seconds{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}) == bool 3)
+  )
+)
+`,
+						Labels: map[string]string{"owner": "team01", "sloth_id": "svc01-slo01", "sloth_service": "svc01", "sloth_slo": "slo01"},
+					},
+				}},
+				AlertRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+					{
+						Alert: "MyAlert",
+						Expr: `(
+    max(slo:sli_error:ratio_rate5m{sloth_id="svc01-slo01"} > 0.01) without (sloth_window)
+)
+and on ()
+(slo:business_hours:active{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"} == 1)
+`,
+						Labels: map[string]string{
+							"sloth_severity": "page",
+							"routing_key":    "oncall",
+							"team":           "team01",
+						},
+						Annotations: map[string]string{
+							"title":   "(page) SLO error budget burn rate is too fast.",
+							"runbook": "https://runbooks.test/page",
+						},
+					},
+					{
+						Alert: "MyAlert",
+						Expr: `(
+    max(slo:sli_error:ratio_rate5m{sloth_id="svc01-slo01"} > 0.01) without (sloth_window)
+)
+unless on ()
+(slo:business_hours:active{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"} == 1)
+`,
+						Labels: map[string]string{
+							"sloth_severity": "ticket",
+							"slack_channel":  "#team01",
+							"team":           "team01",
+						},
+						Annotations: map[string]string{
+							"title": "(ticket) SLO error budget burn rate is too fast.",
+						},
+					},
+				}},
+			}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			plugin, err := pluginslov1testing.NewTestPlugin(t.Context(), pluginslov1testing.TestPluginConfig{
+				PluginConfiguration: test.config,
+			})
+			if test.expLoadErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+
+			gotRes := test.res
+			err = plugin.ProcessSLO(t.Context(), &test.req, &gotRes)
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expRes, gotRes)
+			}
+		})
+	}
+}
+
+func TestPluginRules(t *testing.T) {
+	spec := []byte(`
+version: "prometheus/v1"
+service: "myservice"
+slos:
+  - name: "requests-availability"
+    objective: 99.9
+    sli:
+      events:
+        error_query: sum(rate(http_requests_total{code=~"5.."}[{{.window}}]))
+        total_query: sum(rate(http_requests_total[{{.window}}]))
+    alerting:
+      name: MyServiceHighErrorRate
+      page_alert:
+        labels:
+          routing_key: myteam
+      ticket_alert:
+        disable: true
+        labels:
+          slack_channel: "#alerts-myteam"
+`)
+
+	pt, err := pluginslov1testing.NewPluginTester(t.Context(), pluginslov1testing.TestPluginConfig{
+		PluginConfiguration: json.RawMessage(`{"timezone": "America/New_York", "holidays": ["07-04"]}`),
+	})
+	require.NoError(t, err)
+	req, err := pluginslov1testing.NewRequestFromSpec(t.Context(), spec, "")
+	require.NoError(t, err)
+
+	// Use the core alert rules plugin alerts, like Sloth does.
+	alertRules, err := alertrulesv1.NewPlugin(nil, pluginslov1.AppUtils{})
+	require.NoError(t, err)
+	res := &pluginslov1.Result{}
+	err = alertRules.ProcessSLO(t.Context(), req, res)
+	require.NoError(t, err)
+
+	pt.AssertGoldenYAML(t, "testdata/golden/rules.yml", req, res)
+}
+
+func BenchmarkPluginYaegi(b *testing.B) {
+	plugin, err := pluginslov1testing.NewTestPlugin(b.Context(), pluginslov1testing.TestPluginConfig{
+		PluginConfiguration: json.RawMessage(`{"timezone": "Europe/Madrid"}`),
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	req := &pluginslov1.Request{SLO: baseSLO()}
+	for b.Loop() {
+		res := &pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: baseAlertRules()}}}
+		err = plugin.ProcessSLO(b.Context(), req, res)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPluginGo(b *testing.B) {
+	plugin, err := plugin.NewPlugin([]byte(`{"timezone": "Europe/Madrid"}`), pluginslov1.AppUtils{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	req := &pluginslov1.Request{SLO: baseSLO()}
+	for b.Loop() {
+		res := &pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: baseAlertRules()}}}
+		err = plugin.ProcessSLO(b.Context(), req, res)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
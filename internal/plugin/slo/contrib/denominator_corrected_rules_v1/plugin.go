@@ -17,8 +17,20 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/slo/v1"
-	PluginID      = "sloth.dev/contrib/denominator_corrected_rules/v1"
+	PluginVersion      = "prometheus/slo/v1"
+	PluginID           = "sloth.dev/contrib/denominator_corrected_rules/v1"
+	PluginDescription  = "Replaces the SLI error ratio recording rules with denominator corrected ones, so low traffic windows don't distort the error ratios."
+	PluginConfigSchema = `{
+  "type": "object",
+  "properties": {
+    "disableOptimized": {
+      "type": "boolean",
+      "description": "Disables the optimized long window SLI recording rules."
+    }
+  },
+  "additionalProperties": false
+}`
+	PluginConfigExample = `{"disableOptimized": false}`
 )
 
 const (
@@ -14,8 +14,50 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/slo/v1"
-	PluginID      = "sloth.dev/contrib/error_budget_exhausted_alert/v1"
+	PluginVersion      = "prometheus/slo/v1"
+	PluginID           = "sloth.dev/contrib/error_budget_exhausted_alert/v1"
+	PluginDescription  = "Adds an alert that fires when the SLO error budget remaining ratio is at or below a threshold (by default fully exhausted)."
+	PluginConfigSchema = `{
+  "type": "object",
+  "properties": {
+    "threshold": {
+      "type": "number",
+      "description": "Error budget remaining ratio threshold that triggers the alert (default 0)."
+    },
+    "for": {
+      "type": "string",
+      "pattern": "^(0|([0-9]+(ms|[smhdwy]))+)$",
+      "description": "Duration the condition must be true before firing (default 5m)."
+    },
+    "alert_name": {
+      "type": "string",
+      "description": "Alert name (default ErrorBudgetExhausted)."
+    },
+    "annotations": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "string"
+      },
+      "description": "Additional annotations for the alert."
+    },
+    "selector_labels": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "string"
+      },
+      "description": "Additional label matchers to select the SLO metrics."
+    },
+    "alert_labels": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "string"
+      },
+      "description": "Additional labels for the alert."
+    }
+  },
+  "additionalProperties": false
+}`
+	PluginConfigExample = `{"threshold": 0.1, "for": "10m", "alert_name": "ErrorBudgetAlmostExhausted", "alert_labels": {"severity": "warning"}}`
 )
 
 type Config struct {
@@ -11,8 +11,31 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/slo/v1"
-	PluginID      = "sloth.dev/contrib/info_labels/v1"
+	PluginVersion      = "prometheus/slo/v1"
+	PluginID           = "sloth.dev/contrib/info_labels/v1"
+	PluginDescription  = "Adds labels to the SLO info metadata recording rule."
+	PluginConfigSchema = `{
+  "type": "object",
+  "properties": {
+    "labels": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "string"
+      },
+      "description": "Labels added to the info metric.",
+      "minProperties": 1
+    },
+    "metricName": {
+      "type": "string",
+      "description": "Info metric name (default sloth_slo_info)."
+    }
+  },
+  "additionalProperties": false,
+  "required": [
+    "labels"
+  ]
+}`
+	PluginConfigExample = `{"labels": {"team": "team-a", "tier": "1"}}`
 )
 
 type Config struct {
@@ -0,0 +1,196 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/slok/sloth/pkg/common/conventions"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+const (
+	PluginVersion      = "prometheus/slo/v1"
+	PluginID           = "sloth.dev/contrib/label_matchers/v1"
+	PluginDescription  = "Enforces label matchers (e.g tenant or cluster) on every vector selector of the SLI queries."
+	PluginConfigSchema = `{
+  "type": "object",
+  "properties": {
+    "matchers": {
+      "type": "object",
+      "minProperties": 1,
+      "additionalProperties": {
+        "type": "string"
+      },
+      "description": "Label equality matchers that every vector selector of the SLI queries must have."
+    },
+    "override": {
+      "type": "boolean",
+      "description": "Overrides the conflicting label matchers of the queries instead of failing."
+    }
+  },
+  "required": ["matchers"],
+  "additionalProperties": false
+}`
+	PluginConfigExample = `{"matchers": {"cluster": "eu-west-1", "tenant": "team-a"}}`
+)
+
+// windowPlaceholder is the duration used to render the query window template, it's in the
+// PromQL canonical format, so the placeholder is the same after rendering the rewritten query.
+const windowPlaceholder = "4242d4h42m42s42ms"
+
+type Config struct {
+	Matchers map[string]string `json:"matchers,omitempty"`
+	Override bool              `json:"override,omitempty"`
+}
+
+func NewPlugin(configData json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+	cfg := Config{}
+	if err := json.Unmarshal(configData, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid plugin config: %w", err)
+	}
+
+	if len(cfg.Matchers) == 0 {
+		return nil, fmt.Errorf("at least one label matcher is required")
+	}
+
+	// Sort the matchers so the rewritten queries are deterministic.
+	names := []string{}
+	for k := range cfg.Matchers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	// Get the matchers from a selector, so we use the same matchers the parser does.
+	ms := []string{}
+	for _, k := range names {
+		ms = append(ms, fmt.Sprintf("%s=%s", k, strconv.Quote(cfg.Matchers[k])))
+	}
+	expr, err := parser.ParseExpr("{" + strings.Join(ms, ", ") + "}")
+	if err != nil {
+		return nil, fmt.Errorf("invalid label matchers: %w", err)
+	}
+	enforced, ok := expr.(*parser.VectorSelector)
+	if !ok {
+		return nil, fmt.Errorf("invalid label matchers")
+	}
+
+	return plugin{config: cfg, enforced: enforced}, nil
+}
+
+type plugin struct {
+	config   Config
+	enforced *parser.VectorSelector
+}
+
+func (p plugin) ProcessSLO(_ context.Context, req *pluginslov1.Request, _ *pluginslov1.Result) error {
+	sli := req.SLO.SLI
+
+	// Copy the SLI types before changing them, these could be shared with other SLOs.
+	switch {
+	case sli.Events != nil:
+		events := *sli.Events
+		var err error
+		events.ErrorQuery, err = p.enforceMatchers(events.ErrorQuery)
+		if err != nil {
+			return fmt.Errorf("could not enforce label matchers on SLI error query: %w", err)
+		}
+		events.TotalQuery, err = p.enforceMatchers(events.TotalQuery)
+		if err != nil {
+			return fmt.Errorf("could not enforce label matchers on SLI total query: %w", err)
+		}
+		req.SLO.SLI.Events = &events
+
+	case sli.Raw != nil:
+		raw := *sli.Raw
+		var err error
+		raw.ErrorRatioQuery, err = p.enforceMatchers(raw.ErrorRatioQuery)
+		if err != nil {
+			return fmt.Errorf("could not enforce label matchers on SLI error ratio query: %w", err)
+		}
+		req.SLO.SLI.Raw = &raw
+
+	default:
+		return fmt.Errorf("SLO %q SLI is required", req.SLO.ID)
+	}
+
+	return nil
+}
+
+// enforceMatchers returns the query with the label matchers on all its vector selectors, the
+// query window template is kept.
+func (p plugin) enforceMatchers(query string) (string, error) {
+	tpl, err := template.New("sliExpr").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("could not create SLI expression template data: %w", err)
+	}
+
+	var b bytes.Buffer
+	err = tpl.Execute(&b, map[string]string{
+		conventions.TplSLIQueryWindowVarName: windowPlaceholder,
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not render SLI expression template: %w", err)
+	}
+
+	expr, err := parser.ParseExpr(b.String())
+	if err != nil {
+		return "", fmt.Errorf("invalid query: %w", err)
+	}
+
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		vs, ok := node.(*parser.VectorSelector)
+		if ok && err == nil {
+			err = p.enforceSelectorMatchers(vs)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ReplaceAll(expr.String(), windowPlaceholder, "{{."+conventions.TplSLIQueryWindowVarName+"}}"), nil
+}
+
+func (p plugin) enforceSelectorMatchers(vs *parser.VectorSelector) error {
+	for _, m := range p.enforced.LabelMatchers {
+		found := false
+		conflict := false
+		for _, vsm := range vs.LabelMatchers {
+			if vsm.Name != m.Name {
+				continue
+			}
+			if vsm.Type == m.Type && vsm.Value == m.Value {
+				found = true
+			} else {
+				conflict = true
+			}
+		}
+
+		if conflict && !p.config.Override {
+			return fmt.Errorf("%q selector has a conflicting %q label matcher", vs.String(), m.Name)
+		}
+
+		if found && !conflict {
+			continue
+		}
+
+		// Remove the conflicting matchers and add ours.
+		newMatchers := vs.LabelMatchers[:0:0]
+		for _, vsm := range vs.LabelMatchers {
+			if vsm.Name != m.Name {
+				newMatchers = append(newMatchers, vsm)
+			}
+		}
+		vs.LabelMatchers = append(newMatchers, m)
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package plugin_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	plugin "github.com/slok/sloth/internal/plugin/slo/contrib/label_matchers_v1"
+	"github.com/slok/sloth/pkg/common/model"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+	pluginslov1testing "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1/testing"
+)
+
+func eventsSLO(errorQuery, totalQuery string) model.PromSLO {
+	return model.PromSLO{
+		ID: "svc01-slo01",
+		SLI: model.PromSLI{
+			Events: &model.PromSLIEvents{
+				ErrorQuery: errorQuery,
+				TotalQuery: totalQuery,
+			},
+		},
+	}
+}
+
+func rawSLO(errorRatioQuery string) model.PromSLO {
+	return model.PromSLO{
+		ID: "svc01-slo01",
+		SLI: model.PromSLI{
+			Raw: &model.PromSLIRaw{
+				ErrorRatioQuery: errorRatioQuery,
+			},
+		},
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	tests := map[string]struct {
+		config     json.RawMessage
+		slo        model.PromSLO
+		expSLO     model.PromSLO
+		expLoadErr bool
+		expErr     bool
+	}{
+		"Missing matchers should fail.": {
+			config:     json.RawMessage(`{}`),
+			expLoadErr: true,
+		},
+
+		"Invalid matcher label names should fail.": {
+			config:     json.RawMessage(`{"matchers": {"1nvalid-": "x"}}`),
+			expLoadErr: true,
+		},
+
+		"An SLO without SLI should fail.": {
+			config: json.RawMessage(`{"matchers": {"cluster": "c1"}}`),
+			slo:    model.PromSLO{ID: "svc01-slo01"},
+			expErr: true,
+		},
+
+		"An invalid query should fail.": {
+			config: json.RawMessage(`{"matchers": {"cluster": "c1"}}`),
+			slo:    eventsSLO(`sum(rate(errors_total[{{.window}}])`, `sum(rate(total[{{.window}}]))`),
+			expErr: true,
+		},
+
+		"A query with a different matcher value should fail.": {
+			config: json.RawMessage(`{"matchers": {"cluster": "c1", "tenant": "t1"}}`),
+			slo:    eventsSLO(`sum(rate(errors_total{cluster="c2"}[{{.window}}]))`, `sum(rate(total[{{.window}}]))`),
+			expErr: true,
+		},
+
+		"A query with a different matcher type should fail.": {
+			config: json.RawMessage(`{"matchers": {"cluster": "c1"}}`),
+			slo:    eventsSLO(`sum(rate(errors_total[{{.window}}]))`, `sum(rate(total{cluster=~"c.*"}[{{.window}}]))`),
+			expErr: true,
+		},
+
+		"The matchers should be injected on all the events SLI query selectors, keeping the window template.": {
+			config: json.RawMessage(`{"matchers": {"tenant": "t1", "cluster": "c1"}}`),
+			slo: eventsSLO(
+				`sum(rate(http_requests_total{code=~"5..", cluster="c1"}[{{.window}}])) by (route)`,
+				`sum(rate(http_requests_total[{{ .window }}] offset 1m)) by (route) + sum(max_over_time(up{job="x"}[{{.window}}:1m]))`,
+			),
+			expSLO: eventsSLO(
+				`sum by (route) (rate(http_requests_total{cluster="c1",code=~"5..",tenant="t1"}[{{.window}}]))`,
+				`sum by (route) (rate(http_requests_total{cluster="c1",tenant="t1"}[{{.window}}] offset 1m)) + sum(max_over_time(up{cluster="c1",job="x",tenant="t1"}[{{.window}}:1m]))`,
+			),
+		},
+
+		"The conflicting matchers should be overridden when override is enabled.": {
+			config: json.RawMessage(`{"matchers": {"cluster": "c1"}, "override": true}`),
+			slo:    rawSLO(`sum(rate(errors_total{cluster!="c1"}[{{.window}}])) / sum(rate(total{cluster=~"c.*",cluster="c2"}[{{.window}}]))`),
+			expSLO: rawSLO(`sum(rate(errors_total{cluster="c1"}[{{.window}}])) / sum(rate(total{cluster="c1"}[{{.window}}]))`),
+		},
+
+		"The matchers should be injected on the raw SLI query selectors.": {
+			config: json.RawMessage(`{"matchers": {"cluster": "c1"}}`),
+			slo:    rawSLO(`slo:custom_error:ratio_rate{{.window}}`),
+			expSLO: rawSLO(`slo:custom_error:ratio_rate{{.window}}{cluster="c1"}`),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			plugin, err := pluginslov1testing.NewTestPlugin(t.Context(), pluginslov1testing.TestPluginConfig{
+				PluginConfiguration: test.config,
+			})
+			if test.expLoadErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+
+			req := pluginslov1.Request{SLO: test.slo}
+			err = plugin.ProcessSLO(t.Context(), &req, &pluginslov1.Result{})
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expSLO, req.SLO)
+			}
+		})
+	}
+}
+
+func TestPluginDoesNotChangeSharedSLI(t *testing.T) {
+	assert := assert.New(t)
+
+	plugin, err := plugin.NewPlugin([]byte(`{"matchers": {"cluster": "c1"}}`), pluginslov1.AppUtils{})
+	assert.NoError(err)
+
+	slo := eventsSLO(`sum(rate(errors_total[{{.window}}]))`, `sum(rate(total[{{.window}}]))`)
+	req := pluginslov1.Request{SLO: slo}
+	err = plugin.ProcessSLO(t.Context(), &req, &pluginslov1.Result{})
+	assert.NoError(err)
+
+	assert.Equal(`sum(rate(errors_total{cluster="c1"}[{{.window}}]))`, req.SLO.SLI.Events.ErrorQuery)
+	assert.Equal(`sum(rate(errors_total[{{.window}}]))`, slo.SLI.Events.ErrorQuery)
+}
+
+func BenchmarkPluginYaegi(b *testing.B) {
+	plugin, err := pluginslov1testing.NewTestPlugin(b.Context(), pluginslov1testing.TestPluginConfig{
+		PluginConfiguration: json.RawMessage(`{"matchers": {"cluster": "c1", "tenant": "t1"}}`),
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		req := &pluginslov1.Request{SLO: eventsSLO(`sum(rate(errors_total{code=~"5.."}[{{.window}}]))`, `sum(rate(total[{{.window}}]))`)}
+		err = plugin.ProcessSLO(b.Context(), req, &pluginslov1.Result{})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPluginGo(b *testing.B) {
+	plugin, err := plugin.NewPlugin([]byte(`{"matchers": {"cluster": "c1", "tenant": "t1"}}`), pluginslov1.AppUtils{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		req := &pluginslov1.Request{SLO: eventsSLO(`sum(rate(errors_total{code=~"5.."}[{{.window}}]))`, `sum(rate(total[{{.window}}]))`)}
+		err = plugin.ProcessSLO(b.Context(), req, &pluginslov1.Result{})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/slok/sloth/pkg/common/conventions"
+	"github.com/slok/sloth/pkg/common/model"
+	utilsdata "github.com/slok/sloth/pkg/common/utils/data"
+	promutils "github.com/slok/sloth/pkg/common/utils/prometheus"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+const (
+	PluginVersion      = "prometheus/slo/v1"
+	PluginID           = "sloth.dev/contrib/low_traffic_guard/v1"
+	PluginDescription  = "Adds a minimum number of events condition to the page and ticket alerts, so low traffic services don't get noisy alerts."
+	PluginConfigSchema = `{
+  "type": "object",
+  "properties": {
+    "min_events": {
+      "type": "integer",
+      "minimum": 1,
+      "description": "Minimum number of events in the alert long window required to fire the page and ticket alerts."
+    },
+    "page_min_events": {
+      "type": "integer",
+      "minimum": 1,
+      "description": "Minimum number of events for the page alert (overrides min_events)."
+    },
+    "ticket_min_events": {
+      "type": "integer",
+      "minimum": 1,
+      "description": "Minimum number of events for the ticket alert (overrides min_events)."
+    },
+    "total_query": {
+      "type": "string",
+      "description": "Templated query that returns the events per second, required for raw SLIs (default the SLI events total query)."
+    },
+    "record_events": {
+      "type": "boolean",
+      "description": "Adds recording rules with the number of events per window and uses them on the alerts."
+    }
+  },
+  "additionalProperties": false
+}`
+	PluginConfigExample = `{"min_events": 100, "record_events": true}`
+)
+
+const (
+	eventsMetricFmt = "slo:sli_total_events:count%s" // The recorded number of events metric name.
+)
+
+type Config struct {
+	MinEvents       int    `json:"min_events,omitempty"`
+	PageMinEvents   int    `json:"page_min_events,omitempty"`   // default min_events.
+	TicketMinEvents int    `json:"ticket_min_events,omitempty"` // default min_events.
+	TotalQuery      string `json:"total_query,omitempty"`
+	RecordEvents    bool   `json:"record_events,omitempty"`
+}
+
+func NewPlugin(configData json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+	cfg := Config{}
+	if err := json.Unmarshal(configData, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid plugin config: %w", err)
+	}
+
+	if cfg.PageMinEvents == 0 {
+		cfg.PageMinEvents = cfg.MinEvents
+	}
+
+	if cfg.TicketMinEvents == 0 {
+		cfg.TicketMinEvents = cfg.MinEvents
+	}
+
+	if cfg.PageMinEvents <= 0 || cfg.TicketMinEvents <= 0 {
+		return nil, fmt.Errorf("min events are required")
+	}
+
+	return plugin{config: cfg}, nil
+}
+
+type plugin struct {
+	config Config
+}
+
+func (p plugin) ProcessSLO(_ context.Context, req *pluginslov1.Request, result *pluginslov1.Result) error {
+	slo := req.SLO
+
+	totalQuery := p.config.TotalQuery
+	if totalQuery == "" {
+		if slo.SLI.Events == nil || slo.SLI.Events.TotalQuery == "" {
+			return fmt.Errorf("low traffic guard requires SLI event type or a total query")
+		}
+		totalQuery = slo.SLI.Events.TotalQuery
+	}
+
+	// Map the SLI error metrics to their windows, so we know the windows used by the alerts.
+	windows := []time.Duration{}
+	metricWindows := map[string]time.Duration{}
+	for _, w := range req.MWMBAlertGroup.TimeDurationWindows() {
+		if w <= 0 {
+			continue
+		}
+		windows = append(windows, w)
+		metricWindows[conventions.GetSLIErrorMetric(w)] = w
+	}
+	if len(windows) == 0 {
+		return fmt.Errorf("SLO alert windows are required")
+	}
+
+	minEvents := map[string]int{
+		model.PageAlertSeverity.String():   p.config.PageMinEvents,
+		model.TicketAlertSeverity.String(): p.config.TicketMinEvents,
+	}
+
+	for i, rule := range result.SLORules.AlertRules.Rules {
+		threshold, ok := minEvents[rule.Labels[conventions.PromSLOSeverityLabelName]]
+		if rule.Alert == "" || !ok {
+			continue
+		}
+
+		expr, err := p.guardExpr(slo, rule.Expr, totalQuery, metricWindows, threshold)
+		if err != nil {
+			return fmt.Errorf("could not guard %q alert: %w", rule.Alert, err)
+		}
+		result.SLORules.AlertRules.Rules[i].Expr = expr
+	}
+
+	if p.config.RecordEvents {
+		for _, w := range windows {
+			rule, err := eventsRecordingRule(slo, totalQuery, w)
+			if err != nil {
+				return fmt.Errorf("could not create %q SLO events rule for window %s: %w", slo.ID, w, err)
+			}
+			result.SLORules.SLIErrorRecRules.Rules = append(result.SLORules.SLIErrorRecRules.Rules, *rule)
+		}
+	}
+
+	return nil
+}
+
+// guardExpr adds the minimum events condition to each of the `or` branches (the MWMB
+// conditions) of the alert expression, using the longest SLI window of the branch.
+func (p plugin) guardExpr(slo model.PromSLO, expr, totalQuery string, metricWindows map[string]time.Duration, threshold int) (string, error) {
+	e, err := parser.ParseExpr(expr)
+	if err != nil {
+		return "", fmt.Errorf("invalid alert expression: %w", err)
+	}
+
+	branches := []string{}
+	for _, branch := range orBranches(e) {
+		var longWindow time.Duration
+		parser.Inspect(branch, func(node parser.Node, _ []parser.Node) error {
+			vs, ok := node.(*parser.VectorSelector)
+			if ok && metricWindows[vs.Name] > longWindow {
+				longWindow = metricWindows[vs.Name]
+			}
+			return nil
+		})
+
+		// Not a burn rate condition, leave it as it is.
+		if longWindow == 0 {
+			branches = append(branches, branch.String())
+			continue
+		}
+
+		guardOp, guard, err := p.eventsGuard(slo, totalQuery, longWindow, threshold)
+		if err != nil {
+			return "", err
+		}
+
+		// Render the conditions one per line, like the alert rules plugin does.
+		if pe, ok := branch.(*parser.ParenExpr); ok {
+			branch = pe.Expr
+		}
+		lines := []string{}
+		for i, cond := range andOperands(branch) {
+			if i > 0 {
+				lines = append(lines, "and")
+			}
+			lines = append(lines, cond.String())
+		}
+		lines = append(lines, guardOp, guard)
+		branches = append(branches, "(\n    "+strings.Join(lines, "\n    ")+"\n)")
+	}
+	guarded := strings.Join(branches, "\nor\n") + "\n"
+
+	// Make sure we don't generate broken expressions.
+	if _, err := parser.ParseExpr(guarded); err != nil {
+		return "", fmt.Errorf("invalid guarded alert expression: %w", err)
+	}
+
+	return guarded, nil
+}
+
+// eventsGuard returns the `and` operator and the condition with the minimum events in the window.
+func (p plugin) eventsGuard(slo model.PromSLO, totalQuery string, window time.Duration, threshold int) (op string, cond string, err error) {
+	// The recorded events have the same labels as the SLI error recordings, so they match directly.
+	if p.config.RecordEvents {
+		filter := promutils.LabelsToPromFilter(conventions.GetSLOIDPromLabels(slo))
+		return "and", fmt.Sprintf("max(%s%s >= %d) without (%s)", eventsMetric(window), filter, threshold, conventions.PromSLOWindowLabelName), nil
+	}
+
+	// The query only has its own labels (e.g grouped SLOs), so ignore the ones that Sloth adds.
+	query, err := eventsQuery(totalQuery, window)
+	if err != nil {
+		return "", "", err
+	}
+	ignored := []string{}
+	for k := range utilsdata.MergeLabels(conventions.GetSLOIDPromLabels(slo), slo.Labels) {
+		ignored = append(ignored, k)
+	}
+	sort.Strings(ignored)
+
+	return fmt.Sprintf("and ignoring (%s)", strings.Join(ignored, ", ")), fmt.Sprintf("(%s >= %d)", query, threshold), nil
+}
+
+func eventsRecordingRule(slo model.PromSLO, totalQuery string, window time.Duration) (*rulefmt.Rule, error) {
+	query, err := eventsQuery(totalQuery, window)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rulefmt.Rule{
+		Record: eventsMetric(window),
+		Expr:   query + "\n",
+		Labels: utilsdata.MergeLabels(
+			conventions.GetSLOIDPromLabels(slo),
+			map[string]string{
+				conventions.PromSLOWindowLabelName: promutils.TimeDurationToPromStr(window),
+			},
+			slo.Labels,
+		),
+	}, nil
+}
+
+// eventsQuery returns the query with the number of events in the window, the total query
+// returns events per second (like the SLI event total queries).
+func eventsQuery(totalQuery string, window time.Duration) (string, error) {
+	tpl, err := template.New("sliExpr").Option("missingkey=error").Parse(totalQuery)
+	if err != nil {
+		return "", fmt.Errorf("could not create total expression template data: %w", err)
+	}
+
+	var b bytes.Buffer
+	err = tpl.Execute(&b, map[string]string{
+		conventions.TplSLIQueryWindowVarName: promutils.TimeDurationToPromStr(window),
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not render total expression template: %w", err)
+	}
+
+	return fmt.Sprintf("(%s) * %d", strings.TrimSpace(b.String()), int64(window.Seconds())), nil
+}
+
+func eventsMetric(window time.Duration) string {
+	return fmt.Sprintf(eventsMetricFmt, promutils.TimeDurationToPromStr(window))
+}
+
+// orBranches returns the top level `or` operands of the expression.
+func orBranches(e parser.Expr) []parser.Expr {
+	be, ok := e.(*parser.BinaryExpr)
+	if !ok || be.Op != parser.LOR {
+		return []parser.Expr{e}
+	}
+
+	return append(orBranches(be.LHS), orBranches(be.RHS)...)
+}
+
+// andOperands returns the operands of the expression `and` chain without vector matching
+// modifiers, these can be split safely.
+func andOperands(e parser.Expr) []parser.Expr {
+	be, ok := e.(*parser.BinaryExpr)
+	if !ok || be.Op != parser.LAND || (be.VectorMatching != nil && (be.VectorMatching.On || len(be.VectorMatching.MatchingLabels) > 0)) {
+		return []parser.Expr{e}
+	}
+
+	return append(andOperands(be.LHS), andOperands(be.RHS)...)
+}
@@ -0,0 +1,326 @@
+package plugin_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	plugin "github.com/slok/sloth/internal/plugin/slo/contrib/low_traffic_guard_v1"
+	alertrulesv1 "github.com/slok/sloth/internal/plugin/slo/core/alert_rules_v1"
+	"github.com/slok/sloth/pkg/common/model"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+	pluginslov1testing "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1/testing"
+)
+
+func baseAlertGroup() model.MWMBAlertGroup {
+	return model.MWMBAlertGroup{
+		PageQuick:   model.MWMBAlert{ShortWindow: 5 * time.Minute, LongWindow: 1 * time.Hour},
+		PageSlow:    model.MWMBAlert{ShortWindow: 30 * time.Minute, LongWindow: 6 * time.Hour},
+		TicketQuick: model.MWMBAlert{ShortWindow: 2 * time.Hour, LongWindow: 24 * time.Hour},
+		TicketSlow:  model.MWMBAlert{ShortWindow: 6 * time.Hour, LongWindow: 3 * 24 * time.Hour},
+	}
+}
+
+func baseSLO() model.PromSLO {
+	return model.PromSLO{
+		ID:      "svc01-slo01",
+		Name:    "slo01",
+		Service: "svc01",
+		Labels:  map[string]string{"owner": "team01"},
+		SLI: model.PromSLI{
+			Events: &model.PromSLIEvents{
+				ErrorQuery: `sum(rate(http_requests_total{code=~"5.."}[{{.window}}]))`,
+				TotalQuery: `sum(rate(http_requests_total[{{.window}}]))`,
+			},
+		},
+	}
+}
+
+func baseAlertRules() []rulefmt.Rule {
+	return []rulefmt.Rule{
+		{
+			Alert:  "PageAlert",
+			Expr:   `(max(slo:sli_error:ratio_rate5m{sloth_id="svc01-slo01"} > 0.01) without (sloth_window) and max(slo:sli_error:ratio_rate1h{sloth_id="svc01-slo01"} > 0.01) without (sloth_window)) or (max(slo:sli_error:ratio_rate30m{sloth_id="svc01-slo01"} > 0.005) without (sloth_window) and max(slo:sli_error:ratio_rate6h{sloth_id="svc01-slo01"} > 0.005) without (sloth_window))`,
+			Labels: map[string]string{"sloth_severity": "page"},
+		},
+		{
+			Alert:  "TicketAlert",
+			Expr:   `max(slo:sli_error:ratio_rate2h{sloth_id="svc01-slo01"} > 0.003) without (sloth_window) and max(slo:sli_error:ratio_rate1d{sloth_id="svc01-slo01"} > 0.003) without (sloth_window)`,
+			Labels: map[string]string{"sloth_severity": "ticket"},
+		},
+		{
+			Alert:  "OtherAlert",
+			Expr:   `vector(1)`,
+			Labels: map[string]string{"sloth_severity": "other"},
+		},
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	tests := map[string]struct {
+		config     json.RawMessage
+		req        pluginslov1.Request
+		res        pluginslov1.Result
+		expRes     pluginslov1.Result
+		expLoadErr bool
+		expErr     bool
+	}{
+		"Missing min events should fail.": {
+			config:     json.RawMessage(`{}`),
+			expLoadErr: true,
+		},
+
+		"Missing page min events should fail.": {
+			config:     json.RawMessage(`{"ticket_min_events": 10}`),
+			expLoadErr: true,
+		},
+
+		"A raw SLO without total query should fail.": {
+			config: json.RawMessage(`{"min_events": 100}`),
+			req: pluginslov1.Request{
+				SLO: func() model.PromSLO {
+					slo := baseSLO()
+					slo.SLI = model.PromSLI{Raw: &model.PromSLIRaw{ErrorRatioQuery: `sum(rate(errors_total[{{.window}}]))`}}
+					return slo
+				}(),
+				MWMBAlertGroup: baseAlertGroup(),
+			},
+			expErr: true,
+		},
+
+		"An SLO without alert windows should fail.": {
+			config: json.RawMessage(`{"min_events": 100}`),
+			req:    pluginslov1.Request{SLO: baseSLO()},
+			expErr: true,
+		},
+
+		"An invalid alert expression should fail.": {
+			config: json.RawMessage(`{"min_events": 100}`),
+			req:    pluginslov1.Request{SLO: baseSLO(), MWMBAlertGroup: baseAlertGroup()},
+			res: pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+				{Alert: "PageAlert", Expr: `max(`, Labels: map[string]string{"sloth_severity": "page"}},
+			}}}},
+			expErr: true,
+		},
+
+		"The page and ticket alerts should be guarded with the events query on each burn rate condition long window.": {
+			config: json.RawMessage(`{"min_events": 100, "ticket_min_events": 500}`),
+			req:    pluginslov1.Request{SLO: baseSLO(), MWMBAlertGroup: baseAlertGroup()},
+			res:    pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: baseAlertRules()}}},
+			expRes: pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+				{
+					Alert: "PageAlert",
+					Expr: `(
+    max without (sloth_window) (slo:sli_error:ratio_rate5m{sloth_id="svc01-slo01"} > 0.01)
+    and
+    max without (sloth_window) (slo:sli_error:ratio_rate1h{sloth_id="svc01-slo01"} > 0.01)
+    and ignoring (owner, sloth_id, sloth_service, sloth_slo)
+    ((sum(rate(http_requests_total[1h]))) * 3600 >= 100)
+)
+or
+(
+    max without (sloth_window) (slo:sli_error:ratio_rate30m{sloth_id="svc01-slo01"} > 0.005)
+    and
+    max without (sloth_window) (slo:sli_error:ratio_rate6h{sloth_id="svc01-slo01"} > 0.005)
+    and ignoring (owner, sloth_id, sloth_service, sloth_slo)
+    ((sum(rate(http_requests_total[6h]))) * 21600 >= 100)
+)
+`,
+					Labels: map[string]string{"sloth_severity": "page"},
+				},
+				{
+					Alert: "TicketAlert",
+					Expr: `(
+    max without (sloth_window) (slo:sli_error:ratio_rate2h{sloth_id="svc01-slo01"} > 0.003)
+    and
+    max without (sloth_window) (slo:sli_error:ratio_rate1d{sloth_id="svc01-slo01"} > 0.003)
+    and ignoring (owner, sloth_id, sloth_service, sloth_slo)
+    ((sum(rate(http_requests_total[1d]))) * 86400 >= 500)
+)
+`,
+					Labels: map[string]string{"sloth_severity": "ticket"},
+				},
+				baseAlertRules()[2],
+			}}}},
+		},
+
+		"A raw SLO with a total query and recorded events should be guarded with the recorded events.": {
+			config: json.RawMessage(`{"min_events": 100, "record_events": true, "total_query": "sum(rate(http_requests_total[{{.window}}]))"}`),
+			req: pluginslov1.Request{
+				SLO: func() model.PromSLO {
+					slo := baseSLO()
+					slo.SLI = model.PromSLI{Raw: &model.PromSLIRaw{ErrorRatioQuery: `sum(rate(errors_total[{{.window}}]))`}}
+					return slo
+				}(),
+				MWMBAlertGroup: model.MWMBAlertGroup{
+					PageQuick:   model.MWMBAlert{ShortWindow: 5 * time.Minute, LongWindow: 1 * time.Hour},
+					PageSlow:    model.MWMBAlert{ShortWindow: 5 * time.Minute, LongWindow: 1 * time.Hour},
+					TicketQuick: model.MWMBAlert{ShortWindow: 5 * time.Minute, LongWindow: 1 * time.Hour},
+					TicketSlow:  model.MWMBAlert{ShortWindow: 5 * time.Minute, LongWindow: 1 * time.Hour},
+				},
+			},
+			res: pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+				{
+					Alert:  "PageAlert",
+					Expr:   `max(slo:sli_error:ratio_rate5m{sloth_id="svc01-slo01"} > 0.01) without (sloth_window) and max(slo:sli_error:ratio_rate1h{sloth_id="svc01-slo01"} > 0.01) without (sloth_window)`,
+					Labels: map[string]string{"sloth_severity": "page"},
+				},
+			}}}},
+			expRes: pluginslov1.Result{SLORules: model.PromSLORules{
+				SLIErrorRecRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+					{
+						Record: "slo:sli_total_events:count5m",
+						Expr:   "(sum(rate(http_requests_total[5m]))) * 300\n",
+						Labels: map[string]string{"owner": "team01", "sloth_id": "svc01-slo01", "sloth_service": "svc01", "sloth_slo": "slo01", "sloth_window": "5m"},
+					},
+					{
+						Record: "slo:sli_total_events:count1h",
+						Expr:   "(sum(rate(http_requests_total[1h]))) * 3600\n",
+						Labels: map[string]string{"owner": "team01", "sloth_id": "svc01-slo01", "sloth_service": "svc01", "sloth_slo": "slo01", "sloth_window": "1h"},
+					},
+				}},
+				AlertRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+					{
+						Alert: "PageAlert",
+						Expr: `(
+    max without (sloth_window) (slo:sli_error:ratio_rate5m{sloth_id="svc01-slo01"} > 0.01)
+    and
+    max without (sloth_window) (slo:sli_error:ratio_rate1h{sloth_id="svc01-slo01"} > 0.01)
+    and
+    max(slo:sli_total_events:count1h{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"} >= 100) without (sloth_window)
+)
+`,
+						Labels: map[string]string{"sloth_severity": "page"},
+					},
+				}},
+			}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			plugin, err := pluginslov1testing.NewTestPlugin(t.Context(), pluginslov1testing.TestPluginConfig{
+				PluginConfiguration: test.config,
+			})
+			if test.expLoadErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+
+			gotRes := test.res
+			err = plugin.ProcessSLO(t.Context(), &test.req, &gotRes)
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expRes, gotRes)
+			}
+		})
+	}
+}
+
+func TestPluginRules(t *testing.T) {
+	spec := []byte(`
+version: "prometheus/v1"
+service: "myservice"
+slos:
+  - name: "requests-availability"
+    objective: 99.9
+    sli:
+      events:
+        error_query: sum by (route) (rate(http_requests_total{code=~"5.."}[{{.window}}]))
+        total_query: sum by (route) (rate(http_requests_total[{{.window}}]))
+    alerting:
+      name: MyServiceHighErrorRate
+      ticket_alert:
+        disable: true
+`)
+
+	pt, err := pluginslov1testing.NewPluginTester(t.Context(), pluginslov1testing.TestPluginConfig{
+		PluginConfiguration: json.RawMessage(`{"min_events": 100}`),
+	})
+	require.NoError(t, err)
+	req, err := pluginslov1testing.NewRequestFromSpec(t.Context(), spec, "")
+	require.NoError(t, err)
+
+	// Use the core alert rules plugin alerts, like Sloth does.
+	alertRules, err := alertrulesv1.NewPlugin(nil, pluginslov1.AppUtils{})
+	require.NoError(t, err)
+	res := &pluginslov1.Result{}
+	err = alertRules.ProcessSLO(t.Context(), req, res)
+	require.NoError(t, err)
+
+	pt.AssertYAML(t, `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# DO NOT EDIT.
+
+groups:
+- name: sloth-slo-alerts-myservice-requests-availability
+  rules:
+  - alert: MyServiceHighErrorRate
+    expr: |
+      (
+          max without (sloth_window) (slo:sli_error:ratio_rate5m{sloth_id="myservice-requests-availability",sloth_service="myservice",sloth_slo="requests-availability"} > (14.4 * 0.0009999999999999432))
+          and
+          max without (sloth_window) (slo:sli_error:ratio_rate1h{sloth_id="myservice-requests-availability",sloth_service="myservice",sloth_slo="requests-availability"} > (14.4 * 0.0009999999999999432))
+          and ignoring (sloth_id, sloth_service, sloth_slo)
+          ((sum by (route) (rate(http_requests_total[1h]))) * 3600 >= 100)
+      )
+      or
+      (
+          max without (sloth_window) (slo:sli_error:ratio_rate30m{sloth_id="myservice-requests-availability",sloth_service="myservice",sloth_slo="requests-availability"} > (6 * 0.0009999999999999432))
+          and
+          max without (sloth_window) (slo:sli_error:ratio_rate6h{sloth_id="myservice-requests-availability",sloth_service="myservice",sloth_slo="requests-availability"} > (6 * 0.0009999999999999432))
+          and ignoring (sloth_id, sloth_service, sloth_slo)
+          ((sum by (route) (rate(http_requests_total[6h]))) * 21600 >= 100)
+      )
+    labels:
+      sloth_severity: page
+    annotations:
+      summary: '{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget burn
+        rate is over expected.'
+      title: (page) {{$labels.sloth_service}} {{$labels.sloth_slo}} SLO error budget
+        burn rate is too fast.
+`, req, res)
+}
+
+func BenchmarkPluginYaegi(b *testing.B) {
+	plugin, err := pluginslov1testing.NewTestPlugin(b.Context(), pluginslov1testing.TestPluginConfig{
+		PluginConfiguration: json.RawMessage(`{"min_events": 100}`),
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	req := &pluginslov1.Request{SLO: baseSLO(), MWMBAlertGroup: baseAlertGroup()}
+	for b.Loop() {
+		res := &pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: baseAlertRules()}}}
+		err = plugin.ProcessSLO(b.Context(), req, res)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPluginGo(b *testing.B) {
+	plugin, err := plugin.NewPlugin([]byte(`{"min_events": 100}`), pluginslov1.AppUtils{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	req := &pluginslov1.Request{SLO: baseSLO(), MWMBAlertGroup: baseAlertGroup()}
+	for b.Loop() {
+		res := &pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: baseAlertRules()}}}
+		err = plugin.ProcessSLO(b.Context(), req, res)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -10,8 +10,30 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/slo/v1"
-	PluginID      = "sloth.dev/contrib/remove_labels/v1"
+	PluginVersion      = "prometheus/slo/v1"
+	PluginID           = "sloth.dev/contrib/remove_labels/v1"
+	PluginDescription  = "Removes the custom labels from the SLI and metadata recording rules, except the SLO info metric."
+	PluginConfigSchema = `{
+  "type": "object",
+  "properties": {
+    "preserveLabels": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "description": "Labels that will not be removed."
+    },
+    "skipMetrics": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      },
+      "description": "Metrics whose labels will not be removed."
+    }
+  },
+  "additionalProperties": false
+}`
+	PluginConfigExample = `{"preserveLabels": ["team"], "skipMetrics": ["slo:objective:ratio"]}`
 )
 
 type Config struct {
@@ -11,8 +11,48 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/slo/v1"
-	PluginID      = "sloth.dev/contrib/rule_intervals/v1"
+	PluginVersion      = "prometheus/slo/v1"
+	PluginID           = "sloth.dev/contrib/rule_intervals/v1"
+	PluginDescription  = "Sets the Prometheus rule group evaluation intervals of the SLI, metadata and alert rules."
+	PluginConfigSchema = `{
+  "type": "object",
+  "properties": {
+    "interval": {
+      "type": "object",
+      "properties": {
+        "default": {
+          "type": "string",
+          "pattern": "^(0|([0-9]+(ms|[smhdwy]))+)$",
+          "description": "Interval for all the rule groups."
+        },
+        "sliError": {
+          "type": "string",
+          "pattern": "^(0|([0-9]+(ms|[smhdwy]))+)$",
+          "description": "Interval for the SLI error recording rules."
+        },
+        "metadata": {
+          "type": "string",
+          "pattern": "^(0|([0-9]+(ms|[smhdwy]))+)$",
+          "description": "Interval for the metadata recording rules."
+        },
+        "alert": {
+          "type": "string",
+          "pattern": "^(0|([0-9]+(ms|[smhdwy]))+)$",
+          "description": "Interval for the alert rules."
+        }
+      },
+      "additionalProperties": false,
+      "required": [
+        "default"
+      ]
+    }
+  },
+  "additionalProperties": false,
+  "required": [
+    "interval"
+  ]
+}`
+	PluginConfigExample = `{"interval": {"default": "1m", "alert": "30s"}}`
 )
 
 type ConfigInterval struct {
@@ -0,0 +1,234 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/template"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
+
+	"github.com/slok/sloth/pkg/common/conventions"
+	"github.com/slok/sloth/pkg/common/model"
+	utilsdata "github.com/slok/sloth/pkg/common/utils/data"
+	promutils "github.com/slok/sloth/pkg/common/utils/prometheus"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+const (
+	PluginVersion      = "prometheus/slo/v1"
+	PluginID           = "sloth.dev/contrib/sli_no_data_alert/v1"
+	PluginDescription  = "Adds alerts that fire when the SLI queries return no data or the recorded SLI series (or any of its label groups) go stale, so the SLO alerts don't go silent."
+	PluginConfigSchema = `{
+  "type": "object",
+  "properties": {
+    "for": {
+      "type": "string",
+      "pattern": "^(0|([0-9]+(ms|[smhdwy]))+)$",
+      "description": "Duration the condition must be true before firing (default 10m)."
+    },
+    "stale_lookback": {
+      "type": "string",
+      "pattern": "^(0|([0-9]+(ms|[smhdwy]))+)$",
+      "description": "How far back a recorded SLI series must have been present to be considered stale when missing (default 1h)."
+    },
+    "severity": {
+      "type": "string",
+      "description": "Sloth severity label value of the alerts (default ticket)."
+    },
+    "alert_name": {
+      "type": "string",
+      "description": "Alert name (default SLONoData)."
+    },
+    "alert_labels": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "string"
+      },
+      "description": "Additional labels for the alerts."
+    },
+    "annotations": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "string"
+      },
+      "description": "Additional annotations for the alerts, these override the default ones."
+    },
+    "disable_query_alert": {
+      "type": "boolean",
+      "description": "Disables the alert on the SLI queries without data."
+    },
+    "disable_recording_alert": {
+      "type": "boolean",
+      "description": "Disables the alert on the stale recorded SLI series."
+    }
+  },
+  "additionalProperties": false
+}`
+	PluginConfigExample = `{"for": "15m", "severity": "page", "alert_labels": {"team": "myteam"}}`
+)
+
+// Values of the source label that tells what is missing data.
+const (
+	sourceLabelName          = "sloth_no_data_source"
+	sourceLabelValueQuery    = "sli_query"
+	sourceLabelValueRecorded = "sli_recording"
+)
+
+type Config struct {
+	For                   prommodel.Duration `json:"for,omitempty"`            // default 10m.
+	StaleLookback         prommodel.Duration `json:"stale_lookback,omitempty"` // default 1h.
+	Severity              string             `json:"severity,omitempty"`       // default "ticket".
+	AlertName             string             `json:"alert_name,omitempty"`     // default "SLONoData".
+	AlertLabels           map[string]string  `json:"alert_labels,omitempty"`
+	Annotations           map[string]string  `json:"annotations,omitempty"`
+	DisableQueryAlert     bool               `json:"disable_query_alert,omitempty"`
+	DisableRecordingAlert bool               `json:"disable_recording_alert,omitempty"`
+}
+
+func NewPlugin(configData json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+	cfg := Config{}
+	if err := json.Unmarshal(configData, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid plugin config: %w", err)
+	}
+
+	if cfg.For == 0 {
+		cfg.For = prommodel.Duration(10 * time.Minute)
+	}
+
+	if cfg.StaleLookback == 0 {
+		cfg.StaleLookback = prommodel.Duration(time.Hour)
+	}
+
+	// Stale series stop matching after the lookback, so the alert needs to fire before.
+	if cfg.StaleLookback <= cfg.For {
+		return nil, fmt.Errorf("stale lookback (%s) must be greater than the alert for duration (%s)", cfg.StaleLookback, cfg.For)
+	}
+
+	if cfg.Severity == "" {
+		cfg.Severity = "ticket"
+	}
+
+	if cfg.AlertName == "" {
+		cfg.AlertName = "SLONoData"
+	}
+
+	return plugin{config: cfg}, nil
+}
+
+type plugin struct {
+	config Config
+}
+
+func (p plugin) ProcessSLO(_ context.Context, req *pluginslov1.Request, result *pluginslov1.Result) error {
+	slo := req.SLO
+
+	// Use the shortest SLI window, the first one that will miss the data.
+	var window time.Duration
+	for _, w := range req.MWMBAlertGroup.TimeDurationWindows() {
+		if w > 0 && (window == 0 || w < window) {
+			window = w
+		}
+	}
+	if window == 0 {
+		return fmt.Errorf("SLO alert windows are required")
+	}
+
+	if !p.config.DisableQueryAlert {
+		query, err := sliQuery(slo, window)
+		if err != nil {
+			return err
+		}
+
+		// The query result labels are unknown, so we identify the SLO with the alert labels.
+		result.SLORules.AlertRules.Rules = append(result.SLORules.AlertRules.Rules, rulefmt.Rule{
+			Alert: p.config.AlertName,
+			Expr:  fmt.Sprintf("absent(%s)", query),
+			For:   p.config.For,
+			Labels: utilsdata.MergeLabels(
+				conventions.GetSLOIDPromLabels(slo),
+				slo.Labels,
+				p.alertLabels(sourceLabelValueQuery),
+			),
+			Annotations: p.annotations("SLI query has no data"),
+		})
+	}
+
+	if !p.config.DisableRecordingAlert {
+		// Series present in the lookback but not now, this way a single stale label group of
+		// a grouped SLO is detected (`absent` would only fire when all of them are missing).
+		series := conventions.GetSLIErrorMetric(window) + labelSelector(conventions.GetSLOIDPromLabels(slo))
+		expr := fmt.Sprintf("present_over_time(%s[%s])\nunless\n%s\n", series, p.config.StaleLookback, series)
+
+		result.SLORules.AlertRules.Rules = append(result.SLORules.AlertRules.Rules, rulefmt.Rule{
+			Alert:       p.config.AlertName,
+			Expr:        expr,
+			For:         p.config.For,
+			Labels:      p.alertLabels(sourceLabelValueRecorded),
+			Annotations: p.annotations("recorded SLI is stale"),
+		})
+	}
+
+	return nil
+}
+
+func (p plugin) alertLabels(source string) map[string]string {
+	return utilsdata.MergeLabels(
+		map[string]string{
+			conventions.PromSLOSeverityLabelName: p.config.Severity,
+			sourceLabelName:                      source,
+		},
+		p.config.AlertLabels,
+	)
+}
+
+func (p plugin) annotations(what string) map[string]string {
+	return utilsdata.MergeLabels(
+		map[string]string{
+			"title":   fmt.Sprintf("{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO %s.", what),
+			"summary": fmt.Sprintf("{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO %s, the SLO alerts can't fire without data.", what),
+		},
+		p.config.Annotations,
+	)
+}
+
+// sliQuery returns the SLI query that should always have data: the total query for the events SLIs (the error
+// query can have no data if there are no errors) and the error ratio query for the raw SLIs.
+func sliQuery(slo model.PromSLO, window time.Duration) (string, error) {
+	var query string
+	switch {
+	case slo.SLI.Events != nil:
+		query = slo.SLI.Events.TotalQuery
+	case slo.SLI.Raw != nil:
+		query = slo.SLI.Raw.ErrorRatioQuery
+	default:
+		return "", fmt.Errorf("SLO %q SLI is required", slo.ID)
+	}
+
+	tpl, err := template.New("sliExpr").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("could not create SLI expression template data: %w", err)
+	}
+
+	var b bytes.Buffer
+	err = tpl.Execute(&b, map[string]string{
+		conventions.TplSLIQueryWindowVarName: promutils.TimeDurationToPromStr(window),
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not render SLI expression template: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// labelSelector returns a PromQL label selector with the labels.
+func labelSelector(labels map[string]string) string {
+	ls := prommodel.LabelSet{}
+	for k, v := range labels {
+		ls[prommodel.LabelName(k)] = prommodel.LabelValue(v)
+	}
+	return ls.String()
+}
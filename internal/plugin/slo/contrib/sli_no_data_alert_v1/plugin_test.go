@@ -0,0 +1,303 @@
+package plugin_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/stretchr/testify/assert"
+
+	plugin "github.com/slok/sloth/internal/plugin/slo/contrib/sli_no_data_alert_v1"
+	"github.com/slok/sloth/pkg/common/model"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+	pluginslov1testing "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1/testing"
+)
+
+func baseAlertGroup() model.MWMBAlertGroup {
+	return model.MWMBAlertGroup{
+		PageQuick:   model.MWMBAlert{ShortWindow: 5 * time.Minute, LongWindow: 1 * time.Hour},
+		PageSlow:    model.MWMBAlert{ShortWindow: 30 * time.Minute, LongWindow: 6 * time.Hour},
+		TicketQuick: model.MWMBAlert{ShortWindow: 2 * time.Hour, LongWindow: 24 * time.Hour},
+		TicketSlow:  model.MWMBAlert{ShortWindow: 6 * time.Hour, LongWindow: 3 * 24 * time.Hour},
+	}
+}
+
+func baseSLO() model.PromSLO {
+	return model.PromSLO{
+		ID:      "svc01-slo01",
+		Name:    "slo01",
+		Service: "svc01",
+		Labels:  map[string]string{"owner": "team01"},
+		SLI: model.PromSLI{
+			Events: &model.PromSLIEvents{
+				ErrorQuery: `sum(rate(http_requests_total{code=~"5.."}[{{.window}}]))`,
+				TotalQuery: `sum(rate(http_requests_total[{{.window}}]))`,
+			},
+		},
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	tests := map[string]struct {
+		config     json.RawMessage
+		req        pluginslov1.Request
+		expRes     pluginslov1.Result
+		expLoadErr bool
+		expErr     bool
+	}{
+		"A stale lookback lower than the alert for duration should fail.": {
+			config:     json.RawMessage(`{"for": "1h", "stale_lookback": "30m"}`),
+			expLoadErr: true,
+		},
+
+		"An SLO without SLI should fail.": {
+			config: json.RawMessage(`{}`),
+			req: pluginslov1.Request{
+				SLO:            model.PromSLO{ID: "svc01-slo01"},
+				MWMBAlertGroup: baseAlertGroup(),
+			},
+			expErr: true,
+		},
+
+		"An SLO without alert windows should fail.": {
+			config: json.RawMessage(`{}`),
+			req: pluginslov1.Request{
+				SLO: baseSLO(),
+			},
+			expErr: true,
+		},
+
+		"An events SLO should have the total query and the recorded SLI no data alerts with the defaults.": {
+			config: json.RawMessage(`{}`),
+			req: pluginslov1.Request{
+				SLO:            baseSLO(),
+				MWMBAlertGroup: baseAlertGroup(),
+			},
+			expRes: pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+				{
+					Alert: "SLONoData",
+					Expr:  `absent(sum(rate(http_requests_total[5m])))`,
+					For:   prommodel.Duration(10 * time.Minute),
+					Labels: map[string]string{
+						"owner":                "team01",
+						"sloth_id":             "svc01-slo01",
+						"sloth_service":        "svc01",
+						"sloth_slo":            "slo01",
+						"sloth_severity":       "ticket",
+						"sloth_no_data_source": "sli_query",
+					},
+					Annotations: map[string]string{
+						"title":   "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO SLI query has no data.",
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO SLI query has no data, the SLO alerts can't fire without data.",
+					},
+				},
+				{
+					Alert: "SLONoData",
+					Expr: `present_over_time(slo:sli_error:ratio_rate5m{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}[1h])
+unless
+slo:sli_error:ratio_rate5m{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}
+`,
+					For: prommodel.Duration(10 * time.Minute),
+					Labels: map[string]string{
+						"sloth_severity":       "ticket",
+						"sloth_no_data_source": "sli_recording",
+					},
+					Annotations: map[string]string{
+						"title":   "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO recorded SLI is stale.",
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO recorded SLI is stale, the SLO alerts can't fire without data.",
+					},
+				},
+			}}}},
+		},
+
+		"A grouped raw SLO with custom config should have the raw query and the recorded SLI no data alerts.": {
+			config: json.RawMessage(`{
+  "for": "15m",
+  "stale_lookback": "2h",
+  "severity": "page",
+  "alert_name": "MyNoData",
+  "alert_labels": {"team": "team02"},
+  "annotations": {"title": "No data", "runbook": "https://runbooks.test/no-data"}
+}`),
+			req: pluginslov1.Request{
+				SLO: func() model.PromSLO {
+					slo := baseSLO()
+					slo.SLI = model.PromSLI{Raw: &model.PromSLIRaw{
+						ErrorRatioQuery: `sum by (route) (rate(errors_total[{{.window}}])) / sum by (route) (rate(total[{{.window}}]))`,
+					}}
+					return slo
+				}(),
+				MWMBAlertGroup: baseAlertGroup(),
+			},
+			expRes: pluginslov1.Result{SLORules: model.PromSLORules{AlertRules: model.PromRuleGroup{Rules: []rulefmt.Rule{
+				{
+					Alert: "MyNoData",
+					Expr:  `absent(sum by (route) (rate(errors_total[5m])) / sum by (route) (rate(total[5m])))`,
+					For:   prommodel.Duration(15 * time.Minute),
+					Labels: map[string]string{
+						"owner":                "team01",
+						"team":                 "team02",
+						"sloth_id":             "svc01-slo01",
+						"sloth_service":        "svc01",
+						"sloth_slo":            "slo01",
+						"sloth_severity":       "page",
+						"sloth_no_data_source": "sli_query",
+					},
+					Annotations: map[string]string{
+						"title":   "No data",
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO SLI query has no data, the SLO alerts can't fire without data.",
+						"runbook": "https://runbooks.test/no-data",
+					},
+				},
+				{
+					Alert: "MyNoData",
+					Expr: `present_over_time(slo:sli_error:ratio_rate5m{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}[2h])
+unless
+slo:sli_error:ratio_rate5m{sloth_id="svc01-slo01", sloth_service="svc01", sloth_slo="slo01"}
+`,
+					For: prommodel.Duration(15 * time.Minute),
+					Labels: map[string]string{
+						"team":                 "team02",
+						"sloth_severity":       "page",
+						"sloth_no_data_source": "sli_recording",
+					},
+					Annotations: map[string]string{
+						"title":   "No data",
+						"summary": "{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO recorded SLI is stale, the SLO alerts can't fire without data.",
+						"runbook": "https://runbooks.test/no-data",
+					},
+				},
+			}}}},
+		},
+
+		"Disabling the alerts should not add them.": {
+			config: json.RawMessage(`{"disable_query_alert": true, "disable_recording_alert": true}`),
+			req: pluginslov1.Request{
+				SLO:            baseSLO(),
+				MWMBAlertGroup: baseAlertGroup(),
+			},
+			expRes: pluginslov1.Result{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			plugin, err := pluginslov1testing.NewTestPlugin(t.Context(), pluginslov1testing.TestPluginConfig{
+				PluginConfiguration: test.config,
+			})
+			if test.expLoadErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+
+			gotRes := pluginslov1.Result{}
+			err = plugin.ProcessSLO(t.Context(), &test.req, &gotRes)
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expRes, gotRes)
+			}
+		})
+	}
+}
+
+func TestPluginRules(t *testing.T) {
+	spec := []byte(`
+version: "prometheus/v1"
+service: "myservice"
+slos:
+  - name: "requests-availability"
+    objective: 99.9
+    sli:
+      events:
+        error_query: sum by (route) (rate(http_requests_total{code=~"5.."}[{{.window}}]))
+        total_query: sum by (route) (rate(http_requests_total[{{.window}}]))
+    alerting:
+      page_alert:
+        disable: true
+      ticket_alert:
+        disable: true
+`)
+
+	pt, err := pluginslov1testing.NewPluginTester(t.Context(), pluginslov1testing.TestPluginConfig{
+		PluginConfiguration: json.RawMessage(`{"alert_labels": {"team": "myteam"}}`),
+	})
+	assert.NoError(t, err)
+	req, err := pluginslov1testing.NewRequestFromSpec(t.Context(), spec, "")
+	assert.NoError(t, err)
+
+	pt.AssertYAML(t, `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# DO NOT EDIT.
+
+groups:
+- name: sloth-slo-alerts-myservice-requests-availability
+  rules:
+  - alert: SLONoData
+    expr: absent(sum by (route) (rate(http_requests_total[5m])))
+    for: 10m
+    labels:
+      sloth_id: myservice-requests-availability
+      sloth_no_data_source: sli_query
+      sloth_service: myservice
+      sloth_severity: ticket
+      sloth_slo: requests-availability
+      team: myteam
+    annotations:
+      summary: '{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO SLI query has
+        no data, the SLO alerts can''t fire without data.'
+      title: '{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO SLI query has no
+        data.'
+  - alert: SLONoData
+    expr: |
+      present_over_time(slo:sli_error:ratio_rate5m{sloth_id="myservice-requests-availability", sloth_service="myservice", sloth_slo="requests-availability"}[1h])
+      unless
+      slo:sli_error:ratio_rate5m{sloth_id="myservice-requests-availability", sloth_service="myservice", sloth_slo="requests-availability"}
+    for: 10m
+    labels:
+      sloth_no_data_source: sli_recording
+      sloth_severity: ticket
+      team: myteam
+    annotations:
+      summary: '{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO recorded SLI is
+        stale, the SLO alerts can''t fire without data.'
+      title: '{{$labels.sloth_service}} {{$labels.sloth_slo}} SLO recorded SLI is
+        stale.'
+`, req, nil)
+}
+
+func BenchmarkPluginYaegi(b *testing.B) {
+	plugin, err := pluginslov1testing.NewTestPlugin(b.Context(), pluginslov1testing.TestPluginConfig{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	req := &pluginslov1.Request{SLO: baseSLO(), MWMBAlertGroup: baseAlertGroup()}
+	for b.Loop() {
+		err = plugin.ProcessSLO(b.Context(), req, &pluginslov1.Result{})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPluginGo(b *testing.B) {
+	plugin, err := plugin.NewPlugin([]byte(`{}`), pluginslov1.AppUtils{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	req := &pluginslov1.Request{SLO: baseSLO(), MWMBAlertGroup: baseAlertGroup()}
+	for b.Loop() {
+		err = plugin.ProcessSLO(b.Context(), req, &pluginslov1.Result{})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -15,8 +15,9 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/slo/v1"
-	PluginID      = "sloth.dev/contrib/validate_victoria_metrics/v1"
+	PluginVersion     = "prometheus/slo/v1"
+	PluginID          = "sloth.dev/contrib/validate_victoria_metrics/v1"
+	PluginDescription = "Validates the SLO using the VictoriaMetrics MetricsQL dialect, used instead of the default Prometheus validation."
 )
 
 func NewPlugin(_ json.RawMessage, appUtils pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
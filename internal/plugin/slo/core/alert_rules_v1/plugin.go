@@ -17,8 +17,9 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/slo/v1"
-	PluginID      = "sloth.dev/core/alert_rules/v1"
+	PluginVersion     = "prometheus/slo/v1"
+	PluginID          = "sloth.dev/core/alert_rules/v1"
+	PluginDescription = "Generates the multi window multi burn rate page and ticket alert rules."
 )
 
 func NewPlugin(_ json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
@@ -8,8 +8,28 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/slo/v1"
-	PluginID      = "sloth.dev/core/debug/v1"
+	PluginVersion      = "prometheus/slo/v1"
+	PluginID           = "sloth.dev/core/debug/v1"
+	PluginDescription  = "Logs the SLO plugin request and result in debug level, useful to develop plugin chains."
+	PluginConfigSchema = `{
+  "type": "object",
+  "properties": {
+    "msg": {
+      "type": "string",
+      "description": "Custom message logged with the debug information."
+    },
+    "result": {
+      "type": "boolean",
+      "description": "Log the plugin result."
+    },
+    "request": {
+      "type": "boolean",
+      "description": "Log the plugin request."
+    }
+  },
+  "additionalProperties": false
+}`
+	PluginConfigExample = `{"msg": "after SLI rules", "result": true}`
 )
 
 type Config struct {
@@ -17,8 +17,9 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/slo/v1"
-	PluginID      = "sloth.dev/core/metadata_rules/v1"
+	PluginVersion     = "prometheus/slo/v1"
+	PluginID          = "sloth.dev/core/metadata_rules/v1"
+	PluginDescription = "Generates the SLO metadata recording rules (objective, error budget, burn rates, info...)."
 )
 
 func NewPlugin(_ json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
@@ -8,8 +8,9 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/slo/v1"
-	PluginID      = "sloth.dev/core/noop/v1"
+	PluginVersion     = "prometheus/slo/v1"
+	PluginID          = "sloth.dev/core/noop/v1"
+	PluginDescription = "Does nothing, used as an example and to test plugin chains."
 )
 
 func NewPlugin(_ json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
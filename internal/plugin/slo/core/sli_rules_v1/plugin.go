@@ -17,12 +17,42 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/slo/v1"
-	PluginID      = "sloth.dev/core/sli_rules/v1"
+	PluginVersion      = "prometheus/slo/v1"
+	PluginID           = "sloth.dev/core/sli_rules/v1"
+	PluginDescription  = "Generates the SLI error ratio recording rules for every required window."
+	PluginConfigSchema = `{
+  "type": "object",
+  "properties": {
+    "disableOptimized": {
+      "type": "boolean",
+      "description": "Disables the optimized long window SLI recording rules based on the short window ones."
+    },
+    "optimizationMode": {
+      "type": "string",
+      "enum": ["ratio", "events"],
+      "description": "The long window SLI optimization mode, 'ratio' averages the short window SLI ratio and 'events' divides the sum of the short window recorded error and total event rates (only event SLIs, default 'ratio')."
+    }
+  },
+  "additionalProperties": false
+}`
+	PluginConfigExample = `{"optimizationMode": "events"}`
+)
+
+const (
+	// OptimizationModeRatio derives the long window SLIs from the average of the short window SLI ratio.
+	OptimizationModeRatio = "ratio"
+	// OptimizationModeEvents derives the long window SLIs from the short window recorded error and total event rates.
+	OptimizationModeEvents = "events"
+
+	sliErrorEventsMetricFmt = "slo:sli_error_events:rate%s" // The recorded error events rate metric name.
+	sliTotalEventsMetricFmt = "slo:sli_total_events:rate%s" // The recorded total events rate metric name.
+
+	sliOptimizationAnnotation = "sli_optimization"
 )
 
 type PluginConfig struct {
-	DisableOptimized bool `json:"disableOptimized,omitempty"`
+	DisableOptimized bool   `json:"disableOptimized,omitempty"`
+	OptimizationMode string `json:"optimizationMode,omitempty"` // default ratio.
 }
 
 func NewPlugin(c json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
@@ -32,6 +62,14 @@ func NewPlugin(c json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, e
 		return nil, err
 	}
 
+	if cfg.OptimizationMode == "" {
+		cfg.OptimizationMode = OptimizationModeRatio
+	}
+
+	if cfg.OptimizationMode != OptimizationModeRatio && cfg.OptimizationMode != OptimizationModeEvents {
+		return nil, fmt.Errorf("invalid optimization mode %q", cfg.OptimizationMode)
+	}
+
 	return plugin{cfg: *cfg}, nil
 }
 
@@ -40,6 +78,34 @@ type plugin struct {
 }
 
 func (p plugin) ProcessSLO(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) error {
+	// Events optimization only works with event SLIs, the rest use the ratio optimization.
+	if !p.cfg.DisableOptimized && p.cfg.OptimizationMode == OptimizationModeEvents && request.SLO.SLI.Events != nil {
+		sliRules, desc, err := generateEventsOptimizedSLIRecordingRules(request.SLO, request.MWMBAlertGroup)
+		if err != nil {
+			return err
+		}
+		result.SLORules.SLIErrorRecRules.Rules = sliRules
+
+		// Recording rules can't have annotations, so we describe the SLI calculation on the SLO alerts, the
+		// already generated ones and the ones generated after us.
+		//
+		// The plugin contract recommends not modifying the request, but on the default chain the SLO alerts
+		// are generated by the alert rules plugin after us, and the request SLO alert metadata is the only way
+		// of passing the annotations to them (the result only has the already generated rules). It's safe
+		// because the annotations are merged into new maps, so the maps shared with the original SLO are
+		// never mutated.
+		annotations := map[string]string{sliOptimizationAnnotation: desc}
+		request.SLO.PageAlertMeta.Annotations = utilsdata.MergeLabels(request.SLO.PageAlertMeta.Annotations, annotations)
+		request.SLO.TicketAlertMeta.Annotations = utilsdata.MergeLabels(request.SLO.TicketAlertMeta.Annotations, annotations)
+		for i, rule := range result.SLORules.AlertRules.Rules {
+			if rule.Alert != "" {
+				result.SLORules.AlertRules.Rules[i].Annotations = utilsdata.MergeLabels(rule.Annotations, annotations)
+			}
+		}
+
+		return nil
+	}
+
 	genFunc := factorySLIRecordGenerator
 	if !p.cfg.DisableOptimized {
 		genFunc = optimizedFactorySLIRecordGenerator
@@ -223,3 +289,88 @@ count_over_time({{.metric}}{{.filter}}[{{.window}}])
 		),
 	}, nil
 }
+
+// generateEventsOptimizedSLIRecordingRules generates the SLI recording rules recording the error and total event
+// rates on the shortest window, and deriving the SLI ratio of every window from them as
+// `sum(errors) / sum(totals)` over the window. This is a traffic weighted ratio (unlike an average of ratios) so
+// the long window SLIs are accurate, at a similar cost to the ratio optimization.
+// It returns the rules and the description of the SLI calculation.
+func generateEventsOptimizedSLIRecordingRules(slo model.PromSLO, alerts model.MWMBAlertGroup) ([]rulefmt.Rule, string, error) {
+	// Get the windows we need the recording rules (sorted), the shortest one is the one recorded.
+	windows := []time.Duration{}
+	for _, w := range alerts.TimeDurationWindows() {
+		if w > 0 && w != slo.TimeWindow {
+			windows = append(windows, w)
+		}
+	}
+	windows = append(windows, slo.TimeWindow)
+	shortWindow := windows[0]
+	strShortWindow := promutils.TimeDurationToPromStr(shortWindow)
+
+	errorMetric := fmt.Sprintf(sliErrorEventsMetricFmt, strShortWindow)
+	totalMetric := fmt.Sprintf(sliTotalEventsMetricFmt, strShortWindow)
+	filter := promutils.LabelsToPromFilter(conventions.GetSLOIDPromLabels(slo))
+	shortWindowLabels := utilsdata.MergeLabels(
+		conventions.GetSLOIDPromLabels(slo),
+		map[string]string{
+			conventions.PromSLOWindowLabelName: strShortWindow,
+		},
+		slo.Labels,
+	)
+
+	errorExpr, err := renderSLIQuery(slo.SLI.Events.ErrorQuery, shortWindow)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not create %q SLO error events rule: %w", slo.ID, err)
+	}
+	totalExpr, err := renderSLIQuery(slo.SLI.Events.TotalQuery, shortWindow)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not create %q SLO total events rule: %w", slo.ID, err)
+	}
+
+	rules := []rulefmt.Rule{
+		{Record: errorMetric, Expr: errorExpr, Labels: shortWindowLabels},
+		{Record: totalMetric, Expr: totalExpr, Labels: shortWindowLabels},
+	}
+
+	for _, window := range windows {
+		strWindow := promutils.TimeDurationToPromStr(window)
+
+		expr := fmt.Sprintf("%s%s\n/\n%s%s\n", errorMetric, filter, totalMetric, filter)
+		if window != shortWindow {
+			expr = fmt.Sprintf("sum_over_time(%s%s[%s])\n/\nsum_over_time(%s%s[%s])\n", errorMetric, filter, strWindow, totalMetric, filter, strWindow)
+		}
+
+		rules = append(rules, rulefmt.Rule{
+			Record: conventions.GetSLIErrorMetric(window),
+			Expr:   expr,
+			Labels: utilsdata.MergeLabels(
+				conventions.GetSLOIDPromLabels(slo),
+				map[string]string{
+					conventions.PromSLOWindowLabelName: strWindow,
+				},
+				slo.Labels,
+			),
+		})
+	}
+
+	desc := fmt.Sprintf("SLI windows are calculated as sum(errors)/sum(totals) over the %s recorded error and total event rates (%s and %s).", strShortWindow, errorMetric, totalMetric)
+
+	return rules, desc, nil
+}
+
+func renderSLIQuery(query string, window time.Duration) (string, error) {
+	tpl, err := template.New("sliExpr").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("could not create SLI expression template data: %w", err)
+	}
+
+	var b bytes.Buffer
+	err = tpl.Execute(&b, map[string]string{
+		conventions.TplSLIQueryWindowVarName: promutils.TimeDurationToPromStr(window),
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not render SLI expression template: %w", err)
+	}
+
+	return b.String(), nil
+}
@@ -2,6 +2,7 @@ package plugin_test
 
 import (
 	"encoding/json"
+	"maps"
 	"testing"
 	"time"
 
@@ -519,6 +520,215 @@ func TestGenerateSLIRecordingRules(t *testing.T) {
 	}
 }
 
+func TestGenerateSLIRecordingRulesEventsOptimization(t *testing.T) {
+	expLabels := func(window string) map[string]string {
+		return map[string]string{
+			"kind":          "test",
+			"sloth_service": "test-svc",
+			"sloth_slo":     "test-name",
+			"sloth_id":      "test",
+			"sloth_window":  window,
+		}
+	}
+	expLongWindowRule := func(window string) rulefmt.Rule {
+		return rulefmt.Rule{
+			Record: "slo:sli_error:ratio_rate" + window,
+			Expr:   "sum_over_time(slo:sli_error_events:rate5m{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[" + window + "])\n/\nsum_over_time(slo:sli_total_events:rate5m{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[" + window + "])\n",
+			Labels: expLabels(window),
+		}
+	}
+	expRawWindowRule := func(window string) rulefmt.Rule {
+		return rulefmt.Rule{
+			Record: "slo:sli_error:ratio_rate" + window,
+			Expr:   "(rate(my_metric[" + window + "]{error=\"true\"}))\n/\n(rate(my_metric[" + window + "]))\n",
+			Labels: expLabels(window),
+		}
+	}
+	const expDesc = "SLI windows are calculated as sum(errors)/sum(totals) over the 5m recorded error and total event rates (slo:sli_error_events:rate5m and slo:sli_total_events:rate5m)."
+
+	tests := map[string]struct {
+		config         string
+		slo            func() model.PromSLO
+		alertRules     []rulefmt.Rule
+		expRules       []rulefmt.Rule
+		expAnnotations map[string]string
+		expAlertRules  []rulefmt.Rule
+		expLoadErr     bool
+		expErr         bool
+	}{
+		"An invalid optimization mode should fail.": {
+			config:     `{"optimizationMode": "wrong"}`,
+			slo:        baseSLO,
+			expLoadErr: true,
+		},
+
+		"Having an SLO with SLI(events) and events optimization should record the events and derive the SLI windows from them.": {
+			config: `{"optimizationMode": "events"}`,
+			slo: func() model.PromSLO {
+				slo := baseSLO()
+				slo.PageAlertMeta.Annotations = map[string]string{"runbook": "https://example.com"}
+				return slo
+			},
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:sli_error_events:rate5m",
+					Expr:   `rate(my_metric[5m]{error="true"})`,
+					Labels: expLabels("5m"),
+				},
+				{
+					Record: "slo:sli_total_events:rate5m",
+					Expr:   `rate(my_metric[5m])`,
+					Labels: expLabels("5m"),
+				},
+				{
+					Record: "slo:sli_error:ratio_rate5m",
+					Expr:   "slo:sli_error_events:rate5m{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n/\nslo:sli_total_events:rate5m{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n",
+					Labels: expLabels("5m"),
+				},
+				expLongWindowRule("30m"),
+				expLongWindowRule("1h"),
+				expLongWindowRule("2h"),
+				expLongWindowRule("6h"),
+				expLongWindowRule("1d"),
+				expLongWindowRule("3d"),
+				expLongWindowRule("30d"),
+			},
+			expAnnotations: map[string]string{
+				"runbook":          "https://example.com",
+				"sli_optimization": expDesc,
+			},
+		},
+
+		"Having an SLO with already generated alerts and events optimization should describe the SLI calculation on the alerts.": {
+			config: `{"optimizationMode": "events"}`,
+			slo:    baseSLO,
+			alertRules: []rulefmt.Rule{
+				{Alert: "Test", Expr: "vector(1)", Annotations: map[string]string{"title": "test"}},
+				{Record: "test:record", Expr: "vector(1)"},
+			},
+			expRules: []rulefmt.Rule{
+				{
+					Record: "slo:sli_error_events:rate5m",
+					Expr:   `rate(my_metric[5m]{error="true"})`,
+					Labels: expLabels("5m"),
+				},
+				{
+					Record: "slo:sli_total_events:rate5m",
+					Expr:   `rate(my_metric[5m])`,
+					Labels: expLabels("5m"),
+				},
+				{
+					Record: "slo:sli_error:ratio_rate5m",
+					Expr:   "slo:sli_error_events:rate5m{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n/\nslo:sli_total_events:rate5m{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}\n",
+					Labels: expLabels("5m"),
+				},
+				expLongWindowRule("30m"),
+				expLongWindowRule("1h"),
+				expLongWindowRule("2h"),
+				expLongWindowRule("6h"),
+				expLongWindowRule("1d"),
+				expLongWindowRule("3d"),
+				expLongWindowRule("30d"),
+			},
+			expAnnotations: map[string]string{
+				"sli_optimization": expDesc,
+			},
+			expAlertRules: []rulefmt.Rule{
+				{Alert: "Test", Expr: "vector(1)", Annotations: map[string]string{"title": "test", "sli_optimization": expDesc}},
+				{Record: "test:record", Expr: "vector(1)"},
+			},
+		},
+
+		"Having an SLO with SLI(raw) and events optimization should use the ratio optimization.": {
+			config: `{"optimizationMode": "events"}`,
+			slo: func() model.PromSLO {
+				slo := baseSLO()
+				slo.SLI = model.PromSLI{Raw: &model.PromSLIRaw{ErrorRatioQuery: `rate(my_errors[{{.window}}])`}}
+				return slo
+			},
+			expRules: []rulefmt.Rule{
+				{Record: "slo:sli_error:ratio_rate5m", Expr: "(rate(my_errors[5m]))", Labels: expLabels("5m")},
+				{Record: "slo:sli_error:ratio_rate30m", Expr: "(rate(my_errors[30m]))", Labels: expLabels("30m")},
+				{Record: "slo:sli_error:ratio_rate1h", Expr: "(rate(my_errors[1h]))", Labels: expLabels("1h")},
+				{Record: "slo:sli_error:ratio_rate2h", Expr: "(rate(my_errors[2h]))", Labels: expLabels("2h")},
+				{Record: "slo:sli_error:ratio_rate6h", Expr: "(rate(my_errors[6h]))", Labels: expLabels("6h")},
+				{Record: "slo:sli_error:ratio_rate1d", Expr: "(rate(my_errors[1d]))", Labels: expLabels("1d")},
+				{Record: "slo:sli_error:ratio_rate3d", Expr: "(rate(my_errors[3d]))", Labels: expLabels("3d")},
+				{
+					Record: "slo:sli_error:ratio_rate30d",
+					Expr:   "sum_over_time(slo:sli_error:ratio_rate5m{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n/ ignoring (sloth_window)\ncount_over_time(slo:sli_error:ratio_rate5m{sloth_id=\"test\", sloth_service=\"test-svc\", sloth_slo=\"test-name\"}[30d])\n",
+					Labels: expLabels("30d"),
+				},
+			},
+		},
+
+		"Having an SLO with SLI(events), events optimization and disabled optimizations should not optimize.": {
+			config: `{"optimizationMode": "events", "disableOptimized": true}`,
+			slo:    baseSLO,
+			expRules: []rulefmt.Rule{
+				expRawWindowRule("5m"),
+				expRawWindowRule("30m"),
+				expRawWindowRule("1h"),
+				expRawWindowRule("2h"),
+				expRawWindowRule("6h"),
+				expRawWindowRule("1d"),
+				expRawWindowRule("3d"),
+				expRawWindowRule("30d"),
+			},
+		},
+
+		"Having and wrong variable in the expression should fail.": {
+			config: `{"optimizationMode": "events"}`,
+			slo: func() model.PromSLO {
+				slo := baseSLO()
+				slo.SLI.Events.ErrorQuery = `rate(my_metric[{{.Window}}]{error="true"})`
+				return slo
+			},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			// Load plugin
+			plugin, err := pluginslov1testing.NewTestPlugin(t.Context(), pluginslov1testing.TestPluginConfig{PluginConfiguration: []byte(test.config)})
+			if test.expLoadErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			// Execute plugin.
+			slo := test.slo()
+			origPageAnnotations := maps.Clone(slo.PageAlertMeta.Annotations)
+			req := pluginslov1.Request{
+				SLO:            slo,
+				MWMBAlertGroup: baseAlertGroup(),
+			}
+			res := pluginslov1.Result{}
+			res.SLORules.AlertRules.Rules = test.alertRules
+			err = plugin.ProcessSLO(t.Context(), &req, &res)
+
+			// Check result.
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expRules, res.SLORules.SLIErrorRecRules.Rules)
+				assert.Equal(test.expAlertRules, res.SLORules.AlertRules.Rules)
+				assert.Equal(test.expAnnotations, req.SLO.PageAlertMeta.Annotations)
+				// The original SLO annotations should not be mutated.
+				assert.Equal(origPageAnnotations, slo.PageAlertMeta.Annotations)
+				if test.expAnnotations != nil {
+					assert.Equal(expDesc, req.SLO.TicketAlertMeta.Annotations["sli_optimization"])
+				}
+			}
+		})
+	}
+}
+
 func BenchmarkPluginYaegi(b *testing.B) {
 	plugin, err := pluginslov1testing.NewTestPlugin(b.Context(), pluginslov1testing.TestPluginConfig{
 		PluginConfiguration: []byte("{}"),
@@ -10,8 +10,9 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/slo/v1"
-	PluginID      = "sloth.dev/core/validate/v1"
+	PluginVersion     = "prometheus/slo/v1"
+	PluginID          = "sloth.dev/core/validate/v1"
+	PluginDescription = "Validates the SLO using the Prometheus PromQL dialect."
 )
 
 func NewPlugin(_ json.RawMessage, appUtils pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
@@ -12,6 +12,9 @@ import (
 func init() {
 	Symbols["github.com/slok/sloth/pkg/common/model/model"] = map[string]reflect.Value{
 		// function, constant and variable definitions
+		"ChangeTypeAdded":             reflect.ValueOf(model.ChangeTypeAdded),
+		"ChangeTypeModified":          reflect.ValueOf(model.ChangeTypeModified),
+		"ChangeTypeRemoved":           reflect.ValueOf(model.ChangeTypeRemoved),
 		"ModeAPIGenKubernetes":        reflect.ValueOf(constant.MakeFromLiteral("\"api-gen-k8s\"", token.STRING, 0)),
 		"ModeAPIGenOpenSLO":           reflect.ValueOf(constant.MakeFromLiteral("\"api-gen-openslo\"", token.STRING, 0)),
 		"ModeAPIGenPrometheus":        reflect.ValueOf(constant.MakeFromLiteral("\"api-gen-prom\"", token.STRING, 0)),
@@ -25,24 +28,29 @@ func init() {
 		"UnknownAlertSeverity":        reflect.ValueOf(model.UnknownAlertSeverity),
 
 		// type definitions
-		"AlertSeverity":         reflect.ValueOf((*model.AlertSeverity)(nil)),
-		"Info":                  reflect.ValueOf((*model.Info)(nil)),
-		"K8sMeta":               reflect.ValueOf((*model.K8sMeta)(nil)),
-		"MWMBAlert":             reflect.ValueOf((*model.MWMBAlert)(nil)),
-		"MWMBAlertGroup":        reflect.ValueOf((*model.MWMBAlertGroup)(nil)),
-		"Mode":                  reflect.ValueOf((*model.Mode)(nil)),
-		"PromAlertMeta":         reflect.ValueOf((*model.PromAlertMeta)(nil)),
-		"PromRuleGroup":         reflect.ValueOf((*model.PromRuleGroup)(nil)),
-		"PromSLI":               reflect.ValueOf((*model.PromSLI)(nil)),
-		"PromSLIEvents":         reflect.ValueOf((*model.PromSLIEvents)(nil)),
-		"PromSLIRaw":            reflect.ValueOf((*model.PromSLIRaw)(nil)),
-		"PromSLO":               reflect.ValueOf((*model.PromSLO)(nil)),
-		"PromSLOGroup":          reflect.ValueOf((*model.PromSLOGroup)(nil)),
-		"PromSLOGroupResult":    reflect.ValueOf((*model.PromSLOGroupResult)(nil)),
-		"PromSLOGroupSource":    reflect.ValueOf((*model.PromSLOGroupSource)(nil)),
-		"PromSLOPluginMetadata": reflect.ValueOf((*model.PromSLOPluginMetadata)(nil)),
-		"PromSLOResult":         reflect.ValueOf((*model.PromSLOResult)(nil)),
-		"PromSLORules":          reflect.ValueOf((*model.PromSLORules)(nil)),
-		"SLOPlugins":            reflect.ValueOf((*model.SLOPlugins)(nil)),
+		"AlertSeverity":          reflect.ValueOf((*model.AlertSeverity)(nil)),
+		"ChangeType":             reflect.ValueOf((*model.ChangeType)(nil)),
+		"FieldChange":            reflect.ValueOf((*model.FieldChange)(nil)),
+		"Info":                   reflect.ValueOf((*model.Info)(nil)),
+		"K8sMeta":                reflect.ValueOf((*model.K8sMeta)(nil)),
+		"MWMBAlert":              reflect.ValueOf((*model.MWMBAlert)(nil)),
+		"MWMBAlertGroup":         reflect.ValueOf((*model.MWMBAlertGroup)(nil)),
+		"Mode":                   reflect.ValueOf((*model.Mode)(nil)),
+		"PromAlertMeta":          reflect.ValueOf((*model.PromAlertMeta)(nil)),
+		"PromRuleChange":         reflect.ValueOf((*model.PromRuleChange)(nil)),
+		"PromRuleGroup":          reflect.ValueOf((*model.PromRuleGroup)(nil)),
+		"PromSLI":                reflect.ValueOf((*model.PromSLI)(nil)),
+		"PromSLIEvents":          reflect.ValueOf((*model.PromSLIEvents)(nil)),
+		"PromSLIRaw":             reflect.ValueOf((*model.PromSLIRaw)(nil)),
+		"PromSLO":                reflect.ValueOf((*model.PromSLO)(nil)),
+		"PromSLOGroup":           reflect.ValueOf((*model.PromSLOGroup)(nil)),
+		"PromSLOGroupResult":     reflect.ValueOf((*model.PromSLOGroupResult)(nil)),
+		"PromSLOGroupSource":     reflect.ValueOf((*model.PromSLOGroupSource)(nil)),
+		"PromSLOPluginMetadata":  reflect.ValueOf((*model.PromSLOPluginMetadata)(nil)),
+		"PromSLOPluginTrace":     reflect.ValueOf((*model.PromSLOPluginTrace)(nil)),
+		"PromSLOPluginTraceStep": reflect.ValueOf((*model.PromSLOPluginTraceStep)(nil)),
+		"PromSLOResult":          reflect.ValueOf((*model.PromSLOResult)(nil)),
+		"PromSLORules":           reflect.ValueOf((*model.PromSLORules)(nil)),
+		"SLOPlugins":             reflect.ValueOf((*model.SLOPlugins)(nil)),
 	}
 }
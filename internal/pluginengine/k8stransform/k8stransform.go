@@ -10,12 +10,14 @@ import (
 	"github.com/traefik/yaegi/stdlib"
 	"github.com/traefik/yaegi/stdlib/unsafe"
 
+	"github.com/slok/sloth/internal/pluginengine"
 	"github.com/slok/sloth/internal/pluginengine/k8stransform/custom"
 	plugink8stransformv1 "github.com/slok/sloth/pkg/prometheus/plugin/k8stransform/v1"
 )
 
 type Plugin struct {
 	ID                   string
+	Metadata             pluginengine.Metadata
 	PluginK8sTransformV1 plugink8stransformv1.PluginFactory
 }
 
@@ -26,6 +28,29 @@ type pluginLoader bool
 
 var packageRegexp = regexp.MustCompile(`(?m)^package +([^\s]+) *$`)
 
+// DiscoverRawPlugin knows how to get the plugin ID from source data without interpreting it, this is
+// much cheaper than loading the plugin so it can be used to discover plugins and load them lazily.
+//
+// The plugin version and ID must be declared using string literals (e.g: `PluginID = "my-plugin"`),
+// otherwise the discovery will fail and the plugin will need to be loaded to know its ID.
+func (p pluginLoader) DiscoverRawPlugin(ctx context.Context, src string) (string, error) {
+	consts, err := pluginengine.SourceStringConstants(src)
+	if err != nil {
+		return "", fmt.Errorf("invalid plugin source code: %w", err)
+	}
+
+	if pluginVer := consts["PluginVersion"]; pluginVer != plugink8stransformv1.Version {
+		return "", fmt.Errorf("unsuported plugin version: %s", pluginVer)
+	}
+
+	pluginID := consts["PluginID"]
+	if pluginID == "" {
+		return "", fmt.Errorf("could not get plugin ID")
+	}
+
+	return pluginID, nil
+}
+
 // LoadRawPlugin knows how to load plugins using Yaegi from source data not files,
 // thats why, this implementation will not support any import library except standard
 // library.
@@ -34,6 +59,7 @@ var packageRegexp = regexp.MustCompile(`(?m)^package +([^\s]+) *$`)
 // - A function called `NewPlugin` to obtain the plugin factory.
 // - A constant called `PluginID` to obtain the plugin ID.
 // - A constant called `PluginVersion` to obtain the plugin version.
+// - Optional `PluginDescription` and `PluginModes` metadata.
 func (p pluginLoader) LoadRawPlugin(ctx context.Context, src string) (*Plugin, error) {
 	// Load the plugin in a new interpreter.
 	// For each plugin we need to use an independent interpreter to avoid name collisions.
@@ -87,8 +113,22 @@ func (p pluginLoader) LoadRawPlugin(ctx context.Context, src string) (*Plugin, e
 		return nil, fmt.Errorf("invalid k8s transform plugin type")
 	}
 
+	// Get optional plugin metadata.
+	metadata, err := pluginengine.SourceMetadata(src, pluginengine.MetadataNames{
+		Description: plugink8stransformv1.PluginDescriptionName,
+		Modes:       plugink8stransformv1.PluginModesName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get plugin metadata: %w", err)
+	}
+	err = metadata.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin metadata: %w", err)
+	}
+
 	return &Plugin{
 		ID:                   pluginID,
+		Metadata:             metadata,
 		PluginK8sTransformV1: plugin,
 	}, nil
 }
@@ -248,3 +248,48 @@ func (noopPlugin) TransformK8sObjects(ctx context.Context, kmeta model.K8sMeta,
 		})
 	}
 }
+
+func TestPluginDiscovery(t *testing.T) {
+	tests := map[string]struct {
+		pluginSrc   string
+		expPluginID string
+		expErr      bool
+	}{
+		"An invalid plugin syntax should fail": {
+			pluginSrc: `package test{`,
+			expErr:    true,
+		},
+
+		"A plugin with a different plugin type version, should fail.": {
+			pluginSrc: `package test
+const (
+	PluginVersion = "prometheus/slo/v1"
+	PluginID      = "test-plugin"
+)
+`,
+			expErr: true,
+		},
+
+		"A correct plugin should return the plugin ID.": {
+			pluginSrc: `package test
+const (
+	PluginVersion = "prometheus/k8stransform/v1"
+	PluginID      = "test-plugin"
+)
+`,
+			expPluginID: "test-plugin",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			gotID, err := pluginenginek8stransform.PluginLoader.DiscoverRawPlugin(t.Context(), test.pluginSrc)
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expPluginID, gotID)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package pluginengine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"github.com/slok/sloth/pkg/common/model"
+	pluginslov1exec "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1/exec"
+)
+
+// Metadata is the optional information plugins can export to describe themselves.
+type Metadata struct {
+	// Description is the plugin description.
+	Description string
+	// ConfigSchema is the JSON schema of the plugin configuration.
+	ConfigSchema string
+	// ConfigExample is an example of the plugin configuration in JSON.
+	ConfigExample string
+	// Modes are the Sloth modes supported by the plugin, if empty, all modes are supported.
+	Modes []model.Mode
+}
+
+// Validate validates the metadata, the config schema must be a valid JSON schema and the config
+// example must be valid against the schema.
+func (m Metadata) Validate() error {
+	if m.ConfigSchema != "" {
+		_, err := compileConfigSchema(m.ConfigSchema)
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.ConfigExample != "" {
+		if !json.Valid([]byte(m.ConfigExample)) {
+			return fmt.Errorf("config example is not valid JSON")
+		}
+
+		err := m.ValidateConfig(json.RawMessage(m.ConfigExample))
+		if err != nil {
+			return fmt.Errorf("invalid config example: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SupportsMode returns true if the plugin supports the Sloth mode.
+func (m Metadata) SupportsMode(mode model.Mode) bool {
+	return len(m.Modes) == 0 || slices.Contains(m.Modes, mode)
+}
+
+// ValidateConfig validates a JSON plugin configuration against the config schema, if the plugin doesn't have
+// a config schema, any config will be valid. A missing config is validated as an empty object.
+func (m Metadata) ValidateConfig(config json.RawMessage) error {
+	if m.ConfigSchema == "" {
+		return nil
+	}
+
+	schema, err := compileConfigSchema(m.ConfigSchema)
+	if err != nil {
+		return err
+	}
+
+	config = bytes.TrimSpace(config)
+	if len(config) == 0 || bytes.Equal(config, []byte("null")) {
+		config = json.RawMessage("{}")
+	}
+
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(config))
+	if err != nil {
+		return fmt.Errorf("invalid config JSON: %w", err)
+	}
+
+	err = schema.Validate(instance)
+	if err != nil {
+		return fmt.Errorf("config doesn't match the plugin config schema: %w", err)
+	}
+
+	return nil
+}
+
+// compiledSchemas caches the compiled config schemas by their source, so the same schema
+// is not compiled on every validation (e.g: once per SLO).
+var compiledSchemas sync.Map
+
+const configSchemaURL = "https://sloth.dev/schemas/plugin-config.schema.json"
+
+func compileConfigSchema(schema string) (*jsonschema.Schema, error) {
+	if s, ok := compiledSchemas.Load(schema); ok {
+		return s.(*jsonschema.Schema), nil
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("config schema is not valid JSON: %w", err)
+	}
+
+	c := jsonschema.NewCompiler()
+	c.UseLoader(jsonschema.SchemeURLLoader{}) // Don't load external references (e.g: files).
+	err = c.AddResource(configSchemaURL, doc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config schema: %w", err)
+	}
+
+	s, err := c.Compile(configSchemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config schema: %w", err)
+	}
+	compiledSchemas.Store(schema, s)
+
+	return s, nil
+}
+
+// ManifestMetadata returns the plugin metadata of a plugin manifest (e.g: exec and WASM plugins).
+func ManifestMetadata(mm pluginslov1exec.ManifestMetadata) (Metadata, error) {
+	m := Metadata{
+		Description:   mm.Description,
+		ConfigSchema:  string(mm.ConfigSchema),
+		ConfigExample: string(mm.ConfigExample),
+	}
+	for _, mode := range mm.Modes {
+		m.Modes = append(m.Modes, model.Mode(mode))
+	}
+
+	err := m.Validate()
+	if err != nil {
+		return Metadata{}, fmt.Errorf("invalid plugin metadata: %w", err)
+	}
+
+	return m, nil
+}
+
+// MetadataNames are the names of the metadata declarations on the Go plugins source, empty
+// names will be ignored.
+type MetadataNames struct {
+	Description   string
+	ConfigSchema  string
+	ConfigExample string
+	Modes         string
+}
+
+// SourceMetadata returns the plugin metadata of a Go plugin source without interpreting it. The metadata
+// must be declared at the top level using literals (e.g: `PluginDescription = "..."` and
+// `PluginModes = []string{"cli-gen-prom"}`).
+func SourceMetadata(src string, names MetadataNames) (Metadata, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("could not parse source code: %w", err)
+	}
+
+	consts := fileStringConstants(f)
+	m := Metadata{}
+	if names.Description != "" {
+		m.Description = consts[names.Description]
+	}
+	if names.ConfigSchema != "" {
+		m.ConfigSchema = consts[names.ConfigSchema]
+	}
+	if names.ConfigExample != "" {
+		m.ConfigExample = consts[names.ConfigExample]
+	}
+	if names.Modes == "" {
+		return m, nil
+	}
+	for _, mode := range fileStringSliceVariables(f)[names.Modes] {
+		m.Modes = append(m.Modes, model.Mode(mode))
+	}
+
+	return m, nil
+}
+
+// fileStringSliceVariables returns the top level variables declared using a string slice
+// literal (e.g: `PluginModes = []string{"a", "b"}`).
+func fileStringSliceVariables(f *ast.File) map[string][]string {
+	vars := map[string][]string{}
+	for name, value := range fileTopLevelValues(f) {
+		lit, ok := value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+
+		arrayType, ok := lit.Type.(*ast.ArrayType)
+		if !ok || arrayType.Len != nil {
+			continue
+		}
+
+		values := []string{}
+		for _, elt := range lit.Elts {
+			v, ok := stringLiteral(elt)
+			if !ok {
+				values = nil
+				break
+			}
+			values = append(values, v)
+		}
+		if values != nil {
+			vars[name] = values
+		}
+	}
+
+	return vars
+}
+
+func fileStringConstants(f *ast.File) map[string]string {
+	consts := map[string]string{}
+	for name, value := range fileTopLevelValues(f) {
+		if v, ok := stringLiteral(value); ok {
+			consts[name] = v
+		}
+	}
+
+	return consts
+}
+
+// fileTopLevelValues returns the values of the top level constants and variables.
+func fileTopLevelValues(f *ast.File) map[string]ast.Expr {
+	values := map[string]ast.Expr{}
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || (genDecl.Tok != token.CONST && genDecl.Tok != token.VAR) {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+
+			for i, name := range valueSpec.Names {
+				if i >= len(valueSpec.Values) {
+					break
+				}
+				values[name.Name] = valueSpec.Values[i]
+			}
+		}
+	}
+
+	return values
+}
+
+func stringLiteral(e ast.Expr) (string, bool) {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+
+	v, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+
+	return v, true
+}
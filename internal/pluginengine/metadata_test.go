@@ -0,0 +1,282 @@
+package pluginengine_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slok/sloth/internal/pluginengine"
+	"github.com/slok/sloth/pkg/common/model"
+	pluginslov1exec "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1/exec"
+)
+
+func TestSourceMetadata(t *testing.T) {
+	tests := map[string]struct {
+		src         string
+		names       pluginengine.MetadataNames
+		expMetadata pluginengine.Metadata
+		expErr      bool
+	}{
+		"Invalid source should fail.": {
+			src:    `package test{`,
+			expErr: true,
+		},
+
+		"A source without metadata should return empty metadata.": {
+			src: `package test
+const PluginID = "test"
+`,
+			names:       pluginengine.MetadataNames{Description: "PluginDescription", Modes: "PluginModes"},
+			expMetadata: pluginengine.Metadata{},
+		},
+
+		"A source with metadata should return the metadata.": {
+			src: "package test\n" +
+				"const (\n" +
+				"	PluginDescription = \"Test plugin.\"\n" +
+				"	PluginConfigSchema = `{\"type\": \"object\"}`\n" +
+				"	PluginConfigExample = `{}`\n" +
+				")\n" +
+				"var PluginModes = []string{\"cli-gen-prom\", \"api-gen-prom\"}\n",
+			names: pluginengine.MetadataNames{
+				Description:   "PluginDescription",
+				ConfigSchema:  "PluginConfigSchema",
+				ConfigExample: "PluginConfigExample",
+				Modes:         "PluginModes",
+			},
+			expMetadata: pluginengine.Metadata{
+				Description:   "Test plugin.",
+				ConfigSchema:  `{"type": "object"}`,
+				ConfigExample: `{}`,
+				Modes:         []model.Mode{model.ModeCLIGenPrometheus, model.ModeAPIGenPrometheus},
+			},
+		},
+
+		"Metadata not requested by name should be ignored.": {
+			src: `package test
+const PluginDescription = "Test plugin."
+var PluginModes = []string{"cli-gen-prom"}
+`,
+			names:       pluginengine.MetadataNames{Description: "PluginDescription"},
+			expMetadata: pluginengine.Metadata{Description: "Test plugin."},
+		},
+
+		"Metadata not declared with literals should be ignored.": {
+			src: `package test
+var desc = "Test plugin."
+var PluginDescription = desc
+var PluginModes = []string{"cli-gen-prom", desc}
+`,
+			names:       pluginengine.MetadataNames{Description: "PluginDescription", Modes: "PluginModes"},
+			expMetadata: pluginengine.Metadata{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gotMetadata, err := pluginengine.SourceMetadata(test.src, test.names)
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expMetadata, gotMetadata)
+			}
+		})
+	}
+}
+
+func TestMetadataValidate(t *testing.T) {
+	tests := map[string]struct {
+		metadata pluginengine.Metadata
+		expErr   bool
+	}{
+		"Empty metadata should be valid.": {
+			metadata: pluginengine.Metadata{},
+		},
+
+		"A valid schema and example should be valid.": {
+			metadata: pluginengine.Metadata{
+				ConfigSchema:  `{"type": "object", "required": ["name"]}`,
+				ConfigExample: `{"name": "test"}`,
+			},
+		},
+
+		"A schema that is not JSON should fail.": {
+			metadata: pluginengine.Metadata{ConfigSchema: `{`},
+			expErr:   true,
+		},
+
+		"An invalid schema should fail.": {
+			metadata: pluginengine.Metadata{ConfigSchema: `{"type": "unknown"}`},
+			expErr:   true,
+		},
+
+		"A schema with external references should fail.": {
+			metadata: pluginengine.Metadata{ConfigSchema: `{"$ref": "file:///etc/passwd"}`},
+			expErr:   true,
+		},
+
+		"An example that is not JSON should fail.": {
+			metadata: pluginengine.Metadata{ConfigExample: `{`},
+			expErr:   true,
+		},
+
+		"An example that doesn't match the schema should fail.": {
+			metadata: pluginengine.Metadata{
+				ConfigSchema:  `{"type": "object", "required": ["name"]}`,
+				ConfigExample: `{"other": "test"}`,
+			},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.metadata.Validate()
+
+			if test.expErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMetadataValidateConfig(t *testing.T) {
+	tests := map[string]struct {
+		metadata pluginengine.Metadata
+		config   json.RawMessage
+		expErr   bool
+	}{
+		"Without schema any config should be valid.": {
+			metadata: pluginengine.Metadata{},
+			config:   json.RawMessage(`{"anything": true}`),
+		},
+
+		"A config that matches the schema should be valid.": {
+			metadata: pluginengine.Metadata{ConfigSchema: `{"type": "object", "required": ["name"]}`},
+			config:   json.RawMessage(`{"name": "test"}`),
+		},
+
+		"A config that doesn't match the schema should fail.": {
+			metadata: pluginengine.Metadata{ConfigSchema: `{"type": "object", "required": ["name"]}`},
+			config:   json.RawMessage(`{"other": "test"}`),
+			expErr:   true,
+		},
+
+		"A missing config should be validated as an empty object.": {
+			metadata: pluginengine.Metadata{ConfigSchema: `{"type": "object"}`},
+			config:   nil,
+		},
+
+		"A null config should be validated as an empty object.": {
+			metadata: pluginengine.Metadata{ConfigSchema: `{"type": "object", "required": ["name"]}`},
+			config:   json.RawMessage(`null`),
+			expErr:   true,
+		},
+
+		"A config that is not JSON should fail.": {
+			metadata: pluginengine.Metadata{ConfigSchema: `{"type": "object"}`},
+			config:   json.RawMessage(`{`),
+			expErr:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.metadata.ValidateConfig(test.config)
+
+			if test.expErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMetadataSupportsMode(t *testing.T) {
+	tests := map[string]struct {
+		metadata pluginengine.Metadata
+		mode     model.Mode
+		exp      bool
+	}{
+		"Without modes, all modes should be supported.": {
+			metadata: pluginengine.Metadata{},
+			mode:     model.ModeCLIGenKubernetes,
+			exp:      true,
+		},
+
+		"A declared mode should be supported.": {
+			metadata: pluginengine.Metadata{Modes: []model.Mode{model.ModeCLIGenPrometheus, model.ModeCLIGenKubernetes}},
+			mode:     model.ModeCLIGenKubernetes,
+			exp:      true,
+		},
+
+		"A not declared mode should not be supported.": {
+			metadata: pluginengine.Metadata{Modes: []model.Mode{model.ModeCLIGenKubernetes}},
+			mode:     model.ModeCLIGenPrometheus,
+			exp:      false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.exp, test.metadata.SupportsMode(test.mode))
+		})
+	}
+}
+
+func TestManifestMetadata(t *testing.T) {
+	tests := map[string]struct {
+		manifestMetadata pluginslov1exec.ManifestMetadata
+		expMetadata      pluginengine.Metadata
+		expErr           bool
+	}{
+		"Empty manifest metadata should return empty metadata.": {
+			manifestMetadata: pluginslov1exec.ManifestMetadata{},
+			expMetadata:      pluginengine.Metadata{},
+		},
+
+		"Manifest metadata should be mapped.": {
+			manifestMetadata: pluginslov1exec.ManifestMetadata{
+				Description:   "Test plugin.",
+				ConfigSchema:  json.RawMessage(`{"type": "object"}`),
+				ConfigExample: json.RawMessage(`{}`),
+				Modes:         []string{"cli-gen-prom"},
+			},
+			expMetadata: pluginengine.Metadata{
+				Description:   "Test plugin.",
+				ConfigSchema:  `{"type": "object"}`,
+				ConfigExample: `{}`,
+				Modes:         []model.Mode{model.ModeCLIGenPrometheus},
+			},
+		},
+
+		"Invalid manifest metadata should fail.": {
+			manifestMetadata: pluginslov1exec.ManifestMetadata{
+				ConfigSchema:  json.RawMessage(`{"type": "object", "required": ["name"]}`),
+				ConfigExample: json.RawMessage(`{}`),
+			},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gotMetadata, err := pluginengine.ManifestMetadata(test.manifestMetadata)
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expMetadata, gotMetadata)
+			}
+		})
+	}
+}
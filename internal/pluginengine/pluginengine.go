@@ -0,0 +1,117 @@
+// Package pluginengine has the logic shared by the different plugin engines.
+package pluginengine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"strings"
+
+	"github.com/slok/sloth/internal/log"
+)
+
+// SourceStringConstants returns the top level constants and variables of a Go source that have
+// been declared using a string literal value (e.g: `PluginID = "my-plugin"`).
+//
+// The source is only parsed, not interpreted, so it's a cheap way of getting plugin
+// metadata (like the ID and version) without loading the plugin.
+func SourceStringConstants(src string) (map[string]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse source code: %w", err)
+	}
+
+	return fileStringConstants(f), nil
+}
+
+// LogLineWriter is a writer that logs each written line with the logger, it's used to log the output of
+// the plugins that are executed out of Sloth process (e.g: exec plugins stderr).
+type LogLineWriter struct {
+	logger log.Logger
+	buf    []byte
+}
+
+// NewLogLineWriter returns a new LogLineWriter.
+func NewLogLineWriter(logger log.Logger) *LogLineWriter {
+	return &LogLineWriter{logger: logger}
+}
+
+func (w *LogLineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.log(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush logs the pending data that doesn't end with a new line.
+func (w *LogLineWriter) Flush() {
+	w.log(w.buf)
+	w.buf = nil
+}
+
+func (w *LogLineWriter) log(line []byte) {
+	l := strings.TrimSpace(string(line))
+	if l == "" {
+		return
+	}
+	w.logger.Infof("%s", l)
+}
+
+// ErrWriteLimitExceeded is returned by the LimitedWriter when the write limit has been exceeded.
+var ErrWriteLimitExceeded = errors.New("write limit exceeded")
+
+// LimitedWriter is a writer that only writes up to a limit of bytes, it's used to limit the output of the
+// plugins that are executed out of Sloth process, so they can't exhaust Sloth memory.
+//
+// Once the limit is exceeded, the writes fail and the exceeded callback is called (e.g: to stop the plugin).
+type LimitedWriter struct {
+	w          io.Writer
+	remaining  int64
+	exceeded   bool
+	onExceeded func()
+}
+
+// NewLimitedWriter returns a new LimitedWriter, the exceeded callback is optional.
+func NewLimitedWriter(w io.Writer, limit int64, onExceeded func()) *LimitedWriter {
+	if onExceeded == nil {
+		onExceeded = func() {}
+	}
+	return &LimitedWriter{w: w, remaining: limit, onExceeded: onExceeded}
+}
+
+func (w *LimitedWriter) Write(p []byte) (int, error) {
+	if w.exceeded {
+		return 0, ErrWriteLimitExceeded
+	}
+
+	if int64(len(p)) <= w.remaining {
+		n, err := w.w.Write(p)
+		w.remaining -= int64(n)
+		return n, err
+	}
+
+	n, err := w.w.Write(p[:w.remaining])
+	w.remaining -= int64(n)
+	w.exceeded = true
+	w.onExceeded()
+	if err != nil {
+		return n, err
+	}
+
+	return n, ErrWriteLimitExceeded
+}
+
+// Exceeded returns true if the write limit has been exceeded.
+func (w *LimitedWriter) Exceeded() bool {
+	return w.exceeded
+}
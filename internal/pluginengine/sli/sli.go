@@ -8,12 +8,14 @@ import (
 	"github.com/traefik/yaegi/interp"
 	"github.com/traefik/yaegi/stdlib"
 
+	"github.com/slok/sloth/internal/pluginengine"
 	pluginv1 "github.com/slok/sloth/pkg/prometheus/plugin/v1"
 )
 
 type SLIPlugin struct {
-	ID   string
-	Func pluginv1.SLIPlugin
+	ID       string
+	Metadata pluginengine.Metadata
+	Func     pluginv1.SLIPlugin
 }
 
 // PluginLoader knows how to load Go SLI plugins using Yaegi.
@@ -23,6 +25,29 @@ type sliPluginLoader bool
 
 var packageRegexp = regexp.MustCompile(`(?m)^package +([^\s]+) *$`)
 
+// DiscoverRawSLIPlugin knows how to get the plugin ID from source data without interpreting it, this is
+// much cheaper than loading the plugin so it can be used to discover plugins and load them lazily.
+//
+// The plugin version and ID must be declared using string literals (e.g: `SLIPluginID = "my-plugin"`),
+// otherwise the discovery will fail and the plugin will need to be loaded to know its ID.
+func (s sliPluginLoader) DiscoverRawSLIPlugin(ctx context.Context, src string) (string, error) {
+	consts, err := pluginengine.SourceStringConstants(src)
+	if err != nil {
+		return "", fmt.Errorf("invalid plugin source code: %w", err)
+	}
+
+	if pluginVer := consts["SLIPluginVersion"]; pluginVer != pluginv1.Version {
+		return "", fmt.Errorf("unsuported plugin version: %s", pluginVer)
+	}
+
+	pluginID := consts["SLIPluginID"]
+	if pluginID == "" {
+		return "", fmt.Errorf("could not get plugin ID")
+	}
+
+	return pluginID, nil
+}
+
 // LoadRawSLIPlugin knows how to load plugins using Yaegi from source data not files,
 // thats why, this implementation will not support any import library except standard
 // library.
@@ -31,6 +56,7 @@ var packageRegexp = regexp.MustCompile(`(?m)^package +([^\s]+) *$`)
 // - A function called `SLIPlugin` to obtain the plugin func.
 // - A constant called `SLIPluginID` to obtain the plugin ID.
 // - A constant called `SLIPluginVersion` to obtain the plugin version.
+// - Optional `SLIPluginDescription`, `SLIPluginConfigSchema`, `SLIPluginConfigExample` and `SLIPluginModes` metadata.
 func (s sliPluginLoader) LoadRawSLIPlugin(ctx context.Context, src string) (*SLIPlugin, error) {
 	// Load the plugin in a new interpreter.
 	// For each plugin we need to use an independent interpreter to avoid name collisions.
@@ -84,9 +110,25 @@ func (s sliPluginLoader) LoadRawSLIPlugin(ctx context.Context, src string) (*SLI
 		return nil, fmt.Errorf("invalid SLI plugin type")
 	}
 
+	// Get optional plugin metadata.
+	metadata, err := pluginengine.SourceMetadata(src, pluginengine.MetadataNames{
+		Description:   pluginv1.SLIPluginDescriptionName,
+		ConfigSchema:  pluginv1.SLIPluginConfigSchemaName,
+		ConfigExample: pluginv1.SLIPluginConfigExampleName,
+		Modes:         pluginv1.SLIPluginModesName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get plugin metadata: %w", err)
+	}
+	err = metadata.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin metadata: %w", err)
+	}
+
 	return &SLIPlugin{
-		ID:   pluginID,
-		Func: pluginFunc,
+		ID:       pluginID,
+		Metadata: metadata,
+		Func:     pluginFunc,
 	}, nil
 }
 
@@ -131,3 +131,45 @@ func SLIPlugin(ctx context.Context, meta, labels, options map[string]string) (st
 		})
 	}
 }
+
+func TestSLIPluginDiscovery(t *testing.T) {
+	tests := map[string]struct {
+		pluginSrc   string
+		expPluginID string
+		expErr      bool
+	}{
+		"An invalid plugin syntax should fail": {
+			pluginSrc: `package test{`,
+			expErr:    true,
+		},
+
+		"A plugin without the required version, should fail.": {
+			pluginSrc: `package test
+const SLIPluginID = "test-plugin"
+`,
+			expErr: true,
+		},
+
+		"A correct plugin should return the plugin ID.": {
+			pluginSrc: `package test
+const (
+	SLIPluginVersion = "prometheus/v1"
+	SLIPluginID      = "test-plugin"
+)
+`,
+			expPluginID: "test-plugin",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			gotID, err := sli.PluginLoader.DiscoverRawSLIPlugin(context.Background(), test.pluginSrc)
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expPluginID, gotID)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 //go:generate yaegi extract --name custom github.com/prometheus/prometheus/promql/parser
 
 //go:generate yaegi extract --name custom github.com/slok/sloth/pkg/prometheus/plugin/slo/v1
+//go:generate yaegi extract --name custom github.com/slok/sloth/pkg/prometheus/plugin/slo/v2
 //go:generate yaegi extract --name custom github.com/slok/sloth/pkg/common/conventions
 //go:generate yaegi extract --name custom github.com/slok/sloth/pkg/common/model
 //go:generate yaegi extract --name custom github.com/slok/sloth/pkg/common/utils/data
@@ -0,0 +1,57 @@
+// Code generated by 'yaegi extract github.com/slok/sloth/pkg/prometheus/plugin/slo/v2'. DO NOT EDIT.
+
+package custom
+
+import (
+	"context"
+	"github.com/slok/sloth/pkg/prometheus/plugin/slo/v2"
+	"go/constant"
+	"go/token"
+	"reflect"
+)
+
+func init() {
+	Symbols["github.com/slok/sloth/pkg/prometheus/plugin/slo/v2/v2"] = map[string]reflect.Value{
+		// function, constant and variable definitions
+		"PluginConfigExampleName": reflect.ValueOf(constant.MakeFromLiteral("\"PluginConfigExample\"", token.STRING, 0)),
+		"PluginConfigSchemaName":  reflect.ValueOf(constant.MakeFromLiteral("\"PluginConfigSchema\"", token.STRING, 0)),
+		"PluginDescriptionName":   reflect.ValueOf(constant.MakeFromLiteral("\"PluginDescription\"", token.STRING, 0)),
+		"PluginFactoryName":       reflect.ValueOf(constant.MakeFromLiteral("\"NewPlugin\"", token.STRING, 0)),
+		"PluginIDName":            reflect.ValueOf(constant.MakeFromLiteral("\"PluginID\"", token.STRING, 0)),
+		"PluginModesName":         reflect.ValueOf(constant.MakeFromLiteral("\"PluginModes\"", token.STRING, 0)),
+		"PluginVersionName":       reflect.ValueOf(constant.MakeFromLiteral("\"PluginVersion\"", token.STRING, 0)),
+		"Version":                 reflect.ValueOf(constant.MakeFromLiteral("\"prometheus/slo/v2\"", token.STRING, 0)),
+
+		// type definitions
+		"AppUtils":      reflect.ValueOf((*v2.AppUtils)(nil)),
+		"GroupRequest":  reflect.ValueOf((*v2.GroupRequest)(nil)),
+		"GroupResult":   reflect.ValueOf((*v2.GroupResult)(nil)),
+		"Plugin":        reflect.ValueOf((*v2.Plugin)(nil)),
+		"PluginFactory": reflect.ValueOf((*v2.PluginFactory)(nil)),
+		"PluginID":      reflect.ValueOf((*v2.PluginID)(nil)),
+		"PluginVersion": reflect.ValueOf((*v2.PluginVersion)(nil)),
+		"Request":       reflect.ValueOf((*v2.Request)(nil)),
+		"Result":        reflect.ValueOf((*v2.Result)(nil)),
+
+		// interface wrapper definitions
+		"_Plugin": reflect.ValueOf((*_github_com_slok_sloth_pkg_prometheus_plugin_slo_v2_Plugin)(nil)),
+	}
+}
+
+// _github_com_slok_sloth_pkg_prometheus_plugin_slo_v2_Plugin is an interface wrapper for Plugin type
+type _github_com_slok_sloth_pkg_prometheus_plugin_slo_v2_Plugin struct {
+	IValue               interface{}
+	WPostProcessSLOGroup func(ctx context.Context, request *v2.GroupRequest, result *v2.GroupResult) error
+	WPreProcessSLOGroup  func(ctx context.Context, request *v2.GroupRequest, result *v2.GroupResult) error
+	WProcessSLO          func(ctx context.Context, request *v2.Request, result *v2.Result) error
+}
+
+func (W _github_com_slok_sloth_pkg_prometheus_plugin_slo_v2_Plugin) PostProcessSLOGroup(ctx context.Context, request *v2.GroupRequest, result *v2.GroupResult) error {
+	return W.WPostProcessSLOGroup(ctx, request, result)
+}
+func (W _github_com_slok_sloth_pkg_prometheus_plugin_slo_v2_Plugin) PreProcessSLOGroup(ctx context.Context, request *v2.GroupRequest, result *v2.GroupResult) error {
+	return W.WPreProcessSLOGroup(ctx, request, result)
+}
+func (W _github_com_slok_sloth_pkg_prometheus_plugin_slo_v2_Plugin) ProcessSLO(ctx context.Context, request *v2.Request, result *v2.Result) error {
+	return W.WProcessSLO(ctx, request, result)
+}
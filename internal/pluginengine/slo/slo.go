@@ -10,13 +10,18 @@ import (
 	"github.com/traefik/yaegi/stdlib"
 	"github.com/traefik/yaegi/stdlib/unsafe"
 
+	"github.com/slok/sloth/internal/pluginengine"
 	"github.com/slok/sloth/internal/pluginengine/slo/custom"
 	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+	pluginslov2 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v2"
 )
 
+// Plugin is a loaded SLO plugin, only the factory of the plugin version is set.
 type Plugin struct {
 	ID              string
+	Metadata        pluginengine.Metadata
 	PluginV1Factory pluginslov1.PluginFactory
+	PluginV2Factory pluginslov2.PluginFactory
 }
 
 // PluginLoader knows how to load Go SLO plugins using Yaegi.
@@ -26,6 +31,29 @@ type pluginLoader bool
 
 var packageRegexp = regexp.MustCompile(`(?m)^package +([^\s]+) *$`)
 
+// DiscoverRawPlugin knows how to get the plugin ID from source data without interpreting it, this is
+// much cheaper than loading the plugin so it can be used to discover plugins and load them lazily.
+//
+// The plugin version and ID must be declared using string literals (e.g: `PluginID = "my-plugin"`),
+// otherwise the discovery will fail and the plugin will need to be loaded to know its ID.
+func (p pluginLoader) DiscoverRawPlugin(ctx context.Context, src string) (string, error) {
+	consts, err := pluginengine.SourceStringConstants(src)
+	if err != nil {
+		return "", fmt.Errorf("invalid plugin source code: %w", err)
+	}
+
+	if pluginVer := consts["PluginVersion"]; pluginVer != pluginslov1.Version && pluginVer != pluginslov2.Version {
+		return "", fmt.Errorf("unsuported plugin version: %s", pluginVer)
+	}
+
+	pluginID := consts["PluginID"]
+	if pluginID == "" {
+		return "", fmt.Errorf("could not get plugin ID")
+	}
+
+	return pluginID, nil
+}
+
 // LoadRawPlugin knows how to load plugins using Yaegi from source data not files,
 // thats why, this implementation will not support any import library except standard
 // library.
@@ -33,7 +61,8 @@ var packageRegexp = regexp.MustCompile(`(?m)^package +([^\s]+) *$`)
 // The load process will search for:
 // - A function called `NewPlugin` to obtain the plugin factory.
 // - A constant called `PluginID` to obtain the plugin ID.
-// - A constant called `PluginVersion` to obtain the plugin version.
+// - A constant called `PluginVersion` to obtain the plugin version (`prometheus/slo/v1` or `prometheus/slo/v2`).
+// - Optional `PluginDescription`, `PluginConfigSchema`, `PluginConfigExample` and `PluginModes` metadata.
 func (p pluginLoader) LoadRawPlugin(ctx context.Context, src string) (*Plugin, error) {
 	// Load the plugin in a new interpreter.
 	// For each plugin we need to use an independent interpreter to avoid name collisions.
@@ -61,7 +90,7 @@ func (p pluginLoader) LoadRawPlugin(ctx context.Context, src string) (*Plugin, e
 	}
 
 	pluginVer, ok := pluginVerTmp.Interface().(pluginslov1.PluginVersion)
-	if !ok || (pluginVer != pluginslov1.Version) {
+	if !ok || (pluginVer != pluginslov1.Version && pluginVer != pluginslov2.Version) {
 		return nil, fmt.Errorf("unsuported plugin version: %s", pluginVer)
 	}
 
@@ -82,15 +111,35 @@ func (p pluginLoader) LoadRawPlugin(ctx context.Context, src string) (*Plugin, e
 		return nil, fmt.Errorf("could not get plugin: %w", err)
 	}
 
-	plugin, ok := pluginFuncTmp.Interface().(pluginslov1.PluginFactory)
+	plugin := &Plugin{ID: pluginID}
+	switch pluginVer {
+	case pluginslov1.Version:
+		plugin.PluginV1Factory, ok = pluginFuncTmp.Interface().(pluginslov1.PluginFactory)
+	case pluginslov2.Version:
+		plugin.PluginV2Factory, ok = pluginFuncTmp.Interface().(pluginslov2.PluginFactory)
+	}
 	if !ok {
 		return nil, fmt.Errorf("invalid SLO plugin type")
 	}
 
-	return &Plugin{
-		ID:              pluginID,
-		PluginV1Factory: plugin,
-	}, nil
+	// Get optional plugin metadata.
+	metadata, err := pluginengine.SourceMetadata(src, pluginengine.MetadataNames{
+		Description:   pluginslov1.PluginDescriptionName,
+		ConfigSchema:  pluginslov1.PluginConfigSchemaName,
+		ConfigExample: pluginslov1.PluginConfigExampleName,
+		Modes:         pluginslov1.PluginModesName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get plugin metadata: %w", err)
+	}
+	err = metadata.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin metadata: %w", err)
+	}
+
+	plugin.Metadata = metadata
+
+	return plugin, nil
 }
 
 func newYaeginInterpreter(env, unrestricted bool) (*interp.Interpreter, error) {
@@ -8,9 +8,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/pluginengine"
 	pluginengineslo "github.com/slok/sloth/internal/pluginengine/slo"
 	"github.com/slok/sloth/pkg/common/model"
 	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+	pluginslov2 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v2"
 )
 
 func TestPlugin(t *testing.T) {
@@ -41,7 +43,7 @@ import (
 )
 
 const (
-	PluginVersion = "prometheus/slo/v2"
+	PluginVersion = "prometheus/slo/v99"
 	PluginID      = "sloth.dev/noop/v1"
 )
 
@@ -160,6 +162,203 @@ func (test) ProcessSLO(ctx context.Context, request *pluginslov1.Request, result
 				assert.Equal(t, expResp, *gotResp)
 			},
 		},
+
+		"A v2 plugin with a v1 plugin factory, should fail.": {
+			pluginSrc: `package test
+import (
+	"context"
+	"encoding/json"
+
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+const (
+	PluginVersion = "prometheus/slo/v2"
+	PluginID      = "sloth.dev/noop/v1"
+)
+
+func NewPlugin(_ json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+	return noopPlugin{}, nil
+}
+
+type noopPlugin struct{}
+
+func (noopPlugin) ProcessSLO(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) error {
+	return nil
+}
+`,
+			execPlugin: func(t *testing.T, p pluginengineslo.Plugin) {},
+			expErr:     true,
+		},
+
+		"A correct v2 plugin should execute the plugin SLO group and SLO phases.": {
+			pluginSrc: `package testv2
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/slok/sloth/pkg/common/model"
+	pluginslov2 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v2"
+)
+
+const (
+	PluginVersion = "prometheus/slo/v2"
+	PluginID      = "sloth.dev/test/v2"
+)
+
+func NewPlugin(_ json.RawMessage, _ pluginslov2.AppUtils) (pluginslov2.Plugin, error) {
+	return &test{}, nil
+}
+
+type test struct {
+	slos int
+}
+
+func (t *test) PreProcessSLOGroup(ctx context.Context, request *pluginslov2.GroupRequest, result *pluginslov2.GroupResult) error {
+	t.slos = len(request.SLOs)
+	return nil
+}
+
+func (t *test) ProcessSLO(ctx context.Context, request *pluginslov2.Request, result *pluginslov2.Result) error {
+	result.SLORules.MetadataRecRules.Rules = []rulefmt.Rule{{Expr: "test1"}}
+	return nil
+}
+
+func (t *test) PostProcessSLOGroup(ctx context.Context, request *pluginslov2.GroupRequest, result *pluginslov2.GroupResult) error {
+	result.ExtraRules = append(result.ExtraRules, model.PromRuleGroup{
+		Name:  "test-group",
+		Rules: []rulefmt.Rule{{Record: "test:slos", Expr: "vector(1)", Labels: map[string]string{"slos": string(rune('0' + t.slos))}}},
+	})
+	return nil
+}
+`,
+			execPlugin: func(t *testing.T, p pluginengineslo.Plugin) {
+				require.Nil(t, p.PluginV1Factory)
+				plugin, err := p.PluginV2Factory(nil, pluginslov2.AppUtils{Logger: log.Noop})
+				require.NoError(t, err)
+
+				gotGroupResp := &pluginslov2.GroupResult{}
+				err = plugin.PreProcessSLOGroup(t.Context(), &pluginslov2.GroupRequest{SLOs: []model.PromSLO{{ID: "a"}, {ID: "b"}}}, gotGroupResp)
+				require.NoError(t, err)
+
+				gotResp := &pluginslov2.Result{}
+				err = plugin.ProcessSLO(t.Context(), &pluginslov2.Request{}, gotResp)
+				require.NoError(t, err)
+				expResp := pluginslov2.Result{SLORules: model.PromSLORules{MetadataRecRules: model.PromRuleGroup{Rules: []rulefmt.Rule{{Expr: "test1"}}}}}
+				assert.Equal(t, expResp, *gotResp)
+
+				err = plugin.PostProcessSLOGroup(t.Context(), &pluginslov2.GroupRequest{}, gotGroupResp)
+				require.NoError(t, err)
+				expGroupResp := pluginslov2.GroupResult{ExtraRules: []model.PromRuleGroup{{
+					Name:  "test-group",
+					Rules: []rulefmt.Rule{{Record: "test:slos", Expr: "vector(1)", Labels: map[string]string{"slos": "2"}}},
+				}}}
+				assert.Equal(t, expGroupResp, *gotGroupResp)
+			},
+		},
+
+		"A plugin with metadata should load the metadata.": {
+			pluginSrc: `package test
+
+import (
+	"context"
+	"encoding/json"
+
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+const (
+	PluginVersion       = "prometheus/slo/v1"
+	PluginID            = "sloth.dev/test/v1"
+	PluginDescription   = "Test plugin."
+	PluginConfigSchema  = ` + "`" + `{"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}` + "`" + `
+	PluginConfigExample = ` + "`" + `{"name": "test"}` + "`" + `
+)
+
+var PluginModes = []string{"cli-gen-prom", "cli-gen-k8s"}
+
+func NewPlugin(_ json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+	return test{}, nil
+}
+
+type test struct{}
+
+func (test) ProcessSLO(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) error {
+	return nil
+}
+`,
+			execPlugin: func(t *testing.T, p pluginengineslo.Plugin) {
+				assert.Equal(t, pluginengine.Metadata{
+					Description:   "Test plugin.",
+					ConfigSchema:  `{"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}`,
+					ConfigExample: `{"name": "test"}`,
+					Modes:         []model.Mode{"cli-gen-prom", "cli-gen-k8s"},
+				}, p.Metadata)
+			},
+		},
+
+		"A plugin with an invalid config schema should fail.": {
+			pluginSrc: `package test
+
+import (
+	"context"
+	"encoding/json"
+
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+const (
+	PluginVersion      = "prometheus/slo/v1"
+	PluginID           = "sloth.dev/test/v1"
+	PluginConfigSchema = ` + "`" + `{"type": "unknown"}` + "`" + `
+)
+
+func NewPlugin(_ json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+	return test{}, nil
+}
+
+type test struct{}
+
+func (test) ProcessSLO(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) error {
+	return nil
+}
+`,
+			execPlugin: func(t *testing.T, p pluginengineslo.Plugin) {},
+			expErr:     true,
+		},
+
+		"A plugin with a config example that doesn't match the config schema should fail.": {
+			pluginSrc: `package test
+
+import (
+	"context"
+	"encoding/json"
+
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+const (
+	PluginVersion       = "prometheus/slo/v1"
+	PluginID            = "sloth.dev/test/v1"
+	PluginConfigSchema  = ` + "`" + `{"type": "object", "required": ["name"]}` + "`" + `
+	PluginConfigExample = ` + "`" + `{"other": "test"}` + "`" + `
+)
+
+func NewPlugin(_ json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+	return test{}, nil
+}
+
+type test struct{}
+
+func (test) ProcessSLO(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) error {
+	return nil
+}
+`,
+			execPlugin: func(t *testing.T, p pluginengineslo.Plugin) {},
+			expErr:     true,
+		},
 	}
 
 	for name, test := range tests {
@@ -174,3 +373,81 @@ func (test) ProcessSLO(ctx context.Context, request *pluginslov1.Request, result
 		})
 	}
 }
+
+func TestPluginDiscovery(t *testing.T) {
+	tests := map[string]struct {
+		pluginSrc   string
+		expPluginID string
+		expErr      bool
+	}{
+		"An invalid plugin syntax should fail": {
+			pluginSrc: `package test{`,
+			expErr:    true,
+		},
+
+		"A plugin without the required version, should fail.": {
+			pluginSrc: `package test
+const PluginID = "test-plugin"
+`,
+			expErr: true,
+		},
+
+		"A plugin with a different plugin type version, should fail.": {
+			pluginSrc: `package test
+const (
+	PluginVersion = "prometheus/k8stransform/v1"
+	PluginID      = "test-plugin"
+)
+`,
+			expErr: true,
+		},
+
+		"A plugin without a literal ID, should fail.": {
+			pluginSrc: `package test
+const (
+	PluginVersion = "prometheus/slo/v1"
+	PluginID      = prefix + "test-plugin"
+)
+`,
+			expErr: true,
+		},
+
+		"A correct plugin should return the plugin ID without loading the plugin.": {
+			pluginSrc: `package test
+
+import "github.com/slok/sloth/unknown/but/not/loaded"
+
+const (
+	PluginVersion = "prometheus/slo/v1"
+	PluginID      = "test-plugin"
+)
+
+func NewPlugin() {}
+`,
+			expPluginID: "test-plugin",
+		},
+
+		"A correct v2 plugin should return the plugin ID without loading the plugin.": {
+			pluginSrc: `package test
+
+const (
+	PluginVersion = "prometheus/slo/v2"
+	PluginID      = "test-plugin-v2"
+)
+`,
+			expPluginID: "test-plugin-v2",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			gotID, err := pluginengineslo.PluginLoader.DiscoverRawPlugin(t.Context(), test.pluginSrc)
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expPluginID, gotID)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package sloexec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/pluginengine"
+	pluginengineslo "github.com/slok/sloth/internal/pluginengine/slo"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+	pluginslov1exec "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1/exec"
+)
+
+const (
+	defaultTimeout     = 30 * time.Second
+	defaultMaxOutputMB = 32
+)
+
+// PluginLoader knows how to load SLO plugins that are executed as an external process.
+const PluginLoader = pluginLoader(false)
+
+type pluginLoader bool
+
+// LoadRawPlugin knows how to load exec plugins from the manifest data, the manifest dir is the OS
+// directory where the manifest is, used to resolve the relative command paths.
+//
+// The plugin will send the SLO plugin request, result and config as JSON to the command standard input and
+// will read the new result from the command standard output.
+func (p pluginLoader) LoadRawPlugin(ctx context.Context, manifestDir string, manifestData []byte) (*pluginengineslo.Plugin, error) {
+	manifest := pluginslov1exec.Manifest{}
+	err := json.Unmarshal(manifestData, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exec plugin manifest: %w", err)
+	}
+
+	if manifest.Engine != pluginslov1exec.ManifestEngine {
+		return nil, fmt.Errorf("unsupported plugin engine: %q", manifest.Engine)
+	}
+
+	if manifest.Version != pluginslov1.Version {
+		return nil, fmt.Errorf("unsuported plugin version: %s", manifest.Version)
+	}
+
+	if manifest.ID == "" {
+		return nil, fmt.Errorf("plugin ID is required")
+	}
+
+	if len(manifest.Command) == 0 || manifest.Command[0] == "" {
+		return nil, fmt.Errorf("plugin command is required")
+	}
+
+	timeout := defaultTimeout
+	if manifest.Timeout != "" {
+		timeout, err = time.ParseDuration(manifest.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid plugin timeout: %w", err)
+		}
+		if timeout <= 0 {
+			return nil, fmt.Errorf("plugin timeout must be greater than 0")
+		}
+	}
+
+	maxOutputMB := defaultMaxOutputMB
+	if manifest.MaxOutputMB != 0 {
+		if manifest.MaxOutputMB < 0 {
+			return nil, fmt.Errorf("plugin max output must be greater than 0")
+		}
+		maxOutputMB = manifest.MaxOutputMB
+	}
+
+	// Resolve relative paths from the manifest dir, commands without path will be searched on the PATH.
+	command := manifest.Command[0]
+	if !filepath.IsAbs(command) && strings.ContainsRune(command, filepath.Separator) {
+		command = filepath.Join(manifestDir, command)
+	}
+
+	metadata, err := pluginengine.ManifestMetadata(manifest.ManifestMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pluginengineslo.Plugin{
+		ID:       manifest.ID,
+		Metadata: metadata,
+		PluginV1Factory: func(config json.RawMessage, appUtils pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+			logger := appUtils.Logger
+			if logger == nil {
+				logger = log.Noop
+			}
+
+			return plugin{
+				command:        command,
+				args:           manifest.Command[1:],
+				dir:            manifestDir,
+				timeout:        timeout,
+				maxOutputBytes: int64(maxOutputMB) * 1024 * 1024,
+				config:         config,
+				logger:         logger,
+			}, nil
+		},
+	}, nil
+}
+
+type plugin struct {
+	command        string
+	args           []string
+	dir            string
+	timeout        time.Duration
+	maxOutputBytes int64
+	config         json.RawMessage
+	logger         log.Logger
+}
+
+func (p plugin) ProcessSLO(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) error {
+	input, err := json.Marshal(pluginslov1exec.Input{
+		Config:  p.config,
+		Request: *request,
+		Result:  *result,
+	})
+	if err != nil {
+		return fmt.Errorf("could not marshal exec plugin input: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	stderr := pluginengine.NewLogLineWriter(p.logger)
+	defer stderr.Flush()
+
+	// Limit the output and kill the plugin when exceeded.
+	var stdoutBuffer bytes.Buffer
+	stdout := pluginengine.NewLimitedWriter(&stdoutBuffer, p.maxOutputBytes, cancel)
+
+	cmd := exec.CommandContext(ctx, p.command, p.args...)
+	cmd.Dir = p.dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = time.Second // Don't wait forever for child processes that keep the pipes open.
+
+	err = cmd.Run()
+	if stdout.Exceeded() {
+		return fmt.Errorf("exec plugin output exceeded the %d bytes limit: %w", p.maxOutputBytes, pluginengine.ErrWriteLimitExceeded)
+	}
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("exec plugin timed out after %s", p.timeout)
+		}
+		return fmt.Errorf("exec plugin failed: %w", err)
+	}
+
+	newResult := pluginslov1.Result{}
+	err = json.Unmarshal(stdoutBuffer.Bytes(), &newResult)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal exec plugin result: %w", err)
+	}
+	*result = newResult
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package sloexec_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/pluginengine"
+	"github.com/slok/sloth/internal/pluginengine/sloexec"
+	"github.com/slok/sloth/pkg/common/model"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+type testLogger struct {
+	log.Logger
+	lines []string
+}
+
+func (t *testLogger) Infof(format string, args ...interface{}) {
+	t.lines = append(t.lines, fmt.Sprintf(format, args...))
+}
+
+func TestPlugin(t *testing.T) {
+	tests := map[string]struct {
+		manifest   string
+		files      map[string]string
+		config     json.RawMessage
+		request    pluginslov1.Request
+		result     pluginslov1.Result
+		expLoadErr bool
+		expErr     bool
+		expErrIs   error
+		expResult  pluginslov1.Result
+		expLogs    []string
+	}{
+		"An invalid manifest should fail.": {
+			manifest:   `{`,
+			expLoadErr: true,
+		},
+
+		"A manifest with an invalid engine should fail.": {
+			manifest:   `{"engine": "yaegi", "version": "prometheus/slo/v1", "id": "test", "command": ["cat"]}`,
+			expLoadErr: true,
+		},
+
+		"A manifest with an invalid version should fail.": {
+			manifest:   `{"engine": "exec", "version": "prometheus/slo/v2", "id": "test", "command": ["cat"]}`,
+			expLoadErr: true,
+		},
+
+		"A manifest without ID should fail.": {
+			manifest:   `{"engine": "exec", "version": "prometheus/slo/v1", "command": ["cat"]}`,
+			expLoadErr: true,
+		},
+
+		"A manifest without command should fail.": {
+			manifest:   `{"engine": "exec", "version": "prometheus/slo/v1", "id": "test"}`,
+			expLoadErr: true,
+		},
+
+		"A manifest with an invalid timeout should fail.": {
+			manifest:   `{"engine": "exec", "version": "prometheus/slo/v1", "id": "test", "command": ["cat"], "timeout": "1x"}`,
+			expLoadErr: true,
+		},
+
+		"A manifest with an invalid max output should fail.": {
+			manifest:   `{"engine": "exec", "version": "prometheus/slo/v1", "id": "test", "command": ["cat"], "max_output_mb": -1}`,
+			expLoadErr: true,
+		},
+
+		"A manifest with an invalid config schema should fail.": {
+			manifest:   `{"engine": "exec", "version": "prometheus/slo/v1", "id": "test", "command": ["cat"], "config_schema": {"type": "unknown"}}`,
+			expLoadErr: true,
+		},
+
+		"A plugin that fails should fail.": {
+			manifest: `{"engine": "exec", "version": "prometheus/slo/v1", "id": "test", "command": ["sh", "-c", "echo something failed >&2; exit 1"]}`,
+			expErr:   true,
+			expLogs:  []string{"something failed"},
+		},
+
+		"A plugin that returns an invalid result should fail.": {
+			manifest: `{"engine": "exec", "version": "prometheus/slo/v1", "id": "test", "command": ["sh", "-c", "echo '{'"]}`,
+			expErr:   true,
+		},
+
+		"A plugin that takes more than the timeout should fail.": {
+			manifest: `{"engine": "exec", "version": "prometheus/slo/v1", "id": "test", "command": ["sleep", "10"], "timeout": "100ms"}`,
+			expErr:   true,
+		},
+
+		"A plugin that floods the output should fail.": {
+			manifest: `{"engine": "exec", "version": "prometheus/slo/v1", "id": "test", "command": ["yes"], "max_output_mb": 1}`,
+			expErr:   true,
+			expErrIs: pluginengine.ErrWriteLimitExceeded,
+		},
+
+		"A plugin should receive the config, request and result and return the new result.": {
+			manifest: `{"engine": "exec", "version": "prometheus/slo/v1", "id": "test", "command": ["./bin/plugin.sh", "arg1"]}`,
+			files: map[string]string{
+				// Returns the received input wrapped in a rule expression so we can check what received.
+				"bin/plugin.sh": `#!/bin/sh
+echo "running with $1" >&2
+input=$(cat)
+printf '%s' "$input" > input.json
+echo '{"SLORules": {"AlertRules": {"Name": "test-alerts", "Rules": [{"Alert": "test", "Expr": "up == 0", "For": "5m"}]}}}'
+echo "done" >&2
+`,
+			},
+			config:  json.RawMessage(`{"k1":"v1"}`),
+			request: pluginslov1.Request{SLO: model.PromSLO{ID: "test-slo"}},
+			result: pluginslov1.Result{SLORules: model.PromSLORules{
+				SLIErrorRecRules: model.PromRuleGroup{Name: "test-sli"},
+			}},
+			expResult: pluginslov1.Result{SLORules: model.PromSLORules{
+				AlertRules: model.PromRuleGroup{
+					Name:  "test-alerts",
+					Rules: []rulefmt.Rule{{Alert: "test", Expr: "up == 0", For: 300000000000}},
+				},
+			}},
+			expLogs: []string{"running with arg1", "done"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			dir := t.TempDir()
+			for path, data := range test.files {
+				path = filepath.Join(dir, path)
+				require.NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(os.WriteFile(path, []byte(data), 0o755))
+			}
+
+			p, err := sloexec.PluginLoader.LoadRawPlugin(t.Context(), dir, []byte(test.manifest))
+			if test.expLoadErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal("test", p.ID)
+
+			logger := &testLogger{Logger: log.Noop}
+			plugin, err := p.PluginV1Factory(test.config, pluginslov1.AppUtils{Logger: logger})
+			require.NoError(err)
+
+			result := test.result
+			err = plugin.ProcessSLO(t.Context(), &test.request, &result)
+			assert.Equal(test.expLogs, logger.lines)
+			if test.expErr {
+				assert.Error(err)
+				if test.expErrIs != nil {
+					assert.ErrorIs(err, test.expErrIs)
+				}
+				return
+			}
+			require.NoError(err)
+			assert.Equal(test.expResult, result)
+
+			// Check the plugin received the correct input.
+			gotInput, err := os.ReadFile(filepath.Join(dir, "input.json"))
+			require.NoError(err)
+			gotInputMap := map[string]any{}
+			require.NoError(json.Unmarshal(gotInput, &gotInputMap))
+			assert.Equal(map[string]any{"k1": "v1"}, gotInputMap["config"])
+			assert.Equal("test-slo", gotInputMap["request"].(map[string]any)["SLO"].(map[string]any)["ID"])
+			assert.Equal("test-sli", gotInputMap["result"].(map[string]any)["SLORules"].(map[string]any)["SLIErrorRecRules"].(map[string]any)["Name"])
+		})
+	}
+}
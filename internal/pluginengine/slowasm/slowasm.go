@@ -0,0 +1,283 @@
+package slowasm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/pluginengine"
+	pluginengineslo "github.com/slok/sloth/internal/pluginengine/slo"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+	pluginslov1exec "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1/exec"
+	pluginslov1wasm "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1/wasm"
+)
+
+const (
+	defaultTimeout       = 30 * time.Second
+	defaultMemoryLimitMB = 128
+	wasmPageSize         = 64 * 1024
+	maxMemoryLimitMB     = 4 * 1024 // WASM 32 bit memory limit.
+	maxStderrBytes       = 1024 * 1024
+)
+
+// PluginLoader knows how to load SLO plugins that are executed sandboxed as WASM modules.
+const PluginLoader = pluginLoader(false)
+
+type pluginLoader bool
+
+// LoadRawPlugin knows how to load WASM plugins from the manifest data, the manifest dir is the OS
+// directory where the manifest is, used to resolve the relative module and capability paths.
+//
+// The module is compiled the first time the plugin is used and every plugin execution runs on a new
+// module instance, isolated from the other executions.
+func (p pluginLoader) LoadRawPlugin(ctx context.Context, manifestDir string, manifestData []byte) (*pluginengineslo.Plugin, error) {
+	manifest := pluginslov1wasm.Manifest{}
+	err := json.Unmarshal(manifestData, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wasm plugin manifest: %w", err)
+	}
+
+	if manifest.Engine != pluginslov1wasm.ManifestEngine {
+		return nil, fmt.Errorf("unsupported plugin engine: %q", manifest.Engine)
+	}
+
+	if manifest.Version != pluginslov1.Version {
+		return nil, fmt.Errorf("unsuported plugin version: %s", manifest.Version)
+	}
+
+	if manifest.ID == "" {
+		return nil, fmt.Errorf("plugin ID is required")
+	}
+
+	if manifest.Module == "" {
+		return nil, fmt.Errorf("plugin module is required")
+	}
+
+	timeout := defaultTimeout
+	if manifest.Timeout != "" {
+		timeout, err = time.ParseDuration(manifest.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid plugin timeout: %w", err)
+		}
+		if timeout <= 0 {
+			return nil, fmt.Errorf("plugin timeout must be greater than 0")
+		}
+	}
+
+	memoryLimitMB := defaultMemoryLimitMB
+	if manifest.MemoryLimitMB != 0 {
+		if manifest.MemoryLimitMB < 0 || manifest.MemoryLimitMB > maxMemoryLimitMB {
+			return nil, fmt.Errorf("plugin memory limit must be between 1 and %d MiB", maxMemoryLimitMB)
+		}
+		memoryLimitMB = manifest.MemoryLimitMB
+	}
+
+	wasmModule, err := os.ReadFile(resolvePath(manifestDir, manifest.Module))
+	if err != nil {
+		return nil, fmt.Errorf("could not read plugin module: %w", err)
+	}
+
+	dirs := map[string]string{}
+	for _, d := range manifest.Capabilities.Dirs {
+		if d.Path == "" || d.GuestPath == "" {
+			return nil, fmt.Errorf("plugin dir capabilities require path and guest path")
+		}
+		path := resolvePath(manifestDir, d.Path)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid plugin dir capability: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("invalid plugin dir capability: %q is not a directory", d.Path)
+		}
+		dirs[path] = d.GuestPath
+	}
+
+	mod := &module{
+		id:               manifest.ID,
+		memoryLimitBytes: int64(memoryLimitMB) * 1024 * 1024,
+		wasm:             wasmModule,
+		memoryLimitPages: uint32(memoryLimitMB * 1024 * 1024 / wasmPageSize),
+	}
+
+	metadata, err := pluginengine.ManifestMetadata(manifest.ManifestMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pluginengineslo.Plugin{
+		ID:       manifest.ID,
+		Metadata: metadata,
+		PluginV1Factory: func(config json.RawMessage, appUtils pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+			logger := appUtils.Logger
+			if logger == nil {
+				logger = log.Noop
+			}
+
+			return plugin{
+				module:  mod,
+				timeout: timeout,
+				env:     manifest.Capabilities.Env,
+				dirs:    dirs,
+				config:  config,
+				logger:  logger,
+			}, nil
+		},
+	}, nil
+}
+
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// module is the lazily compiled WASM module shared by all the plugin instances.
+type module struct {
+	id               string
+	wasm             []byte
+	memoryLimitPages uint32
+	// memoryLimitBytes is also used as the output limit, the plugins can't exhaust Sloth memory with their
+	// output when they are limited.
+	memoryLimitBytes int64
+
+	once     sync.Once
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+	err      error
+}
+
+func (m *module) compile(ctx context.Context) (wazero.Runtime, wazero.CompiledModule, error) {
+	m.once.Do(func() {
+		// Don't let the context of the first execution cancel the compilation for the rest.
+		ctx := context.WithoutCancel(ctx)
+
+		rtConfig := wazero.NewRuntimeConfig().
+			WithMemoryLimitPages(m.memoryLimitPages).
+			WithCloseOnContextDone(true)
+		rt := wazero.NewRuntimeWithConfig(ctx, rtConfig)
+
+		_, err := wasi_snapshot_preview1.Instantiate(ctx, rt)
+		if err != nil {
+			_ = rt.Close(ctx)
+			m.err = fmt.Errorf("could not instantiate WASI: %w", err)
+			return
+		}
+
+		compiled, err := rt.CompileModule(ctx, m.wasm)
+		if err != nil {
+			_ = rt.Close(ctx)
+			m.err = fmt.Errorf("could not compile %q plugin module: %w", m.id, err)
+			return
+		}
+
+		m.runtime = rt
+		m.compiled = compiled
+
+		// Release the compiled code when the plugin is not used anymore (e.g: after a plugin reload).
+		runtime.AddCleanup(m, func(rt wazero.Runtime) { _ = rt.Close(context.Background()) }, rt)
+	})
+
+	return m.runtime, m.compiled, m.err
+}
+
+type plugin struct {
+	module  *module
+	timeout time.Duration
+	env     []string
+	dirs    map[string]string
+	config  json.RawMessage
+	logger  log.Logger
+}
+
+func (p plugin) ProcessSLO(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) error {
+	rt, compiled, err := p.module.compile(ctx)
+	if err != nil {
+		return err
+	}
+
+	input, err := json.Marshal(pluginslov1exec.Input{
+		Config:  p.config,
+		Request: *request,
+		Result:  *result,
+	})
+	if err != nil {
+		return fmt.Errorf("could not marshal wasm plugin input: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	// Limit the output and stop the plugin when exceeded, the module is closed when the context is done.
+	stderrLogger := pluginengine.NewLogLineWriter(p.logger)
+	defer stderrLogger.Flush()
+	stderr := pluginengine.NewLimitedWriter(stderrLogger, maxStderrBytes, cancel)
+
+	var stdoutBuffer bytes.Buffer
+	stdout := pluginengine.NewLimitedWriter(&stdoutBuffer, p.module.memoryLimitBytes, cancel)
+
+	modConfig := wazero.NewModuleConfig().
+		WithName(""). // Anonymous modules can be instantiated concurrently.
+		WithArgs(p.module.id).
+		WithStdin(bytes.NewReader(input)).
+		WithStdout(stdout).
+		WithStderr(stderr).
+		WithSysWalltime().
+		WithSysNanotime()
+
+	// Only the granted capabilities.
+	for _, name := range p.env {
+		if value, ok := os.LookupEnv(name); ok {
+			modConfig = modConfig.WithEnv(name, value)
+		}
+	}
+	if len(p.dirs) > 0 {
+		fsConfig := wazero.NewFSConfig()
+		for path, guestPath := range p.dirs {
+			fsConfig = fsConfig.WithReadOnlyDirMount(path, guestPath)
+		}
+		modConfig = modConfig.WithFSConfig(fsConfig)
+	}
+
+	mod, err := rt.InstantiateModule(ctx, compiled, modConfig)
+	if mod != nil {
+		defer mod.Close(context.WithoutCancel(ctx))
+	}
+	switch {
+	case stdout.Exceeded():
+		return fmt.Errorf("wasm plugin output exceeded the %d bytes limit", p.module.memoryLimitBytes)
+	case stderr.Exceeded():
+		return fmt.Errorf("wasm plugin stderr exceeded the %d bytes limit", maxStderrBytes)
+	}
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("wasm plugin timed out after %s", p.timeout)
+		}
+		if exitErr, ok := err.(*sys.ExitError); ok {
+			return fmt.Errorf("wasm plugin failed with exit code %d", exitErr.ExitCode())
+		}
+		return fmt.Errorf("wasm plugin failed: %w", err)
+	}
+
+	newResult := pluginslov1.Result{}
+	err = json.Unmarshal(stdoutBuffer.Bytes(), &newResult)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal wasm plugin result: %w", err)
+	}
+	*result = newResult
+
+	return nil
+}
@@ -0,0 +1,401 @@
+package slowasm_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/pluginengine/slowasm"
+	"github.com/slok/sloth/pkg/common/model"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+type testLogger struct {
+	log.Logger
+	lines []string
+}
+
+func (t *testLogger) Infof(format string, args ...interface{}) {
+	t.lines = append(t.lines, fmt.Sprintf(format, args...))
+}
+
+// testModule is a minimal WASI command module that is assembled by hand, so the tests don't depend on
+// WASM toolchains.
+type testModule struct {
+	memPages int
+	// stdout is written to the standard output.
+	stdout string
+	// echoStdinToStderr writes the standard input to the standard error.
+	echoStdinToStderr bool
+	// loop runs forever.
+	loop bool
+	// loopWriteFD writes the stdout data to the file descriptor forever (disabled if 0).
+	loopWriteFD int
+	// exitWithEnvCount exits with the number of environment variables as the exit code.
+	exitWithEnvCount bool
+	// exitWithPreopenErrno exits with the errno of getting the first preopened directory as the exit code.
+	exitWithPreopenErrno bool
+	exitCode             int
+}
+
+// Memory layout: [0, 8) stdout iovec, [8, 16) stdin iovec, [16, 32) scratch, [32, ...) stdout data,
+// [1024, 64KiB) stdin buffer.
+const (
+	stdinBufferPtr  = 1024
+	stdinBufferSize = 60000
+	scratchPtr      = 16
+)
+
+const (
+	fnFDWrite = iota
+	fnProcExit
+	fnEnvironSizesGet
+	fnFDPrestatGet
+	fnFDRead
+	fnStart
+)
+
+func (t testModule) wasm() []byte {
+	data := make([]byte, 32+len(t.stdout))
+	binary.LittleEndian.PutUint32(data[0:], 32)
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(t.stdout)))
+	binary.LittleEndian.PutUint32(data[8:], stdinBufferPtr)
+	binary.LittleEndian.PutUint32(data[12:], stdinBufferSize)
+	copy(data[32:], t.stdout)
+
+	const (
+		opLoop     = 0x03
+		opBr       = 0x0c
+		opEnd      = 0x0b
+		opCall     = 0x10
+		opDrop     = 0x1a
+		opI32Load  = 0x28
+		opI32Store = 0x36
+	)
+	i32 := func(v int) []byte { return append([]byte{0x41}, sleb(v)...) }
+	call := func(fn int) []byte { return []byte{opCall, byte(fn)} }
+
+	var code []byte
+	add := func(bs ...[]byte) {
+		for _, b := range bs {
+			code = append(code, b...)
+		}
+	}
+
+	if t.echoStdinToStderr {
+		// fd_read(0, stdin iovec, 1, scratch), then point the stdin iovec length to the read bytes and fd_write(2, ...).
+		add(i32(0), i32(8), i32(1), i32(scratchPtr), call(fnFDRead), []byte{opDrop})
+		add(i32(12), i32(scratchPtr), []byte{opI32Load, 0x02, 0x00}, []byte{opI32Store, 0x02, 0x00})
+		add(i32(2), i32(8), i32(1), i32(scratchPtr), call(fnFDWrite), []byte{opDrop})
+	}
+	if t.stdout != "" {
+		add(i32(1), i32(0), i32(1), i32(scratchPtr), call(fnFDWrite), []byte{opDrop})
+	}
+	if t.loopWriteFD != 0 {
+		add([]byte{opLoop, 0x40})
+		add(i32(t.loopWriteFD), i32(0), i32(1), i32(scratchPtr), call(fnFDWrite), []byte{opDrop})
+		add([]byte{opBr, 0x00, opEnd})
+	}
+	if t.loop {
+		add([]byte{opLoop, 0x40, opBr, 0x00, opEnd})
+	}
+	if t.exitWithEnvCount {
+		add(i32(scratchPtr), i32(scratchPtr+4), call(fnEnvironSizesGet), []byte{opDrop})
+		add(i32(scratchPtr), []byte{opI32Load, 0x02, 0x00}, call(fnProcExit))
+	}
+	if t.exitWithPreopenErrno {
+		add(i32(3), i32(scratchPtr), call(fnFDPrestatGet), call(fnProcExit))
+	}
+	if t.exitCode != 0 {
+		add(i32(t.exitCode), call(fnProcExit))
+	}
+
+	const i32t = 0x7f
+	funcType := func(params, results int) []byte {
+		b := []byte{0x60, byte(params)}
+		for range params {
+			b = append(b, i32t)
+		}
+		b = append(b, byte(results))
+		for range results {
+			b = append(b, i32t)
+		}
+		return b
+	}
+	wasiImport := func(name string, typeIdx byte) []byte {
+		return concat(name2bytes("wasi_snapshot_preview1"), name2bytes(name), []byte{0x00, typeIdx})
+	}
+
+	memPages := t.memPages
+	if memPages == 0 {
+		memPages = 1
+	}
+
+	body := concat([]byte{0x00}, code, []byte{opEnd})
+	return concat(
+		[]byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00},
+		section(1, vec(funcType(4, 1), funcType(1, 0), funcType(0, 0), funcType(2, 1))),
+		section(2, vec(
+			wasiImport("fd_write", 0),
+			wasiImport("proc_exit", 1),
+			wasiImport("environ_sizes_get", 3),
+			wasiImport("fd_prestat_get", 3),
+			wasiImport("fd_read", 0),
+		)),
+		section(3, vec([]byte{2})),
+		section(5, vec(concat([]byte{0x00}, uleb(memPages)))),
+		section(7, vec(
+			concat(name2bytes("memory"), []byte{0x02, 0x00}),
+			concat(name2bytes("_start"), []byte{0x00, fnStart}),
+		)),
+		section(10, vec(concat(uleb(len(body)), body))),
+		section(11, vec(concat([]byte{0x00}, i32(0), []byte{opEnd}, uleb(len(data)), data))),
+	)
+}
+
+func concat(bs ...[]byte) []byte {
+	r := []byte{}
+	for _, b := range bs {
+		r = append(r, b...)
+	}
+	return r
+}
+
+func section(id byte, content []byte) []byte {
+	return concat([]byte{id}, uleb(len(content)), content)
+}
+
+func vec(items ...[]byte) []byte {
+	return concat(uleb(len(items)), concat(items...))
+}
+
+func name2bytes(s string) []byte {
+	return concat(uleb(len(s)), []byte(s))
+}
+
+func uleb(v int) []byte {
+	b := []byte{}
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func sleb(v int) []byte {
+	b := []byte{}
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	const okResult = `{"SLORules": {"AlertRules": {"Name": "test-alerts"}}}`
+
+	tests := map[string]struct {
+		manifest       string
+		module         testModule
+		rawWasm        []byte
+		dirs           []string
+		env            map[string]string
+		config         json.RawMessage
+		request        pluginslov1.Request
+		expLoadErr     bool
+		expErr         bool
+		expResult      pluginslov1.Result
+		expLogContains []string
+	}{
+		"An invalid manifest should fail.": {
+			manifest:   `{`,
+			expLoadErr: true,
+		},
+
+		"A manifest with an invalid engine should fail.": {
+			manifest:   `{"engine": "exec", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm"}`,
+			expLoadErr: true,
+		},
+
+		"A manifest with an invalid version should fail.": {
+			manifest:   `{"engine": "wasm", "version": "prometheus/slo/v2", "id": "test", "module": "plugin.wasm"}`,
+			expLoadErr: true,
+		},
+
+		"A manifest without ID should fail.": {
+			manifest:   `{"engine": "wasm", "version": "prometheus/slo/v1", "module": "plugin.wasm"}`,
+			expLoadErr: true,
+		},
+
+		"A manifest without module should fail.": {
+			manifest:   `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test"}`,
+			expLoadErr: true,
+		},
+
+		"A manifest with a missing module should fail.": {
+			manifest:   `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "missing.wasm"}`,
+			expLoadErr: true,
+		},
+
+		"A manifest with an invalid timeout should fail.": {
+			manifest:   `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm", "timeout": "1x"}`,
+			expLoadErr: true,
+		},
+
+		"A manifest with an invalid memory limit should fail.": {
+			manifest:   `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm", "memory_limit_mb": -1}`,
+			expLoadErr: true,
+		},
+
+		"A manifest with a missing dir capability should fail.": {
+			manifest:   `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm", "capabilities": {"dirs": [{"path": "./missing", "guest_path": "/data"}]}}`,
+			expLoadErr: true,
+		},
+
+		"An invalid module should fail.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm"}`,
+			rawWasm:  []byte("not a wasm module"),
+			expErr:   true,
+		},
+
+		"A module that fails should fail.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm"}`,
+			module:   testModule{exitCode: 3},
+			expErr:   true,
+		},
+
+		"A module that returns an invalid result should fail.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm"}`,
+			module:   testModule{stdout: "{"},
+			expErr:   true,
+		},
+
+		"A module that takes more than the timeout should fail.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm", "timeout": "100ms"}`,
+			module:   testModule{loop: true},
+			expErr:   true,
+		},
+
+		"A module that requires more memory than the limit should fail.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm", "memory_limit_mb": 1}`,
+			module:   testModule{stdout: okResult, memPages: 17},
+			expErr:   true,
+		},
+
+		"A module that writes more output than the memory limit should fail.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm", "memory_limit_mb": 1}`,
+			module:   testModule{stdout: strings.Repeat("x", 1000), loopWriteFD: 1},
+			expErr:   true,
+		},
+
+		"A module that writes more logs than the limit should fail.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm"}`,
+			module:   testModule{stdout: strings.Repeat("x", 999) + "\n", loopWriteFD: 2},
+			expErr:   true,
+		},
+
+		"A module should not have access to the environment by default.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm"}`,
+			module:   testModule{stdout: okResult, exitWithEnvCount: true},
+			env:      map[string]string{"SLOTH_WASM_TEST": "secret"},
+			expResult: pluginslov1.Result{SLORules: model.PromSLORules{
+				AlertRules: model.PromRuleGroup{Name: "test-alerts"},
+			}},
+		},
+
+		"A module should have access to the granted environment variables.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm", "capabilities": {"env": ["SLOTH_WASM_TEST"]}}`,
+			module:   testModule{stdout: okResult, exitWithEnvCount: true},
+			env:      map[string]string{"SLOTH_WASM_TEST": "secret"},
+			expErr:   true, // Exits with 1 env var.
+		},
+
+		"A module should not have access to the file system by default.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm"}`,
+			module:   testModule{stdout: okResult, exitWithPreopenErrno: true},
+			expErr:   true, // Exits with EBADF.
+		},
+
+		"A module should have access to the granted directories.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm", "capabilities": {"dirs": [{"path": "./data", "guest_path": "/data"}]}}`,
+			module:   testModule{stdout: okResult, exitWithPreopenErrno: true},
+			dirs:     []string{"data"},
+			expResult: pluginslov1.Result{SLORules: model.PromSLORules{
+				AlertRules: model.PromRuleGroup{Name: "test-alerts"},
+			}},
+		},
+
+		"A module should receive the input and return the new result.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "test", "module": "plugin.wasm"}`,
+			module:   testModule{stdout: okResult, echoStdinToStderr: true},
+			config:   json.RawMessage(`{"k1":"v1"}`),
+			request:  pluginslov1.Request{SLO: model.PromSLO{ID: "test-slo"}},
+			expResult: pluginslov1.Result{SLORules: model.PromSLORules{
+				AlertRules: model.PromRuleGroup{Name: "test-alerts"},
+			}},
+			expLogContains: []string{`"config":{"k1":"v1"}`, `"SLO":{"ID":"test-slo"`, `"result":{"SLORules":`},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+
+			dir := t.TempDir()
+			for _, d := range test.dirs {
+				require.NoError(os.MkdirAll(filepath.Join(dir, d), 0o755))
+			}
+			wasm := test.rawWasm
+			if wasm == nil {
+				wasm = test.module.wasm()
+			}
+			require.NoError(os.WriteFile(filepath.Join(dir, "plugin.wasm"), wasm, 0o644))
+
+			p, err := slowasm.PluginLoader.LoadRawPlugin(t.Context(), dir, []byte(test.manifest))
+			if test.expLoadErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal("test", p.ID)
+
+			logger := &testLogger{Logger: log.Noop}
+			plugin, err := p.PluginV1Factory(test.config, pluginslov1.AppUtils{Logger: logger})
+			require.NoError(err)
+
+			result := pluginslov1.Result{}
+			err = plugin.ProcessSLO(t.Context(), &test.request, &result)
+			if test.expErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(test.expResult, result)
+			if len(test.expLogContains) > 0 {
+				require.Len(logger.lines, 1)
+				for _, exp := range test.expLogContains {
+					assert.Contains(logger.lines[0], exp)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package slotemplate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/slok/sloth/internal/log"
+	utilsdata "github.com/slok/sloth/pkg/common/utils/data"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	"github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/scheme"
+)
+
+// FSTemplateRepoConfig is the configuration of FSTemplateRepo.
+type FSTemplateRepoConfig struct {
+	FS     fs.FS
+	Logger log.Logger
+}
+
+func (c *FSTemplateRepoConfig) defaults() error {
+	if c.FS == nil {
+		return fmt.Errorf("file system is required")
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"svc": "slotemplate.FSTemplateRepo"})
+
+	return nil
+}
+
+// FSTemplateRepo is an SLO template repository that loads the PrometheusServiceLevelTemplate YAML
+// manifests of a file system.
+//
+// The templates without namespace are used as templates of any namespace, this way the same template
+// files can be used with specs of different namespaces.
+type FSTemplateRepo struct {
+	// templates are the loaded templates by namespace and name.
+	templates map[string]map[string]slothv1.PrometheusServiceLevelTemplate
+	decoder   runtime.Decoder
+}
+
+// NewFSTemplateRepo returns a new SLO template repository with the templates of the file system.
+func NewFSTemplateRepo(config FSTemplateRepoConfig) (*FSTemplateRepo, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	r := &FSTemplateRepo{
+		templates: map[string]map[string]slothv1.PrometheusServiceLevelTemplate{},
+		decoder:   scheme.Codecs.UniversalDeserializer(),
+	}
+
+	total, err := r.load(config.FS)
+	if err != nil {
+		return nil, fmt.Errorf("could not load SLO templates: %w", err)
+	}
+
+	config.Logger.WithValues(log.Kv{"templates": total}).Infof("SLO templates loaded")
+
+	return r, nil
+}
+
+var templateKindRegex = regexp.MustCompile(`(?m)^kind: +['"]?PrometheusServiceLevelTemplate['"]? *$`)
+
+func (r *FSTemplateRepo) load(templatesFS fs.FS) (int, error) {
+	total := 0
+	err := fs.WalkDir(templatesFS, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		ext := filepath.Ext(path)
+		if ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		data, err := fs.ReadFile(templatesFS, path)
+		if err != nil {
+			return fmt.Errorf("could not read %q SLO templates file: %w", path, err)
+		}
+
+		// Ignore anything that is not a template (e.g: templates along with SLO specs).
+		for _, doc := range utilsdata.SplitYAML(data) {
+			if !templateKindRegex.MatchString(doc) {
+				continue
+			}
+
+			obj, _, err := r.decoder.Decode([]byte(doc), nil, nil)
+			if err != nil {
+				return fmt.Errorf("could not decode %q SLO template: %w", path, err)
+			}
+			tpl, ok := obj.(*slothv1.PrometheusServiceLevelTemplate)
+			if !ok {
+				return fmt.Errorf("%q is not a PrometheusServiceLevelTemplate", path)
+			}
+
+			nsTemplates, ok := r.templates[tpl.Namespace]
+			if !ok {
+				nsTemplates = map[string]slothv1.PrometheusServiceLevelTemplate{}
+				r.templates[tpl.Namespace] = nsTemplates
+			}
+			if _, ok := nsTemplates[tpl.Name]; ok {
+				return fmt.Errorf("%q SLO template is already loaded", tpl.Name)
+			}
+			nsTemplates[tpl.Name] = *tpl
+			total++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not discover SLO templates: %w", err)
+	}
+
+	return total, nil
+}
+
+func (r *FSTemplateRepo) GetPrometheusServiceLevelTemplate(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevelTemplate, error) {
+	// Namespaced templates have preference over the ones without namespace.
+	for _, tplNS := range []string{ns, ""} {
+		tpl, ok := r.templates[tplNS][name]
+		if ok {
+			return &tpl, nil
+		}
+	}
+
+	return nil, fmt.Errorf("template %q missing", name)
+}
@@ -0,0 +1,197 @@
+package slotemplate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+// TemplateGetter knows how to get SLO templates.
+type TemplateGetter interface {
+	GetPrometheusServiceLevelTemplate(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevelTemplate, error)
+}
+
+// TemplateError is the error of an SLO template that could not be rendered.
+type TemplateError struct {
+	Namespace string
+	Name      string
+	Err       error
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("SLO template %s/%s: %s", e.Namespace, e.Name, e.Err)
+}
+
+func (e *TemplateError) Unwrap() error { return e.Err }
+
+// Result is the result of rendering the SLO templates instances of a spec.
+type Result struct {
+	// Spec is the spec with the rendered template SLOs added after the spec SLOs.
+	Spec slothv1.PrometheusServiceLevelSpec
+	// Templates are the used templates, in the same order as the spec template instances.
+	Templates []slothv1.PrometheusServiceLevelTemplate
+}
+
+// Renderer knows how to render the SLO templates instances of PrometheusServiceLevel specs.
+type Renderer struct {
+	templateGetter TemplateGetter
+}
+
+// NewRenderer returns a new SLO templates renderer.
+func NewRenderer(templateGetter TemplateGetter) Renderer {
+	return Renderer{templateGetter: templateGetter}
+}
+
+// Render renders the SLO template instances of the spec. The templates referenced without a namespace are
+// get from the default namespace. The instances objective overrides the objective of the rendered SLOs.
+func (r Renderer) Render(ctx context.Context, defaultNamespace string, spec slothv1.PrometheusServiceLevelSpec) (*Result, error) {
+	res := &Result{Spec: *spec.DeepCopy()}
+	if len(spec.Templates) == 0 {
+		return res, nil
+	}
+
+	for _, instance := range spec.Templates {
+		ns := instance.Namespace
+		if ns == "" {
+			ns = defaultNamespace
+		}
+
+		tpl, err := r.templateGetter.GetPrometheusServiceLevelTemplate(ctx, ns, instance.Name)
+		if err != nil {
+			return nil, &TemplateError{Namespace: ns, Name: instance.Name, Err: fmt.Errorf("could not get template: %w", err)}
+		}
+
+		slos, err := RenderTemplate(*tpl, instance.Params)
+		if err != nil {
+			return nil, &TemplateError{Namespace: ns, Name: instance.Name, Err: err}
+		}
+		if instance.Objective != nil {
+			for i := range slos {
+				slos[i].Objective = *instance.Objective
+			}
+		}
+
+		res.Spec.SLOs = append(res.Spec.SLOs, slos...)
+		res.Templates = append(res.Templates, *tpl)
+	}
+
+	return res, nil
+}
+
+var paramRegex = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// RenderTemplate renders the SLOs of a template with the params values. The params without value use
+// their default, if they don't have default the render will fail.
+//
+// The params are only replaced on the SLO string fields.
+func RenderTemplate(tpl slothv1.PrometheusServiceLevelTemplate, params map[string]string) ([]slothv1.SLO, error) {
+	values := map[string]string{}
+	for _, p := range tpl.Spec.Params {
+		if p.Default != nil {
+			values[p.Name] = *p.Default
+		}
+	}
+
+	// Set the instance params.
+	for k, v := range params {
+		if !hasParam(tpl, k) {
+			return nil, fmt.Errorf("unknown %q param", k)
+		}
+		values[k] = v
+	}
+
+	// Check required params.
+	missing := []string{}
+	for _, p := range tpl.Spec.Params {
+		if _, ok := values[p.Name]; !ok {
+			missing = append(missing, p.Name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing required params: %v", missing)
+	}
+
+	// Replace the params on the string fields of a generic representation of the SLOs, this way
+	// the values are correctly escaped regardless of their content.
+	data, err := json.Marshal(tpl.Spec.SLOs)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal template SLOs: %w", err)
+	}
+	var generic any
+	err = json.Unmarshal(data, &generic)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal template SLOs: %w", err)
+	}
+
+	generic, err = replaceParams(generic, values)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(generic)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal rendered SLOs: %w", err)
+	}
+	slos := []slothv1.SLO{}
+	err = json.Unmarshal(data, &slos)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal rendered SLOs: %w", err)
+	}
+
+	return slos, nil
+}
+
+func hasParam(tpl slothv1.PrometheusServiceLevelTemplate, name string) bool {
+	for _, p := range tpl.Spec.Params {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func replaceParams(v any, values map[string]string) (any, error) {
+	switch tv := v.(type) {
+	case string:
+		var err error
+		res := paramRegex.ReplaceAllStringFunc(tv, func(s string) string {
+			name := paramRegex.FindStringSubmatch(s)[1]
+			value, ok := values[name]
+			if !ok && err == nil {
+				err = fmt.Errorf("unknown %q param used on the template", name)
+			}
+			return value
+		})
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+
+	case []any:
+		for i := range tv {
+			r, err := replaceParams(tv[i], values)
+			if err != nil {
+				return nil, err
+			}
+			tv[i] = r
+		}
+		return tv, nil
+
+	case map[string]any:
+		for k := range tv {
+			r, err := replaceParams(tv[k], values)
+			if err != nil {
+				return nil, err
+			}
+			tv[k] = r
+		}
+		return tv, nil
+	}
+
+	return v, nil
+}
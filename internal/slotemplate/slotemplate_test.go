@@ -0,0 +1,345 @@
+package slotemplate_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/slok/sloth/internal/slotemplate"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+func strPtr(s string) *string { return &s }
+
+func float64Ptr(f float64) *float64 { return &f }
+
+func getTestTemplate() slothv1.PrometheusServiceLevelTemplate {
+	return slothv1.PrometheusServiceLevelTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "http", Namespace: "monitoring", Generation: 3},
+		Spec: slothv1.PrometheusServiceLevelTemplateSpec{
+			Params: []slothv1.SLOTemplateParam{
+				{Name: "service"},
+				{Name: "selector", Default: strPtr(`job="http"`)},
+			},
+			SLOs: []slothv1.SLO{
+				{
+					Name:      "${service}-availability",
+					Objective: 99.9,
+					Labels:    map[string]string{"service": "${service}"},
+					SLI: slothv1.SLI{Events: &slothv1.SLIEvents{
+						ErrorQuery: `sum(rate(http_requests_total{${selector},code=~"5.."}[{{.window}}]))`,
+						TotalQuery: `sum(rate(http_requests_total{${selector}}[{{.window}}]))`,
+					}},
+				},
+			},
+		},
+	}
+}
+
+type testTemplateGetter map[string]slothv1.PrometheusServiceLevelTemplate
+
+func (t testTemplateGetter) GetPrometheusServiceLevelTemplate(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevelTemplate, error) {
+	tpl, ok := t[ns+"/"+name]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return &tpl, nil
+}
+
+func TestRenderTemplate(t *testing.T) {
+	tests := map[string]struct {
+		tpl     func() slothv1.PrometheusServiceLevelTemplate
+		params  map[string]string
+		expSLOs []slothv1.SLO
+		expErr  bool
+	}{
+		"Rendering a template with the params should replace the params on the SLO string fields.": {
+			tpl:    getTestTemplate,
+			params: map[string]string{"service": "svc1", "selector": `job="svc1",handler!="/health"`},
+			expSLOs: []slothv1.SLO{
+				{
+					Name:      "svc1-availability",
+					Objective: 99.9,
+					Labels:    map[string]string{"service": "svc1"},
+					SLI: slothv1.SLI{Events: &slothv1.SLIEvents{
+						ErrorQuery: `sum(rate(http_requests_total{job="svc1",handler!="/health",code=~"5.."}[{{.window}}]))`,
+						TotalQuery: `sum(rate(http_requests_total{job="svc1",handler!="/health"}[{{.window}}]))`,
+					}},
+				},
+			},
+		},
+
+		"Rendering a template without optional params should use the param defaults.": {
+			tpl:    getTestTemplate,
+			params: map[string]string{"service": "svc1"},
+			expSLOs: []slothv1.SLO{
+				{
+					Name:      "svc1-availability",
+					Objective: 99.9,
+					Labels:    map[string]string{"service": "svc1"},
+					SLI: slothv1.SLI{Events: &slothv1.SLIEvents{
+						ErrorQuery: `sum(rate(http_requests_total{job="http",code=~"5.."}[{{.window}}]))`,
+						TotalQuery: `sum(rate(http_requests_total{job="http"}[{{.window}}]))`,
+					}},
+				},
+			},
+		},
+
+		"Rendering a template without the required params should fail.": {
+			tpl:    getTestTemplate,
+			params: map[string]string{"selector": `job="svc1"`},
+			expErr: true,
+		},
+
+		"Rendering a template with unknown params should fail.": {
+			tpl:    getTestTemplate,
+			params: map[string]string{"service": "svc1", "team": "team1"},
+			expErr: true,
+		},
+
+		"Rendering a template that uses undeclared params should fail.": {
+			tpl: func() slothv1.PrometheusServiceLevelTemplate {
+				tpl := getTestTemplate()
+				tpl.Spec.SLOs[0].Description = "${team} SLO"
+				return tpl
+			},
+			params: map[string]string{"service": "svc1"},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			gotSLOs, err := slotemplate.RenderTemplate(test.tpl(), test.params)
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expSLOs, gotSLOs)
+			}
+		})
+	}
+}
+
+func TestRendererRender(t *testing.T) {
+	tests := map[string]struct {
+		templates     testTemplateGetter
+		defaultNS     string
+		spec          slothv1.PrometheusServiceLevelSpec
+		expSLONames   []string
+		expObjectives []float64
+		expTemplates  []string
+		expErr        bool
+	}{
+		"Specs without templates should not be changed.": {
+			templates:   testTemplateGetter{},
+			spec:        slothv1.PrometheusServiceLevelSpec{Service: "svc", SLOs: []slothv1.SLO{{Name: "slo1"}}},
+			expSLONames: []string{"slo1"},
+		},
+
+		"The template SLOs should be added after the spec SLOs.": {
+			templates: testTemplateGetter{"monitoring/http": getTestTemplate()},
+			defaultNS: "monitoring",
+			spec: slothv1.PrometheusServiceLevelSpec{
+				Service: "svc",
+				SLOs:    []slothv1.SLO{{Name: "slo1"}},
+				Templates: []slothv1.SLOTemplateInstance{
+					{Name: "http", Params: map[string]string{"service": "svc1"}},
+					{Name: "http", Namespace: "monitoring", Params: map[string]string{"service": "svc2"}},
+				},
+			},
+			expSLONames:  []string{"slo1", "svc1-availability", "svc2-availability"},
+			expTemplates: []string{"monitoring/http", "monitoring/http"},
+		},
+
+		"The instance objective should override the template SLOs objective.": {
+			templates: testTemplateGetter{"monitoring/http": getTestTemplate()},
+			defaultNS: "monitoring",
+			spec: slothv1.PrometheusServiceLevelSpec{
+				Service:   "svc",
+				Templates: []slothv1.SLOTemplateInstance{{Name: "http", Objective: float64Ptr(99.5), Params: map[string]string{"service": "svc1"}}},
+			},
+			expSLONames:   []string{"svc1-availability"},
+			expObjectives: []float64{99.5},
+			expTemplates:  []string{"monitoring/http"},
+		},
+
+		"Missing templates should fail.": {
+			templates: testTemplateGetter{"monitoring/http": getTestTemplate()},
+			defaultNS: "default",
+			spec: slothv1.PrometheusServiceLevelSpec{
+				Service:   "svc",
+				Templates: []slothv1.SLOTemplateInstance{{Name: "http", Params: map[string]string{"service": "svc1"}}},
+			},
+			expErr: true,
+		},
+
+		"Invalid template instances should fail.": {
+			templates: testTemplateGetter{"monitoring/http": getTestTemplate()},
+			defaultNS: "monitoring",
+			spec: slothv1.PrometheusServiceLevelSpec{
+				Service:   "svc",
+				Templates: []slothv1.SLOTemplateInstance{{Name: "http"}},
+			},
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			r := slotemplate.NewRenderer(test.templates)
+			res, err := r.Render(context.TODO(), test.defaultNS, test.spec)
+
+			if test.expErr {
+				var tplErr *slotemplate.TemplateError
+				assert.ErrorAs(err, &tplErr)
+				return
+			}
+			require.NoError(err)
+
+			gotSLONames := []string{}
+			for _, s := range res.Spec.SLOs {
+				gotSLONames = append(gotSLONames, s.Name)
+			}
+			assert.Equal(test.expSLONames, gotSLONames)
+
+			if test.expObjectives != nil {
+				gotObjectives := []float64{}
+				for _, s := range res.Spec.SLOs {
+					gotObjectives = append(gotObjectives, s.Objective)
+				}
+				assert.Equal(test.expObjectives, gotObjectives)
+			}
+
+			var gotTemplates []string
+			for _, tpl := range res.Templates {
+				gotTemplates = append(gotTemplates, tpl.Namespace+"/"+tpl.Name)
+			}
+			assert.Equal(test.expTemplates, gotTemplates)
+			assert.Equal(test.spec.Templates, res.Spec.Templates)
+		})
+	}
+}
+
+func TestFSTemplateRepo(t *testing.T) {
+	tests := map[string]struct {
+		files   fstest.MapFS
+		ns      string
+		name    string
+		expSLO  string
+		expErr  bool
+		loadErr bool
+	}{
+		"Templates should be get by namespace and name.": {
+			files: fstest.MapFS{
+				"tpl.yaml": {Data: []byte(`
+apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevelTemplate
+metadata:
+  name: http
+  namespace: ns1
+spec:
+  slos:
+    - name: ns1-slo
+---
+apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevelTemplate
+metadata:
+  name: http
+spec:
+  slos:
+    - name: global-slo
+`)},
+			},
+			ns:     "ns1",
+			name:   "http",
+			expSLO: "ns1-slo",
+		},
+
+		"Templates without namespace should be used on any namespace.": {
+			files: fstest.MapFS{
+				"tpls/tpl.yml": {Data: []byte(`
+apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevelTemplate
+metadata:
+  name: http
+spec:
+  slos:
+    - name: global-slo
+`)},
+			},
+			ns:     "ns1",
+			name:   "http",
+			expSLO: "global-slo",
+		},
+
+		"Non template manifests and files should be ignored.": {
+			files: fstest.MapFS{
+				"README.md": {Data: []byte(`# Templates`)},
+				"slos.yaml": {Data: []byte(`
+apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevel
+metadata:
+  name: svc
+spec:
+  service: svc
+`)},
+			},
+			ns:     "ns1",
+			name:   "svc",
+			expErr: true,
+		},
+
+		"Duplicated templates should fail.": {
+			files: fstest.MapFS{
+				"tpl1.yaml": {Data: []byte(`
+apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevelTemplate
+metadata:
+  name: http
+spec: {}
+`)},
+				"tpl2.yaml": {Data: []byte(`
+apiVersion: sloth.slok.dev/v1
+kind: PrometheusServiceLevelTemplate
+metadata:
+  name: http
+spec: {}
+`)},
+			},
+			loadErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			repo, err := slotemplate.NewFSTemplateRepo(slotemplate.FSTemplateRepoConfig{FS: test.files})
+			if test.loadErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			tpl, err := repo.GetPrometheusServiceLevelTemplate(context.TODO(), test.ns, test.name)
+			if test.expErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(test.expSLO, tpl.Spec.SLOs[0].Name)
+		})
+	}
+}
@@ -40,6 +40,72 @@ func (_m *SLIPluginLoader) EXPECT() *SLIPluginLoader_Expecter {
 	return &SLIPluginLoader_Expecter{mock: &_m.Mock}
 }
 
+// DiscoverRawSLIPlugin provides a mock function for the type SLIPluginLoader
+func (_mock *SLIPluginLoader) DiscoverRawSLIPlugin(ctx context.Context, src string) (string, error) {
+	ret := _mock.Called(ctx, src)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiscoverRawSLIPlugin")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, src)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, src)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, src)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// SLIPluginLoader_DiscoverRawSLIPlugin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DiscoverRawSLIPlugin'
+type SLIPluginLoader_DiscoverRawSLIPlugin_Call struct {
+	*mock.Call
+}
+
+// DiscoverRawSLIPlugin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - src string
+func (_e *SLIPluginLoader_Expecter) DiscoverRawSLIPlugin(ctx interface{}, src interface{}) *SLIPluginLoader_DiscoverRawSLIPlugin_Call {
+	return &SLIPluginLoader_DiscoverRawSLIPlugin_Call{Call: _e.mock.On("DiscoverRawSLIPlugin", ctx, src)}
+}
+
+func (_c *SLIPluginLoader_DiscoverRawSLIPlugin_Call) Run(run func(ctx context.Context, src string)) *SLIPluginLoader_DiscoverRawSLIPlugin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *SLIPluginLoader_DiscoverRawSLIPlugin_Call) Return(s string, err error) *SLIPluginLoader_DiscoverRawSLIPlugin_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *SLIPluginLoader_DiscoverRawSLIPlugin_Call) RunAndReturn(run func(ctx context.Context, src string) (string, error)) *SLIPluginLoader_DiscoverRawSLIPlugin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LoadRawSLIPlugin provides a mock function for the type SLIPluginLoader
 func (_mock *SLIPluginLoader) LoadRawSLIPlugin(ctx context.Context, src string) (*sli.SLIPlugin, error) {
 	ret := _mock.Called(ctx, src)
@@ -135,6 +201,72 @@ func (_m *SLOPluginLoader) EXPECT() *SLOPluginLoader_Expecter {
 	return &SLOPluginLoader_Expecter{mock: &_m.Mock}
 }
 
+// DiscoverRawPlugin provides a mock function for the type SLOPluginLoader
+func (_mock *SLOPluginLoader) DiscoverRawPlugin(ctx context.Context, src string) (string, error) {
+	ret := _mock.Called(ctx, src)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiscoverRawPlugin")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, src)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, src)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, src)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// SLOPluginLoader_DiscoverRawPlugin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DiscoverRawPlugin'
+type SLOPluginLoader_DiscoverRawPlugin_Call struct {
+	*mock.Call
+}
+
+// DiscoverRawPlugin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - src string
+func (_e *SLOPluginLoader_Expecter) DiscoverRawPlugin(ctx interface{}, src interface{}) *SLOPluginLoader_DiscoverRawPlugin_Call {
+	return &SLOPluginLoader_DiscoverRawPlugin_Call{Call: _e.mock.On("DiscoverRawPlugin", ctx, src)}
+}
+
+func (_c *SLOPluginLoader_DiscoverRawPlugin_Call) Run(run func(ctx context.Context, src string)) *SLOPluginLoader_DiscoverRawPlugin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *SLOPluginLoader_DiscoverRawPlugin_Call) Return(s string, err error) *SLOPluginLoader_DiscoverRawPlugin_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *SLOPluginLoader_DiscoverRawPlugin_Call) RunAndReturn(run func(ctx context.Context, src string) (string, error)) *SLOPluginLoader_DiscoverRawPlugin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LoadRawPlugin provides a mock function for the type SLOPluginLoader
 func (_mock *SLOPluginLoader) LoadRawPlugin(ctx context.Context, src string) (*slo.Plugin, error) {
 	ret := _mock.Called(ctx, src)
@@ -230,6 +362,72 @@ func (_m *K8sTransformPluginLoader) EXPECT() *K8sTransformPluginLoader_Expecter
 	return &K8sTransformPluginLoader_Expecter{mock: &_m.Mock}
 }
 
+// DiscoverRawPlugin provides a mock function for the type K8sTransformPluginLoader
+func (_mock *K8sTransformPluginLoader) DiscoverRawPlugin(ctx context.Context, src string) (string, error) {
+	ret := _mock.Called(ctx, src)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiscoverRawPlugin")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, src)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, src)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, src)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// K8sTransformPluginLoader_DiscoverRawPlugin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DiscoverRawPlugin'
+type K8sTransformPluginLoader_DiscoverRawPlugin_Call struct {
+	*mock.Call
+}
+
+// DiscoverRawPlugin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - src string
+func (_e *K8sTransformPluginLoader_Expecter) DiscoverRawPlugin(ctx interface{}, src interface{}) *K8sTransformPluginLoader_DiscoverRawPlugin_Call {
+	return &K8sTransformPluginLoader_DiscoverRawPlugin_Call{Call: _e.mock.On("DiscoverRawPlugin", ctx, src)}
+}
+
+func (_c *K8sTransformPluginLoader_DiscoverRawPlugin_Call) Run(run func(ctx context.Context, src string)) *K8sTransformPluginLoader_DiscoverRawPlugin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *K8sTransformPluginLoader_DiscoverRawPlugin_Call) Return(s string, err error) *K8sTransformPluginLoader_DiscoverRawPlugin_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *K8sTransformPluginLoader_DiscoverRawPlugin_Call) RunAndReturn(run func(ctx context.Context, src string) (string, error)) *K8sTransformPluginLoader_DiscoverRawPlugin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LoadRawPlugin provides a mock function for the type K8sTransformPluginLoader
 func (_mock *K8sTransformPluginLoader) LoadRawPlugin(ctx context.Context, src string) (*slo0.Plugin, error) {
 	ret := _mock.Called(ctx, src)
@@ -2,54 +2,113 @@ package fs
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
+	"os"
+	"path/filepath"
 	"regexp"
+	"runtime"
 	"strings"
 	"sync"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/slok/sloth/internal/log"
 	pluginenginek8stransform "github.com/slok/sloth/internal/pluginengine/k8stransform"
 	pluginenginesli "github.com/slok/sloth/internal/pluginengine/sli"
 	pluginengineslo "github.com/slok/sloth/internal/pluginengine/slo"
+	"github.com/slok/sloth/internal/pluginengine/sloexec"
+	"github.com/slok/sloth/internal/pluginengine/slowasm"
 	commonerrors "github.com/slok/sloth/pkg/common/errors"
+	pluginslov1exec "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1/exec"
+	pluginslov1wasm "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1/wasm"
 )
 
 type SLIPluginLoader interface {
+	DiscoverRawSLIPlugin(ctx context.Context, src string) (string, error)
 	LoadRawSLIPlugin(ctx context.Context, src string) (*pluginenginesli.SLIPlugin, error)
 }
 
 type SLOPluginLoader interface {
+	DiscoverRawPlugin(ctx context.Context, src string) (string, error)
 	LoadRawPlugin(ctx context.Context, src string) (*pluginengineslo.Plugin, error)
 }
 
 type K8sTransformPluginLoader interface {
+	DiscoverRawPlugin(ctx context.Context, src string) (string, error)
 	LoadRawPlugin(ctx context.Context, src string) (*pluginenginek8stransform.Plugin, error)
 }
 
+// OSDirFS is a file system of an OS directory that knows the OS paths of its files, this is required by
+// the plugins that are loaded from OS files instead of the source (e.g: exec and WASM plugins).
+type OSDirFS struct {
+	fs.FS
+	dir string
+}
+
+// NewOSDirFS returns a new OSDirFS for the directory.
+func NewOSDirFS(dir string) OSDirFS {
+	return OSDirFS{FS: os.DirFS(dir), dir: dir}
+}
+
+// OSPath returns the OS path of a file system path.
+func (o OSDirFS) OSPath(name string) string {
+	return filepath.Join(o.dir, filepath.FromSlash(name))
+}
+
+type osPathFS interface {
+	OSPath(name string) string
+}
+
+// FilePluginRepo discovers plugins from file systems and loads them lazily, the first time they are used.
+//
+// Plugins are discovered by statically getting their metadata from the source code (without interpreting it),
+// if the plugin metadata can't be discovered statically, the plugin will be loaded to know what plugin it is.
+// The loaded plugins are reused on reloads as long as their source code doesn't change.
+//
+// Apart from the Yaegi plugins (`plugin.go` files), SLO exec and WASM plugins are discovered by their manifest
+// file (`sloth-plugin.json`), these plugins are only supported on file systems that know their OS paths (`OSDirFS`).
+//
+// Optionally, a cache directory can be set to store the discovery results on disk by the plugin source hash, so
+// plugins that can't be discovered statically are not loaded eagerly on every process start.
 type FilePluginRepo struct {
 	fss                []fs.FS
+	cacheDir           string
 	sloPluginLoader    SLOPluginLoader
 	sliPluginLoader    SLIPluginLoader
 	k8sTransformLoader K8sTransformPluginLoader
-	sloPluginCache     map[string]pluginengineslo.Plugin
-	sliPluginCache     map[string]pluginenginesli.SLIPlugin
-	k8sTransformCache  map[string]pluginenginek8stransform.Plugin
+	sloPluginCache     map[string]*lazyPlugin[pluginengineslo.Plugin]
+	sliPluginCache     map[string]*lazyPlugin[pluginenginesli.SLIPlugin]
+	k8sTransformCache  map[string]*lazyPlugin[pluginenginek8stransform.Plugin]
 	logger             log.Logger
 	mu                 sync.RWMutex
 	failOnError        bool
 }
 
 // NewFilePluginRepo returns a new FilePluginRepo that loads SLI and SLO plugins from the given file system.
-func NewFilePluginRepo(logger log.Logger, failOnError bool, sliPluginLoader SLIPluginLoader, sloPluginLoader SLOPluginLoader, k8sTransformLoader K8sTransformPluginLoader, fss ...fs.FS) (*FilePluginRepo, error) {
+// If the cache dir is empty, the plugins discovery on disk cache will be disabled.
+// On fail on error mode, plugins will be loaded eagerly, so any plugin load error is detected at the start.
+func NewFilePluginRepo(logger log.Logger, failOnError bool, cacheDir string, sliPluginLoader SLIPluginLoader, sloPluginLoader SLOPluginLoader, k8sTransformLoader K8sTransformPluginLoader, fss ...fs.FS) (*FilePluginRepo, error) {
+	if cacheDir != "" {
+		err := os.MkdirAll(cacheDir, os.ModePerm)
+		if err != nil {
+			return nil, fmt.Errorf("could not create plugins cache dir: %w", err)
+		}
+	}
+
 	r := &FilePluginRepo{
 		fss:                fss,
+		cacheDir:           cacheDir,
 		sliPluginLoader:    sliPluginLoader,
 		sloPluginLoader:    sloPluginLoader,
 		k8sTransformLoader: k8sTransformLoader,
-		sloPluginCache:     map[string]pluginengineslo.Plugin{},
-		sliPluginCache:     map[string]pluginenginesli.SLIPlugin{},
-		k8sTransformCache:  map[string]pluginenginek8stransform.Plugin{},
+		sloPluginCache:     map[string]*lazyPlugin[pluginengineslo.Plugin]{},
+		sliPluginCache:     map[string]*lazyPlugin[pluginenginesli.SLIPlugin]{},
+		k8sTransformCache:  map[string]*lazyPlugin[pluginenginek8stransform.Plugin]{},
 		logger:             logger,
 		failOnError:        failOnError,
 	}
@@ -83,13 +142,17 @@ func (r *FilePluginRepo) Reload(ctx context.Context) error {
 
 func (r *FilePluginRepo) GetSLOPlugin(ctx context.Context, id string) (*pluginengineslo.Plugin, error) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	p, ok := r.sloPluginCache[id]
+	lp, ok := r.sloPluginCache[id]
+	r.mu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("plugin %q not found: %w", id, commonerrors.ErrNotFound)
 	}
 
+	p, err := lp.get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not load plugin %q: %w", id, err)
+	}
+
 	return &p, nil
 }
 
@@ -97,18 +160,22 @@ func (r *FilePluginRepo) ListSLOPlugins(ctx context.Context) (map[string]plugine
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	return r.sloPluginCache, nil
+	return listLazyPlugins(ctx, r.logger, r.sloPluginCache), nil
 }
 
 func (r *FilePluginRepo) GetSLIPlugin(ctx context.Context, id string) (*pluginenginesli.SLIPlugin, error) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	p, ok := r.sliPluginCache[id]
+	lp, ok := r.sliPluginCache[id]
+	r.mu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("plugin %q not found: %w", id, commonerrors.ErrNotFound)
 	}
 
+	p, err := lp.get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not load plugin %q: %w", id, err)
+	}
+
 	return &p, nil
 }
 
@@ -116,18 +183,22 @@ func (r *FilePluginRepo) ListSLIPlugins(ctx context.Context) (map[string]plugine
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	return r.sliPluginCache, nil
+	return listLazyPlugins(ctx, r.logger, r.sliPluginCache), nil
 }
 
 func (r *FilePluginRepo) GetK8sTransformPlugin(ctx context.Context, id string) (*pluginenginek8stransform.Plugin, error) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	p, ok := r.k8sTransformCache[id]
+	lp, ok := r.k8sTransformCache[id]
+	r.mu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("plugin %q not found: %w", id, commonerrors.ErrNotFound)
 	}
 
+	p, err := lp.get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not load plugin %q: %w", id, err)
+	}
+
 	return &p, nil
 }
 
@@ -135,14 +206,173 @@ func (r *FilePluginRepo) ListK8sTransformPlugins(ctx context.Context) (map[strin
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	return r.k8sTransformCache, nil
+	return listLazyPlugins(ctx, r.logger, r.k8sTransformCache), nil
+}
+
+type pluginKind string
+
+const (
+	pluginKindSLI          pluginKind = "sli"
+	pluginKindSLO          pluginKind = "slo"
+	pluginKindK8sTransform pluginKind = "k8s-transform"
+)
+
+// lazyPlugin is a plugin that will be loaded only once, the first time it's used.
+//
+// Failed loads are not kept, so the load is retried the next time the plugin is used (e.g: transient
+// errors don't break the plugin until its source changes).
+type lazyPlugin[T any] struct {
+	srcHash string
+	src     string
+	load    func(ctx context.Context, src string) (*T, error)
+	mu      sync.Mutex
+	loaded  bool
+	plugin  T
+}
+
+func newLoadedLazyPlugin[T any](srcHash string, plugin T) *lazyPlugin[T] {
+	return &lazyPlugin[T]{srcHash: srcHash, plugin: plugin, loaded: true}
+}
+
+func (l *lazyPlugin[T]) get(ctx context.Context) (T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.loaded {
+		return l.plugin, nil
+	}
+
+	// The load is shared by all the waiting callers, so it shouldn't be cancelled by the first one.
+	p, err := l.load(context.WithoutCancel(ctx), l.src)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	l.plugin = *p
+	l.loaded = true
+
+	return l.plugin, nil
 }
 
-func (r *FilePluginRepo) loadPlugins(ctx context.Context, fss ...fs.FS) (map[string]pluginengineslo.Plugin, map[string]pluginenginesli.SLIPlugin, map[string]pluginenginek8stransform.Plugin, error) {
-	sloPlugins := map[string]pluginengineslo.Plugin{}
-	sliPlugins := map[string]pluginenginesli.SLIPlugin{}
-	k8sTransformPlugins := map[string]pluginenginek8stransform.Plugin{}
+func listLazyPlugins[T any](ctx context.Context, logger log.Logger, lps map[string]*lazyPlugin[T]) map[string]T {
+	plugins := map[string]T{}
+	for id, lp := range lps {
+		p, err := lp.get(ctx)
+		if err != nil {
+			logger.Errorf("could not load plugin %q: %s", id, err)
+			continue
+		}
+		plugins[id] = p
+	}
+
+	return plugins
+}
+
+// reuseOrNewLazyPlugin will reuse the current lazy plugin if the source didn't change, this
+// way, reloads don't need to load again the plugins that were already loaded. The failed loads
+// are not kept by the lazy plugins, so they are never reused.
+func reuseOrNewLazyPlugin[T any](current *lazyPlugin[T], srcHash, src string, load func(ctx context.Context, src string) (*T, error)) *lazyPlugin[T] {
+	if current != nil && current.srcHash == srcHash {
+		return current
+	}
+
+	return &lazyPlugin[T]{srcHash: srcHash, src: src, load: load}
+}
+
+type pluginSource struct {
+	path string
+	src  string
+	hash string
+	// manifestDir is the OS directory of the plugin when the source is a plugin manifest (exec and WASM plugins).
+	manifestDir string
+}
+
+type discoveredPlugin struct {
+	kind         pluginKind
+	id           string
+	sli          *lazyPlugin[pluginenginesli.SLIPlugin]
+	slo          *lazyPlugin[pluginengineslo.Plugin]
+	k8sTransform *lazyPlugin[pluginenginek8stransform.Plugin]
+}
+
+func (d discoveredPlugin) load(ctx context.Context) error {
+	var err error
+	switch d.kind {
+	case pluginKindSLI:
+		_, err = d.sli.get(ctx)
+	case pluginKindSLO:
+		_, err = d.slo.get(ctx)
+	case pluginKindK8sTransform:
+		_, err = d.k8sTransform.get(ctx)
+	}
+
+	return err
+}
+
+func (r *FilePluginRepo) loadPlugins(ctx context.Context, fss ...fs.FS) (map[string]*lazyPlugin[pluginengineslo.Plugin], map[string]*lazyPlugin[pluginenginesli.SLIPlugin], map[string]*lazyPlugin[pluginenginek8stransform.Plugin], error) {
+	srcs, err := readPluginSources(r.logger, fss...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Discover the plugins concurrently.
+	discovered := make([]*discoveredPlugin, len(srcs))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(runtime.GOMAXPROCS(0))
+	for i, src := range srcs {
+		g.Go(func() error {
+			d, err := r.discoverPlugin(gctx, src)
+			if err != nil {
+				if r.failOnError {
+					return err
+				}
+				r.logger.Errorf(err.Error())
+				return nil
+			}
+			discovered[i] = d
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, nil, fmt.Errorf("could not discover plugins: %w", err)
+	}
+
+	// Index the plugins in discovery order.
+	sloPlugins := map[string]*lazyPlugin[pluginengineslo.Plugin]{}
+	sliPlugins := map[string]*lazyPlugin[pluginenginesli.SLIPlugin]{}
+	k8sTransformPlugins := map[string]*lazyPlugin[pluginenginek8stransform.Plugin]{}
+	for _, d := range discovered {
+		if d == nil {
+			continue
+		}
+
+		switch d.kind {
+		case pluginKindSLI:
+			if _, ok := sliPlugins[d.id]; ok {
+				return nil, nil, nil, fmt.Errorf("plugin %q already loaded", d.id)
+			}
+			sliPlugins[d.id] = d.sli
+			r.logger.WithValues(log.Kv{"sli-plugin-id": d.id}).Debugf("SLI plugin discovered")
+		case pluginKindSLO:
+			if _, ok := sloPlugins[d.id]; ok {
+				return nil, nil, nil, fmt.Errorf("plugin %q already loaded", d.id)
+			}
+			sloPlugins[d.id] = d.slo
+			r.logger.WithValues(log.Kv{"slo-plugin-id": d.id}).Debugf("SLO plugin discovered")
+		case pluginKindK8sTransform:
+			if _, ok := k8sTransformPlugins[d.id]; ok {
+				return nil, nil, nil, fmt.Errorf("plugin %q already loaded", d.id)
+			}
+			k8sTransformPlugins[d.id] = d.k8sTransform
+			r.logger.WithValues(log.Kv{"k8s-transform-plugin-id": d.id}).Debugf("K8s transform plugin discovered")
+		}
+	}
+
+	return sloPlugins, sliPlugins, k8sTransformPlugins, nil
+}
 
+func readPluginSources(logger log.Logger, fss ...fs.FS) ([]pluginSource, error) {
+	srcs := []pluginSource{}
 	for _, f := range fss {
 		err := fs.WalkDir(f, ".", func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
@@ -155,7 +385,8 @@ func (r *FilePluginRepo) loadPlugins(ctx context.Context, fss ...fs.FS) (map[str
 				return nil
 			}
 
-			if !pluginNameRegex.MatchString(path) {
+			isManifest := d.Name() == pluginslov1exec.ManifestFileName
+			if !isManifest && !pluginNameRegex.MatchString(path) {
 				return nil
 			}
 
@@ -163,56 +394,215 @@ func (r *FilePluginRepo) loadPlugins(ctx context.Context, fss ...fs.FS) (map[str
 			if err != nil {
 				return fmt.Errorf("could not read %q plugin data: %w", path, err)
 			}
-			pluginData := string(pluginDataBytes)
-
-			// Try SLI plugin if not, SLO plugin.
-			sliPlugin, sliErr := r.sliPluginLoader.LoadRawSLIPlugin(ctx, pluginData)
-			if sliErr == nil {
-				_, ok := sliPlugins[sliPlugin.ID]
-				if ok {
-					return fmt.Errorf("plugin %q already loaded", sliPlugin.ID)
-				}
-				sliPlugins[sliPlugin.ID] = *sliPlugin
-				r.logger.WithValues(log.Kv{"sli-plugin-id": sliPlugin.ID}).Debugf("SLI plugin discovered and loaded")
-				return nil
-			}
+			hash := sha256.Sum256(pluginDataBytes)
 
-			// Try SLO plugin.
-			sloPlugin, sloErr := r.sloPluginLoader.LoadRawPlugin(ctx, pluginData)
-			if sloErr == nil {
-				_, ok := sloPlugins[sloPlugin.ID]
-				if ok {
-					return fmt.Errorf("plugin %q already loaded", sloPlugin.ID)
-				}
-				sloPlugins[sloPlugin.ID] = *sloPlugin
-				r.logger.WithValues(log.Kv{"slo-plugin-id": sloPlugin.ID}).Debugf("SLO plugin discovered and loaded")
-				return nil
+			ps := pluginSource{
+				path: path,
+				src:  string(pluginDataBytes),
+				hash: hex.EncodeToString(hash[:]),
 			}
 
-			// Try K8s transform plugin.
-			k8sTransformPlugin, k8sErr := r.k8sTransformLoader.LoadRawPlugin(ctx, pluginData)
-			if k8sErr == nil {
-				_, ok := k8sTransformPlugins[k8sTransformPlugin.ID]
-				if ok {
-					return fmt.Errorf("plugin %q already loaded", k8sTransformPlugin.ID)
+			if isManifest {
+				osf, ok := f.(osPathFS)
+				if !ok {
+					logger.Warningf("Ignoring %q plugin manifest, the file system doesn't support manifest plugins", path)
+					return nil
 				}
-				k8sTransformPlugins[k8sTransformPlugin.ID] = *k8sTransformPlugin
-				r.logger.WithValues(log.Kv{"k8s-transform-plugin-id": k8sTransformPlugin.ID}).Debugf("K8s transform plugin discovered and loaded")
-				return nil
+				ps.manifestDir = filepath.Dir(osf.OSPath(path))
 			}
 
-			err = fmt.Errorf("could not load %q as any kind of plugin: (SLI plugin error: %w | SLO plugin error: %w | K8s transform plugin error: %w)", path, sliErr, sloErr, k8sErr)
-			if r.failOnError {
-				return err
-			}
-			r.logger.Errorf(err.Error())
+			srcs = append(srcs, ps)
 
 			return nil
 		})
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("could not walk dir: %w", err)
+			return nil, fmt.Errorf("could not walk dir: %w", err)
 		}
 	}
 
-	return sloPlugins, sliPlugins, k8sTransformPlugins, nil
+	return srcs, nil
+}
+
+// loadManifestPlugin loads the SLO plugin of a plugin manifest with the engine set on the manifest.
+func loadManifestPlugin(ctx context.Context, ps pluginSource) (*pluginengineslo.Plugin, error) {
+	manifest := struct {
+		Engine string `json:"engine"`
+	}{}
+	err := json.Unmarshal([]byte(ps.src), &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	switch manifest.Engine {
+	case pluginslov1exec.ManifestEngine:
+		return sloexec.PluginLoader.LoadRawPlugin(ctx, ps.manifestDir, []byte(ps.src))
+	case pluginslov1wasm.ManifestEngine:
+		return slowasm.PluginLoader.LoadRawPlugin(ctx, ps.manifestDir, []byte(ps.src))
+	default:
+		return nil, fmt.Errorf("unsupported plugin engine: %q", manifest.Engine)
+	}
+}
+
+// discoverPlugin discovers the plugin kind and ID using, in order: the on disk cache, the static discovery
+// and finally loading the plugin with each of the plugin loaders.
+func (r *FilePluginRepo) discoverPlugin(ctx context.Context, ps pluginSource) (*discoveredPlugin, error) {
+	// Manifest plugins are loaded from their manifest, there is no need to discover them.
+	if ps.manifestDir != "" {
+		sloPlugin, err := loadManifestPlugin(ctx, ps)
+		if err != nil {
+			return nil, fmt.Errorf("could not load %q plugin manifest: %w", ps.path, err)
+		}
+		return &discoveredPlugin{kind: pluginKindSLO, id: sloPlugin.ID, slo: newLoadedLazyPlugin(ps.hash, *sloPlugin)}, nil
+	}
+
+	// Try the on disk cache.
+	if entry, ok := r.getCacheEntry(ps.hash); ok {
+		d := r.newDiscoveredPlugin(entry.Kind, entry.ID, ps)
+		return d, r.loadIfStrict(ctx, ps, d)
+	}
+
+	// Try static discovery.
+	var d *discoveredPlugin
+	if id, err := r.sliPluginLoader.DiscoverRawSLIPlugin(ctx, ps.src); err == nil {
+		d = r.newDiscoveredPlugin(pluginKindSLI, id, ps)
+	} else if id, err := r.sloPluginLoader.DiscoverRawPlugin(ctx, ps.src); err == nil {
+		d = r.newDiscoveredPlugin(pluginKindSLO, id, ps)
+	} else if id, err := r.k8sTransformLoader.DiscoverRawPlugin(ctx, ps.src); err == nil {
+		d = r.newDiscoveredPlugin(pluginKindK8sTransform, id, ps)
+	}
+	if d != nil {
+		err := r.loadIfStrict(ctx, ps, d)
+		if err != nil {
+			return nil, err
+		}
+		r.setCacheEntry(ps.hash, d.kind, d.id)
+		return d, nil
+	}
+
+	// Fallback to load the plugin trying with each plugin type (SLI, SLO and K8s transform).
+	sliPlugin, sliErr := r.sliPluginLoader.LoadRawSLIPlugin(ctx, ps.src)
+	if sliErr == nil {
+		r.setCacheEntry(ps.hash, pluginKindSLI, sliPlugin.ID)
+		return &discoveredPlugin{kind: pluginKindSLI, id: sliPlugin.ID, sli: newLoadedLazyPlugin(ps.hash, *sliPlugin)}, nil
+	}
+
+	sloPlugin, sloErr := r.sloPluginLoader.LoadRawPlugin(ctx, ps.src)
+	if sloErr == nil {
+		r.setCacheEntry(ps.hash, pluginKindSLO, sloPlugin.ID)
+		return &discoveredPlugin{kind: pluginKindSLO, id: sloPlugin.ID, slo: newLoadedLazyPlugin(ps.hash, *sloPlugin)}, nil
+	}
+
+	k8sTransformPlugin, k8sErr := r.k8sTransformLoader.LoadRawPlugin(ctx, ps.src)
+	if k8sErr == nil {
+		r.setCacheEntry(ps.hash, pluginKindK8sTransform, k8sTransformPlugin.ID)
+		return &discoveredPlugin{kind: pluginKindK8sTransform, id: k8sTransformPlugin.ID, k8sTransform: newLoadedLazyPlugin(ps.hash, *k8sTransformPlugin)}, nil
+	}
+
+	return nil, fmt.Errorf("could not load %q as any kind of plugin: (SLI plugin error: %w | SLO plugin error: %w | K8s transform plugin error: %w)", ps.path, sliErr, sloErr, k8sErr)
+}
+
+func (r *FilePluginRepo) newDiscoveredPlugin(kind pluginKind, id string, ps pluginSource) *discoveredPlugin {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d := &discoveredPlugin{kind: kind, id: id}
+	switch kind {
+	case pluginKindSLI:
+		d.sli = reuseOrNewLazyPlugin(r.sliPluginCache[id], ps.hash, ps.src, r.sliPluginLoader.LoadRawSLIPlugin)
+	case pluginKindSLO:
+		d.slo = reuseOrNewLazyPlugin(r.sloPluginCache[id], ps.hash, ps.src, r.sloPluginLoader.LoadRawPlugin)
+	case pluginKindK8sTransform:
+		d.k8sTransform = reuseOrNewLazyPlugin(r.k8sTransformCache[id], ps.hash, ps.src, r.k8sTransformLoader.LoadRawPlugin)
+	}
+
+	return d
+}
+
+// loadIfStrict loads the plugin when we are on fail on error mode, so we know on the discovery if the plugin can be loaded.
+func (r *FilePluginRepo) loadIfStrict(ctx context.Context, ps pluginSource, d *discoveredPlugin) error {
+	if !r.failOnError {
+		return nil
+	}
+
+	err := d.load(ctx)
+	if err != nil {
+		return fmt.Errorf("could not load %q plugin: %w", ps.path, err)
+	}
+
+	return nil
+}
+
+// pluginCacheVersion is the version of the plugin cache entries, bump it when the
+// format or the discovery semantics change to invalidate old cache entries.
+const pluginCacheVersion = 1
+
+type pluginCacheEntry struct {
+	Version int        `json:"version"`
+	Kind    pluginKind `json:"kind"`
+	ID      string     `json:"id"`
+}
+
+func (r *FilePluginRepo) cacheEntryPath(srcHash string) string {
+	return filepath.Join(r.cacheDir, srcHash+".json")
+}
+
+func (r *FilePluginRepo) getCacheEntry(srcHash string) (*pluginCacheEntry, bool) {
+	if r.cacheDir == "" {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(r.cacheEntryPath(srcHash))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			r.logger.Warningf("could not read plugin cache entry: %s", err)
+		}
+		return nil, false
+	}
+
+	entry := &pluginCacheEntry{}
+	err = json.Unmarshal(data, entry)
+	if err != nil || entry.Version != pluginCacheVersion || entry.ID == "" {
+		return nil, false
+	}
+
+	switch entry.Kind {
+	case pluginKindSLI, pluginKindSLO, pluginKindK8sTransform:
+		return entry, true
+	default:
+		return nil, false
+	}
+}
+
+func (r *FilePluginRepo) setCacheEntry(srcHash string, kind pluginKind, id string) {
+	if r.cacheDir == "" {
+		return
+	}
+
+	data, err := json.Marshal(pluginCacheEntry{Version: pluginCacheVersion, Kind: kind, ID: id})
+	if err != nil {
+		r.logger.Warningf("could not marshal plugin cache entry: %s", err)
+		return
+	}
+
+	// Write atomically so concurrent processes sharing the cache never read partial entries.
+	tmpFile, err := os.CreateTemp(r.cacheDir, srcHash+".*.tmp")
+	if err != nil {
+		r.logger.Warningf("could not create plugin cache entry: %s", err)
+		return
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(data)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		r.logger.Warningf("could not write plugin cache entry: %s", err)
+		return
+	}
+
+	err = os.Rename(tmpFile.Name(), r.cacheEntryPath(srcHash))
+	if err != nil {
+		r.logger.Warningf("could not write plugin cache entry: %s", err)
+	}
 }
@@ -3,6 +3,8 @@ package fs_test
 import (
 	"fmt"
 	"io/fs"
+	"os"
+	"path/filepath"
 	"testing"
 	"testing/fstest"
 
@@ -17,6 +19,13 @@ import (
 	"github.com/slok/sloth/internal/storage/fs/fsmock"
 )
 
+// mockNoStaticDiscovery makes the plugin static discovery fail, so the plugins need to be loaded to be discovered.
+func mockNoStaticDiscovery(mslopl *fsmock.SLOPluginLoader, mslipl *fsmock.SLIPluginLoader, mk8stl *fsmock.K8sTransformPluginLoader) {
+	mslipl.On("DiscoverRawSLIPlugin", mock.Anything, mock.Anything).Maybe().Return("", fmt.Errorf("something"))
+	mslopl.On("DiscoverRawPlugin", mock.Anything, mock.Anything).Maybe().Return("", fmt.Errorf("something"))
+	mk8stl.On("DiscoverRawPlugin", mock.Anything, mock.Anything).Maybe().Return("", fmt.Errorf("something"))
+}
+
 func TestFilePluginRepoListSLOPlugins(t *testing.T) {
 	tests := map[string]struct {
 		failOnError bool
@@ -148,9 +157,10 @@ func TestFilePluginRepoListSLOPlugins(t *testing.T) {
 			mslipl := fsmock.NewSLIPluginLoader(t)
 			mk8stl := fsmock.NewK8sTransformPluginLoader(t)
 			test.mock(mslopl, mslipl, mk8stl)
+			mockNoStaticDiscovery(mslopl, mslipl, mk8stl)
 
 			// Create repository and load plugins.
-			repo, err := storagefs.NewFilePluginRepo(log.Noop, test.failOnError, mslipl, mslopl, mk8stl, test.fss()...)
+			repo, err := storagefs.NewFilePluginRepo(log.Noop, test.failOnError, "", mslipl, mslopl, mk8stl, test.fss()...)
 			if test.expLoadErr {
 				assert.Error(err)
 				return
@@ -211,9 +221,10 @@ func TestFilePluginRepoGetSLOPlugin(t *testing.T) {
 			mslipl := fsmock.NewSLIPluginLoader(t)
 			mk8stl := fsmock.NewK8sTransformPluginLoader(t)
 			test.mock(mslopl, mslipl, mk8stl)
+			mockNoStaticDiscovery(mslopl, mslipl, mk8stl)
 
 			// Create repository and load plugins.
-			repo, err := storagefs.NewFilePluginRepo(log.Noop, false, mslipl, mslopl, mk8stl, test.fss()...)
+			repo, err := storagefs.NewFilePluginRepo(log.Noop, false, "", mslipl, mslopl, mk8stl, test.fss()...)
 			require.NoError(err)
 
 			plugin, err := repo.GetSLOPlugin(t.Context(), test.pluginID)
@@ -339,9 +350,10 @@ func TestFilePluginRepoListSLIPlugins(t *testing.T) {
 			mslipl := fsmock.NewSLIPluginLoader(t)
 			mk8stl := fsmock.NewK8sTransformPluginLoader(t)
 			test.mock(mslopl, mslipl, mk8stl)
+			mockNoStaticDiscovery(mslopl, mslipl, mk8stl)
 
 			// Create repository and load plugins.
-			repo, err := storagefs.NewFilePluginRepo(log.Noop, test.failOnError, mslipl, mslopl, mk8stl, test.fss()...)
+			repo, err := storagefs.NewFilePluginRepo(log.Noop, test.failOnError, "", mslipl, mslopl, mk8stl, test.fss()...)
 			if test.expLoadErr {
 				assert.Error(err)
 				return
@@ -399,9 +411,10 @@ func TestFilePluginRepoGetSLIPlugin(t *testing.T) {
 			mslipl := fsmock.NewSLIPluginLoader(t)
 			mk8stl := fsmock.NewK8sTransformPluginLoader(t)
 			test.mock(mslopl, mslipl, mk8stl)
+			mockNoStaticDiscovery(mslopl, mslipl, mk8stl)
 
 			// Create repository and load plugins.
-			repo, err := storagefs.NewFilePluginRepo(log.Noop, false, mslipl, mslopl, mk8stl, test.fss()...)
+			repo, err := storagefs.NewFilePluginRepo(log.Noop, false, "", mslipl, mslopl, mk8stl, test.fss()...)
 			require.NoError(err)
 
 			plugin, err := repo.GetSLIPlugin(t.Context(), test.pluginID)
@@ -413,3 +426,290 @@ func TestFilePluginRepoGetSLIPlugin(t *testing.T) {
 		})
 	}
 }
+
+func TestFilePluginRepoLazyLoad(t *testing.T) {
+	tests := map[string]struct {
+		failOnError bool
+		fss         func() []fs.FS
+		mock        func(mslopl *fsmock.SLOPluginLoader, mslipl *fsmock.SLIPluginLoader, mk8stl *fsmock.K8sTransformPluginLoader)
+		exec        func(t *testing.T, fss []fs.FS, repo *storagefs.FilePluginRepo)
+		expLoadErr  bool
+	}{
+		"Statically discovered plugins should only be loaded once when used.": {
+			fss: func() []fs.FS {
+				m := make(fstest.MapFS)
+				m["m1/pl1/plugin.go"] = &fstest.MapFile{Data: []byte("p1")}
+				m["m1/pl2/plugin.go"] = &fstest.MapFile{Data: []byte("p2")}
+				m["m1/pl3/plugin.go"] = &fstest.MapFile{Data: []byte("p3")}
+				return []fs.FS{m}
+			},
+			mock: func(mslopl *fsmock.SLOPluginLoader, mslipl *fsmock.SLIPluginLoader, mk8stl *fsmock.K8sTransformPluginLoader) {
+				mslipl.On("DiscoverRawSLIPlugin", mock.Anything, "p1").Once().Return("p1", nil)
+				mslipl.On("DiscoverRawSLIPlugin", mock.Anything, mock.Anything).Return("", fmt.Errorf("something"))
+				mslopl.On("DiscoverRawPlugin", mock.Anything, "p2").Once().Return("p2", nil)
+				mslopl.On("DiscoverRawPlugin", mock.Anything, mock.Anything).Return("", fmt.Errorf("something"))
+				mk8stl.On("DiscoverRawPlugin", mock.Anything, "p3").Once().Return("p3", nil)
+
+				// Only the used plugin is loaded, and only once.
+				mslopl.On("LoadRawPlugin", mock.Anything, "p2").Once().Return(&pluginengineslo.Plugin{ID: "p2"}, nil)
+			},
+			exec: func(t *testing.T, fss []fs.FS, repo *storagefs.FilePluginRepo) {
+				for range 3 {
+					p, err := repo.GetSLOPlugin(t.Context(), "p2")
+					require.NoError(t, err)
+					assert.Equal(t, pluginengineslo.Plugin{ID: "p2"}, *p)
+				}
+
+				_, err := repo.GetSLOPlugin(t.Context(), "p1")
+				assert.Error(t, err)
+			},
+		},
+
+		"Statically discovered plugins that fail on load should fail when used.": {
+			fss: func() []fs.FS {
+				m := make(fstest.MapFS)
+				m["m1/pl1/plugin.go"] = &fstest.MapFile{Data: []byte("p1")}
+				return []fs.FS{m}
+			},
+			mock: func(mslopl *fsmock.SLOPluginLoader, mslipl *fsmock.SLIPluginLoader, mk8stl *fsmock.K8sTransformPluginLoader) {
+				mslipl.On("DiscoverRawSLIPlugin", mock.Anything, "p1").Once().Return("", fmt.Errorf("something"))
+				mslopl.On("DiscoverRawPlugin", mock.Anything, "p1").Once().Return("p1", nil)
+				// The failed loads are not cached, so listing retries the load.
+				mslopl.On("LoadRawPlugin", mock.Anything, "p1").Twice().Return(nil, fmt.Errorf("something"))
+			},
+			exec: func(t *testing.T, fss []fs.FS, repo *storagefs.FilePluginRepo) {
+				_, err := repo.GetSLOPlugin(t.Context(), "p1")
+				assert.Error(t, err)
+
+				plugins, err := repo.ListSLOPlugins(t.Context())
+				require.NoError(t, err)
+				assert.Empty(t, plugins)
+			},
+		},
+
+		"Statically discovered plugins that fail on load should be retried on the next use and after reloading.": {
+			fss: func() []fs.FS {
+				m := make(fstest.MapFS)
+				m["m1/pl1/plugin.go"] = &fstest.MapFile{Data: []byte("p1")}
+				return []fs.FS{m}
+			},
+			mock: func(mslopl *fsmock.SLOPluginLoader, mslipl *fsmock.SLIPluginLoader, mk8stl *fsmock.K8sTransformPluginLoader) {
+				mslipl.On("DiscoverRawSLIPlugin", mock.Anything, "p1").Return("", fmt.Errorf("something"))
+				mslopl.On("DiscoverRawPlugin", mock.Anything, "p1").Return("p1", nil)
+				mslopl.On("LoadRawPlugin", mock.Anything, "p1").Twice().Return(nil, fmt.Errorf("something"))
+				mslopl.On("LoadRawPlugin", mock.Anything, "p1").Once().Return(&pluginengineslo.Plugin{ID: "p1"}, nil)
+			},
+			exec: func(t *testing.T, fss []fs.FS, repo *storagefs.FilePluginRepo) {
+				_, err := repo.GetSLOPlugin(t.Context(), "p1")
+				assert.Error(t, err)
+				_, err = repo.GetSLOPlugin(t.Context(), "p1")
+				assert.Error(t, err)
+
+				// Reloading with the same source should not reuse the failed load.
+				err = repo.Reload(t.Context())
+				require.NoError(t, err)
+
+				p, err := repo.GetSLOPlugin(t.Context(), "p1")
+				require.NoError(t, err)
+				assert.Equal(t, pluginengineslo.Plugin{ID: "p1"}, *p)
+			},
+		},
+
+		"Statically discovered plugins on strict mode should be loaded eagerly and fail on load errors.": {
+			failOnError: true,
+			fss: func() []fs.FS {
+				m := make(fstest.MapFS)
+				m["m1/pl1/plugin.go"] = &fstest.MapFile{Data: []byte("p1")}
+				return []fs.FS{m}
+			},
+			mock: func(mslopl *fsmock.SLOPluginLoader, mslipl *fsmock.SLIPluginLoader, mk8stl *fsmock.K8sTransformPluginLoader) {
+				mslipl.On("DiscoverRawSLIPlugin", mock.Anything, "p1").Once().Return("", fmt.Errorf("something"))
+				mslopl.On("DiscoverRawPlugin", mock.Anything, "p1").Once().Return("p1", nil)
+				mslopl.On("LoadRawPlugin", mock.Anything, "p1").Once().Return(nil, fmt.Errorf("something"))
+			},
+			expLoadErr: true,
+		},
+
+		"Reloading should reuse the already loaded plugins if the source didn't change.": {
+			fss: func() []fs.FS {
+				m := make(fstest.MapFS)
+				m["m1/pl1/plugin.go"] = &fstest.MapFile{Data: []byte("p1")}
+				m["m1/pl2/plugin.go"] = &fstest.MapFile{Data: []byte("p2")}
+				return []fs.FS{m}
+			},
+			mock: func(mslopl *fsmock.SLOPluginLoader, mslipl *fsmock.SLIPluginLoader, mk8stl *fsmock.K8sTransformPluginLoader) {
+				mslipl.On("DiscoverRawSLIPlugin", mock.Anything, mock.Anything).Return("", fmt.Errorf("something"))
+				mslopl.On("DiscoverRawPlugin", mock.Anything, "p1").Twice().Return("p1", nil)
+				mslopl.On("DiscoverRawPlugin", mock.Anything, "p2").Once().Return("p2", nil)
+				mslopl.On("DiscoverRawPlugin", mock.Anything, "p2-changed").Once().Return("p2", nil)
+
+				mslopl.On("LoadRawPlugin", mock.Anything, "p1").Once().Return(&pluginengineslo.Plugin{ID: "p1"}, nil)
+				mslopl.On("LoadRawPlugin", mock.Anything, "p2").Once().Return(&pluginengineslo.Plugin{ID: "p2"}, nil)
+				mslopl.On("LoadRawPlugin", mock.Anything, "p2-changed").Once().Return(&pluginengineslo.Plugin{ID: "p2"}, nil)
+			},
+			exec: func(t *testing.T, fss []fs.FS, repo *storagefs.FilePluginRepo) {
+				_, err := repo.GetSLOPlugin(t.Context(), "p1")
+				require.NoError(t, err)
+				_, err = repo.GetSLOPlugin(t.Context(), "p2")
+				require.NoError(t, err)
+
+				// Change one of the plugins and reload.
+				fss[0].(fstest.MapFS)["m1/pl2/plugin.go"] = &fstest.MapFile{Data: []byte("p2-changed")}
+				err = repo.Reload(t.Context())
+				require.NoError(t, err)
+
+				_, err = repo.GetSLOPlugin(t.Context(), "p1")
+				require.NoError(t, err)
+				_, err = repo.GetSLOPlugin(t.Context(), "p2")
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			mslopl := fsmock.NewSLOPluginLoader(t)
+			mslipl := fsmock.NewSLIPluginLoader(t)
+			mk8stl := fsmock.NewK8sTransformPluginLoader(t)
+			test.mock(mslopl, mslipl, mk8stl)
+
+			fss := test.fss()
+			repo, err := storagefs.NewFilePluginRepo(log.Noop, test.failOnError, "", mslipl, mslopl, mk8stl, fss...)
+			if test.expLoadErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(t, err)
+
+			test.exec(t, fss, repo)
+		})
+	}
+}
+
+func TestFilePluginRepoDiskCache(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	cacheDir := t.TempDir()
+	m := make(fstest.MapFS)
+	m["m1/pl1/plugin.go"] = &fstest.MapFile{Data: []byte("p1")}
+
+	// First execution, the plugin can't be discovered statically, so it's loaded to be discovered.
+	mslopl := fsmock.NewSLOPluginLoader(t)
+	mslipl := fsmock.NewSLIPluginLoader(t)
+	mk8stl := fsmock.NewK8sTransformPluginLoader(t)
+	mslipl.On("LoadRawSLIPlugin", mock.Anything, "p1").Once().Return(nil, fmt.Errorf("something"))
+	mslopl.On("LoadRawPlugin", mock.Anything, "p1").Once().Return(&pluginengineslo.Plugin{ID: "p1"}, nil)
+	mockNoStaticDiscovery(mslopl, mslipl, mk8stl)
+
+	repo, err := storagefs.NewFilePluginRepo(log.Noop, false, cacheDir, mslipl, mslopl, mk8stl, m)
+	require.NoError(err)
+	p, err := repo.GetSLOPlugin(t.Context(), "p1")
+	require.NoError(err)
+	assert.Equal(pluginengineslo.Plugin{ID: "p1"}, *p)
+
+	// Second execution, the plugin discovery is cached on disk, so it's only loaded when used.
+	mslopl = fsmock.NewSLOPluginLoader(t)
+	mslipl = fsmock.NewSLIPluginLoader(t)
+	mk8stl = fsmock.NewK8sTransformPluginLoader(t)
+
+	repo, err = storagefs.NewFilePluginRepo(log.Noop, false, cacheDir, mslipl, mslopl, mk8stl, m)
+	require.NoError(err)
+	mslopl.AssertNotCalled(t, "LoadRawPlugin", mock.Anything, mock.Anything)
+
+	mslopl.On("LoadRawPlugin", mock.Anything, "p1").Once().Return(&pluginengineslo.Plugin{ID: "p1"}, nil)
+	p, err = repo.GetSLOPlugin(t.Context(), "p1")
+	require.NoError(err)
+	assert.Equal(pluginengineslo.Plugin{ID: "p1"}, *p)
+}
+
+func TestFilePluginRepoExecPlugins(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(dir, "pl1"), 0o755))
+	require.NoError(os.WriteFile(filepath.Join(dir, "pl1", "sloth-plugin.json"), []byte(`{"engine": "exec", "version": "prometheus/slo/v1", "id": "exec-p1", "command": ["./plugin"]}`), 0o644))
+	require.NoError(os.MkdirAll(filepath.Join(dir, "pl2"), 0o755))
+	require.NoError(os.WriteFile(filepath.Join(dir, "pl2", "plugin.go"), []byte("p2"), 0o644))
+
+	mslopl := fsmock.NewSLOPluginLoader(t)
+	mslipl := fsmock.NewSLIPluginLoader(t)
+	mk8stl := fsmock.NewK8sTransformPluginLoader(t)
+	mslipl.On("LoadRawSLIPlugin", mock.Anything, "p2").Once().Return(nil, fmt.Errorf("something"))
+	mslopl.On("LoadRawPlugin", mock.Anything, "p2").Once().Return(&pluginengineslo.Plugin{ID: "p2"}, nil)
+	mockNoStaticDiscovery(mslopl, mslipl, mk8stl)
+
+	repo, err := storagefs.NewFilePluginRepo(log.Noop, true, "", mslipl, mslopl, mk8stl, storagefs.NewOSDirFS(dir))
+	require.NoError(err)
+
+	plugins, err := repo.ListSLOPlugins(t.Context())
+	require.NoError(err)
+	assert.Len(plugins, 2)
+	assert.Contains(plugins, "p2")
+	require.Contains(plugins, "exec-p1")
+	assert.Equal("exec-p1", plugins["exec-p1"].ID)
+	assert.NotNil(plugins["exec-p1"].PluginV1Factory)
+
+	// Exec plugin manifests on file systems that are not on the OS, are ignored.
+	m := make(fstest.MapFS)
+	m["pl1/sloth-plugin.json"] = &fstest.MapFile{Data: []byte(`{"engine": "exec", "version": "prometheus/slo/v1", "id": "exec-p1", "command": ["./plugin"]}`)}
+	repo, err = storagefs.NewFilePluginRepo(log.Noop, true, "", mslipl, mslopl, mk8stl, m)
+	require.NoError(err)
+	plugins, err = repo.ListSLOPlugins(t.Context())
+	require.NoError(err)
+	assert.Len(plugins, 0)
+}
+
+func TestFilePluginRepoManifestPluginEngines(t *testing.T) {
+	tests := map[string]struct {
+		manifest string
+		expErr   bool
+	}{
+		"Exec plugin manifests should be loaded.": {
+			manifest: `{"engine": "exec", "version": "prometheus/slo/v1", "id": "p1", "command": ["./plugin"]}`,
+		},
+
+		"WASM plugin manifests should be loaded.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "p1", "module": "./plugin.wasm"}`,
+		},
+
+		"WASM plugin manifests with missing modules should fail.": {
+			manifest: `{"engine": "wasm", "version": "prometheus/slo/v1", "id": "p1", "module": "./missing.wasm"}`,
+			expErr:   true,
+		},
+
+		"Plugin manifests with unknown engines should fail.": {
+			manifest: `{"engine": "unknown", "version": "prometheus/slo/v1", "id": "p1"}`,
+			expErr:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+			assert := assert.New(t)
+
+			dir := t.TempDir()
+			require.NoError(os.WriteFile(filepath.Join(dir, "sloth-plugin.json"), []byte(test.manifest), 0o644))
+			require.NoError(os.WriteFile(filepath.Join(dir, "plugin.wasm"), []byte("wasm"), 0o644))
+
+			mslopl := fsmock.NewSLOPluginLoader(t)
+			mslipl := fsmock.NewSLIPluginLoader(t)
+			mk8stl := fsmock.NewK8sTransformPluginLoader(t)
+
+			repo, err := storagefs.NewFilePluginRepo(log.Noop, true, "", mslipl, mslopl, mk8stl, storagefs.NewOSDirFS(dir))
+			if test.expErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			p, err := repo.GetSLOPlugin(t.Context(), "p1")
+			require.NoError(err)
+			assert.Equal("p1", p.ID)
+		})
+	}
+}
@@ -32,6 +32,24 @@ func (c K8sSlothPrometheusCRSpecLoader) LoadSpec(ctx context.Context, spec *k8sp
 	return mapSpecToModel(ctx, c.windowPeriod, c.pluginsRepo, spec)
 }
 
+// LoadClusterSpec loads a cluster scoped Kubernetes CR spec into a model. The SLOs are loaded as if they were
+// from a PrometheusServiceLevel on the target namespace.
+func (c K8sSlothPrometheusCRSpecLoader) LoadClusterSpec(ctx context.Context, spec *k8sprometheusv1.ClusterPrometheusServiceLevel) (*model.PromSLOGroup, error) {
+	psl := &k8sprometheusv1.PrometheusServiceLevel{
+		ObjectMeta: *spec.ObjectMeta.DeepCopy(),
+		Spec:       spec.Spec.PrometheusServiceLevelSpec,
+	}
+	psl.Namespace = spec.Spec.TargetNamespace
+
+	m, err := mapSpecToModel(ctx, c.windowPeriod, c.pluginsRepo, psl)
+	if err != nil {
+		return nil, err
+	}
+	m.OriginalSource = model.PromSLOGroupSource{K8sSlothClusterV1: spec}
+
+	return m, nil
+}
+
 // K8sSlothPrometheusYAMLSpecLoader knows how to load Kubernetes ServiceLevel YAML specs and converts them to a model.
 type K8sSlothPrometheusYAMLSpecLoader struct {
 	windowPeriod time.Duration
@@ -86,9 +104,9 @@ func (l K8sSlothPrometheusYAMLSpecLoader) LoadAPI(ctx context.Context, data []by
 		return nil, fmt.Errorf("can't type assert runtime.Object to v1.PrometheusServiceLeve")
 	}
 
-	// Check at least we have one SLO.
-	if len(kslo.Spec.SLOs) == 0 {
-		return nil, fmt.Errorf("at least one SLO is required")
+	// Check at least we have one SLO (declared or from SLO templates).
+	if len(kslo.Spec.SLOs) == 0 && len(kslo.Spec.Templates) == 0 {
+		return nil, fmt.Errorf("at least one SLO or SLO template is required")
 	}
 
 	return kslo, nil
@@ -177,6 +195,11 @@ func mapSpecToModel(ctx context.Context, defaultWindowPeriod time.Duration, plug
 				prometheuspluginv1.SLIPluginMetaObjective: fmt.Sprintf("%f", specSLO.Objective),
 			}
 
+			err = validateSLIPluginOptions(*plugin, specSLO.SLI.Plugin.Options)
+			if err != nil {
+				return nil, err
+			}
+
 			rawQuery, err := plugin.Func(ctx, meta, spec.Labels, specSLO.SLI.Plugin.Options)
 			if err != nil {
 				return nil, fmt.Errorf("plugin %q execution error: %w", specSLO.SLI.Plugin.ID, err)
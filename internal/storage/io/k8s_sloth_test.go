@@ -529,3 +529,83 @@ kind:               PrometheusServiceLevel
 		})
 	}
 }
+
+func TestK8sSlothPrometheusCRSpecLoaderLoadClusterSpec(t *testing.T) {
+	cpsl := &kubeslothv1.ClusterPrometheusServiceLevel{
+		ObjectMeta: metav1.ObjectMeta{Name: "k8s-test-svc"},
+		Spec: kubeslothv1.ClusterPrometheusServiceLevelSpec{
+			TargetNamespace: "test-ns",
+			PrometheusServiceLevelSpec: kubeslothv1.PrometheusServiceLevelSpec{
+				Service: "test-svc",
+				Labels:  map[string]string{"owner": "myteam"},
+				SLOs: []kubeslothv1.SLO{
+					{Name: "slo1", Objective: 99.9,
+						SLI: kubeslothv1.SLI{Raw: &kubeslothv1.SLIRaw{ErrorRatioQuery: "test_expr_ratio_1"}},
+						Alerting: kubeslothv1.Alerting{
+							PageAlert:   kubeslothv1.Alert{Disable: true},
+							TicketAlert: kubeslothv1.Alert{Disable: true},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		cpsl     *kubeslothv1.ClusterPrometheusServiceLevel
+		expModel *model.PromSLOGroup
+		expErr   bool
+	}{
+		"A cluster spec with an unknown SLI plugin should fail.": {
+			cpsl: &kubeslothv1.ClusterPrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "k8s-test-svc"},
+				Spec: kubeslothv1.ClusterPrometheusServiceLevelSpec{
+					TargetNamespace: "test-ns",
+					PrometheusServiceLevelSpec: kubeslothv1.PrometheusServiceLevelSpec{
+						Service: "test-svc",
+						SLOs: []kubeslothv1.SLO{
+							{Name: "slo1", Objective: 99.9, SLI: kubeslothv1.SLI{Plugin: &kubeslothv1.SLIPlugin{ID: "unknown_plugin"}}},
+						},
+					},
+				},
+			},
+			expErr: true,
+		},
+
+		"A cluster spec should be loaded with the cluster object as the original source.": {
+			cpsl: cpsl,
+			expModel: &model.PromSLOGroup{
+				SLOs: []model.PromSLO{
+					{
+						ID:              "test-svc-slo1",
+						Name:            "slo1",
+						Service:         "test-svc",
+						TimeWindow:      30 * 24 * time.Hour,
+						SLI:             model.PromSLI{Raw: &model.PromSLIRaw{ErrorRatioQuery: "test_expr_ratio_1"}},
+						Objective:       99.9,
+						Labels:          map[string]string{"owner": "myteam"},
+						PageAlertMeta:   model.PromAlertMeta{Disable: true},
+						TicketAlertMeta: model.PromAlertMeta{Disable: true},
+						Plugins:         model.SLOPlugins{Plugins: []model.PromSLOPluginMetadata{}},
+					},
+				},
+				OriginalSource: model.PromSLOGroupSource{K8sSlothClusterV1: cpsl},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			loader := io.NewK8sSlothPrometheusCRSpecLoader(testMemPluginsRepo(nil), 30*24*time.Hour)
+			gotModel, err := loader.LoadClusterSpec(context.TODO(), test.cpsl)
+
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expModel, gotModel)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package io
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/slok/sloth/pkg/common/model"
+)
+
+// SLOPluginTrace is the SLO plugin chain trace of an SLO.
+type SLOPluginTrace struct {
+	// Source is where the SLO comes from (e.g: the spec file).
+	Source string
+	SLOID  string
+	Trace  model.PromSLOPluginTrace
+}
+
+// NewTextSLOPluginTraceRepo returns a new TextSLOPluginTraceRepo.
+func NewTextSLOPluginTraceRepo(writer io.Writer) TextSLOPluginTraceRepo {
+	return TextSLOPluginTraceRepo{writer: writer}
+}
+
+// TextSLOPluginTraceRepo knows how to store the SLO plugin chain traces as a human readable report.
+type TextSLOPluginTraceRepo struct {
+	writer io.Writer
+}
+
+func (r TextSLOPluginTraceRepo) StoreSLOPluginTraces(ctx context.Context, traces []SLOPluginTrace) error {
+	var b strings.Builder
+	for _, t := range traces {
+		if t.Source != "" {
+			fmt.Fprintf(&b, "# %s: SLO %q\n", t.Source, t.SLOID)
+		} else {
+			fmt.Fprintf(&b, "# SLO %q\n", t.SLOID)
+		}
+
+		for i, step := range t.Trace.Steps {
+			fmt.Fprintf(&b, "%d. %s (priority: %d, duration: %s)\n", i+1, step.PluginID, step.Priority, step.Duration)
+			if len(step.RuleChanges) == 0 && len(step.SLOChanges) == 0 {
+				b.WriteString("   No changes\n")
+				continue
+			}
+
+			for _, rc := range step.RuleChanges {
+				if rc.Rule == "" {
+					fmt.Fprintf(&b, "   %s rule group %s\n", changeMark(rc.Type), rc.Group)
+				} else {
+					fmt.Fprintf(&b, "   %s rule %s/%s\n", changeMark(rc.Type), rc.Group, rc.Rule)
+				}
+				writeTextFieldChanges(&b, rc.Fields)
+			}
+
+			if len(step.SLOChanges) > 0 {
+				fmt.Fprintf(&b, "   %s SLO\n", changeMark(model.ChangeTypeModified))
+				writeTextFieldChanges(&b, step.SLOChanges)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(r.writer, b.String())
+	if err != nil {
+		return fmt.Errorf("could not write SLO plugin traces: %w", err)
+	}
+
+	return nil
+}
+
+func writeTextFieldChanges(b *strings.Builder, changes []model.FieldChange) {
+	for _, c := range changes {
+		switch c.Type {
+		case model.ChangeTypeAdded:
+			fmt.Fprintf(b, "       %s %s: %q\n", changeMark(c.Type), c.Path, c.New)
+		case model.ChangeTypeRemoved:
+			fmt.Fprintf(b, "       %s %s: %q\n", changeMark(c.Type), c.Path, c.Old)
+		default:
+			fmt.Fprintf(b, "       %s %s: %q -> %q\n", changeMark(c.Type), c.Path, c.Old, c.New)
+		}
+	}
+}
+
+func changeMark(t model.ChangeType) string {
+	switch t {
+	case model.ChangeTypeAdded:
+		return "+"
+	case model.ChangeTypeRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// NewJSONSLOPluginTraceRepo returns a new JSONSLOPluginTraceRepo.
+func NewJSONSLOPluginTraceRepo(writer io.Writer) JSONSLOPluginTraceRepo {
+	return JSONSLOPluginTraceRepo{writer: writer}
+}
+
+// JSONSLOPluginTraceRepo knows how to store the SLO plugin chain traces in JSON.
+type JSONSLOPluginTraceRepo struct {
+	writer io.Writer
+}
+
+func (r JSONSLOPluginTraceRepo) StoreSLOPluginTraces(ctx context.Context, traces []SLOPluginTrace) error {
+	jTraces := []jsonSLOPluginTrace{}
+	for _, t := range traces {
+		jt := jsonSLOPluginTrace{Source: t.Source, SLOID: t.SLOID, Steps: []jsonSLOPluginTraceStep{}}
+		for _, step := range t.Trace.Steps {
+			js := jsonSLOPluginTraceStep{
+				PluginID:    step.PluginID,
+				Priority:    step.Priority,
+				Duration:    step.Duration.String(),
+				DurationNS:  step.Duration.Nanoseconds(),
+				RuleChanges: []jsonRuleChange{},
+				SLOChanges:  mapJSONFieldChanges(step.SLOChanges),
+			}
+			for _, rc := range step.RuleChanges {
+				js.RuleChanges = append(js.RuleChanges, jsonRuleChange{
+					Group:  rc.Group,
+					Rule:   rc.Rule,
+					Type:   string(rc.Type),
+					Fields: mapJSONFieldChanges(rc.Fields),
+				})
+			}
+			jt.Steps = append(jt.Steps, js)
+		}
+		jTraces = append(jTraces, jt)
+	}
+
+	enc := json.NewEncoder(r.writer)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(jTraces)
+	if err != nil {
+		return fmt.Errorf("could not write SLO plugin traces: %w", err)
+	}
+
+	return nil
+}
+
+func mapJSONFieldChanges(changes []model.FieldChange) []jsonFieldChange {
+	jcs := []jsonFieldChange{}
+	for _, c := range changes {
+		jcs = append(jcs, jsonFieldChange{Path: c.Path, Type: string(c.Type), Old: c.Old, New: c.New})
+	}
+	return jcs
+}
+
+type jsonSLOPluginTrace struct {
+	Source string                   `json:"source,omitempty"`
+	SLOID  string                   `json:"slo_id"`
+	Steps  []jsonSLOPluginTraceStep `json:"steps"`
+}
+
+type jsonSLOPluginTraceStep struct {
+	PluginID    string            `json:"plugin_id"`
+	Priority    int               `json:"priority"`
+	Duration    string            `json:"duration"`
+	DurationNS  int64             `json:"duration_ns"`
+	RuleChanges []jsonRuleChange  `json:"rule_changes"`
+	SLOChanges  []jsonFieldChange `json:"slo_changes"`
+}
+
+type jsonRuleChange struct {
+	Group  string            `json:"group"`
+	Rule   string            `json:"rule,omitempty"`
+	Type   string            `json:"type"`
+	Fields []jsonFieldChange `json:"fields"`
+}
+
+type jsonFieldChange struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
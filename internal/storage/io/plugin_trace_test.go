@@ -0,0 +1,186 @@
+package io_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slok/sloth/internal/storage/io"
+	"github.com/slok/sloth/pkg/common/model"
+)
+
+func getTestSLOPluginTraces() []io.SLOPluginTrace {
+	return []io.SLOPluginTrace{
+		{
+			Source: "slos.yml",
+			SLOID:  "test-id",
+			Trace: model.PromSLOPluginTrace{Steps: []model.PromSLOPluginTraceStep{
+				{PluginID: "test-plugin1", Duration: 42 * time.Millisecond},
+				{
+					PluginID: "test-plugin2",
+					Priority: 10,
+					Duration: time.Second,
+					RuleChanges: []model.PromRuleChange{
+						{Group: "alerts", Type: model.ChangeTypeModified, Fields: []model.FieldChange{
+							{Path: "Interval", Type: model.ChangeTypeAdded, New: "2m0s"},
+						}},
+						{Group: "alerts", Rule: "test-alert", Type: model.ChangeTypeModified, Fields: []model.FieldChange{
+							{Path: "Labels.severity", Type: model.ChangeTypeModified, Old: "page", New: "ticket"},
+						}},
+						{Group: "extra[0]", Rule: "test-record", Type: model.ChangeTypeRemoved, Fields: []model.FieldChange{
+							{Path: "Record", Type: model.ChangeTypeRemoved, Old: "test-record"},
+						}},
+					},
+					SLOChanges: []model.FieldChange{
+						{Path: "Labels.team", Type: model.ChangeTypeAdded, New: "b"},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestTextSLOPluginTraceRepoStore(t *testing.T) {
+	tests := map[string]struct {
+		traces  []io.SLOPluginTrace
+		expText string
+	}{
+		"Having no traces should not render anything.": {
+			traces:  nil,
+			expText: "",
+		},
+
+		"Having traces should render them.": {
+			traces: getTestSLOPluginTraces(),
+			expText: `# slos.yml: SLO "test-id"
+1. test-plugin1 (priority: 0, duration: 42ms)
+   No changes
+2. test-plugin2 (priority: 10, duration: 1s)
+   ~ rule group alerts
+       + Interval: "2m0s"
+   ~ rule alerts/test-alert
+       ~ Labels.severity: "page" -> "ticket"
+   - rule extra[0]/test-record
+       - Record: "test-record"
+   ~ SLO
+       + Labels.team: "b"
+
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var gotText bytes.Buffer
+			repo := io.NewTextSLOPluginTraceRepo(&gotText)
+			err := repo.StoreSLOPluginTraces(context.TODO(), test.traces)
+
+			if assert.NoError(err) {
+				assert.Equal(test.expText, gotText.String())
+			}
+		})
+	}
+}
+
+func TestJSONSLOPluginTraceRepoStore(t *testing.T) {
+	tests := map[string]struct {
+		traces  []io.SLOPluginTrace
+		expJSON string
+	}{
+		"Having no traces should render an empty list.": {
+			traces:  nil,
+			expJSON: "[]\n",
+		},
+
+		"Having traces should render them.": {
+			traces: getTestSLOPluginTraces(),
+			expJSON: `[
+  {
+    "source": "slos.yml",
+    "slo_id": "test-id",
+    "steps": [
+      {
+        "plugin_id": "test-plugin1",
+        "priority": 0,
+        "duration": "42ms",
+        "duration_ns": 42000000,
+        "rule_changes": [],
+        "slo_changes": []
+      },
+      {
+        "plugin_id": "test-plugin2",
+        "priority": 10,
+        "duration": "1s",
+        "duration_ns": 1000000000,
+        "rule_changes": [
+          {
+            "group": "alerts",
+            "type": "modified",
+            "fields": [
+              {
+                "path": "Interval",
+                "type": "added",
+                "new": "2m0s"
+              }
+            ]
+          },
+          {
+            "group": "alerts",
+            "rule": "test-alert",
+            "type": "modified",
+            "fields": [
+              {
+                "path": "Labels.severity",
+                "type": "modified",
+                "old": "page",
+                "new": "ticket"
+              }
+            ]
+          },
+          {
+            "group": "extra[0]",
+            "rule": "test-record",
+            "type": "removed",
+            "fields": [
+              {
+                "path": "Record",
+                "type": "removed",
+                "old": "test-record"
+              }
+            ]
+          }
+        ],
+        "slo_changes": [
+          {
+            "path": "Labels.team",
+            "type": "added",
+            "new": "b"
+          }
+        ]
+      }
+    ]
+  }
+]
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var gotJSON bytes.Buffer
+			repo := io.NewJSONSLOPluginTraceRepo(&gotJSON)
+			err := repo.StoreSLOPluginTraces(context.TODO(), test.traces)
+
+			if assert.NoError(err) {
+				assert.Equal(test.expJSON, gotJSON.String())
+			}
+		})
+	}
+}
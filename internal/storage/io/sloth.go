@@ -2,6 +2,7 @@ package io
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"time"
@@ -151,6 +152,11 @@ func (l SlothPrometheusYAMLSpecLoader) MapSpecToModel(ctx context.Context, spec
 				prometheuspluginv1.SLIPluginMetaObjective: fmt.Sprintf("%f", specSLO.Objective),
 			}
 
+			err = validateSLIPluginOptions(*plugin, specSLO.SLI.Plugin.Options)
+			if err != nil {
+				return nil, err
+			}
+
 			rawQuery, err := plugin.Func(ctx, meta, spec.Labels, specSLO.SLI.Plugin.Options)
 			if err != nil {
 				return nil, fmt.Errorf("plugin %q execution error: %w", specSLO.SLI.Plugin.ID, err)
@@ -186,3 +192,18 @@ func (l SlothPrometheusYAMLSpecLoader) MapSpecToModel(ctx context.Context, spec
 		OriginalSource: model.PromSLOGroupSource{SlothV1: &spec},
 	}, nil
 }
+
+// validateSLIPluginOptions validates the SLI plugin options against the plugin config schema, if any.
+func validateSLIPluginOptions(plugin pluginenginesli.SLIPlugin, options map[string]string) error {
+	data, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("could not marshal plugin %q options: %w", plugin.ID, err)
+	}
+
+	err = plugin.Metadata.ValidateConfig(data)
+	if err != nil {
+		return fmt.Errorf("invalid plugin %q options: %w", plugin.ID, err)
+	}
+
+	return nil
+}
@@ -86,6 +86,19 @@ func (r StdPrometheusGroupedRulesYAMLRepo) StoreSLOs(ctx context.Context, slos m
 		}
 	}
 
+	// SLO group extra rules.
+	for _, extraRuleGroup := range slos.ExtraRules {
+		if len(extraRuleGroup.Rules) == 0 {
+			continue
+		}
+
+		ruleGroups.Groups = append(ruleGroups.Groups, stdPromRuleGroupYAMLv2{
+			Interval: prommodel.Duration(extraRuleGroup.Interval),
+			Name:     extraRuleGroup.Name,
+			Rules:    extraRuleGroup.Rules,
+		})
+	}
+
 	// If we don't have anything to store, error so we can increase the reliability
 	// because maybe this was due to an unintended error (typos, misconfig, too many disable...).
 	if len(ruleGroups.Groups) == 0 {
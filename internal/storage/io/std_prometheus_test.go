@@ -63,6 +63,46 @@ groups:
       test-label: one
 `,
 		},
+		"Having SLO group extra rules should render them after the SLO rules.": {
+			slos: model.PromSLOGroupResult{
+				SLOResults: []model.PromSLOResult{
+					{
+						SLO: model.PromSLO{ID: "test1"},
+						PrometheusRules: model.PromSLORules{
+							SLIErrorRecRules: model.PromRuleGroup{
+								Name:  "sloth-slo-sli-recordings-test1",
+								Rules: []rulefmt.Rule{{Record: "test:record", Expr: "test-expr"}},
+							},
+						},
+					},
+				},
+				ExtraRules: []model.PromRuleGroup{
+					{
+						Name:     "sloth-slo-group-rollup",
+						Interval: 2 * time.Minute,
+						Rules:    []rulefmt.Rule{{Record: "test:rollup", Expr: "test-expr-rollup"}},
+					},
+					{Name: "sloth-slo-group-empty"}, // Should be skipped.
+				},
+			},
+			expYAML: `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# DO NOT EDIT.
+
+groups:
+- name: sloth-slo-sli-recordings-test1
+  rules:
+  - record: test:record
+    expr: test-expr
+- name: sloth-slo-group-rollup
+  interval: 2m
+  rules:
+  - record: test:rollup
+    expr: test-expr-rollup
+`,
+		},
+
 		"Having a single metadata recording rule should render correctly.": {
 			slos: model.PromSLOGroupResult{SLOResults: []model.PromSLOResult{
 				{
@@ -2,8 +2,13 @@ package k8s
 
 import (
 	"context"
+	"fmt"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/slok/sloth/internal/log"
@@ -32,12 +37,93 @@ func (r DryRunApiserverRepository) WatchPrometheusServiceLevels(ctx context.Cont
 	return r.svc.WatchPrometheusServiceLevels(ctx, ns, opts)
 }
 
-func (r DryRunApiserverRepository) EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, err error) error {
+func (r DryRunApiserverRepository) EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, status slothv1.PrometheusServiceLevelStatus) error {
 	r.logger.Infof("Dry run EnsurePrometheusServiceLevelStatus")
 	return nil
 }
 
+func (r DryRunApiserverRepository) ListClusterPrometheusServiceLevels(ctx context.Context, opts metav1.ListOptions) (*slothv1.ClusterPrometheusServiceLevelList, error) {
+	return r.svc.ListClusterPrometheusServiceLevels(ctx, opts)
+}
+
+func (r DryRunApiserverRepository) WatchClusterPrometheusServiceLevels(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.svc.WatchClusterPrometheusServiceLevels(ctx, opts)
+}
+
+func (r DryRunApiserverRepository) EnsureClusterPrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.ClusterPrometheusServiceLevel, status slothv1.PrometheusServiceLevelStatus) error {
+	r.logger.Infof("Dry run EnsureClusterPrometheusServiceLevelStatus")
+	return nil
+}
+
+func (r DryRunApiserverRepository) GetPrometheusServiceLevelTemplate(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevelTemplate, error) {
+	return r.svc.GetPrometheusServiceLevelTemplate(ctx, ns, name)
+}
+
+func (r DryRunApiserverRepository) ListPrometheusServiceLevelTemplates(ctx context.Context, ns string, opts metav1.ListOptions) (*slothv1.PrometheusServiceLevelTemplateList, error) {
+	return r.svc.ListPrometheusServiceLevelTemplates(ctx, ns, opts)
+}
+
+func (r DryRunApiserverRepository) WatchPrometheusServiceLevelTemplates(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.svc.WatchPrometheusServiceLevelTemplates(ctx, ns, opts)
+}
+
+func (r DryRunApiserverRepository) GetPrometheusServiceLevel(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevel, error) {
+	return r.svc.GetPrometheusServiceLevel(ctx, ns, name)
+}
+
+func (r DryRunApiserverRepository) EnsurePrometheusServiceLevel(ctx context.Context, psl *slothv1.PrometheusServiceLevel) error {
+	r.logger.Infof("Dry run EnsurePrometheusServiceLevel")
+	return nil
+}
+
+func (r DryRunApiserverRepository) DeletePrometheusServiceLevel(ctx context.Context, ns, name string) error {
+	r.logger.Infof("Dry run DeletePrometheusServiceLevel")
+	return nil
+}
+
+func (r DryRunApiserverRepository) ListServices(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.ServiceList, error) {
+	return r.svc.ListServices(ctx, ns, opts)
+}
+
+func (r DryRunApiserverRepository) WatchServices(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.svc.WatchServices(ctx, ns, opts)
+}
+
+func (r DryRunApiserverRepository) ListDeployments(ctx context.Context, ns string, opts metav1.ListOptions) (*appsv1.DeploymentList, error) {
+	return r.svc.ListDeployments(ctx, ns, opts)
+}
+
+func (r DryRunApiserverRepository) WatchDeployments(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.svc.WatchDeployments(ctx, ns, opts)
+}
+
+func (r DryRunApiserverRepository) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	return r.svc.GetNamespace(ctx, name)
+}
+
 func (r DryRunApiserverRepository) StoreSLOs(ctx context.Context, kmeta model.K8sMeta, slos model.PromSLOGroupResult) error {
 	r.logger.Infof("Dry run StoreSLOs")
 	return nil
 }
+
+type DryRunEventRecorder struct {
+	logger log.Logger
+}
+
+// NewDryRunEventRecorder returns a new Kubernetes event recorder that will only log the events.
+func NewDryRunEventRecorder(logger log.Logger) DryRunEventRecorder {
+	return DryRunEventRecorder{
+		logger: logger.WithValues(log.Kv{"service": "storage.k8s.DryRunEventRecorder"}),
+	}
+}
+
+func (r DryRunEventRecorder) Eventf(obj runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	object := ""
+	if m, err := meta.Accessor(obj); err == nil {
+		object = m.GetNamespace() + "/" + m.GetName()
+	}
+	r.logger.Infof("Dry run event %s %s on %s: %s", eventType, reason, object, fmt.Sprintf(messageFmt, args...))
+}
+
+// Shutdown is a noop, the dry run events are not sent anywhere.
+func (r DryRunEventRecorder) Shutdown() {}
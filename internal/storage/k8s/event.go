@@ -0,0 +1,71 @@
+package k8s
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/slok/sloth/internal/log"
+	slothscheme "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/scheme"
+)
+
+type EventRecorderConfig struct {
+	KubeCli kubernetes.Interface
+	// Component is the source component of the events.
+	Component string
+	Logger    log.Logger
+}
+
+func (c *EventRecorderConfig) defaults() error {
+	if c.KubeCli == nil {
+		return fmt.Errorf("kubernetes CLI must be set")
+	}
+
+	if c.Component == "" {
+		c.Component = "sloth"
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "storage.k8s.EventRecorder"})
+
+	return nil
+}
+
+// EventRecorder records Kubernetes events on the apiserver. The repeated events are deduplicated
+// (aggregated increasing the event count) and rate limited before being sent to the apiserver.
+type EventRecorder struct {
+	broadcaster record.EventBroadcaster
+	recorder    record.EventRecorder
+}
+
+// NewEventRecorder returns a new Kubernetes event recorder.
+func NewEventRecorder(config EventRecorderConfig) (*EventRecorder, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(config.Logger.Debugf)
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: config.KubeCli.CoreV1().Events("")})
+
+	return &EventRecorder{
+		broadcaster: broadcaster,
+		recorder:    broadcaster.NewRecorder(slothscheme.Scheme, corev1.EventSource{Component: config.Component}),
+	}, nil
+}
+
+func (r EventRecorder) Eventf(obj runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	r.recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
+
+// Shutdown stops sending the recorded events to the apiserver.
+func (r EventRecorder) Shutdown() {
+	r.broadcaster.Shutdown()
+}
@@ -3,12 +3,18 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"sync"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
 	fakediscovery "k8s.io/client-go/discovery/fake"
 	fakedynamic "k8s.io/client-go/dynamic/fake"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/slok/sloth/internal/log"
@@ -28,7 +34,10 @@ func NewFakeApiserverRepository(logger log.Logger, k8sTransformPlugin plugink8st
 	// Setup fake dynamic client with ConfigMap scheme.
 	// Important: When adding new k8s transform plugins we need to add their
 	// resources to the fake discovery client to be able to fake them.
-	dynamicCli := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	dynamicCli := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "configmaps"}:                                      "ConfigMapList",
+		{Group: "monitoring.coreos.com", Version: "v1", Resource: "prometheusrules"}: "PrometheusRuleList",
+	})
 	fakeDiscovery := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
 		{
 			GroupVersion: "v1",
@@ -45,6 +54,7 @@ func NewFakeApiserverRepository(logger log.Logger, k8sTransformPlugin plugink8st
 	}}}
 
 	c, err := NewApiserverRepository(ApiserverRepositoryConfig{
+		KubeCli:            kubernetesfake.NewClientset(append(namespaceFakes, autoSLOWorkloadFakes...)...),
 		SlothCli:           slothclientsetfake.NewClientset(prometheusServiceLevelFakes...),
 		DynamicCli:         dynamicCli,
 		DiscoveryCli:       fakeDiscovery,
@@ -68,14 +78,132 @@ func (r FakeApiserverRepository) WatchPrometheusServiceLevels(ctx context.Contex
 	return r.ksvc.WatchPrometheusServiceLevels(ctx, ns, opts)
 }
 
-func (r FakeApiserverRepository) EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, err error) error {
-	return r.ksvc.EnsurePrometheusServiceLevelStatus(ctx, slo, err)
+func (r FakeApiserverRepository) EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, status slothv1.PrometheusServiceLevelStatus) error {
+	return r.ksvc.EnsurePrometheusServiceLevelStatus(ctx, slo, status)
+}
+
+func (r FakeApiserverRepository) ListClusterPrometheusServiceLevels(ctx context.Context, opts metav1.ListOptions) (*slothv1.ClusterPrometheusServiceLevelList, error) {
+	return r.ksvc.ListClusterPrometheusServiceLevels(ctx, opts)
+}
+
+func (r FakeApiserverRepository) WatchClusterPrometheusServiceLevels(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.ksvc.WatchClusterPrometheusServiceLevels(ctx, opts)
+}
+
+func (r FakeApiserverRepository) EnsureClusterPrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.ClusterPrometheusServiceLevel, status slothv1.PrometheusServiceLevelStatus) error {
+	return r.ksvc.EnsureClusterPrometheusServiceLevelStatus(ctx, slo, status)
+}
+
+func (r FakeApiserverRepository) GetPrometheusServiceLevelTemplate(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevelTemplate, error) {
+	return r.ksvc.GetPrometheusServiceLevelTemplate(ctx, ns, name)
+}
+
+func (r FakeApiserverRepository) ListPrometheusServiceLevelTemplates(ctx context.Context, ns string, opts metav1.ListOptions) (*slothv1.PrometheusServiceLevelTemplateList, error) {
+	return r.ksvc.ListPrometheusServiceLevelTemplates(ctx, ns, opts)
+}
+
+func (r FakeApiserverRepository) WatchPrometheusServiceLevelTemplates(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.ksvc.WatchPrometheusServiceLevelTemplates(ctx, ns, opts)
+}
+
+func (r FakeApiserverRepository) GetPrometheusServiceLevel(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevel, error) {
+	return r.ksvc.GetPrometheusServiceLevel(ctx, ns, name)
+}
+
+func (r FakeApiserverRepository) EnsurePrometheusServiceLevel(ctx context.Context, psl *slothv1.PrometheusServiceLevel) error {
+	return r.ksvc.EnsurePrometheusServiceLevel(ctx, psl)
+}
+
+func (r FakeApiserverRepository) DeletePrometheusServiceLevel(ctx context.Context, ns, name string) error {
+	return r.ksvc.DeletePrometheusServiceLevel(ctx, ns, name)
+}
+
+func (r FakeApiserverRepository) ListServices(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.ServiceList, error) {
+	return r.ksvc.ListServices(ctx, ns, opts)
+}
+
+func (r FakeApiserverRepository) WatchServices(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.ksvc.WatchServices(ctx, ns, opts)
+}
+
+func (r FakeApiserverRepository) ListDeployments(ctx context.Context, ns string, opts metav1.ListOptions) (*appsv1.DeploymentList, error) {
+	return r.ksvc.ListDeployments(ctx, ns, opts)
+}
+
+func (r FakeApiserverRepository) WatchDeployments(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.ksvc.WatchDeployments(ctx, ns, opts)
+}
+
+func (r FakeApiserverRepository) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	return r.ksvc.GetNamespace(ctx, name)
 }
 
 func (r FakeApiserverRepository) StoreSLOs(ctx context.Context, kmeta model.K8sMeta, slos model.PromSLOGroupResult) error {
 	return r.ksvc.StoreSLOs(ctx, kmeta, slos)
 }
 
+// FakeEvent is a Kubernetes event recorded by the FakeEventRecorder.
+type FakeEvent struct {
+	// Object is the `namespace/name` of the event object.
+	Object  string
+	Type    string
+	Reason  string
+	Message string
+	// Count is the number of times the event has been recorded.
+	Count int
+}
+
+// FakeEventRecorder is a Kubernetes event recorder that stores the events in memory instead of
+// sending them to the apiserver. Like the real recorder, repeated events are deduplicated
+// increasing the event count.
+type FakeEventRecorder struct {
+	mu     sync.Mutex
+	events []FakeEvent
+	logger log.Logger
+}
+
+// NewFakeEventRecorder returns a new fake Kubernetes event recorder.
+func NewFakeEventRecorder(logger log.Logger) *FakeEventRecorder {
+	if logger == nil {
+		logger = log.Noop
+	}
+
+	return &FakeEventRecorder{
+		logger: logger.WithValues(log.Kv{"service": "storage.k8s.FakeEventRecorder"}),
+	}
+}
+
+func (r *FakeEventRecorder) Eventf(obj runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	object := ""
+	if m, err := meta.Accessor(obj); err == nil {
+		object = m.GetNamespace() + "/" + m.GetName()
+	}
+	message := fmt.Sprintf(messageFmt, args...)
+	r.logger.Debugf("Event %s %s on %s: %s", eventType, reason, object, message)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, e := range r.events {
+		if e.Object == object && e.Type == eventType && e.Reason == reason && e.Message == message {
+			r.events[i].Count++
+			return
+		}
+	}
+	r.events = append(r.events, FakeEvent{Object: object, Type: eventType, Reason: reason, Message: message, Count: 1})
+}
+
+// Shutdown is a noop, the fake events are not sent anywhere.
+func (r *FakeEventRecorder) Shutdown() {}
+
+// Events returns the recorded events in the order they were first recorded.
+func (r *FakeEventRecorder) Events() []FakeEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]FakeEvent{}, r.events...)
+}
+
 var prometheusServiceLevelFakes = []runtime.Object{
 	&slothv1.PrometheusServiceLevel{
 		ObjectMeta: metav1.ObjectMeta{
@@ -130,4 +258,82 @@ sum(rate(http_request_duration_seconds_count{job="myservice2"}[{{.window}}]))
 			},
 		},
 	},
+	&slothv1.ClusterPrometheusServiceLevel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "fake-cluster01",
+		},
+		Spec: slothv1.ClusterPrometheusServiceLevelSpec{
+			TargetNamespace: "monitoring",
+			PrometheusServiceLevelSpec: slothv1.PrometheusServiceLevelSpec{
+				Service: "cluster-svc01",
+				SLOs: []slothv1.SLO{
+					{
+						Name:      "slo01",
+						Objective: 99.9,
+						SLI: slothv1.SLI{Events: &slothv1.SLIEvents{
+							ErrorQuery: `sum(rate(apiserver_request_total{code=~"(5..|429)"}[{{.window}}]))`,
+							TotalQuery: `sum(rate(apiserver_request_total[{{.window}}]))`,
+						}},
+						Alerting: slothv1.Alerting{
+							PageAlert:   slothv1.Alert{Disable: true},
+							TicketAlert: slothv1.Alert{Disable: true},
+						},
+					},
+				},
+			},
+		},
+	},
+	&slothv1.PrometheusServiceLevelTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "fake-http",
+		},
+		Spec: slothv1.PrometheusServiceLevelTemplateSpec{
+			Params: []slothv1.SLOTemplateParam{
+				{Name: "job"},
+			},
+			SLOs: []slothv1.SLO{
+				{
+					Name:      "${job}-availability",
+					Objective: 99.9,
+					SLI: slothv1.SLI{Events: &slothv1.SLIEvents{
+						ErrorQuery: `sum(rate(http_request_duration_seconds_count{job="${job}",code=~"(5..|429)"}[{{.window}}]))`,
+						TotalQuery: `sum(rate(http_request_duration_seconds_count{job="${job}"}[{{.window}}]))`,
+					}},
+					Alerting: slothv1.Alerting{
+						PageAlert:   slothv1.Alert{Disable: true},
+						TicketAlert: slothv1.Alert{Disable: true},
+					},
+				},
+			},
+		},
+	},
+	&slothv1.PrometheusServiceLevel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "fake-templated01",
+		},
+		Spec: slothv1.PrometheusServiceLevelSpec{
+			Service: "svc02",
+			Templates: []slothv1.SLOTemplateInstance{
+				{Name: "fake-http", Params: map[string]string{"job": "myservice3"}},
+			},
+		},
+	},
+}
+
+var namespaceFakes = []runtime.Object{
+	&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+}
+
+var autoSLOWorkloadFakes = []runtime.Object{
+	&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "fake-app01",
+			UID:  "fake-app01-uid",
+			Annotations: map[string]string{
+				"sloth.dev/slo-template":  "fake-http",
+				"sloth.dev/objective":     "99.5",
+				"sloth.dev/slo-param-job": "fake-app01",
+			},
+		},
+	},
 }
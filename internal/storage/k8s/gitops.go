@@ -0,0 +1,520 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/slok/sloth/internal/log"
+	storageio "github.com/slok/sloth/internal/storage/io"
+	"github.com/slok/sloth/pkg/common/model"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+)
+
+const (
+	gitOpsPSLFilePrefix  = "prometheusservicelevel_"
+	gitOpsCPSLFilePrefix = "clusterprometheusservicelevel_"
+	gitOpsFileExt        = ".yml"
+)
+
+// GitOpsRepositoryConfig is the GitOps repository configuration.
+type GitOpsRepositoryConfig struct {
+	// Repository is the repository used for all the operations except storing the generated SLOs.
+	Repository ApiserverRepository
+	// RulesDir is the directory where the Prometheus rule files will be written.
+	RulesDir string
+	// PrometheusReloadURL is the Prometheus reload URL (e.g: `http://prometheus:9090/-/reload`) called
+	// after the rule files change, disabled if empty.
+	PrometheusReloadURL string
+	HTTPClient          *http.Client
+	// Namespace is the namespace of the PrometheusServiceLevels whose rule files are kept, by default all.
+	Namespace string
+	// LabelSelector is the label selector of the service levels whose rule files are kept.
+	LabelSelector labels.Selector
+	// NamespaceLabelSelector is the label selector of the namespaces whose PrometheusServiceLevels rule files
+	// are kept, ClusterPrometheusServiceLevels are not affected by this selector.
+	NamespaceLabelSelector labels.Selector
+	// DisableClusterServiceLevels removes the rule files of the ClusterPrometheusServiceLevels.
+	DisableClusterServiceLevels bool
+	// CleanInterval is the interval between the stale rule files cleanups.
+	CleanInterval time.Duration
+	Logger        log.Logger
+}
+
+func (c *GitOpsRepositoryConfig) defaults() error {
+	if c.RulesDir == "" {
+		return fmt.Errorf("rules directory is required")
+	}
+
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if c.LabelSelector == nil {
+		c.LabelSelector = labels.Everything()
+	}
+
+	if c.NamespaceLabelSelector == nil {
+		c.NamespaceLabelSelector = labels.Everything()
+	}
+
+	if c.CleanInterval <= 0 {
+		c.CleanInterval = 1 * time.Minute
+	}
+
+	if c.Logger == nil {
+		c.Logger = log.Noop
+	}
+	c.Logger = c.Logger.WithValues(log.Kv{"service": "storage.k8s.GitOpsRepository"})
+
+	return nil
+}
+
+// GitOpsRepository is a Kubernetes repository that instead of storing the generated SLOs as Kubernetes
+// objects, it writes them as Prometheus rule files on a directory (one file per service level), so they
+// can be used by Prometheus instances running outside Kubernetes (e.g: synced with Git). The rest of the
+// operations use the Kubernetes apiserver.
+type GitOpsRepository struct {
+	svc                 ApiserverRepository
+	rulesDir            string
+	promReloadURL       string
+	httpCli             *http.Client
+	namespace           string
+	labelSelector       labels.Selector
+	nsLabelSelector     labels.Selector
+	disableCluster      bool
+	cleanInterval       time.Duration
+	logger              log.Logger
+	mu                  sync.Mutex
+	promReloadRequested bool
+}
+
+// NewGitOpsRepository returns a new GitOps repository.
+func NewGitOpsRepository(config GitOpsRepositoryConfig) (*GitOpsRepository, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	err = os.MkdirAll(config.RulesDir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create rules directory: %w", err)
+	}
+
+	return &GitOpsRepository{
+		svc:             config.Repository,
+		rulesDir:        config.RulesDir,
+		promReloadURL:   config.PrometheusReloadURL,
+		httpCli:         config.HTTPClient,
+		namespace:       config.Namespace,
+		labelSelector:   config.LabelSelector,
+		nsLabelSelector: config.NamespaceLabelSelector,
+		disableCluster:  config.DisableClusterServiceLevels,
+		cleanInterval:   config.CleanInterval,
+		logger:          config.Logger,
+	}, nil
+}
+
+func (r *GitOpsRepository) ListPrometheusServiceLevels(ctx context.Context, ns string, opts metav1.ListOptions) (*slothv1.PrometheusServiceLevelList, error) {
+	return r.svc.ListPrometheusServiceLevels(ctx, ns, opts)
+}
+
+func (r *GitOpsRepository) WatchPrometheusServiceLevels(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.svc.WatchPrometheusServiceLevels(ctx, ns, opts)
+}
+
+func (r *GitOpsRepository) EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, status slothv1.PrometheusServiceLevelStatus) error {
+	return r.svc.EnsurePrometheusServiceLevelStatus(ctx, slo, status)
+}
+
+func (r *GitOpsRepository) ListClusterPrometheusServiceLevels(ctx context.Context, opts metav1.ListOptions) (*slothv1.ClusterPrometheusServiceLevelList, error) {
+	return r.svc.ListClusterPrometheusServiceLevels(ctx, opts)
+}
+
+func (r *GitOpsRepository) WatchClusterPrometheusServiceLevels(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.svc.WatchClusterPrometheusServiceLevels(ctx, opts)
+}
+
+func (r *GitOpsRepository) EnsureClusterPrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.ClusterPrometheusServiceLevel, status slothv1.PrometheusServiceLevelStatus) error {
+	return r.svc.EnsureClusterPrometheusServiceLevelStatus(ctx, slo, status)
+}
+
+func (r *GitOpsRepository) GetPrometheusServiceLevelTemplate(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevelTemplate, error) {
+	return r.svc.GetPrometheusServiceLevelTemplate(ctx, ns, name)
+}
+
+func (r *GitOpsRepository) ListPrometheusServiceLevelTemplates(ctx context.Context, ns string, opts metav1.ListOptions) (*slothv1.PrometheusServiceLevelTemplateList, error) {
+	return r.svc.ListPrometheusServiceLevelTemplates(ctx, ns, opts)
+}
+
+func (r *GitOpsRepository) WatchPrometheusServiceLevelTemplates(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.svc.WatchPrometheusServiceLevelTemplates(ctx, ns, opts)
+}
+
+func (r *GitOpsRepository) GetPrometheusServiceLevel(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevel, error) {
+	return r.svc.GetPrometheusServiceLevel(ctx, ns, name)
+}
+
+func (r *GitOpsRepository) EnsurePrometheusServiceLevel(ctx context.Context, psl *slothv1.PrometheusServiceLevel) error {
+	return r.svc.EnsurePrometheusServiceLevel(ctx, psl)
+}
+
+func (r *GitOpsRepository) DeletePrometheusServiceLevel(ctx context.Context, ns, name string) error {
+	return r.svc.DeletePrometheusServiceLevel(ctx, ns, name)
+}
+
+func (r *GitOpsRepository) ListServices(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.ServiceList, error) {
+	return r.svc.ListServices(ctx, ns, opts)
+}
+
+func (r *GitOpsRepository) WatchServices(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.svc.WatchServices(ctx, ns, opts)
+}
+
+func (r *GitOpsRepository) ListDeployments(ctx context.Context, ns string, opts metav1.ListOptions) (*appsv1.DeploymentList, error) {
+	return r.svc.ListDeployments(ctx, ns, opts)
+}
+
+func (r *GitOpsRepository) WatchDeployments(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.svc.WatchDeployments(ctx, ns, opts)
+}
+
+func (r *GitOpsRepository) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	return r.svc.GetNamespace(ctx, name)
+}
+
+// StoreSLOs writes the SLOs Prometheus rules on the service level rule file, if the service level doesn't
+// have any rule the file will be removed.
+func (r *GitOpsRepository) StoreSLOs(ctx context.Context, kmeta model.K8sMeta, slos model.PromSLOGroupResult) error {
+	var fileName string
+	switch {
+	case slos.OriginalSource.K8sSlothV1 != nil:
+		fileName = gitOpsPSLFileName(slos.OriginalSource.K8sSlothV1.Namespace, slos.OriginalSource.K8sSlothV1.Name)
+	case slos.OriginalSource.K8sSlothClusterV1 != nil:
+		fileName = gitOpsCPSLFileName(slos.OriginalSource.K8sSlothClusterV1.Name)
+	default:
+		return fmt.Errorf("SLOs without Kubernetes original source can't be stored")
+	}
+	logger := r.logger.WithCtxValues(ctx).WithValues(log.Kv{"file": fileName})
+
+	var b bytes.Buffer
+	err := storageio.NewStdPrometheusGroupedRulesYAMLRepo(&b, logger).StoreSLOs(ctx, slos)
+	if err != nil {
+		if !errors.Is(err, storageio.ErrNoSLORules) {
+			return fmt.Errorf("could not render Prometheus rules: %w", err)
+		}
+
+		err := r.removeRuleFile(ctx, fileName)
+		if err != nil {
+			return err
+		}
+		return r.reloadPrometheus(ctx)
+	}
+
+	path := filepath.Join(r.rulesDir, fileName)
+	current, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not read rule file %q: %w", path, err)
+	}
+
+	if err == nil && bytes.Equal(current, b.Bytes()) {
+		logger.Debugf("Rule file is up to date")
+		return r.reloadPrometheus(ctx)
+	}
+
+	err = writeFileAtomically(path, b.Bytes())
+	if err != nil {
+		return fmt.Errorf("could not write rule file %q: %w", path, err)
+	}
+	logger.Debugf("Rule file has been written")
+	r.requestPrometheusReload()
+
+	return r.reloadPrometheus(ctx)
+}
+
+// Run removes the rule files of the service levels when they are deleted, and removes the stale rule
+// files on every clean interval until the context is done.
+func (r *GitOpsRepository) Run(ctx context.Context) error {
+	informers := []cache.Controller{r.newDeleteInformer(ctx, &slothv1.PrometheusServiceLevel{}, cache.ToListWatcherWithWatchListSemantics(&cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = r.labelSelector.String()
+			return r.svc.ListPrometheusServiceLevels(ctx, r.namespace, options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = r.labelSelector.String()
+			return r.svc.WatchPrometheusServiceLevels(ctx, r.namespace, options)
+		},
+	}, r.svc.slothCli))}
+	if !r.disableCluster {
+		informers = append(informers, r.newDeleteInformer(ctx, &slothv1.ClusterPrometheusServiceLevel{}, cache.ToListWatcherWithWatchListSemantics(&cache.ListWatch{
+			ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = r.labelSelector.String()
+				return r.svc.ListClusterPrometheusServiceLevels(ctx, options)
+			},
+			WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = r.labelSelector.String()
+				return r.svc.WatchClusterPrometheusServiceLevels(ctx, options)
+			},
+		}, r.svc.slothCli)))
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for _, informer := range informers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			informer.RunWithContext(ctx)
+		}()
+	}
+
+	for {
+		err := r.CleanRuleFiles(ctx)
+		if err != nil {
+			r.logger.Errorf("Could not clean stale rule files: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(r.cleanInterval):
+		}
+	}
+}
+
+// newDeleteInformer returns an informer that removes the rule files of the deleted service levels.
+func (r *GitOpsRepository) newDeleteInformer(ctx context.Context, objType runtime.Object, lw cache.ListerWatcher) cache.Controller {
+	_, informer := cache.NewInformerWithOptions(cache.InformerOptions{
+		ListerWatcher: lw,
+		ObjectType:    objType,
+		Handler: cache.ResourceEventHandlerFuncs{
+			DeleteFunc: func(obj any) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+
+				var fileName string
+				switch sl := obj.(type) {
+				case *slothv1.PrometheusServiceLevel:
+					fileName = gitOpsPSLFileName(sl.Namespace, sl.Name)
+				case *slothv1.ClusterPrometheusServiceLevel:
+					fileName = gitOpsCPSLFileName(sl.Name)
+				default:
+					return
+				}
+
+				err := r.removeRuleFile(ctx, fileName)
+				if err == nil {
+					err = r.reloadPrometheus(ctx)
+				}
+				if err != nil {
+					r.logger.WithValues(log.Kv{"file": fileName}).Errorf("Could not remove deleted service level rule file: %v", err)
+				}
+			},
+		},
+	})
+
+	return informer
+}
+
+// CleanRuleFiles removes the rule files of the service levels that don't exist anymore (e.g: deleted) or
+// are not selected by the label selectors. Only the rule files managed by the repository are removed, the
+// rest of the directory files are ignored.
+func (r *GitOpsRepository) CleanRuleFiles(ctx context.Context) error {
+	// Get the rule files before listing the service levels, this way the rule files of the service
+	// levels created while listing are not removed.
+	entries, err := os.ReadDir(r.rulesDir)
+	if err != nil {
+		return fmt.Errorf("could not read rules directory: %w", err)
+	}
+
+	opts := metav1.ListOptions{LabelSelector: r.labelSelector.String()}
+	keep := map[string]bool{}
+
+	psls, err := r.svc.ListPrometheusServiceLevels(ctx, r.namespace, opts)
+	if err != nil {
+		return fmt.Errorf("could not list PrometheusServiceLevels: %w", err)
+	}
+	selectedNamespaces := map[string]bool{}
+	for _, psl := range psls.Items {
+		selected, ok := selectedNamespaces[psl.Namespace]
+		if !ok {
+			selected, err = r.isNamespaceSelected(ctx, psl.Namespace)
+			if err != nil {
+				return err
+			}
+			selectedNamespaces[psl.Namespace] = selected
+		}
+
+		if selected {
+			keep[gitOpsPSLFileName(psl.Namespace, psl.Name)] = true
+		}
+	}
+
+	if !r.disableCluster {
+		cpsls, err := r.svc.ListClusterPrometheusServiceLevels(ctx, opts)
+		if err != nil && !kubeerrors.IsNotFound(err) {
+			return fmt.Errorf("could not list ClusterPrometheusServiceLevels: %w", err)
+		}
+		if err == nil {
+			for _, cpsl := range cpsls.Items {
+				keep[gitOpsCPSLFileName(cpsl.Name)] = true
+			}
+		}
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		managed := strings.HasPrefix(name, gitOpsPSLFilePrefix) || strings.HasPrefix(name, gitOpsCPSLFilePrefix)
+		if e.IsDir() || !managed || !strings.HasSuffix(name, gitOpsFileExt) || keep[name] {
+			continue
+		}
+
+		err := r.removeRuleFile(ctx, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.reloadPrometheus(ctx)
+}
+
+func (r *GitOpsRepository) isNamespaceSelected(ctx context.Context, name string) (bool, error) {
+	if r.nsLabelSelector.Empty() {
+		return true, nil
+	}
+
+	ns, err := r.svc.GetNamespace(ctx, name)
+	if err != nil {
+		// If the namespace is gone, its objects will be gone soon.
+		if kubeerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("could not get %q namespace: %w", name, err)
+	}
+
+	return r.nsLabelSelector.Matches(labels.Set(ns.Labels)), nil
+}
+
+func (r *GitOpsRepository) removeRuleFile(ctx context.Context, fileName string) error {
+	path := filepath.Join(r.rulesDir, fileName)
+	err := os.Remove(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("could not remove rule file %q: %w", path, err)
+	}
+
+	r.logger.WithCtxValues(ctx).WithValues(log.Kv{"file": fileName}).Infof("Rule file has been removed")
+	r.requestPrometheusReload()
+
+	return nil
+}
+
+func (r *GitOpsRepository) requestPrometheusReload() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.promReloadRequested = true
+}
+
+// reloadPrometheus reloads Prometheus if the rule files changed since the last successful reload, this
+// way a failed reload is retried on the next operation even if the rule files didn't change again.
+func (r *GitOpsRepository) reloadPrometheus(ctx context.Context) error {
+	if r.promReloadURL == "" {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.promReloadRequested {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.promReloadURL, nil)
+	if err != nil {
+		return fmt.Errorf("could not create Prometheus reload request: %w", err)
+	}
+	resp, err := r.httpCli.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not reload Prometheus: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("could not reload Prometheus: unexpected %d status code", resp.StatusCode)
+	}
+
+	r.promReloadRequested = false
+	r.logger.WithCtxValues(ctx).Infof("Prometheus has been reloaded")
+
+	return nil
+}
+
+func gitOpsPSLFileName(ns, name string) string {
+	return gitOpsPSLFilePrefix + ns + "_" + name + gitOpsFileExt
+}
+
+func gitOpsCPSLFileName(name string) string {
+	return gitOpsCPSLFilePrefix + name + gitOpsFileExt
+}
+
+// writeFileAtomically writes the file on a temporary file of the same directory and then renames it, so
+// the readers (e.g: Prometheus) never see a partially written file.
+func writeFileAtomically(path string, data []byte) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("could not create temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("could not write temporary file: %w", err)
+	}
+
+	err = tmp.Sync()
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("could not sync temporary file: %w", err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("could not close temporary file: %w", err)
+	}
+
+	err = os.Chmod(tmp.Name(), 0o644)
+	if err != nil {
+		return fmt.Errorf("could not set temporary file permissions: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("could not rename temporary file: %w", err)
+	}
+
+	return nil
+}
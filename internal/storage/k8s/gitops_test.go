@@ -0,0 +1,375 @@
+package k8s_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/slok/sloth/internal/log"
+	storagek8s "github.com/slok/sloth/internal/storage/k8s"
+	"github.com/slok/sloth/pkg/common/model"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	slothclientsetfake "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/fake"
+)
+
+const gitOpsTestRules = `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# DO NOT EDIT.
+
+groups:
+- name: sloth-slo-sli-recordings-testa
+  rules:
+  - record: test:record-a1
+    expr: test-expr-a1
+`
+
+func newGitOpsTestRepository(t *testing.T, config storagek8s.GitOpsRepositoryConfig, kubeCli *kubernetesfake.Clientset, slothCli *slothclientsetfake.Clientset) *storagek8s.GitOpsRepository {
+	repo, err := storagek8s.NewApiserverRepository(storagek8s.ApiserverRepositoryConfig{
+		KubeCli:            kubeCli,
+		SlothCli:           slothCli,
+		DynamicCli:         fakedynamic.NewSimpleDynamicClient(runtime.NewScheme()),
+		DiscoveryCli:       &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}},
+		K8sTransformPlugin: noopK8sTransformPlugin{},
+		Logger:             log.Noop,
+	})
+	require.NoError(t, err)
+
+	config.Repository = *repo
+	config.Logger = log.Noop
+	gitOpsRepo, err := storagek8s.NewGitOpsRepository(config)
+	require.NoError(t, err)
+
+	return gitOpsRepo
+}
+
+func newPrometheusReloadServer(t *testing.T) (*httptest.Server, *int32) {
+	var reloads int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/-/reload" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		atomic.AddInt32(&reloads, 1)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &reloads
+}
+
+func TestGitOpsRepositoryStoreSLOs(t *testing.T) {
+	sloResults := []model.PromSLOResult{
+		{
+			SLO: model.PromSLO{ID: "testa"},
+			PrometheusRules: model.PromSLORules{
+				SLIErrorRecRules: model.PromRuleGroup{
+					Name:  "sloth-slo-sli-recordings-testa",
+					Rules: []rulefmt.Rule{{Record: "test:record-a1", Expr: "test-expr-a1"}},
+				},
+			},
+		},
+	}
+	pslSource := model.PromSLOGroupSource{K8sSlothV1: &slothv1.PrometheusServiceLevel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns"},
+	}}
+
+	tests := map[string]struct {
+		files      map[string]string
+		slos       model.PromSLOGroupResult
+		expFiles   map[string]string
+		expReloads int32
+		expErr     bool
+	}{
+		"Storing the SLOs should write the service level rule file and reload Prometheus.": {
+			files: map[string]string{"other.yml": "groups: []"},
+			slos:  model.PromSLOGroupResult{OriginalSource: pslSource, SLOResults: sloResults},
+			expFiles: map[string]string{
+				"other.yml": "groups: []",
+				"prometheusservicelevel_test-ns_test-name.yml": gitOpsTestRules,
+			},
+			expReloads: 1,
+		},
+
+		"Storing cluster service level SLOs should write the cluster service level rule file.": {
+			slos: model.PromSLOGroupResult{
+				OriginalSource: model.PromSLOGroupSource{K8sSlothClusterV1: &slothv1.ClusterPrometheusServiceLevel{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-name"},
+				}},
+				SLOResults: sloResults,
+			},
+			expFiles: map[string]string{
+				"clusterprometheusservicelevel_test-name.yml": gitOpsTestRules,
+			},
+			expReloads: 1,
+		},
+
+		"Storing the SLOs on an outdated rule file should override it.": {
+			files: map[string]string{"prometheusservicelevel_test-ns_test-name.yml": "groups: []"},
+			slos:  model.PromSLOGroupResult{OriginalSource: pslSource, SLOResults: sloResults},
+			expFiles: map[string]string{
+				"prometheusservicelevel_test-ns_test-name.yml": gitOpsTestRules,
+			},
+			expReloads: 1,
+		},
+
+		"Storing the same SLOs should not reload Prometheus.": {
+			files: map[string]string{"prometheusservicelevel_test-ns_test-name.yml": gitOpsTestRules},
+			slos:  model.PromSLOGroupResult{OriginalSource: pslSource, SLOResults: sloResults},
+			expFiles: map[string]string{
+				"prometheusservicelevel_test-ns_test-name.yml": gitOpsTestRules,
+			},
+			expReloads: 0,
+		},
+
+		"Storing SLOs without rules should remove the rule file.": {
+			files: map[string]string{"prometheusservicelevel_test-ns_test-name.yml": gitOpsTestRules},
+			slos: model.PromSLOGroupResult{
+				OriginalSource: pslSource,
+				SLOResults:     []model.PromSLOResult{{SLO: model.PromSLO{ID: "testa"}}},
+			},
+			expFiles:   map[string]string{},
+			expReloads: 1,
+		},
+
+		"SLOs without a Kubernetes original source should fail.": {
+			slos:     model.PromSLOGroupResult{SLOResults: sloResults},
+			expFiles: map[string]string{},
+			expErr:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			dir := t.TempDir()
+			for name, content := range test.files {
+				err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+				require.NoError(err)
+			}
+			srv, reloads := newPrometheusReloadServer(t)
+			repo := newGitOpsTestRepository(t,
+				storagek8s.GitOpsRepositoryConfig{RulesDir: dir, PrometheusReloadURL: srv.URL + "/-/reload"},
+				kubernetesfake.NewClientset(),
+				slothclientsetfake.NewClientset(),
+			)
+
+			err := repo.StoreSLOs(context.TODO(), model.K8sMeta{Name: "test-name", Namespace: "test-ns"}, test.slos)
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			gotFiles := map[string]string{}
+			entries, err := os.ReadDir(dir)
+			require.NoError(err)
+			for _, e := range entries {
+				data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+				require.NoError(err)
+				gotFiles[e.Name()] = string(data)
+			}
+			assert.Equal(test.expFiles, gotFiles)
+			assert.Equal(test.expReloads, atomic.LoadInt32(reloads))
+		})
+	}
+}
+
+func TestGitOpsRepositoryCleanRuleFiles(t *testing.T) {
+	tests := map[string]struct {
+		files      []string
+		kubeObjs   []runtime.Object
+		slothObjs  []runtime.Object
+		nsSelector labels.Selector
+		onList     func(dir string, tracker k8stesting.ObjectTracker)
+		expFiles   []string
+		expReloads int32
+	}{
+		"The rule files of the missing service levels should be removed.": {
+			files: []string{
+				"prometheusservicelevel_test-ns_test-name.yml",
+				"prometheusservicelevel_test-ns_test-deleted.yml",
+				"clusterprometheusservicelevel_test-name.yml",
+				"clusterprometheusservicelevel_test-deleted.yml",
+				"other.yml",
+			},
+			slothObjs: []runtime.Object{
+				&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns"}},
+				&slothv1.ClusterPrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test-name"}},
+			},
+			expFiles: []string{
+				"clusterprometheusservicelevel_test-name.yml",
+				"other.yml",
+				"prometheusservicelevel_test-ns_test-name.yml",
+			},
+			expReloads: 1,
+		},
+
+		"The rule files of the service levels on namespaces not selected should be removed.": {
+			files: []string{
+				"prometheusservicelevel_test-ns_test-name.yml",
+				"prometheusservicelevel_test-ns2_test-name.yml",
+				"prometheusservicelevel_test-ns3_test-name.yml",
+				"clusterprometheusservicelevel_test-name.yml",
+			},
+			kubeObjs: []runtime.Object{
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns", Labels: map[string]string{"sloth": "enabled"}}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns2"}},
+			},
+			slothObjs: []runtime.Object{
+				&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns"}},
+				&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns2"}},
+				&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns3"}},
+				&slothv1.ClusterPrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test-name"}},
+			},
+			nsSelector: labels.SelectorFromSet(labels.Set{"sloth": "enabled"}),
+			expFiles: []string{
+				"clusterprometheusservicelevel_test-name.yml",
+				"prometheusservicelevel_test-ns_test-name.yml",
+			},
+			expReloads: 1,
+		},
+
+		"The rule files of the service levels created while listing should not be removed.": {
+			onList: func(dir string, tracker k8stesting.ObjectTracker) {
+				_ = tracker.Add(&slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test-new", Namespace: "test-ns"}})
+				_ = os.WriteFile(filepath.Join(dir, "prometheusservicelevel_test-ns_test-new.yml"), []byte(gitOpsTestRules), 0o644)
+			},
+			expFiles: []string{
+				"prometheusservicelevel_test-ns_test-new.yml",
+			},
+			expReloads: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			dir := t.TempDir()
+			for _, name := range test.files {
+				err := os.WriteFile(filepath.Join(dir, name), []byte(gitOpsTestRules), 0o644)
+				require.NoError(err)
+			}
+
+			slothCli := slothclientsetfake.NewClientset(test.slothObjs...)
+			if test.onList != nil {
+				// Simulate a service level created and stored after the list has been done.
+				var once sync.Once
+				listReaction := k8stesting.ObjectReaction(slothCli.Tracker())
+				slothCli.PrependReactor("list", "prometheusservicelevels", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					handled, obj, err := listReaction(action)
+					once.Do(func() { test.onList(dir, slothCli.Tracker()) })
+					return handled, obj, err
+				})
+			}
+			srv, reloads := newPrometheusReloadServer(t)
+			repo := newGitOpsTestRepository(t,
+				storagek8s.GitOpsRepositoryConfig{
+					RulesDir:               dir,
+					PrometheusReloadURL:    srv.URL + "/-/reload",
+					NamespaceLabelSelector: test.nsSelector,
+				},
+				kubernetesfake.NewClientset(test.kubeObjs...),
+				slothCli,
+			)
+
+			// Clean twice, the second time nothing should be removed.
+			err := repo.CleanRuleFiles(context.TODO())
+			require.NoError(err)
+			err = repo.CleanRuleFiles(context.TODO())
+			require.NoError(err)
+
+			gotFiles := []string{}
+			entries, err := os.ReadDir(dir)
+			require.NoError(err)
+			for _, e := range entries {
+				gotFiles = append(gotFiles, e.Name())
+			}
+			assert.Equal(test.expFiles, gotFiles)
+			assert.Equal(test.expReloads, atomic.LoadInt32(reloads))
+		})
+	}
+}
+
+func TestGitOpsRepositoryRunRemovesDeletedServiceLevelRuleFiles(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	files := []string{
+		"prometheusservicelevel_test-ns_test-name.yml",
+		"clusterprometheusservicelevel_test-name.yml",
+	}
+	for _, name := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(gitOpsTestRules), 0o644)
+		require.NoError(err)
+	}
+
+	psl := &slothv1.PrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-ns"}}
+	cpsl := &slothv1.ClusterPrometheusServiceLevel{ObjectMeta: metav1.ObjectMeta{Name: "test-name"}}
+	slothCli := slothclientsetfake.NewClientset(psl, cpsl)
+
+	// Control the service level watches, so we can send the delete events once they are watched.
+	pslWatcher, cpslWatcher := watch.NewFake(), watch.NewFake()
+	pslWatched, cpslWatched := make(chan struct{}), make(chan struct{})
+	var pslOnce, cpslOnce sync.Once
+	slothCli.PrependWatchReactor("prometheusservicelevels", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		pslOnce.Do(func() { close(pslWatched) })
+		return true, pslWatcher, nil
+	})
+	slothCli.PrependWatchReactor("clusterprometheusservicelevels", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		cpslOnce.Do(func() { close(cpslWatched) })
+		return true, cpslWatcher, nil
+	})
+
+	srv, reloads := newPrometheusReloadServer(t)
+	repo := newGitOpsTestRepository(t,
+		storagek8s.GitOpsRepositoryConfig{RulesDir: dir, PrometheusReloadURL: srv.URL + "/-/reload"},
+		kubernetesfake.NewClientset(),
+		slothCli,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- repo.Run(ctx) }()
+	defer func() {
+		cancel()
+		require.NoError(<-runErr)
+	}()
+
+	fileExists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+
+	<-pslWatched
+	pslWatcher.Delete(psl)
+	require.Eventually(func() bool { return !fileExists(files[0]) }, 5*time.Second, 10*time.Millisecond)
+	require.True(fileExists(files[1]))
+
+	<-cpslWatched
+	cpslWatcher.Delete(cpsl)
+	require.Eventually(func() bool { return !fileExists(files[1]) }, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(func() bool { return atomic.LoadInt32(reloads) == 2 }, 5*time.Second, 10*time.Millisecond)
+}
@@ -3,15 +3,20 @@ package k8s
 import (
 	"context"
 	"fmt"
-	"time"
+	"slices"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/restmapper"
 
 	"github.com/slok/sloth/internal/log"
@@ -22,6 +27,7 @@ import (
 )
 
 type ApiserverRepositoryConfig struct {
+	KubeCli            kubernetes.Interface
 	SlothCli           slothclientset.Interface
 	DynamicCli         dynamic.Interface
 	DiscoveryCli       discovery.DiscoveryInterface
@@ -30,6 +36,7 @@ type ApiserverRepositoryConfig struct {
 }
 
 type ApiserverRepository struct {
+	kubeCli      kubernetes.Interface
 	slothCli     slothclientset.Interface
 	dynamicCli   dynamic.Interface
 	restMapper   meta.RESTMapper
@@ -38,6 +45,10 @@ type ApiserverRepository struct {
 }
 
 func (c *ApiserverRepositoryConfig) defaults() error {
+	if c.KubeCli == nil {
+		return fmt.Errorf("KubeCli must be set")
+	}
+
 	if c.SlothCli == nil {
 		return fmt.Errorf("SlothCli must be set")
 	}
@@ -74,6 +85,7 @@ func NewApiserverRepository(config ApiserverRepositoryConfig) (*ApiserverReposit
 	}
 
 	return &ApiserverRepository{
+		kubeCli:      config.KubeCli,
 		slothCli:     config.SlothCli,
 		dynamicCli:   config.DynamicCli,
 		k8sTransform: config.K8sTransformPlugin,
@@ -91,34 +103,127 @@ func (r ApiserverRepository) WatchPrometheusServiceLevels(ctx context.Context, n
 	return r.slothCli.SlothV1().PrometheusServiceLevels(ns).Watch(ctx, opts)
 }
 
-// EnsurePrometheusServiceLevelStatus updates the status of a PrometheusServiceLeve, be aware that updating
+// EnsurePrometheusServiceLevelStatus updates the status of a PrometheusServiceLevel, be aware that updating
 // an status will trigger a watch update event on a controller.
-// In case of no error we will update "last correct Prometheus operation rules generated" TS so we can be in
-// a infinite loop of handling, the handler should break this loop somehow (e.g: if ok and last generated < 5m, ignore).
-func (r ApiserverRepository) EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, err error) error {
+func (r ApiserverRepository) EnsurePrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.PrometheusServiceLevel, status slothv1.PrometheusServiceLevelStatus) error {
 	slo = slo.DeepCopy()
+	slo.Status = status
 
-	slo.Status.PromOpRulesGenerated = false
-	slo.Status.PromOpRulesGeneratedSLOs = 0
-	slo.Status.ProcessedSLOs = len(slo.Spec.SLOs)
-	slo.Status.ObservedGeneration = slo.Generation
+	_, err := r.slothCli.SlothV1().PrometheusServiceLevels(slo.Namespace).UpdateStatus(ctx, slo, metav1.UpdateOptions{})
+	return err
+}
 
-	if err == nil {
-		slo.Status.PromOpRulesGenerated = true
-		slo.Status.PromOpRulesGeneratedSLOs = len(slo.Spec.SLOs)
-		slo.Status.LastPromOpRulesSuccessfulGenerated = &metav1.Time{Time: time.Now().UTC()}
-	}
+func (r ApiserverRepository) ListClusterPrometheusServiceLevels(ctx context.Context, opts metav1.ListOptions) (*slothv1.ClusterPrometheusServiceLevelList, error) {
+	return r.slothCli.SlothV1().ClusterPrometheusServiceLevels().List(ctx, opts)
+}
 
-	_, err = r.slothCli.SlothV1().PrometheusServiceLevels(slo.Namespace).UpdateStatus(ctx, slo, metav1.UpdateOptions{})
+func (r ApiserverRepository) WatchClusterPrometheusServiceLevels(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.slothCli.SlothV1().ClusterPrometheusServiceLevels().Watch(ctx, opts)
+}
+
+// EnsureClusterPrometheusServiceLevelStatus updates the status of a ClusterPrometheusServiceLevel, be aware that updating
+// an status will trigger a watch update event on a controller.
+func (r ApiserverRepository) EnsureClusterPrometheusServiceLevelStatus(ctx context.Context, slo *slothv1.ClusterPrometheusServiceLevel, status slothv1.PrometheusServiceLevelStatus) error {
+	slo = slo.DeepCopy()
+	slo.Status = status
+
+	_, err := r.slothCli.SlothV1().ClusterPrometheusServiceLevels().UpdateStatus(ctx, slo, metav1.UpdateOptions{})
 	return err
 }
 
+func (r ApiserverRepository) GetPrometheusServiceLevelTemplate(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevelTemplate, error) {
+	return r.slothCli.SlothV1().PrometheusServiceLevelTemplates(ns).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (r ApiserverRepository) ListPrometheusServiceLevelTemplates(ctx context.Context, ns string, opts metav1.ListOptions) (*slothv1.PrometheusServiceLevelTemplateList, error) {
+	return r.slothCli.SlothV1().PrometheusServiceLevelTemplates(ns).List(ctx, opts)
+}
+
+func (r ApiserverRepository) WatchPrometheusServiceLevelTemplates(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.slothCli.SlothV1().PrometheusServiceLevelTemplates(ns).Watch(ctx, opts)
+}
+
+func (r ApiserverRepository) GetPrometheusServiceLevel(ctx context.Context, ns, name string) (*slothv1.PrometheusServiceLevel, error) {
+	return r.slothCli.SlothV1().PrometheusServiceLevels(ns).Get(ctx, name, metav1.GetOptions{})
+}
+
+// EnsurePrometheusServiceLevel creates or overrides a PrometheusServiceLevel, the stored status is kept.
+func (r ApiserverRepository) EnsurePrometheusServiceLevel(ctx context.Context, psl *slothv1.PrometheusServiceLevel) error {
+	psl = psl.DeepCopy()
+	cli := r.slothCli.SlothV1().PrometheusServiceLevels(psl.Namespace)
+
+	stored, err := cli.Get(ctx, psl.Name, metav1.GetOptions{})
+	if err != nil {
+		if !kubeerrors.IsNotFound(err) {
+			return fmt.Errorf("could not get PrometheusServiceLevel %s/%s: %w", psl.Namespace, psl.Name, err)
+		}
+		_, err = cli.Create(ctx, psl, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("could not create PrometheusServiceLevel %s/%s: %w", psl.Namespace, psl.Name, err)
+		}
+		return nil
+	}
+
+	psl.ResourceVersion = stored.ResourceVersion
+	psl.Status = stored.Status
+	_, err = cli.Update(ctx, psl, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("could not update PrometheusServiceLevel %s/%s: %w", psl.Namespace, psl.Name, err)
+	}
+
+	return nil
+}
+
+func (r ApiserverRepository) DeletePrometheusServiceLevel(ctx context.Context, ns, name string) error {
+	return r.slothCli.SlothV1().PrometheusServiceLevels(ns).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func (r ApiserverRepository) ListServices(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.ServiceList, error) {
+	return r.kubeCli.CoreV1().Services(ns).List(ctx, opts)
+}
+
+func (r ApiserverRepository) WatchServices(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.kubeCli.CoreV1().Services(ns).Watch(ctx, opts)
+}
+
+func (r ApiserverRepository) ListDeployments(ctx context.Context, ns string, opts metav1.ListOptions) (*appsv1.DeploymentList, error) {
+	return r.kubeCli.AppsV1().Deployments(ns).List(ctx, opts)
+}
+
+func (r ApiserverRepository) WatchDeployments(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return r.kubeCli.AppsV1().Deployments(ns).Watch(ctx, opts)
+}
+
+func (r ApiserverRepository) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	return r.kubeCli.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+}
+
 func (r ApiserverRepository) StoreSLOs(ctx context.Context, kmeta model.K8sMeta, slos model.PromSLOGroupResult) error {
-	ownRef := metav1.OwnerReference{
-		Kind:       "PrometheusServiceLevel",
-		APIVersion: "sloth.slok.dev/v1",
-		Name:       slos.OriginalSource.K8sSlothV1.Name,
-		UID:        slos.OriginalSource.K8sSlothV1.UID,
+	var ownRef metav1.OwnerReference
+	// A cluster service level can change its target namespace, if we don't know the previous one
+	// (e.g: created by an older version) we need to search the stale objects on all the namespaces.
+	var prevNamespace string
+	unknownPrevNamespace := false
+	switch {
+	case slos.OriginalSource.K8sSlothV1 != nil:
+		ownRef = metav1.OwnerReference{
+			Kind:       "PrometheusServiceLevel",
+			APIVersion: "sloth.slok.dev/v1",
+			Name:       slos.OriginalSource.K8sSlothV1.Name,
+			UID:        slos.OriginalSource.K8sSlothV1.UID,
+		}
+		prevNamespace = slos.OriginalSource.K8sSlothV1.Status.TargetNamespace
+	case slos.OriginalSource.K8sSlothClusterV1 != nil:
+		ownRef = metav1.OwnerReference{
+			Kind:       "ClusterPrometheusServiceLevel",
+			APIVersion: "sloth.slok.dev/v1",
+			Name:       slos.OriginalSource.K8sSlothClusterV1.Name,
+			UID:        slos.OriginalSource.K8sSlothClusterV1.UID,
+		}
+		prevNamespace = slos.OriginalSource.K8sSlothClusterV1.Status.TargetNamespace
+		unknownPrevNamespace = prevNamespace == ""
+	default:
+		return fmt.Errorf("SLOs without Kubernetes original source can't be stored")
 	}
 
 	// Transform to k8s objects.
@@ -127,11 +232,21 @@ func (r ApiserverRepository) StoreSLOs(ctx context.Context, kmeta model.K8sMeta,
 		return fmt.Errorf("could not transform to k8s objects: %w", err)
 	}
 
-	// Add object reference.
+	// Add object reference and owner label, the label is used to garbage collect the objects that
+	// are not generated anymore (e.g: stale PrometheusRule shards).
 	for _, obj := range k8sObjs.Items {
 		ownRefs := obj.GetOwnerReferences()
 		ownRefs = append(ownRefs, ownRef)
 		obj.SetOwnerReferences(ownRefs)
+
+		if ownRef.UID != "" {
+			l := obj.GetLabels()
+			if l == nil {
+				l = map[string]string{}
+			}
+			l[ownerUIDLabel] = string(ownRef.UID)
+			obj.SetLabels(l)
+		}
 	}
 
 	// Ensure k8s objects.
@@ -139,9 +254,126 @@ func (r ApiserverRepository) StoreSLOs(ctx context.Context, kmeta model.K8sMeta,
 	if err != nil {
 		return fmt.Errorf("could not ensure k8s objects: %w", err)
 	}
+
+	// Garbage collect stale objects.
+	if ownRef.UID != "" {
+		err = r.deleteStaleK8sObjects(ctx, string(ownRef.UID), prevNamespace, unknownPrevNamespace, k8sObjs.Items)
+		if err != nil {
+			return fmt.Errorf("could not delete stale k8s objects: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ownerUIDLabel is the label set on the stored SLO objects with the UID of the service level that
+// generated them.
+const ownerUIDLabel = "sloth.dev/owner-uid"
+
+// deleteStaleK8sObjects deletes the objects of the owner that have not been generated on the latest
+// store. The stale objects are searched on the namespaces of the stored objects and on the previous
+// target namespace, so namespaced RBAC is enough. When the previous target namespace is unknown, all
+// the namespaces are searched if the controller is allowed to.
+func (r ApiserverRepository) deleteStaleK8sObjects(ctx context.Context, ownerUID, prevNamespace string, allNamespaces bool, objs []*unstructured.Unstructured) error {
+	logger := r.logger.WithCtxValues(ctx)
+
+	type objectKey struct {
+		gvr       schema.GroupVersionResource
+		namespace string
+		name      string
+	}
+	stored := map[objectKey]bool{}
+	gvrs := []schema.GroupVersionResource{}
+	gvrNamespaces := map[schema.GroupVersionResource][]string{}
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		mapping, err := r.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return fmt.Errorf("could not get GVR for GVK %v: %w", gvk, err)
+		}
+
+		gvr, namespace := mapping.Resource, obj.GetNamespace()
+		if !slices.Contains(gvrs, gvr) {
+			gvrs = append(gvrs, gvr)
+			// Namespaced objects could be stale on the previous target namespace.
+			if namespace != "" && prevNamespace != "" {
+				gvrNamespaces[gvr] = append(gvrNamespaces[gvr], prevNamespace)
+			}
+		}
+		if !slices.Contains(gvrNamespaces[gvr], namespace) {
+			gvrNamespaces[gvr] = append(gvrNamespaces[gvr], namespace)
+		}
+		stored[objectKey{gvr: gvr, namespace: namespace, name: obj.GetName()}] = true
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{ownerUIDLabel: ownerUID}).String()
+	for _, gvr := range gvrs {
+		items, err := r.listOwnedK8sObjects(ctx, gvr, gvrNamespaces[gvr], allNamespaces, selector)
+		if err != nil {
+			return fmt.Errorf("could not list %s objects: %w", gvr, err)
+		}
+
+		for _, item := range items {
+			namespace, name := item.GetNamespace(), item.GetName()
+			if stored[objectKey{gvr: gvr, namespace: namespace, name: name}] {
+				continue
+			}
+
+			var resource dynamic.ResourceInterface
+			if namespace != "" {
+				resource = r.dynamicCli.Resource(gvr).Namespace(namespace)
+			} else {
+				resource = r.dynamicCli.Resource(gvr)
+			}
+
+			err := resource.Delete(ctx, name, metav1.DeleteOptions{})
+			if err != nil && !kubeerrors.IsNotFound(err) {
+				return fmt.Errorf("could not delete stale object %s/%s: %w", namespace, name, err)
+			}
+			logger.WithValues(log.Kv{"gvr": gvr.String(), "namespace": namespace, "name": name}).Infof("Stale resource has been deleted")
+		}
+	}
+
 	return nil
 }
 
+// listOwnedK8sObjects lists the objects of a resource on the namespaces, if all namespaces are requested
+// and the controller is not allowed to list them, it will fallback to the namespaces.
+func (r ApiserverRepository) listOwnedK8sObjects(ctx context.Context, gvr schema.GroupVersionResource, namespaces []string, allNamespaces bool, selector string) ([]unstructured.Unstructured, error) {
+	opts := metav1.ListOptions{LabelSelector: selector}
+
+	if allNamespaces {
+		// Listing without namespace returns the objects of all the namespaces.
+		list, err := r.dynamicCli.Resource(gvr).List(ctx, opts)
+		switch {
+		case err == nil:
+			return list.Items, nil
+		case kubeerrors.IsForbidden(err):
+			r.logger.WithCtxValues(ctx).WithValues(log.Kv{"gvr": gvr.String()}).Debugf("Not allowed to list on all namespaces, stale objects will be searched on the target namespaces")
+		default:
+			return nil, err
+		}
+	}
+
+	items := []unstructured.Unstructured{}
+	for _, ns := range namespaces {
+		var resource dynamic.ResourceInterface
+		if ns != "" {
+			resource = r.dynamicCli.Resource(gvr).Namespace(ns)
+		} else {
+			resource = r.dynamicCli.Resource(gvr)
+		}
+
+		list, err := resource.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, list.Items...)
+	}
+
+	return items, nil
+}
+
 func (r ApiserverRepository) ensureK8sObjects(ctx context.Context, objs []*unstructured.Unstructured) error {
 	logger := r.logger.WithCtxValues(ctx)
 
@@ -189,6 +421,13 @@ func (r ApiserverRepository) ensureK8sObjects(ctx context.Context, objs []*unstr
 			continue
 		}
 
+		// Don't take over the objects of other service levels, e.g: a service level named like the
+		// PrometheusRule shard of another service level.
+		storedOwnerUID, ownerUID := stored.GetLabels()[ownerUIDLabel], l[ownerUIDLabel]
+		if storedOwnerUID != "" && ownerUID != "" && storedOwnerUID != ownerUID {
+			return fmt.Errorf("object %s/%s is owned by a different service level (%s uid)", namespace, name, storedOwnerUID)
+		}
+
 		obj.SetResourceVersion(stored.GetResourceVersion())
 		_, err = resource.Update(ctx, obj, metav1.UpdateOptions{})
 		if err != nil {
@@ -2,6 +2,7 @@ package k8s_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -9,12 +10,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	fakediscovery "k8s.io/client-go/discovery/fake"
 	fakedynamic "k8s.io/client-go/dynamic/fake"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/slok/sloth/internal/log"
@@ -23,6 +26,7 @@ import (
 	"github.com/slok/sloth/pkg/common/model"
 	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
 	slothclientsetfake "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/fake"
+	plugink8stransformv1 "github.com/slok/sloth/pkg/prometheus/plugin/k8stransform/v1"
 )
 
 var originalSource = model.PromSLOGroupSource{
@@ -218,10 +222,12 @@ func (testPlugin) TransformK8sObjects(ctx context.Context, kmeta model.K8sMeta,
 }`
 
 	tests := map[string]struct {
-		k8sMeta model.K8sMeta
-		slos    model.PromSLOGroupResult
-		expObjs []unstructured.Unstructured
-		expErr  bool
+		stored         []runtime.Object
+		namespacedRBAC bool
+		k8sMeta        model.K8sMeta
+		slos           model.PromSLOGroupResult
+		expObjs        []unstructured.Unstructured
+		expErr         bool
 	}{
 		"Having multiple SLOs should create unstructured objects correctly.": {
 			k8sMeta: model.K8sMeta{
@@ -243,6 +249,7 @@ func (testPlugin) TransformK8sObjects(ctx context.Context, kmeta model.K8sMeta,
 								"lk1":                          "lv1",
 								"app.kubernetes.io/component":  "SLO",
 								"app.kubernetes.io/managed-by": "sloth",
+								"sloth.dev/owner-uid":          "test-uid",
 							},
 							"annotations": map[string]interface{}{
 								"ak1": "av1",
@@ -272,6 +279,7 @@ func (testPlugin) TransformK8sObjects(ctx context.Context, kmeta model.K8sMeta,
 								"lk1":                          "lv1",
 								"app.kubernetes.io/component":  "SLO",
 								"app.kubernetes.io/managed-by": "sloth",
+								"sloth.dev/owner-uid":          "test-uid",
 							},
 							"annotations": map[string]interface{}{
 								"ak1": "av1",
@@ -292,6 +300,371 @@ func (testPlugin) TransformK8sObjects(ctx context.Context, kmeta model.K8sMeta,
 				},
 			},
 		},
+
+		"SLOs from a cluster scoped object should be owned by the cluster object.": {
+			k8sMeta: model.K8sMeta{
+				Name:      "test-name",
+				Namespace: "test-ns",
+			},
+			slos: func() model.PromSLOGroupResult {
+				r := testPromSLOGroupResult
+				r.SLOResults = r.SLOResults[:1]
+				r.OriginalSource = model.PromSLOGroupSource{
+					K8sSlothClusterV1: &slothv1.ClusterPrometheusServiceLevel{
+						ObjectMeta: metav1.ObjectMeta{Name: "test-name", UID: types.UID("test-uid")},
+					},
+				}
+				return r
+			}(),
+			expObjs: []unstructured.Unstructured{
+				{
+					Object: map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "ConfigMap",
+						"metadata": map[string]interface{}{
+							"name":      "test-name-testa",
+							"namespace": "test-ns",
+							"labels": map[string]interface{}{
+								"app.kubernetes.io/component":  "SLO",
+								"app.kubernetes.io/managed-by": "sloth",
+								"sloth.dev/owner-uid":          "test-uid",
+							},
+							"annotations": map[string]interface{}{},
+							"ownerReferences": []interface{}{
+								map[string]interface{}{
+									"apiVersion": "sloth.slok.dev/v1",
+									"kind":       "ClusterPrometheusServiceLevel",
+									"name":       "test-name",
+									"uid":        "test-uid",
+								},
+							},
+						},
+						"data": map[string]interface{}{
+							"slo_id": "testa",
+						},
+					},
+				},
+			},
+		},
+
+		"Stale objects of the same owner should be deleted.": {
+			stored: []runtime.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-name-stale",
+					Namespace: "test-ns",
+					Labels:    map[string]string{"sloth.dev/owner-uid": "test-uid"},
+				}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-name-other",
+					Namespace: "test-ns",
+					Labels:    map[string]string{"sloth.dev/owner-uid": "other-uid"},
+				}},
+			},
+			k8sMeta: model.K8sMeta{
+				Name:      "test-name",
+				Namespace: "test-ns",
+			},
+			slos: func() model.PromSLOGroupResult {
+				r := testPromSLOGroupResult
+				r.SLOResults = r.SLOResults[:1]
+				return r
+			}(),
+			expObjs: []unstructured.Unstructured{
+				{
+					Object: map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "ConfigMap",
+						"metadata": map[string]interface{}{
+							"name":      "test-name-other",
+							"namespace": "test-ns",
+							"labels": map[string]interface{}{
+								"sloth.dev/owner-uid": "other-uid",
+							},
+						},
+					},
+				},
+				{
+					Object: map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "ConfigMap",
+						"metadata": map[string]interface{}{
+							"name":      "test-name-testa",
+							"namespace": "test-ns",
+							"labels": map[string]interface{}{
+								"app.kubernetes.io/component":  "SLO",
+								"app.kubernetes.io/managed-by": "sloth",
+								"sloth.dev/owner-uid":          "test-uid",
+							},
+							"annotations": map[string]interface{}{},
+							"ownerReferences": []interface{}{
+								map[string]interface{}{
+									"apiVersion": "sloth.slok.dev/v1",
+									"kind":       "PrometheusServiceLevel",
+									"name":       "test-name",
+									"uid":        "test-uid",
+								},
+							},
+						},
+						"data": map[string]interface{}{
+							"slo_id": "testa",
+						},
+					},
+				},
+			},
+		},
+
+		"Retargeting the SLOs to a different namespace should delete the objects on the previous namespace.": {
+			stored: []runtime.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-name-testa",
+					Namespace: "test-old-ns",
+					Labels:    map[string]string{"sloth.dev/owner-uid": "test-uid"},
+				}},
+			},
+			k8sMeta: model.K8sMeta{
+				Name:      "test-name",
+				Namespace: "test-ns",
+			},
+			slos: func() model.PromSLOGroupResult {
+				r := testPromSLOGroupResult
+				r.SLOResults = r.SLOResults[:1]
+				r.OriginalSource = model.PromSLOGroupSource{
+					K8sSlothClusterV1: &slothv1.ClusterPrometheusServiceLevel{
+						ObjectMeta: metav1.ObjectMeta{Name: "test-name", UID: types.UID("test-uid")},
+					},
+				}
+				return r
+			}(),
+			expObjs: []unstructured.Unstructured{
+				{
+					Object: map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "ConfigMap",
+						"metadata": map[string]interface{}{
+							"name":      "test-name-testa",
+							"namespace": "test-ns",
+							"labels": map[string]interface{}{
+								"app.kubernetes.io/component":  "SLO",
+								"app.kubernetes.io/managed-by": "sloth",
+								"sloth.dev/owner-uid":          "test-uid",
+							},
+							"annotations": map[string]interface{}{},
+							"ownerReferences": []interface{}{
+								map[string]interface{}{
+									"apiVersion": "sloth.slok.dev/v1",
+									"kind":       "ClusterPrometheusServiceLevel",
+									"name":       "test-name",
+									"uid":        "test-uid",
+								},
+							},
+						},
+						"data": map[string]interface{}{
+							"slo_id": "testa",
+						},
+					},
+				},
+			},
+		},
+
+		"With namespaced RBAC, stale objects of the same owner should be deleted on the target namespace.": {
+			stored: []runtime.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-name-stale",
+					Namespace: "test-ns",
+					Labels:    map[string]string{"sloth.dev/owner-uid": "test-uid"},
+				}},
+			},
+			namespacedRBAC: true,
+			k8sMeta: model.K8sMeta{
+				Name:      "test-name",
+				Namespace: "test-ns",
+			},
+			slos: func() model.PromSLOGroupResult {
+				r := testPromSLOGroupResult
+				r.SLOResults = r.SLOResults[:1]
+				return r
+			}(),
+			expObjs: []unstructured.Unstructured{
+				{
+					Object: map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "ConfigMap",
+						"metadata": map[string]interface{}{
+							"name":      "test-name-testa",
+							"namespace": "test-ns",
+							"labels": map[string]interface{}{
+								"app.kubernetes.io/component":  "SLO",
+								"app.kubernetes.io/managed-by": "sloth",
+								"sloth.dev/owner-uid":          "test-uid",
+							},
+							"annotations": map[string]interface{}{},
+							"ownerReferences": []interface{}{
+								map[string]interface{}{
+									"apiVersion": "sloth.slok.dev/v1",
+									"kind":       "PrometheusServiceLevel",
+									"name":       "test-name",
+									"uid":        "test-uid",
+								},
+							},
+						},
+						"data": map[string]interface{}{
+							"slo_id": "testa",
+						},
+					},
+				},
+			},
+		},
+
+		"With namespaced RBAC, retargeting the SLOs should delete the objects on the previous target namespace.": {
+			stored: []runtime.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-name-testa",
+					Namespace: "test-old-ns",
+					Labels:    map[string]string{"sloth.dev/owner-uid": "test-uid"},
+				}},
+			},
+			namespacedRBAC: true,
+			k8sMeta: model.K8sMeta{
+				Name:      "test-name",
+				Namespace: "test-ns",
+			},
+			slos: func() model.PromSLOGroupResult {
+				r := testPromSLOGroupResult
+				r.SLOResults = r.SLOResults[:1]
+				r.OriginalSource = model.PromSLOGroupSource{
+					K8sSlothClusterV1: &slothv1.ClusterPrometheusServiceLevel{
+						ObjectMeta: metav1.ObjectMeta{Name: "test-name", UID: types.UID("test-uid")},
+						Status:     slothv1.PrometheusServiceLevelStatus{TargetNamespace: "test-old-ns"},
+					},
+				}
+				return r
+			}(),
+			expObjs: []unstructured.Unstructured{
+				{
+					Object: map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "ConfigMap",
+						"metadata": map[string]interface{}{
+							"name":      "test-name-testa",
+							"namespace": "test-ns",
+							"labels": map[string]interface{}{
+								"app.kubernetes.io/component":  "SLO",
+								"app.kubernetes.io/managed-by": "sloth",
+								"sloth.dev/owner-uid":          "test-uid",
+							},
+							"annotations": map[string]interface{}{},
+							"ownerReferences": []interface{}{
+								map[string]interface{}{
+									"apiVersion": "sloth.slok.dev/v1",
+									"kind":       "ClusterPrometheusServiceLevel",
+									"name":       "test-name",
+									"uid":        "test-uid",
+								},
+							},
+						},
+						"data": map[string]interface{}{
+							"slo_id": "testa",
+						},
+					},
+				},
+			},
+		},
+
+		"With namespaced RBAC and an unknown previous target namespace, stale objects should be deleted on the target namespace.": {
+			stored: []runtime.Object{
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-name-stale",
+					Namespace: "test-ns",
+					Labels:    map[string]string{"sloth.dev/owner-uid": "test-uid"},
+				}},
+			},
+			namespacedRBAC: true,
+			k8sMeta: model.K8sMeta{
+				Name:      "test-name",
+				Namespace: "test-ns",
+			},
+			slos: func() model.PromSLOGroupResult {
+				r := testPromSLOGroupResult
+				r.SLOResults = r.SLOResults[:1]
+				r.OriginalSource = model.PromSLOGroupSource{
+					K8sSlothClusterV1: &slothv1.ClusterPrometheusServiceLevel{
+						ObjectMeta: metav1.ObjectMeta{Name: "test-name", UID: types.UID("test-uid")},
+					},
+				}
+				return r
+			}(),
+			expObjs: []unstructured.Unstructured{
+				{
+					Object: map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "ConfigMap",
+						"metadata": map[string]interface{}{
+							"name":      "test-name-testa",
+							"namespace": "test-ns",
+							"labels": map[string]interface{}{
+								"app.kubernetes.io/component":  "SLO",
+								"app.kubernetes.io/managed-by": "sloth",
+								"sloth.dev/owner-uid":          "test-uid",
+							},
+							"annotations": map[string]interface{}{},
+							"ownerReferences": []interface{}{
+								map[string]interface{}{
+									"apiVersion": "sloth.slok.dev/v1",
+									"kind":       "ClusterPrometheusServiceLevel",
+									"name":       "test-name",
+									"uid":        "test-uid",
+								},
+							},
+						},
+						"data": map[string]interface{}{
+							"slo_id": "testa",
+						},
+					},
+				},
+			},
+		},
+
+		"Objects with the same name owned by a different service level should not be overwritten.": {
+			stored: []runtime.Object{
+				// E.g: A service level named like the PrometheusRule shard of another service level.
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-name-testa",
+					Namespace: "test-ns",
+					Labels:    map[string]string{"sloth.dev/owner-uid": "other-uid"},
+				}},
+			},
+			k8sMeta: model.K8sMeta{
+				Name:      "test-name",
+				Namespace: "test-ns",
+			},
+			slos: func() model.PromSLOGroupResult {
+				r := testPromSLOGroupResult
+				r.SLOResults = r.SLOResults[:1]
+				return r
+			}(),
+			expObjs: []unstructured.Unstructured{
+				{
+					Object: map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "ConfigMap",
+						"metadata": map[string]interface{}{
+							"name":      "test-name-testa",
+							"namespace": "test-ns",
+							"labels": map[string]interface{}{
+								"sloth.dev/owner-uid": "other-uid",
+							},
+						},
+					},
+				},
+			},
+			expErr: true,
+		},
+
+		"SLOs without a Kubernetes original source should fail.": {
+			k8sMeta: model.K8sMeta{Name: "test-name", Namespace: "test-ns"},
+			slos:    model.PromSLOGroupResult{SLOResults: testPromSLOGroupResult.SLOResults},
+			expErr:  true,
+		},
 	}
 
 	for name, test := range tests {
@@ -313,7 +686,16 @@ func (testPlugin) TransformK8sObjects(ctx context.Context, kmeta model.K8sMeta,
 			scheme := runtime.NewScheme()
 			err = corev1.AddToScheme(scheme)
 			require.NoError(err)
-			dynamicCli := fakedynamic.NewSimpleDynamicClient(scheme)
+			dynamicCli := fakedynamic.NewSimpleDynamicClient(scheme, test.stored...)
+
+			// Simulate namespaced RBAC by forbidding the lists on all the namespaces.
+			forbidAllNamespaces := test.namespacedRBAC
+			dynamicCli.PrependReactor("list", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if forbidAllNamespaces && action.GetNamespace() == "" {
+					return true, nil, kubeerrors.NewForbidden(corev1.Resource("configmaps"), "", fmt.Errorf("namespaced RBAC"))
+				}
+				return false, nil, nil
+			})
 			fakeDiscovery := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
 				{
 					GroupVersion: "v1",
@@ -322,6 +704,7 @@ func (testPlugin) TransformK8sObjects(ctx context.Context, kmeta model.K8sMeta,
 			}}}
 
 			repo, err := storagek8s.NewApiserverRepository(storagek8s.ApiserverRepositoryConfig{
+				KubeCli:            kubernetesfake.NewClientset(),
 				SlothCli:           slothCLI,
 				DynamicCli:         dynamicCli,
 				DiscoveryCli:       fakeDiscovery,
@@ -333,11 +716,86 @@ func (testPlugin) TransformK8sObjects(ctx context.Context, kmeta model.K8sMeta,
 			err = repo.StoreSLOs(context.TODO(), test.k8sMeta, test.slos)
 			if test.expErr {
 				assert.Error(err)
-			} else if assert.NoError(err) {
-				gotObjs, err := dynamicCli.Resource(corev1.SchemeGroupVersion.WithResource("configmaps")).Namespace("").List(context.TODO(), metav1.ListOptions{})
-				require.NoError(err)
-				assert.Equal(test.expObjs, gotObjs.Items)
+			} else {
+				assert.NoError(err)
 			}
+
+			forbidAllNamespaces = false
+			gotObjs, err := dynamicCli.Resource(corev1.SchemeGroupVersion.WithResource("configmaps")).Namespace("").List(context.TODO(), metav1.ListOptions{})
+			require.NoError(err)
+			assert.Equal(test.expObjs, gotObjs.Items)
 		})
 	}
 }
+
+func TestApiserverRepositoryEnsurePrometheusServiceLevel(t *testing.T) {
+	tests := map[string]struct {
+		stored []runtime.Object
+		psl    *slothv1.PrometheusServiceLevel
+		expPSL *slothv1.PrometheusServiceLevel
+	}{
+		"Missing PrometheusServiceLevels should be created.": {
+			psl: &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Labels: map[string]string{"k1": "v1"}},
+				Spec:       slothv1.PrometheusServiceLevelSpec{Service: "svc1"},
+			},
+			expPSL: &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Labels: map[string]string{"k1": "v1"}},
+				Spec:       slothv1.PrometheusServiceLevelSpec{Service: "svc1"},
+			},
+		},
+
+		"Existing PrometheusServiceLevels should be overridden keeping their status.": {
+			stored: []runtime.Object{
+				&slothv1.PrometheusServiceLevel{
+					ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Labels: map[string]string{"k0": "v0"}},
+					Spec:       slothv1.PrometheusServiceLevelSpec{Service: "svc0"},
+					Status:     slothv1.PrometheusServiceLevelStatus{ProcessedSLOs: 1},
+				},
+			},
+			psl: &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Labels: map[string]string{"k1": "v1"}},
+				Spec:       slothv1.PrometheusServiceLevelSpec{Service: "svc1"},
+			},
+			expPSL: &slothv1.PrometheusServiceLevel{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", Labels: map[string]string{"k1": "v1"}},
+				Spec:       slothv1.PrometheusServiceLevelSpec{Service: "svc1"},
+				Status:     slothv1.PrometheusServiceLevelStatus{ProcessedSLOs: 1},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			// The field managed fake clientset can't create or update Sloth objects (the apply
+			// configurations don't have the Sloth types schema).
+			slothCLI := slothclientsetfake.NewSimpleClientset(test.stored...) //nolint:staticcheck
+			repo, err := storagek8s.NewApiserverRepository(storagek8s.ApiserverRepositoryConfig{
+				KubeCli:            kubernetesfake.NewClientset(),
+				SlothCli:           slothCLI,
+				DynamicCli:         fakedynamic.NewSimpleDynamicClient(runtime.NewScheme()),
+				DiscoveryCli:       &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}},
+				K8sTransformPlugin: noopK8sTransformPlugin{},
+				Logger:             log.Noop,
+			})
+			require.NoError(err)
+
+			err = repo.EnsurePrometheusServiceLevel(context.TODO(), test.psl)
+			require.NoError(err)
+
+			gotPSL, err := slothCLI.SlothV1().PrometheusServiceLevels(test.psl.Namespace).Get(context.TODO(), test.psl.Name, metav1.GetOptions{})
+			require.NoError(err)
+			gotPSL.ResourceVersion = ""
+			assert.Equal(test.expPSL, gotPSL)
+		})
+	}
+}
+
+type noopK8sTransformPlugin struct{}
+
+func (noopK8sTransformPlugin) TransformK8sObjects(ctx context.Context, kmeta model.K8sMeta, sloResult model.PromSLOGroupResult) (*plugink8stransformv1.K8sObjects, error) {
+	return &plugink8stransformv1.K8sObjects{}, nil
+}
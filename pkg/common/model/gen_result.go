@@ -4,10 +4,14 @@ package model
 type PromSLOGroupResult struct {
 	OriginalSource PromSLOGroupSource
 	SLOResults     []PromSLOResult
+	// ExtraRules are the SLO group level rule groups (e.g: generated by SLO group plugins).
+	ExtraRules []PromRuleGroup
 }
 
 // PromSLOResult is the result of generating standard Prometheus SLO rules from SLO definitions.
 type PromSLOResult struct {
 	SLO             PromSLO
 	PrometheusRules PromSLORules
+	// PluginTrace is the SLO plugin chain execution trace, only set when plugin tracing is enabled.
+	PluginTrace *PromSLOPluginTrace
 }
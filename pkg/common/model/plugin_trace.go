@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// PromSLOPluginTrace is the trace of the SLO plugin chain execution of an SLO.
+type PromSLOPluginTrace struct {
+	// Steps are the executed plugins in execution order.
+	Steps []PromSLOPluginTraceStep
+}
+
+// PromSLOPluginTraceStep is the trace of a single SLO plugin execution of the SLO plugin chain.
+type PromSLOPluginTraceStep struct {
+	PluginID string
+	Priority int
+	Duration time.Duration
+	// RuleChanges are the changes the plugin made on the SLO Prometheus rules.
+	RuleChanges []PromRuleChange
+	// SLOChanges are the changes the plugin made on the SLO.
+	SLOChanges []FieldChange
+}
+
+// ChangeType is the type of a change.
+type ChangeType string
+
+const (
+	ChangeTypeAdded    ChangeType = "added"
+	ChangeTypeRemoved  ChangeType = "removed"
+	ChangeTypeModified ChangeType = "modified"
+)
+
+// PromRuleChange is a change on a Prometheus rule of the SLO rule groups.
+type PromRuleChange struct {
+	// Group is the SLO rule group where the rule is (e.g: `alerts`, `extra[0]`).
+	Group string
+	// Rule is the record or alert name of the rule, if empty, the change is on the rule group itself (e.g: name or interval).
+	Rule string
+	Type ChangeType
+	// Fields are the changed fields of the rule (or rule group).
+	Fields []FieldChange
+}
+
+// FieldChange is a change on a field, the path is the location of the field (e.g: `Labels.team`).
+type FieldChange struct {
+	Path string
+	Type ChangeType
+	Old  string
+	New  string
+}
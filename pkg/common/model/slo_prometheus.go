@@ -67,9 +67,10 @@ type PromSLOGroup struct {
 // Used to store the original source of the SLO group in case we need to make low-level decision
 // based on where the SLOs came from.
 type PromSLOGroupSource struct {
-	K8sSlothV1     *k8sprometheusv1.PrometheusServiceLevel
-	SlothV1        *prometheusv1.Spec
-	OpenSLOV1Alpha *openslov1alpha.SLO
+	K8sSlothV1        *k8sprometheusv1.PrometheusServiceLevel
+	K8sSlothClusterV1 *k8sprometheusv1.ClusterPrometheusServiceLevel
+	SlothV1           *prometheusv1.Spec
+	OpenSLOV1Alpha    *openslov1alpha.SLO
 }
 
 // PromSLORules are the prometheus rules required by an SLO.
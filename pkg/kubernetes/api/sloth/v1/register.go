@@ -42,6 +42,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&PrometheusServiceLevel{},
 		&PrometheusServiceLevelList{},
+		&ClusterPrometheusServiceLevel{},
+		&ClusterPrometheusServiceLevelList{},
+		&PrometheusServiceLevelTemplate{},
+		&PrometheusServiceLevelTemplateList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
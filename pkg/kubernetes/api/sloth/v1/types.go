@@ -15,6 +15,10 @@ import (
 // +kubebuilder:printcolumn:name="DESIRED SLOs",type="integer",JSONPath=".status.processedSLOs"
 // +kubebuilder:printcolumn:name="READY SLOs",type="integer",JSONPath=".status.promOpRulesGeneratedSLOs"
 // +kubebuilder:printcolumn:name="GEN OK",type="boolean",JSONPath=".status.promOpRulesGenerated"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="REASON",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason"
+// +kubebuilder:printcolumn:name="BURNING SLOs",type="integer",JSONPath=".status.slosBurningOverBudget"
+// +kubebuilder:printcolumn:name="ALERTING SLOs",type="integer",JSONPath=".status.slosWithFiringAlerts"
 // +kubebuilder:printcolumn:name="GEN AGE",type="date",JSONPath=".status.lastPromOpRulesSuccessfulGenerated"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:singular=prometheusservicelevel,path=prometheusservicelevels,shortName=psl;pslo,scope=Namespaced,categories=slo;slos;sli;slis
@@ -29,6 +33,8 @@ type PrometheusServiceLevel struct {
 	Status PrometheusServiceLevelStatus `json:"status,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="(has(self.slos) && size(self.slos) > 0) || (has(self.templates) && size(self.templates) > 0)",message="at least one SLO or SLO template is required"
+//
 // ServiceLevelSpec is the spec for a PrometheusServiceLevel.
 type PrometheusServiceLevelSpec struct {
 	// +kubebuilder:validation:Required
@@ -44,10 +50,37 @@ type PrometheusServiceLevelSpec struct {
 	// +optional
 	SLOPlugins *SLOPlugins `json:"sloPlugins,omitempty"`
 
-	// +kubebuilder:validation:MinItems=1
-	//
-	// SLOs are the SLOs of the service.
+	// SLOs are the SLOs of the service. At least one SLO is required, declared here or
+	// instantiated from the SLO templates.
+	// +optional
 	SLOs []SLO `json:"slos,omitempty"`
+
+	// Templates are the PrometheusServiceLevelTemplate instances of the service, the SLOs
+	// rendered from the templates are added to the service SLOs.
+	// +optional
+	Templates []SLOTemplateInstance `json:"templates,omitempty"`
+}
+
+// SLOTemplateInstance is an instance of a PrometheusServiceLevelTemplate with the values of its params.
+type SLOTemplateInstance struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	//
+	// Name is the name of the PrometheusServiceLevelTemplate.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the PrometheusServiceLevelTemplate, by default the namespace
+	// of the PrometheusServiceLevel (the target namespace on ClusterPrometheusServiceLevels).
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Params are the values of the template params by param name.
+	// +optional
+	Params map[string]string `json:"params,omitempty"`
+
+	// Objective overrides the objective of the template SLOs, the percentage (0, 100] (e.g 99.9).
+	// +optional
+	Objective *float64 `json:"objective,omitempty"`
 }
 
 // SLO is the configuration/declaration of the service level objective of
@@ -231,8 +264,91 @@ type PrometheusServiceLevelStatus struct {
 	// infinite loop when the status is updated because it sends a watch updated event to the watchers
 	// of the K8s object.
 	ObservedGeneration int64 `json:"observedGeneration"`
+	// Conditions are the latest observations of the PrometheusServiceLevel state
+	// (e.g: Ready, SpecValid and RulesApplied).
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// SLOs are the status of each of the SLOs of the spec.
+	// +optional
+	SLOs []SLOStatus `json:"slos,omitempty"`
+	// Templates are the SLO templates used on the last handling, the SLOs are rendered again when
+	// the templates change.
+	// +optional
+	Templates []SLOTemplateStatus `json:"templates,omitempty"`
+	// SLOsBurningOverBudget tells how many SLOs are currently burning error budget faster than the
+	// SLO period allows, only set when the live SLO error budgets are enabled.
+	// +optional
+	SLOsBurningOverBudget *int `json:"slosBurningOverBudget,omitempty"`
+	// SLOsWithFiringAlerts tells how many SLOs have firing alerts, only set when the live SLO error
+	// budgets are enabled.
+	// +optional
+	SLOsWithFiringAlerts *int `json:"slosWithFiringAlerts,omitempty"`
+	// LastBudgetsUpdate is the last time the live SLO error budgets were updated.
+	// +optional
+	LastBudgetsUpdate *metav1.Time `json:"lastBudgetsUpdate,omitempty"`
+	// TargetNamespace is the namespace where the rules were stored on the last successful generation,
+	// used to garbage collect the objects of a previous target namespace.
+	// +optional
+	TargetNamespace string `json:"targetNamespace,omitempty"`
 }
 
+// SLOTemplateStatus is the status of a PrometheusServiceLevelTemplate used by a PrometheusServiceLevel.
+type SLOTemplateStatus struct {
+	// Name is the name of the PrometheusServiceLevelTemplate.
+	Name string `json:"name"`
+	// Namespace is the namespace of the PrometheusServiceLevelTemplate.
+	Namespace string `json:"namespace"`
+	// ObservedGeneration is the generation of the template used to render the SLOs.
+	ObservedGeneration int64 `json:"observedGeneration"`
+}
+
+// SLOStatus is the status of an SLO of a PrometheusServiceLevel.
+type SLOStatus struct {
+	// Name is the name of the SLO.
+	Name string `json:"name"`
+	// RuleGroups are the names of the Prometheus rule groups generated for the SLO.
+	// +optional
+	RuleGroups []string `json:"ruleGroups,omitempty"`
+	// Error is the error that made the SLO generation fail.
+	// +optional
+	Error string `json:"error,omitempty"`
+	// BurnRate is the current error budget burn rate of the SLO, 1 means that the SLO is burning
+	// exactly the error budget of the SLO period. Grouped SLOs report the highest one.
+	// +optional
+	BurnRate *float64 `json:"burnRate,omitempty"`
+	// RemainingBudgetPercent is the remaining error budget percent of the SLO period, negative
+	// when the budget has been exhausted. Grouped SLOs report the lowest one.
+	// +optional
+	RemainingBudgetPercent *float64 `json:"remainingBudgetPercent,omitempty"`
+	// FiringAlerts are the names of the SLO alerts that are currently firing.
+	// +optional
+	FiringAlerts []string `json:"firingAlerts,omitempty"`
+}
+
+// PrometheusServiceLevel status condition types.
+const (
+	// ConditionTypeReady tells if all the SLO rules of the PrometheusServiceLevel have been generated and applied.
+	ConditionTypeReady = "Ready"
+	// ConditionTypeSpecValid tells if the PrometheusServiceLevel spec and its SLOs are valid.
+	ConditionTypeSpecValid = "SpecValid"
+	// ConditionTypeRulesApplied tells if the generated SLO rules have been applied on Kubernetes.
+	ConditionTypeRulesApplied = "RulesApplied"
+)
+
+// PrometheusServiceLevel status condition reasons.
+const (
+	ConditionReasonReady            = "Ready"
+	ConditionReasonValidSpec        = "ValidSpec"
+	ConditionReasonRulesApplied     = "RulesApplied"
+	ConditionReasonInvalidSpec      = "InvalidSpec"
+	ConditionReasonInvalidSLO       = "InvalidSLO"
+	ConditionReasonInvalidTemplate  = "InvalidTemplate"
+	ConditionReasonGenerationFailed = "GenerationFailed"
+	ConditionReasonRulesApplyFailed = "RulesApplyFailed"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 //
 // PrometheusServiceLevelList is a list of PrometheusServiceLevel resources.
@@ -242,3 +358,108 @@ type PrometheusServiceLevelList struct {
 
 	Items []PrometheusServiceLevel `json:"items"`
 }
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="SERVICE",type="string",JSONPath=".spec.service"
+// +kubebuilder:printcolumn:name="TARGET NS",type="string",JSONPath=".spec.targetNamespace"
+// +kubebuilder:printcolumn:name="DESIRED SLOs",type="integer",JSONPath=".status.processedSLOs"
+// +kubebuilder:printcolumn:name="READY SLOs",type="integer",JSONPath=".status.promOpRulesGeneratedSLOs"
+// +kubebuilder:printcolumn:name="GEN OK",type="boolean",JSONPath=".status.promOpRulesGenerated"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="REASON",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason"
+// +kubebuilder:printcolumn:name="BURNING SLOs",type="integer",JSONPath=".status.slosBurningOverBudget"
+// +kubebuilder:printcolumn:name="ALERTING SLOs",type="integer",JSONPath=".status.slosWithFiringAlerts"
+// +kubebuilder:printcolumn:name="GEN AGE",type="date",JSONPath=".status.lastPromOpRulesSuccessfulGenerated"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:singular=clusterprometheusservicelevel,path=clusterprometheusservicelevels,shortName=cpsl;cpslo,scope=Cluster,categories=slo;slos;sli;slis
+//
+// ClusterPrometheusServiceLevel is the cluster scoped version of PrometheusServiceLevel, used for
+// the SLOs that don't belong to a namespace (e.g: platform or cluster components SLOs).
+// The generated rules will be stored on the spec target namespace.
+type ClusterPrometheusServiceLevel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterPrometheusServiceLevelSpec `json:"spec,omitempty"`
+	Status PrometheusServiceLevelStatus      `json:"status,omitempty"`
+}
+
+// ClusterPrometheusServiceLevelSpec is the spec for a ClusterPrometheusServiceLevel.
+type ClusterPrometheusServiceLevelSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	//
+	// TargetNamespace is the namespace where the generated rules will be stored.
+	TargetNamespace string `json:"targetNamespace"`
+
+	PrometheusServiceLevelSpec `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//
+// ClusterPrometheusServiceLevelList is a list of ClusterPrometheusServiceLevel resources.
+type ClusterPrometheusServiceLevelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClusterPrometheusServiceLevel `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:singular=prometheusserviceleveltemplate,path=prometheusserviceleveltemplates,shortName=pslt;pslotpl,scope=Namespaced,categories=slo;slos;sli;slis
+//
+// PrometheusServiceLevelTemplate is a reusable and parameterized set of SLOs that PrometheusServiceLevels
+// can instantiate with the values of the template params.
+//
+// The params are used on the SLO string fields (e.g: SLI queries, names, labels...) with the `${param}` form.
+type PrometheusServiceLevelTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PrometheusServiceLevelTemplateSpec `json:"spec,omitempty"`
+}
+
+// PrometheusServiceLevelTemplateSpec is the spec for a PrometheusServiceLevelTemplate.
+type PrometheusServiceLevelTemplateSpec struct {
+	// Params are the params that the template instances can set.
+	// +optional
+	Params []SLOTemplateParam `json:"params,omitempty"`
+
+	// +kubebuilder:validation:MinItems=1
+	//
+	// SLOs are the SLOs rendered for each template instance.
+	SLOs []SLO `json:"slos,omitempty"`
+}
+
+// SLOTemplateParam is a param of a PrometheusServiceLevelTemplate.
+type SLOTemplateParam struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	//
+	// Name is the name of the param, used on the template SLOs with the `${name}` form.
+	Name string `json:"name"`
+
+	// Description is the description of the param.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Default is the value used when the template instance doesn't set the param, the params
+	// without default are required.
+	// +optional
+	Default *string `json:"default,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//
+// PrometheusServiceLevelTemplateList is a list of PrometheusServiceLevelTemplate resources.
+type PrometheusServiceLevelTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []PrometheusServiceLevelTemplate `json:"items"`
+}
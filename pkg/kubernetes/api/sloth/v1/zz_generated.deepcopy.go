@@ -8,6 +8,7 @@ package v1
 import (
 	json "encoding/json"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -73,6 +74,84 @@ func (in *Alerting) DeepCopy() *Alerting {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPrometheusServiceLevel) DeepCopyInto(out *ClusterPrometheusServiceLevel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPrometheusServiceLevel.
+func (in *ClusterPrometheusServiceLevel) DeepCopy() *ClusterPrometheusServiceLevel {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPrometheusServiceLevel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterPrometheusServiceLevel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPrometheusServiceLevelList) DeepCopyInto(out *ClusterPrometheusServiceLevelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterPrometheusServiceLevel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPrometheusServiceLevelList.
+func (in *ClusterPrometheusServiceLevelList) DeepCopy() *ClusterPrometheusServiceLevelList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPrometheusServiceLevelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterPrometheusServiceLevelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPrometheusServiceLevelSpec) DeepCopyInto(out *ClusterPrometheusServiceLevelSpec) {
+	*out = *in
+	in.PrometheusServiceLevelSpec.DeepCopyInto(&out.PrometheusServiceLevelSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPrometheusServiceLevelSpec.
+func (in *ClusterPrometheusServiceLevelSpec) DeepCopy() *ClusterPrometheusServiceLevelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPrometheusServiceLevelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrometheusServiceLevel) DeepCopyInto(out *PrometheusServiceLevel) {
 	*out = *in
@@ -156,6 +235,13 @@ func (in *PrometheusServiceLevelSpec) DeepCopyInto(out *PrometheusServiceLevelSp
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]SLOTemplateInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -176,6 +262,39 @@ func (in *PrometheusServiceLevelStatus) DeepCopyInto(out *PrometheusServiceLevel
 		in, out := &in.LastPromOpRulesSuccessfulGenerated, &out.LastPromOpRulesSuccessfulGenerated
 		*out = (*in).DeepCopy()
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SLOs != nil {
+		in, out := &in.SLOs, &out.SLOs
+		*out = make([]SLOStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]SLOTemplateStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.SLOsBurningOverBudget != nil {
+		in, out := &in.SLOsBurningOverBudget, &out.SLOsBurningOverBudget
+		*out = new(int)
+		**out = **in
+	}
+	if in.SLOsWithFiringAlerts != nil {
+		in, out := &in.SLOsWithFiringAlerts, &out.SLOsWithFiringAlerts
+		*out = new(int)
+		**out = **in
+	}
+	if in.LastBudgetsUpdate != nil {
+		in, out := &in.LastBudgetsUpdate, &out.LastBudgetsUpdate
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -189,6 +308,96 @@ func (in *PrometheusServiceLevelStatus) DeepCopy() *PrometheusServiceLevelStatus
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusServiceLevelTemplate) DeepCopyInto(out *PrometheusServiceLevelTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusServiceLevelTemplate.
+func (in *PrometheusServiceLevelTemplate) DeepCopy() *PrometheusServiceLevelTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusServiceLevelTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PrometheusServiceLevelTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusServiceLevelTemplateList) DeepCopyInto(out *PrometheusServiceLevelTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PrometheusServiceLevelTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusServiceLevelTemplateList.
+func (in *PrometheusServiceLevelTemplateList) DeepCopy() *PrometheusServiceLevelTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusServiceLevelTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PrometheusServiceLevelTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusServiceLevelTemplateSpec) DeepCopyInto(out *PrometheusServiceLevelTemplateSpec) {
+	*out = *in
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make([]SLOTemplateParam, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SLOs != nil {
+		in, out := &in.SLOs, &out.SLOs
+		*out = make([]SLO, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusServiceLevelTemplateSpec.
+func (in *PrometheusServiceLevelTemplateSpec) DeepCopy() *PrometheusServiceLevelTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusServiceLevelTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SLI) DeepCopyInto(out *SLI) {
 	*out = *in
@@ -348,3 +557,104 @@ func (in *SLOPlugins) DeepCopy() *SLOPlugins {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOStatus) DeepCopyInto(out *SLOStatus) {
+	*out = *in
+	if in.RuleGroups != nil {
+		in, out := &in.RuleGroups, &out.RuleGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BurnRate != nil {
+		in, out := &in.BurnRate, &out.BurnRate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.RemainingBudgetPercent != nil {
+		in, out := &in.RemainingBudgetPercent, &out.RemainingBudgetPercent
+		*out = new(float64)
+		**out = **in
+	}
+	if in.FiringAlerts != nil {
+		in, out := &in.FiringAlerts, &out.FiringAlerts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOStatus.
+func (in *SLOStatus) DeepCopy() *SLOStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOTemplateInstance) DeepCopyInto(out *SLOTemplateInstance) {
+	*out = *in
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Objective != nil {
+		in, out := &in.Objective, &out.Objective
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOTemplateInstance.
+func (in *SLOTemplateInstance) DeepCopy() *SLOTemplateInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOTemplateInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOTemplateParam) DeepCopyInto(out *SLOTemplateParam) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOTemplateParam.
+func (in *SLOTemplateParam) DeepCopy() *SLOTemplateParam {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOTemplateParam)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOTemplateStatus) DeepCopyInto(out *SLOTemplateStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOTemplateStatus.
+func (in *SLOTemplateStatus) DeepCopy() *SLOTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
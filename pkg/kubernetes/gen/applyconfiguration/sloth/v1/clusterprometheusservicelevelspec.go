@@ -0,0 +1,83 @@
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+// ClusterPrometheusServiceLevelSpecApplyConfiguration represents a declarative configuration of the ClusterPrometheusServiceLevelSpec type for use
+// with apply.
+//
+// ClusterPrometheusServiceLevelSpec is the spec for a ClusterPrometheusServiceLevel.
+type ClusterPrometheusServiceLevelSpecApplyConfiguration struct {
+	// TargetNamespace is the namespace where the generated rules will be stored.
+	TargetNamespace                              *string `json:"targetNamespace,omitempty"`
+	PrometheusServiceLevelSpecApplyConfiguration `json:",inline"`
+}
+
+// ClusterPrometheusServiceLevelSpecApplyConfiguration constructs a declarative configuration of the ClusterPrometheusServiceLevelSpec type for use with
+// apply.
+func ClusterPrometheusServiceLevelSpec() *ClusterPrometheusServiceLevelSpecApplyConfiguration {
+	return &ClusterPrometheusServiceLevelSpecApplyConfiguration{}
+}
+
+// WithTargetNamespace sets the TargetNamespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TargetNamespace field is set to the value of the last call.
+func (b *ClusterPrometheusServiceLevelSpecApplyConfiguration) WithTargetNamespace(value string) *ClusterPrometheusServiceLevelSpecApplyConfiguration {
+	b.TargetNamespace = &value
+	return b
+}
+
+// WithService sets the Service field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Service field is set to the value of the last call.
+func (b *ClusterPrometheusServiceLevelSpecApplyConfiguration) WithService(value string) *ClusterPrometheusServiceLevelSpecApplyConfiguration {
+	b.PrometheusServiceLevelSpecApplyConfiguration.Service = &value
+	return b
+}
+
+// WithLabels puts the entries into the Labels field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the entries provided by each call will be put on the Labels field,
+// overwriting an existing map entries in Labels field with the same key.
+func (b *ClusterPrometheusServiceLevelSpecApplyConfiguration) WithLabels(entries map[string]string) *ClusterPrometheusServiceLevelSpecApplyConfiguration {
+	if b.PrometheusServiceLevelSpecApplyConfiguration.Labels == nil && len(entries) > 0 {
+		b.PrometheusServiceLevelSpecApplyConfiguration.Labels = make(map[string]string, len(entries))
+	}
+	for k, v := range entries {
+		b.PrometheusServiceLevelSpecApplyConfiguration.Labels[k] = v
+	}
+	return b
+}
+
+// WithSLOPlugins sets the SLOPlugins field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SLOPlugins field is set to the value of the last call.
+func (b *ClusterPrometheusServiceLevelSpecApplyConfiguration) WithSLOPlugins(value *SLOPluginsApplyConfiguration) *ClusterPrometheusServiceLevelSpecApplyConfiguration {
+	b.PrometheusServiceLevelSpecApplyConfiguration.SLOPlugins = value
+	return b
+}
+
+// WithSLOs adds the given value to the SLOs field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the SLOs field.
+func (b *ClusterPrometheusServiceLevelSpecApplyConfiguration) WithSLOs(values ...*SLOApplyConfiguration) *ClusterPrometheusServiceLevelSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithSLOs")
+		}
+		b.PrometheusServiceLevelSpecApplyConfiguration.SLOs = append(b.PrometheusServiceLevelSpecApplyConfiguration.SLOs, *values[i])
+	}
+	return b
+}
+
+// WithTemplates adds the given value to the Templates field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Templates field.
+func (b *ClusterPrometheusServiceLevelSpecApplyConfiguration) WithTemplates(values ...*SLOTemplateInstanceApplyConfiguration) *ClusterPrometheusServiceLevelSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithTemplates")
+		}
+		b.PrometheusServiceLevelSpecApplyConfiguration.Templates = append(b.PrometheusServiceLevelSpecApplyConfiguration.Templates, *values[i])
+	}
+	return b
+}
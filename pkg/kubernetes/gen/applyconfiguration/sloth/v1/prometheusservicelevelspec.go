@@ -14,8 +14,12 @@ type PrometheusServiceLevelSpecApplyConfiguration struct {
 	Labels map[string]string `json:"labels,omitempty"`
 	// SLOPlugins will be added to the SLO generation plugin chain of all SLOs.
 	SLOPlugins *SLOPluginsApplyConfiguration `json:"sloPlugins,omitempty"`
-	// SLOs are the SLOs of the service.
+	// SLOs are the SLOs of the service. At least one SLO is required, declared here or
+	// instantiated from the SLO templates.
 	SLOs []SLOApplyConfiguration `json:"slos,omitempty"`
+	// Templates are the PrometheusServiceLevelTemplate instances of the service, the SLOs
+	// rendered from the templates are added to the service SLOs.
+	Templates []SLOTemplateInstanceApplyConfiguration `json:"templates,omitempty"`
 }
 
 // PrometheusServiceLevelSpecApplyConfiguration constructs a declarative configuration of the PrometheusServiceLevelSpec type for use with
@@ -66,3 +70,16 @@ func (b *PrometheusServiceLevelSpecApplyConfiguration) WithSLOs(values ...*SLOAp
 	}
 	return b
 }
+
+// WithTemplates adds the given value to the Templates field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Templates field.
+func (b *PrometheusServiceLevelSpecApplyConfiguration) WithTemplates(values ...*SLOTemplateInstanceApplyConfiguration) *PrometheusServiceLevelSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithTemplates")
+		}
+		b.Templates = append(b.Templates, *values[i])
+	}
+	return b
+}
@@ -4,6 +4,7 @@ package v1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	applyconfigurationsmetav1 "k8s.io/client-go/applyconfigurations/meta/v1"
 )
 
 // PrometheusServiceLevelStatusApplyConfiguration represents a declarative configuration of the PrometheusServiceLevelStatus type for use
@@ -21,6 +22,25 @@ type PrometheusServiceLevelStatusApplyConfiguration struct {
 	// infinite loop when the status is updated because it sends a watch updated event to the watchers
 	// of the K8s object.
 	ObservedGeneration *int64 `json:"observedGeneration,omitempty"`
+	// Conditions are the latest observations of the PrometheusServiceLevel state
+	// (e.g: Ready, SpecValid and RulesApplied).
+	Conditions []applyconfigurationsmetav1.ConditionApplyConfiguration `json:"conditions,omitempty"`
+	// SLOs are the status of each of the SLOs of the spec.
+	SLOs []SLOStatusApplyConfiguration `json:"slos,omitempty"`
+	// Templates are the SLO templates used on the last handling, the SLOs are rendered again when
+	// the templates change.
+	Templates []SLOTemplateStatusApplyConfiguration `json:"templates,omitempty"`
+	// SLOsBurningOverBudget tells how many SLOs are currently burning error budget faster than the
+	// SLO period allows, only set when the live SLO error budgets are enabled.
+	SLOsBurningOverBudget *int `json:"slosBurningOverBudget,omitempty"`
+	// SLOsWithFiringAlerts tells how many SLOs have firing alerts, only set when the live SLO error
+	// budgets are enabled.
+	SLOsWithFiringAlerts *int `json:"slosWithFiringAlerts,omitempty"`
+	// LastBudgetsUpdate is the last time the live SLO error budgets were updated.
+	LastBudgetsUpdate *metav1.Time `json:"lastBudgetsUpdate,omitempty"`
+	// TargetNamespace is the namespace where the rules were stored on the last successful generation,
+	// used to garbage collect the objects of a previous target namespace.
+	TargetNamespace *string `json:"targetNamespace,omitempty"`
 }
 
 // PrometheusServiceLevelStatusApplyConfiguration constructs a declarative configuration of the PrometheusServiceLevelStatus type for use with
@@ -68,3 +88,74 @@ func (b *PrometheusServiceLevelStatusApplyConfiguration) WithObservedGeneration(
 	b.ObservedGeneration = &value
 	return b
 }
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *PrometheusServiceLevelStatusApplyConfiguration) WithConditions(values ...*applyconfigurationsmetav1.ConditionApplyConfiguration) *PrometheusServiceLevelStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithConditions")
+		}
+		b.Conditions = append(b.Conditions, *values[i])
+	}
+	return b
+}
+
+// WithSLOs adds the given value to the SLOs field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the SLOs field.
+func (b *PrometheusServiceLevelStatusApplyConfiguration) WithSLOs(values ...*SLOStatusApplyConfiguration) *PrometheusServiceLevelStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithSLOs")
+		}
+		b.SLOs = append(b.SLOs, *values[i])
+	}
+	return b
+}
+
+// WithTemplates adds the given value to the Templates field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Templates field.
+func (b *PrometheusServiceLevelStatusApplyConfiguration) WithTemplates(values ...*SLOTemplateStatusApplyConfiguration) *PrometheusServiceLevelStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithTemplates")
+		}
+		b.Templates = append(b.Templates, *values[i])
+	}
+	return b
+}
+
+// WithSLOsBurningOverBudget sets the SLOsBurningOverBudget field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SLOsBurningOverBudget field is set to the value of the last call.
+func (b *PrometheusServiceLevelStatusApplyConfiguration) WithSLOsBurningOverBudget(value int) *PrometheusServiceLevelStatusApplyConfiguration {
+	b.SLOsBurningOverBudget = &value
+	return b
+}
+
+// WithSLOsWithFiringAlerts sets the SLOsWithFiringAlerts field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SLOsWithFiringAlerts field is set to the value of the last call.
+func (b *PrometheusServiceLevelStatusApplyConfiguration) WithSLOsWithFiringAlerts(value int) *PrometheusServiceLevelStatusApplyConfiguration {
+	b.SLOsWithFiringAlerts = &value
+	return b
+}
+
+// WithLastBudgetsUpdate sets the LastBudgetsUpdate field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastBudgetsUpdate field is set to the value of the last call.
+func (b *PrometheusServiceLevelStatusApplyConfiguration) WithLastBudgetsUpdate(value metav1.Time) *PrometheusServiceLevelStatusApplyConfiguration {
+	b.LastBudgetsUpdate = &value
+	return b
+}
+
+// WithTargetNamespace sets the TargetNamespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TargetNamespace field is set to the value of the last call.
+func (b *PrometheusServiceLevelStatusApplyConfiguration) WithTargetNamespace(value string) *PrometheusServiceLevelStatusApplyConfiguration {
+	b.TargetNamespace = &value
+	return b
+}
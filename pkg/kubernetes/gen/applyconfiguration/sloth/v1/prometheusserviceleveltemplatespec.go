@@ -0,0 +1,46 @@
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+// PrometheusServiceLevelTemplateSpecApplyConfiguration represents a declarative configuration of the PrometheusServiceLevelTemplateSpec type for use
+// with apply.
+//
+// PrometheusServiceLevelTemplateSpec is the spec for a PrometheusServiceLevelTemplate.
+type PrometheusServiceLevelTemplateSpecApplyConfiguration struct {
+	// Params are the params that the template instances can set.
+	Params []SLOTemplateParamApplyConfiguration `json:"params,omitempty"`
+	// SLOs are the SLOs rendered for each template instance.
+	SLOs []SLOApplyConfiguration `json:"slos,omitempty"`
+}
+
+// PrometheusServiceLevelTemplateSpecApplyConfiguration constructs a declarative configuration of the PrometheusServiceLevelTemplateSpec type for use with
+// apply.
+func PrometheusServiceLevelTemplateSpec() *PrometheusServiceLevelTemplateSpecApplyConfiguration {
+	return &PrometheusServiceLevelTemplateSpecApplyConfiguration{}
+}
+
+// WithParams adds the given value to the Params field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Params field.
+func (b *PrometheusServiceLevelTemplateSpecApplyConfiguration) WithParams(values ...*SLOTemplateParamApplyConfiguration) *PrometheusServiceLevelTemplateSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithParams")
+		}
+		b.Params = append(b.Params, *values[i])
+	}
+	return b
+}
+
+// WithSLOs adds the given value to the SLOs field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the SLOs field.
+func (b *PrometheusServiceLevelTemplateSpecApplyConfiguration) WithSLOs(values ...*SLOApplyConfiguration) *PrometheusServiceLevelTemplateSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithSLOs")
+		}
+		b.SLOs = append(b.SLOs, *values[i])
+	}
+	return b
+}
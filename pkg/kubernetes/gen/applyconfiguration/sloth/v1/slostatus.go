@@ -0,0 +1,82 @@
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+// SLOStatusApplyConfiguration represents a declarative configuration of the SLOStatus type for use
+// with apply.
+//
+// SLOStatus is the status of an SLO of a PrometheusServiceLevel.
+type SLOStatusApplyConfiguration struct {
+	// Name is the name of the SLO.
+	Name *string `json:"name,omitempty"`
+	// RuleGroups are the names of the Prometheus rule groups generated for the SLO.
+	RuleGroups []string `json:"ruleGroups,omitempty"`
+	// Error is the error that made the SLO generation fail.
+	Error *string `json:"error,omitempty"`
+	// BurnRate is the current error budget burn rate of the SLO, 1 means that the SLO is burning
+	// exactly the error budget of the SLO period. Grouped SLOs report the highest one.
+	BurnRate *float64 `json:"burnRate,omitempty"`
+	// RemainingBudgetPercent is the remaining error budget percent of the SLO period, negative
+	// when the budget has been exhausted. Grouped SLOs report the lowest one.
+	RemainingBudgetPercent *float64 `json:"remainingBudgetPercent,omitempty"`
+	// FiringAlerts are the names of the SLO alerts that are currently firing.
+	FiringAlerts []string `json:"firingAlerts,omitempty"`
+}
+
+// SLOStatusApplyConfiguration constructs a declarative configuration of the SLOStatus type for use with
+// apply.
+func SLOStatus() *SLOStatusApplyConfiguration {
+	return &SLOStatusApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *SLOStatusApplyConfiguration) WithName(value string) *SLOStatusApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithRuleGroups adds the given value to the RuleGroups field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the RuleGroups field.
+func (b *SLOStatusApplyConfiguration) WithRuleGroups(values ...string) *SLOStatusApplyConfiguration {
+	for i := range values {
+		b.RuleGroups = append(b.RuleGroups, values[i])
+	}
+	return b
+}
+
+// WithError sets the Error field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Error field is set to the value of the last call.
+func (b *SLOStatusApplyConfiguration) WithError(value string) *SLOStatusApplyConfiguration {
+	b.Error = &value
+	return b
+}
+
+// WithBurnRate sets the BurnRate field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BurnRate field is set to the value of the last call.
+func (b *SLOStatusApplyConfiguration) WithBurnRate(value float64) *SLOStatusApplyConfiguration {
+	b.BurnRate = &value
+	return b
+}
+
+// WithRemainingBudgetPercent sets the RemainingBudgetPercent field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RemainingBudgetPercent field is set to the value of the last call.
+func (b *SLOStatusApplyConfiguration) WithRemainingBudgetPercent(value float64) *SLOStatusApplyConfiguration {
+	b.RemainingBudgetPercent = &value
+	return b
+}
+
+// WithFiringAlerts adds the given value to the FiringAlerts field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the FiringAlerts field.
+func (b *SLOStatusApplyConfiguration) WithFiringAlerts(values ...string) *SLOStatusApplyConfiguration {
+	for i := range values {
+		b.FiringAlerts = append(b.FiringAlerts, values[i])
+	}
+	return b
+}
@@ -0,0 +1,63 @@
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+// SLOTemplateInstanceApplyConfiguration represents a declarative configuration of the SLOTemplateInstance type for use
+// with apply.
+//
+// SLOTemplateInstance is an instance of a PrometheusServiceLevelTemplate with the values of its params.
+type SLOTemplateInstanceApplyConfiguration struct {
+	// Name is the name of the PrometheusServiceLevelTemplate.
+	Name *string `json:"name,omitempty"`
+	// Namespace is the namespace of the PrometheusServiceLevelTemplate, by default the namespace
+	// of the PrometheusServiceLevel (the target namespace on ClusterPrometheusServiceLevels).
+	Namespace *string `json:"namespace,omitempty"`
+	// Params are the values of the template params by param name.
+	Params map[string]string `json:"params,omitempty"`
+	// Objective overrides the objective of the template SLOs, the percentage (0, 100] (e.g 99.9).
+	Objective *float64 `json:"objective,omitempty"`
+}
+
+// SLOTemplateInstanceApplyConfiguration constructs a declarative configuration of the SLOTemplateInstance type for use with
+// apply.
+func SLOTemplateInstance() *SLOTemplateInstanceApplyConfiguration {
+	return &SLOTemplateInstanceApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *SLOTemplateInstanceApplyConfiguration) WithName(value string) *SLOTemplateInstanceApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithNamespace sets the Namespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Namespace field is set to the value of the last call.
+func (b *SLOTemplateInstanceApplyConfiguration) WithNamespace(value string) *SLOTemplateInstanceApplyConfiguration {
+	b.Namespace = &value
+	return b
+}
+
+// WithParams puts the entries into the Params field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the entries provided by each call will be put on the Params field,
+// overwriting an existing map entries in Params field with the same key.
+func (b *SLOTemplateInstanceApplyConfiguration) WithParams(entries map[string]string) *SLOTemplateInstanceApplyConfiguration {
+	if b.Params == nil && len(entries) > 0 {
+		b.Params = make(map[string]string, len(entries))
+	}
+	for k, v := range entries {
+		b.Params[k] = v
+	}
+	return b
+}
+
+// WithObjective sets the Objective field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Objective field is set to the value of the last call.
+func (b *SLOTemplateInstanceApplyConfiguration) WithObjective(value float64) *SLOTemplateInstanceApplyConfiguration {
+	b.Objective = &value
+	return b
+}
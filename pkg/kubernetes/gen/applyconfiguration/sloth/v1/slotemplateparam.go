@@ -0,0 +1,47 @@
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+// SLOTemplateParamApplyConfiguration represents a declarative configuration of the SLOTemplateParam type for use
+// with apply.
+//
+// SLOTemplateParam is a param of a PrometheusServiceLevelTemplate.
+type SLOTemplateParamApplyConfiguration struct {
+	// Name is the name of the param, used on the template SLOs with the `${name}` form.
+	Name *string `json:"name,omitempty"`
+	// Description is the description of the param.
+	Description *string `json:"description,omitempty"`
+	// Default is the value used when the template instance doesn't set the param, the params
+	// without default are required.
+	Default *string `json:"default,omitempty"`
+}
+
+// SLOTemplateParamApplyConfiguration constructs a declarative configuration of the SLOTemplateParam type for use with
+// apply.
+func SLOTemplateParam() *SLOTemplateParamApplyConfiguration {
+	return &SLOTemplateParamApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *SLOTemplateParamApplyConfiguration) WithName(value string) *SLOTemplateParamApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithDescription sets the Description field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Description field is set to the value of the last call.
+func (b *SLOTemplateParamApplyConfiguration) WithDescription(value string) *SLOTemplateParamApplyConfiguration {
+	b.Description = &value
+	return b
+}
+
+// WithDefault sets the Default field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Default field is set to the value of the last call.
+func (b *SLOTemplateParamApplyConfiguration) WithDefault(value string) *SLOTemplateParamApplyConfiguration {
+	b.Default = &value
+	return b
+}
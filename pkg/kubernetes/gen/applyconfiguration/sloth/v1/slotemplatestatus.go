@@ -0,0 +1,46 @@
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1
+
+// SLOTemplateStatusApplyConfiguration represents a declarative configuration of the SLOTemplateStatus type for use
+// with apply.
+//
+// SLOTemplateStatus is the status of a PrometheusServiceLevelTemplate used by a PrometheusServiceLevel.
+type SLOTemplateStatusApplyConfiguration struct {
+	// Name is the name of the PrometheusServiceLevelTemplate.
+	Name *string `json:"name,omitempty"`
+	// Namespace is the namespace of the PrometheusServiceLevelTemplate.
+	Namespace *string `json:"namespace,omitempty"`
+	// ObservedGeneration is the generation of the template used to render the SLOs.
+	ObservedGeneration *int64 `json:"observedGeneration,omitempty"`
+}
+
+// SLOTemplateStatusApplyConfiguration constructs a declarative configuration of the SLOTemplateStatus type for use with
+// apply.
+func SLOTemplateStatus() *SLOTemplateStatusApplyConfiguration {
+	return &SLOTemplateStatusApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *SLOTemplateStatusApplyConfiguration) WithName(value string) *SLOTemplateStatusApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithNamespace sets the Namespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Namespace field is set to the value of the last call.
+func (b *SLOTemplateStatusApplyConfiguration) WithNamespace(value string) *SLOTemplateStatusApplyConfiguration {
+	b.Namespace = &value
+	return b
+}
+
+// WithObservedGeneration sets the ObservedGeneration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObservedGeneration field is set to the value of the last call.
+func (b *SLOTemplateStatusApplyConfiguration) WithObservedGeneration(value int64) *SLOTemplateStatusApplyConfiguration {
+	b.ObservedGeneration = &value
+	return b
+}
@@ -20,12 +20,20 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &slothv1.AlertApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("Alerting"):
 		return &slothv1.AlertingApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("ClusterPrometheusServiceLevel"):
+		return &slothv1.ClusterPrometheusServiceLevelApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("ClusterPrometheusServiceLevelSpec"):
+		return &slothv1.ClusterPrometheusServiceLevelSpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("PrometheusServiceLevel"):
 		return &slothv1.PrometheusServiceLevelApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("PrometheusServiceLevelSpec"):
 		return &slothv1.PrometheusServiceLevelSpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("PrometheusServiceLevelStatus"):
 		return &slothv1.PrometheusServiceLevelStatusApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("PrometheusServiceLevelTemplate"):
+		return &slothv1.PrometheusServiceLevelTemplateApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("PrometheusServiceLevelTemplateSpec"):
+		return &slothv1.PrometheusServiceLevelTemplateSpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("SLI"):
 		return &slothv1.SLIApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("SLIEvents"):
@@ -40,6 +48,14 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &slothv1.SLOPluginApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("SLOPlugins"):
 		return &slothv1.SLOPluginsApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("SLOStatus"):
+		return &slothv1.SLOStatusApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("SLOTemplateInstance"):
+		return &slothv1.SLOTemplateInstanceApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("SLOTemplateParam"):
+		return &slothv1.SLOTemplateParamApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("SLOTemplateStatus"):
+		return &slothv1.SLOTemplateStatusApplyConfiguration{}
 
 	}
 	return nil
@@ -0,0 +1,58 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	applyconfigurationslothv1 "github.com/slok/sloth/pkg/kubernetes/gen/applyconfiguration/sloth/v1"
+	scheme "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// ClusterPrometheusServiceLevelsGetter has a method to return a ClusterPrometheusServiceLevelInterface.
+// A group's client should implement this interface.
+type ClusterPrometheusServiceLevelsGetter interface {
+	ClusterPrometheusServiceLevels() ClusterPrometheusServiceLevelInterface
+}
+
+// ClusterPrometheusServiceLevelInterface has methods to work with ClusterPrometheusServiceLevel resources.
+type ClusterPrometheusServiceLevelInterface interface {
+	Create(ctx context.Context, clusterPrometheusServiceLevel *slothv1.ClusterPrometheusServiceLevel, opts metav1.CreateOptions) (*slothv1.ClusterPrometheusServiceLevel, error)
+	Update(ctx context.Context, clusterPrometheusServiceLevel *slothv1.ClusterPrometheusServiceLevel, opts metav1.UpdateOptions) (*slothv1.ClusterPrometheusServiceLevel, error)
+	// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+	UpdateStatus(ctx context.Context, clusterPrometheusServiceLevel *slothv1.ClusterPrometheusServiceLevel, opts metav1.UpdateOptions) (*slothv1.ClusterPrometheusServiceLevel, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*slothv1.ClusterPrometheusServiceLevel, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*slothv1.ClusterPrometheusServiceLevelList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *slothv1.ClusterPrometheusServiceLevel, err error)
+	Apply(ctx context.Context, clusterPrometheusServiceLevel *applyconfigurationslothv1.ClusterPrometheusServiceLevelApplyConfiguration, opts metav1.ApplyOptions) (result *slothv1.ClusterPrometheusServiceLevel, err error)
+	// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+	ApplyStatus(ctx context.Context, clusterPrometheusServiceLevel *applyconfigurationslothv1.ClusterPrometheusServiceLevelApplyConfiguration, opts metav1.ApplyOptions) (result *slothv1.ClusterPrometheusServiceLevel, err error)
+	ClusterPrometheusServiceLevelExpansion
+}
+
+// clusterPrometheusServiceLevels implements ClusterPrometheusServiceLevelInterface
+type clusterPrometheusServiceLevels struct {
+	*gentype.ClientWithListAndApply[*slothv1.ClusterPrometheusServiceLevel, *slothv1.ClusterPrometheusServiceLevelList, *applyconfigurationslothv1.ClusterPrometheusServiceLevelApplyConfiguration]
+}
+
+// newClusterPrometheusServiceLevels returns a ClusterPrometheusServiceLevels
+func newClusterPrometheusServiceLevels(c *SlothV1Client) *clusterPrometheusServiceLevels {
+	return &clusterPrometheusServiceLevels{
+		gentype.NewClientWithListAndApply[*slothv1.ClusterPrometheusServiceLevel, *slothv1.ClusterPrometheusServiceLevelList, *applyconfigurationslothv1.ClusterPrometheusServiceLevelApplyConfiguration](
+			"clusterprometheusservicelevels",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			"",
+			func() *slothv1.ClusterPrometheusServiceLevel { return &slothv1.ClusterPrometheusServiceLevel{} },
+			func() *slothv1.ClusterPrometheusServiceLevelList { return &slothv1.ClusterPrometheusServiceLevelList{} },
+		),
+	}
+}
@@ -0,0 +1,37 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/gen/applyconfiguration/sloth/v1"
+	typedslothv1 "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/typed/sloth/v1"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// fakeClusterPrometheusServiceLevels implements ClusterPrometheusServiceLevelInterface
+type fakeClusterPrometheusServiceLevels struct {
+	*gentype.FakeClientWithListAndApply[*v1.ClusterPrometheusServiceLevel, *v1.ClusterPrometheusServiceLevelList, *slothv1.ClusterPrometheusServiceLevelApplyConfiguration]
+	Fake *FakeSlothV1
+}
+
+func newFakeClusterPrometheusServiceLevels(fake *FakeSlothV1) typedslothv1.ClusterPrometheusServiceLevelInterface {
+	return &fakeClusterPrometheusServiceLevels{
+		gentype.NewFakeClientWithListAndApply[*v1.ClusterPrometheusServiceLevel, *v1.ClusterPrometheusServiceLevelList, *slothv1.ClusterPrometheusServiceLevelApplyConfiguration](
+			fake.Fake,
+			"",
+			v1.SchemeGroupVersion.WithResource("clusterprometheusservicelevels"),
+			v1.SchemeGroupVersion.WithKind("ClusterPrometheusServiceLevel"),
+			func() *v1.ClusterPrometheusServiceLevel { return &v1.ClusterPrometheusServiceLevel{} },
+			func() *v1.ClusterPrometheusServiceLevelList { return &v1.ClusterPrometheusServiceLevelList{} },
+			func(dst, src *v1.ClusterPrometheusServiceLevelList) { dst.ListMeta = src.ListMeta },
+			func(list *v1.ClusterPrometheusServiceLevelList) []*v1.ClusterPrometheusServiceLevel {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *v1.ClusterPrometheusServiceLevelList, items []*v1.ClusterPrometheusServiceLevel) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
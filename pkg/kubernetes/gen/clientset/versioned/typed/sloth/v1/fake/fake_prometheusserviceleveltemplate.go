@@ -0,0 +1,37 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/gen/applyconfiguration/sloth/v1"
+	typedslothv1 "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/typed/sloth/v1"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// fakePrometheusServiceLevelTemplates implements PrometheusServiceLevelTemplateInterface
+type fakePrometheusServiceLevelTemplates struct {
+	*gentype.FakeClientWithListAndApply[*v1.PrometheusServiceLevelTemplate, *v1.PrometheusServiceLevelTemplateList, *slothv1.PrometheusServiceLevelTemplateApplyConfiguration]
+	Fake *FakeSlothV1
+}
+
+func newFakePrometheusServiceLevelTemplates(fake *FakeSlothV1, namespace string) typedslothv1.PrometheusServiceLevelTemplateInterface {
+	return &fakePrometheusServiceLevelTemplates{
+		gentype.NewFakeClientWithListAndApply[*v1.PrometheusServiceLevelTemplate, *v1.PrometheusServiceLevelTemplateList, *slothv1.PrometheusServiceLevelTemplateApplyConfiguration](
+			fake.Fake,
+			namespace,
+			v1.SchemeGroupVersion.WithResource("prometheusserviceleveltemplates"),
+			v1.SchemeGroupVersion.WithKind("PrometheusServiceLevelTemplate"),
+			func() *v1.PrometheusServiceLevelTemplate { return &v1.PrometheusServiceLevelTemplate{} },
+			func() *v1.PrometheusServiceLevelTemplateList { return &v1.PrometheusServiceLevelTemplateList{} },
+			func(dst, src *v1.PrometheusServiceLevelTemplateList) { dst.ListMeta = src.ListMeta },
+			func(list *v1.PrometheusServiceLevelTemplateList) []*v1.PrometheusServiceLevelTemplate {
+				return gentype.ToPointerSlice(list.Items)
+			},
+			func(list *v1.PrometheusServiceLevelTemplateList, items []*v1.PrometheusServiceLevelTemplate) {
+				list.Items = gentype.FromPointerSlice(items)
+			},
+		),
+		fake,
+	}
+}
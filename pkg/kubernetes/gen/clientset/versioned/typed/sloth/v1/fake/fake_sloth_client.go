@@ -12,10 +12,18 @@ type FakeSlothV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeSlothV1) ClusterPrometheusServiceLevels() v1.ClusterPrometheusServiceLevelInterface {
+	return newFakeClusterPrometheusServiceLevels(c)
+}
+
 func (c *FakeSlothV1) PrometheusServiceLevels(namespace string) v1.PrometheusServiceLevelInterface {
 	return newFakePrometheusServiceLevels(c, namespace)
 }
 
+func (c *FakeSlothV1) PrometheusServiceLevelTemplates(namespace string) v1.PrometheusServiceLevelTemplateInterface {
+	return newFakePrometheusServiceLevelTemplates(c, namespace)
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeSlothV1) RESTClient() rest.Interface {
@@ -2,4 +2,8 @@
 
 package v1
 
+type ClusterPrometheusServiceLevelExpansion interface{}
+
 type PrometheusServiceLevelExpansion interface{}
+
+type PrometheusServiceLevelTemplateExpansion interface{}
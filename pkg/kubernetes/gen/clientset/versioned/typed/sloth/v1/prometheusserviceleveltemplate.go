@@ -0,0 +1,56 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	applyconfigurationslothv1 "github.com/slok/sloth/pkg/kubernetes/gen/applyconfiguration/sloth/v1"
+	scheme "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// PrometheusServiceLevelTemplatesGetter has a method to return a PrometheusServiceLevelTemplateInterface.
+// A group's client should implement this interface.
+type PrometheusServiceLevelTemplatesGetter interface {
+	PrometheusServiceLevelTemplates(namespace string) PrometheusServiceLevelTemplateInterface
+}
+
+// PrometheusServiceLevelTemplateInterface has methods to work with PrometheusServiceLevelTemplate resources.
+type PrometheusServiceLevelTemplateInterface interface {
+	Create(ctx context.Context, prometheusServiceLevelTemplate *slothv1.PrometheusServiceLevelTemplate, opts metav1.CreateOptions) (*slothv1.PrometheusServiceLevelTemplate, error)
+	Update(ctx context.Context, prometheusServiceLevelTemplate *slothv1.PrometheusServiceLevelTemplate, opts metav1.UpdateOptions) (*slothv1.PrometheusServiceLevelTemplate, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*slothv1.PrometheusServiceLevelTemplate, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*slothv1.PrometheusServiceLevelTemplateList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *slothv1.PrometheusServiceLevelTemplate, err error)
+	Apply(ctx context.Context, prometheusServiceLevelTemplate *applyconfigurationslothv1.PrometheusServiceLevelTemplateApplyConfiguration, opts metav1.ApplyOptions) (result *slothv1.PrometheusServiceLevelTemplate, err error)
+	PrometheusServiceLevelTemplateExpansion
+}
+
+// prometheusServiceLevelTemplates implements PrometheusServiceLevelTemplateInterface
+type prometheusServiceLevelTemplates struct {
+	*gentype.ClientWithListAndApply[*slothv1.PrometheusServiceLevelTemplate, *slothv1.PrometheusServiceLevelTemplateList, *applyconfigurationslothv1.PrometheusServiceLevelTemplateApplyConfiguration]
+}
+
+// newPrometheusServiceLevelTemplates returns a PrometheusServiceLevelTemplates
+func newPrometheusServiceLevelTemplates(c *SlothV1Client, namespace string) *prometheusServiceLevelTemplates {
+	return &prometheusServiceLevelTemplates{
+		gentype.NewClientWithListAndApply[*slothv1.PrometheusServiceLevelTemplate, *slothv1.PrometheusServiceLevelTemplateList, *applyconfigurationslothv1.PrometheusServiceLevelTemplateApplyConfiguration](
+			"prometheusserviceleveltemplates",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			namespace,
+			func() *slothv1.PrometheusServiceLevelTemplate { return &slothv1.PrometheusServiceLevelTemplate{} },
+			func() *slothv1.PrometheusServiceLevelTemplateList {
+				return &slothv1.PrometheusServiceLevelTemplateList{}
+			},
+		),
+	}
+}
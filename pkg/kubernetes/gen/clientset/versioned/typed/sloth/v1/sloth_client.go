@@ -12,7 +12,9 @@ import (
 
 type SlothV1Interface interface {
 	RESTClient() rest.Interface
+	ClusterPrometheusServiceLevelsGetter
 	PrometheusServiceLevelsGetter
+	PrometheusServiceLevelTemplatesGetter
 }
 
 // SlothV1Client is used to interact with features provided by the sloth.slok.dev group.
@@ -20,10 +22,18 @@ type SlothV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *SlothV1Client) ClusterPrometheusServiceLevels() ClusterPrometheusServiceLevelInterface {
+	return newClusterPrometheusServiceLevels(c)
+}
+
 func (c *SlothV1Client) PrometheusServiceLevels(namespace string) PrometheusServiceLevelInterface {
 	return newPrometheusServiceLevels(c, namespace)
 }
 
+func (c *SlothV1Client) PrometheusServiceLevelTemplates(namespace string) PrometheusServiceLevelTemplateInterface {
+	return newPrometheusServiceLevelTemplates(c, namespace)
+}
+
 // NewForConfig creates a new SlothV1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -37,8 +37,12 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=sloth.slok.dev, Version=v1
+	case v1.SchemeGroupVersion.WithResource("clusterprometheusservicelevels"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sloth().V1().ClusterPrometheusServiceLevels().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("prometheusservicelevels"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Sloth().V1().PrometheusServiceLevels().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("prometheusserviceleveltemplates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sloth().V1().PrometheusServiceLevelTemplates().Informer()}, nil
 
 	}
 
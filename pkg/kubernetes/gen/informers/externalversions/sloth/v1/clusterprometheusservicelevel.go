@@ -0,0 +1,85 @@
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	time "time"
+
+	apislothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	versioned "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned"
+	internalinterfaces "github.com/slok/sloth/pkg/kubernetes/gen/informers/externalversions/internalinterfaces"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/gen/listers/sloth/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// ClusterPrometheusServiceLevelInformer provides access to a shared informer and lister for
+// ClusterPrometheusServiceLevels.
+type ClusterPrometheusServiceLevelInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() slothv1.ClusterPrometheusServiceLevelLister
+}
+
+type clusterPrometheusServiceLevelInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewClusterPrometheusServiceLevelInformer constructs a new informer for ClusterPrometheusServiceLevel type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewClusterPrometheusServiceLevelInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredClusterPrometheusServiceLevelInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredClusterPrometheusServiceLevelInformer constructs a new informer for ClusterPrometheusServiceLevel type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredClusterPrometheusServiceLevelInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		cache.ToListWatcherWithWatchListSemantics(&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SlothV1().ClusterPrometheusServiceLevels().List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SlothV1().ClusterPrometheusServiceLevels().Watch(context.Background(), options)
+			},
+			ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SlothV1().ClusterPrometheusServiceLevels().List(ctx, options)
+			},
+			WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SlothV1().ClusterPrometheusServiceLevels().Watch(ctx, options)
+			},
+		}, client),
+		&apislothv1.ClusterPrometheusServiceLevel{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *clusterPrometheusServiceLevelInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredClusterPrometheusServiceLevelInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *clusterPrometheusServiceLevelInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&apislothv1.ClusterPrometheusServiceLevel{}, f.defaultInformer)
+}
+
+func (f *clusterPrometheusServiceLevelInformer) Lister() slothv1.ClusterPrometheusServiceLevelLister {
+	return slothv1.NewClusterPrometheusServiceLevelLister(f.Informer().GetIndexer())
+}
@@ -8,8 +8,12 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ClusterPrometheusServiceLevels returns a ClusterPrometheusServiceLevelInformer.
+	ClusterPrometheusServiceLevels() ClusterPrometheusServiceLevelInformer
 	// PrometheusServiceLevels returns a PrometheusServiceLevelInformer.
 	PrometheusServiceLevels() PrometheusServiceLevelInformer
+	// PrometheusServiceLevelTemplates returns a PrometheusServiceLevelTemplateInformer.
+	PrometheusServiceLevelTemplates() PrometheusServiceLevelTemplateInformer
 }
 
 type version struct {
@@ -23,7 +27,17 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ClusterPrometheusServiceLevels returns a ClusterPrometheusServiceLevelInformer.
+func (v *version) ClusterPrometheusServiceLevels() ClusterPrometheusServiceLevelInformer {
+	return &clusterPrometheusServiceLevelInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // PrometheusServiceLevels returns a PrometheusServiceLevelInformer.
 func (v *version) PrometheusServiceLevels() PrometheusServiceLevelInformer {
 	return &prometheusServiceLevelInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// PrometheusServiceLevelTemplates returns a PrometheusServiceLevelTemplateInformer.
+func (v *version) PrometheusServiceLevelTemplates() PrometheusServiceLevelTemplateInformer {
+	return &prometheusServiceLevelTemplateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -0,0 +1,86 @@
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	time "time"
+
+	apislothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	versioned "github.com/slok/sloth/pkg/kubernetes/gen/clientset/versioned"
+	internalinterfaces "github.com/slok/sloth/pkg/kubernetes/gen/informers/externalversions/internalinterfaces"
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/gen/listers/sloth/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// PrometheusServiceLevelTemplateInformer provides access to a shared informer and lister for
+// PrometheusServiceLevelTemplates.
+type PrometheusServiceLevelTemplateInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() slothv1.PrometheusServiceLevelTemplateLister
+}
+
+type prometheusServiceLevelTemplateInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewPrometheusServiceLevelTemplateInformer constructs a new informer for PrometheusServiceLevelTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewPrometheusServiceLevelTemplateInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredPrometheusServiceLevelTemplateInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredPrometheusServiceLevelTemplateInformer constructs a new informer for PrometheusServiceLevelTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredPrometheusServiceLevelTemplateInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		cache.ToListWatcherWithWatchListSemantics(&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SlothV1().PrometheusServiceLevelTemplates(namespace).List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SlothV1().PrometheusServiceLevelTemplates(namespace).Watch(context.Background(), options)
+			},
+			ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SlothV1().PrometheusServiceLevelTemplates(namespace).List(ctx, options)
+			},
+			WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SlothV1().PrometheusServiceLevelTemplates(namespace).Watch(ctx, options)
+			},
+		}, client),
+		&apislothv1.PrometheusServiceLevelTemplate{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *prometheusServiceLevelTemplateInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredPrometheusServiceLevelTemplateInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *prometheusServiceLevelTemplateInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&apislothv1.PrometheusServiceLevelTemplate{}, f.defaultInformer)
+}
+
+func (f *prometheusServiceLevelTemplateInformer) Lister() slothv1.PrometheusServiceLevelTemplateLister {
+	return slothv1.NewPrometheusServiceLevelTemplateLister(f.Informer().GetIndexer())
+}
@@ -0,0 +1,32 @@
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	listers "k8s.io/client-go/listers"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// ClusterPrometheusServiceLevelLister helps list ClusterPrometheusServiceLevels.
+// All objects returned here must be treated as read-only.
+type ClusterPrometheusServiceLevelLister interface {
+	// List lists all ClusterPrometheusServiceLevels in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*slothv1.ClusterPrometheusServiceLevel, err error)
+	// Get retrieves the ClusterPrometheusServiceLevel from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*slothv1.ClusterPrometheusServiceLevel, error)
+	ClusterPrometheusServiceLevelListerExpansion
+}
+
+// clusterPrometheusServiceLevelLister implements the ClusterPrometheusServiceLevelLister interface.
+type clusterPrometheusServiceLevelLister struct {
+	listers.ResourceIndexer[*slothv1.ClusterPrometheusServiceLevel]
+}
+
+// NewClusterPrometheusServiceLevelLister returns a new ClusterPrometheusServiceLevelLister.
+func NewClusterPrometheusServiceLevelLister(indexer cache.Indexer) ClusterPrometheusServiceLevelLister {
+	return &clusterPrometheusServiceLevelLister{listers.New[*slothv1.ClusterPrometheusServiceLevel](indexer, slothv1.Resource("clusterprometheusservicelevel"))}
+}
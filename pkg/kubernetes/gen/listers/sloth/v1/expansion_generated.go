@@ -2,6 +2,10 @@
 
 package v1
 
+// ClusterPrometheusServiceLevelListerExpansion allows custom methods to be added to
+// ClusterPrometheusServiceLevelLister.
+type ClusterPrometheusServiceLevelListerExpansion interface{}
+
 // PrometheusServiceLevelListerExpansion allows custom methods to be added to
 // PrometheusServiceLevelLister.
 type PrometheusServiceLevelListerExpansion interface{}
@@ -9,3 +13,11 @@ type PrometheusServiceLevelListerExpansion interface{}
 // PrometheusServiceLevelNamespaceListerExpansion allows custom methods to be added to
 // PrometheusServiceLevelNamespaceLister.
 type PrometheusServiceLevelNamespaceListerExpansion interface{}
+
+// PrometheusServiceLevelTemplateListerExpansion allows custom methods to be added to
+// PrometheusServiceLevelTemplateLister.
+type PrometheusServiceLevelTemplateListerExpansion interface{}
+
+// PrometheusServiceLevelTemplateNamespaceListerExpansion allows custom methods to be added to
+// PrometheusServiceLevelTemplateNamespaceLister.
+type PrometheusServiceLevelTemplateNamespaceListerExpansion interface{}
@@ -0,0 +1,54 @@
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	slothv1 "github.com/slok/sloth/pkg/kubernetes/api/sloth/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	listers "k8s.io/client-go/listers"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// PrometheusServiceLevelTemplateLister helps list PrometheusServiceLevelTemplates.
+// All objects returned here must be treated as read-only.
+type PrometheusServiceLevelTemplateLister interface {
+	// List lists all PrometheusServiceLevelTemplates in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*slothv1.PrometheusServiceLevelTemplate, err error)
+	// PrometheusServiceLevelTemplates returns an object that can list and get PrometheusServiceLevelTemplates.
+	PrometheusServiceLevelTemplates(namespace string) PrometheusServiceLevelTemplateNamespaceLister
+	PrometheusServiceLevelTemplateListerExpansion
+}
+
+// prometheusServiceLevelTemplateLister implements the PrometheusServiceLevelTemplateLister interface.
+type prometheusServiceLevelTemplateLister struct {
+	listers.ResourceIndexer[*slothv1.PrometheusServiceLevelTemplate]
+}
+
+// NewPrometheusServiceLevelTemplateLister returns a new PrometheusServiceLevelTemplateLister.
+func NewPrometheusServiceLevelTemplateLister(indexer cache.Indexer) PrometheusServiceLevelTemplateLister {
+	return &prometheusServiceLevelTemplateLister{listers.New[*slothv1.PrometheusServiceLevelTemplate](indexer, slothv1.Resource("prometheusserviceleveltemplate"))}
+}
+
+// PrometheusServiceLevelTemplates returns an object that can list and get PrometheusServiceLevelTemplates.
+func (s *prometheusServiceLevelTemplateLister) PrometheusServiceLevelTemplates(namespace string) PrometheusServiceLevelTemplateNamespaceLister {
+	return prometheusServiceLevelTemplateNamespaceLister{listers.NewNamespaced[*slothv1.PrometheusServiceLevelTemplate](s.ResourceIndexer, namespace)}
+}
+
+// PrometheusServiceLevelTemplateNamespaceLister helps list and get PrometheusServiceLevelTemplates.
+// All objects returned here must be treated as read-only.
+type PrometheusServiceLevelTemplateNamespaceLister interface {
+	// List lists all PrometheusServiceLevelTemplates in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*slothv1.PrometheusServiceLevelTemplate, err error)
+	// Get retrieves the PrometheusServiceLevelTemplate from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*slothv1.PrometheusServiceLevelTemplate, error)
+	PrometheusServiceLevelTemplateNamespaceListerExpansion
+}
+
+// prometheusServiceLevelTemplateNamespaceLister implements the PrometheusServiceLevelTemplateNamespaceLister
+// interface.
+type prometheusServiceLevelTemplateNamespaceLister struct {
+	listers.ResourceIndexer[*slothv1.PrometheusServiceLevelTemplate]
+}
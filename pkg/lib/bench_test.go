@@ -2,7 +2,9 @@ package lib_test
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"strings"
 	"testing"
 
 	"github.com/slok/sloth/pkg/lib"
@@ -66,3 +68,57 @@ slos:
 		}
 	}
 }
+
+func BenchmarkLibGenerateConcurrency(b *testing.B) {
+	// Create a big SLO group spec with Yaegi interpreted plugins.
+	var sb strings.Builder
+	sb.WriteString(`
+version: "prometheus/v1"
+service: "myservice"
+slo_plugins:
+  chain:
+    - id: "sloth.dev/contrib/info_labels/v1"
+      config:
+        labels:
+          owner: "myteam"
+slos:
+`)
+	for i := range 100 {
+		fmt.Fprintf(&sb, `
+  - name: "requests-availability-%[1]d"
+    objective: 99.9
+    sli:
+      events:
+        error_query: sum(rate(http_request_duration_seconds_count{job="myservice",handler="%[1]d",code=~"(5..|429)"}[{{.window}}]))
+        total_query: sum(rate(http_request_duration_seconds_count{job="myservice",handler="%[1]d"}[{{.window}}]))
+    alerting:
+      name: "MyServiceHighErrorRate%[1]d"
+`, i)
+	}
+	sloSpec := []byte(sb.String())
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers-%d", workers), func(b *testing.B) {
+			gen, err := lib.NewPrometheusSLOGenerator(lib.PrometheusSLOGeneratorConfig{
+				Workers: workers,
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			for b.Loop() {
+				ctx := context.Background()
+
+				slo, err := gen.GenerateFromRaw(ctx, sloSpec)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				err = gen.WriteResultAsPrometheusStd(ctx, *slo, io.Discard)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/slok/sloth/internal/app/generate"
 	"github.com/slok/sloth/internal/info"
 	k8stransformpromopv1 "github.com/slok/sloth/internal/plugin/k8stransform/prom_operator_prometheus_rule_v1"
+	"github.com/slok/sloth/internal/slotemplate"
 	storagefs "github.com/slok/sloth/internal/storage/fs"
 	storageio "github.com/slok/sloth/internal/storage/io"
 	"github.com/slok/sloth/pkg/common/model"
@@ -43,8 +44,14 @@ type PrometheusSLOGeneratorConfig struct {
 	WindowsFS fs.FS
 	// PluginsFS are the FSs where custom SLO and SLI plugins exist.
 	PluginsFS []fs.FS
+	// SLOTemplatesFS is the FS where the Kubernetes PrometheusServiceLevelTemplate manifests used by the
+	// Kubernetes specs exist (When not set the Kubernetes specs can't use SLO templates).
+	SLOTemplatesFS fs.FS
 	// StrictPlugins makes the plugin loader fail when a plugin can't be loaded.
 	StrictPlugins bool
+	// PluginsCacheDir is the directory where the plugins discovery results will be cached by plugin source hash,
+	// this way plugins are loaded lazily, only when used, across process executions (When not set the cache is disabled).
+	PluginsCacheDir string
 	// DefaultSLOPeriod is the default SLO period to use when not specified in the SLO definition.
 	DefaultSLOPeriod time.Duration
 	// DisableDefaultPlugins disables the default SLO plugins, normally used along with custom SLO plugins to fully customize Sloth behavior.
@@ -55,6 +62,12 @@ type PrometheusSLOGeneratorConfig struct {
 	ExtraLabels map[string]string
 	// CallerAgent is the agent calling the library (The identity or form of calling it).
 	CallerAgent CallerAgent
+	// Workers is the number of SLOs of the same spec that will be generated concurrently (by default 1, no concurrency).
+	// The generated results will maintain the same order regardless of the concurrency.
+	Workers int
+	// TracePlugins traces the SLO plugin chain execution of each SLO, the traces are returned on the SLO results
+	// with the executed plugins, their durations and the changes each plugin made on the SLO rules and the SLO.
+	TracePlugins bool
 	// Logger is the logger to use for the library.
 	Logger log.Logger
 }
@@ -72,6 +85,14 @@ func (c *PrometheusSLOGeneratorConfig) defaults() error {
 		return fmt.Errorf("invalid caller agent: %q", c.CallerAgent)
 	}
 
+	if c.Workers < 0 {
+		return fmt.Errorf("workers can't be negative")
+	}
+
+	if c.Workers == 0 {
+		c.Workers = 1
+	}
+
 	if c.Logger == nil {
 		c.Logger = log.Noop
 	}
@@ -80,12 +101,14 @@ func (c *PrometheusSLOGeneratorConfig) defaults() error {
 }
 
 // PrometheusSLOGenerator is a Prometheus SLO rules generator from the Sloth supported SLO definitions.
+// It's safe to use it concurrently.
 type PrometheusSLOGenerator struct {
 	genSvc            generate.Service
 	promYAMLLoader    storageio.SlothPrometheusYAMLSpecLoader
 	kubeYAMLLoader    storageio.K8sSlothPrometheusYAMLSpecLoader
 	openSLOYAMLLoader storageio.OpenSLOYAMLSpecLoader
 	pluginsRepo       *storagefs.FilePluginRepo
+	templateRenderer  *slotemplate.Renderer
 	extraLabels       map[string]string
 	agent             CallerAgent
 	logger            log.Logger
@@ -113,11 +136,25 @@ func NewPrometheusSLOGenerator(config PrometheusSLOGeneratorConfig) (*Prometheus
 	}
 
 	// Create plugin repo.
-	pluginRepo, err := createPluginLoader(ctx, config.Logger, config.PluginsFS, config.StrictPlugins)
+	pluginRepo, err := createPluginLoader(ctx, config.Logger, config.PluginsFS, config.StrictPlugins, config.PluginsCacheDir)
 	if err != nil {
 		return nil, fmt.Errorf("could not create plugin repository: %w", err)
 	}
 
+	// Create SLO templates renderer.
+	var templateRenderer *slotemplate.Renderer
+	if config.SLOTemplatesFS != nil {
+		templateRepo, err := slotemplate.NewFSTemplateRepo(slotemplate.FSTemplateRepoConfig{
+			FS:     config.SLOTemplatesFS,
+			Logger: config.Logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not load SLO templates repository: %w", err)
+		}
+		r := slotemplate.NewRenderer(templateRepo)
+		templateRenderer = &r
+	}
+
 	defSLOPlugins := []generate.SLOProcessor{}
 	if !config.DisableDefaultPlugins {
 		// Load default slo plugins.
@@ -133,6 +170,8 @@ func NewPrometheusSLOGenerator(config PrometheusSLOGeneratorConfig) (*Prometheus
 		DefaultPlugins:  defSLOPlugins,
 		SLOPluginGetter: pluginRepo,
 		ExtraPlugins:    config.CMDSLOPlugins,
+		Workers:         config.Workers,
+		TracePlugins:    config.TracePlugins,
 		Logger:          config.Logger,
 	})
 	if err != nil {
@@ -145,12 +184,24 @@ func NewPrometheusSLOGenerator(config PrometheusSLOGeneratorConfig) (*Prometheus
 		kubeYAMLLoader:    storageio.NewK8sSlothPrometheusYAMLSpecLoader(pluginRepo, config.DefaultSLOPeriod),
 		openSLOYAMLLoader: storageio.NewOpenSLOYAMLSpecLoader(config.DefaultSLOPeriod),
 		pluginsRepo:       pluginRepo,
+		templateRenderer:  templateRenderer,
 		extraLabels:       config.ExtraLabels,
 		agent:             config.CallerAgent,
 		logger:            config.Logger,
 	}, nil
 }
 
+// ReloadPlugins reloads the plugins from the configured plugin file systems, new generations
+// will use the reloaded plugins. If the reload fails the previously loaded plugins are kept.
+func (p PrometheusSLOGenerator) ReloadPlugins(ctx context.Context) error {
+	err := p.pluginsRepo.Reload(ctx)
+	if err != nil {
+		return fmt.Errorf("could not reload plugins: %w", err)
+	}
+
+	return nil
+}
+
 // GenerateFromRaw generates SLO rules from raw data, it will infer what type of SLO spec receives. This method is the most
 // generic one as the user doesn't need to know what type of SLO spec is using.
 // For more custom programmatic usage use the other Go struct API spec generators.
@@ -224,7 +275,20 @@ func (p PrometheusSLOGenerator) GenerateFromSlothV1(ctx context.Context, spec pr
 }
 
 // GenerateFromK8sV1 generates SLO rules from a Kubernetes Sloth CR SLO definition spec struct.
+// The SLO template instances of the spec are rendered with the configured SLO templates.
 func (p PrometheusSLOGenerator) GenerateFromK8sV1(ctx context.Context, spec kubernetesv1.PrometheusServiceLevel) (*model.PromSLOGroupResult, error) {
+	if len(spec.Spec.Templates) > 0 {
+		if p.templateRenderer == nil {
+			return nil, fmt.Errorf("the spec uses SLO templates but no SLO templates have been configured")
+		}
+
+		res, err := p.templateRenderer.Render(ctx, spec.Namespace, spec.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("could not render SLO templates: %w", err)
+		}
+		spec.Spec = res.Spec
+	}
+
 	sloGroup, err := p.kubeYAMLLoader.MapSpecToModel(ctx, spec)
 	if err != nil {
 		return nil, fmt.Errorf("could not map to model: %w", err)
@@ -292,15 +356,31 @@ func (p PrometheusSLOGenerator) generateFromModel(ctx context.Context, req gener
 		result = append(result, model.PromSLOResult{
 			SLO:             r.SLO,
 			PrometheusRules: r.SLORules,
+			PluginTrace:     r.PluginTrace,
 		})
 	}
 
 	return &model.PromSLOGroupResult{
 		OriginalSource: req.SLOGroup.OriginalSource,
 		SLOResults:     result,
+		ExtraRules:     res.ExtraRules,
 	}, nil
 }
 
+// ShareSLIRules de-duplicates the SLI error recording rules of the SLOs with identical SLI recordings across
+// all the results (e.g: all the results of a generation run).
+// The shared SLI recording rules are generated once under a shared ID (as SLO group extra rules of the first
+// result that uses them), and the metadata and alert rules of these SLOs are rewritten to use them.
+// The results are modified in place, so they should be shared before writing them.
+func (p PrometheusSLOGenerator) ShareSLIRules(ctx context.Context, results []*model.PromSLOGroupResult) error {
+	err := generate.ShareSLIRules(results)
+	if err != nil {
+		return fmt.Errorf("could not share SLI rules: %w", err)
+	}
+
+	return nil
+}
+
 // WriteResultAsPrometheusStd writes the SLO results into the writer as a Prometheus standard rules file.
 // More information in:
 //   - https://prometheus.io/docs/prometheus/latest/configuration/recording_rules/#recording-rules.
@@ -18,14 +18,14 @@ import (
 	storagefs "github.com/slok/sloth/internal/storage/fs"
 )
 
-func createPluginLoader(ctx context.Context, logger log.Logger, pluginsFS []fs.FS, strict bool) (*storagefs.FilePluginRepo, error) {
+func createPluginLoader(ctx context.Context, logger log.Logger, pluginsFS []fs.FS, strict bool, cacheDir string) (*storagefs.FilePluginRepo, error) {
 	// We should load at least the Sloth embedded default ones.
 	fss := append([]fs.FS{}, pluginsFS...)
 	if len(fss) == 0 {
 		fss = append(fss, plugin.EmbeddedDefaultSLOPlugins, plugin.EmbeddedDefaultK8sTransformPlugins)
 	}
 
-	pluginsRepo, err := storagefs.NewFilePluginRepo(logger, strict, pluginenginesli.PluginLoader, pluginengineslo.PluginLoader, pluginenginesk8stransform.PluginLoader, fss...)
+	pluginsRepo, err := storagefs.NewFilePluginRepo(logger, strict, cacheDir, pluginenginesli.PluginLoader, pluginengineslo.PluginLoader, pluginenginesk8stransform.PluginLoader, fss...)
 	if err != nil {
 		return nil, fmt.Errorf("could not create file SLO and SLI plugins repository: %w", err)
 	}
@@ -45,7 +45,7 @@ func createDefaultSLOPlugins(logger log.Logger, disableRecordings, disableAlerts
 		if err != nil {
 			return nil, fmt.Errorf("could not create SLI rules plugin: %w", err)
 		}
-		sliRuleGen = sliPlugin
+		sliRuleGen = generate.NewIDSLOProcessor(plugincoreslirulesv1.PluginID, sliPlugin)
 
 		metadataPlugin, err := generate.NewSLOProcessorFromSLOPluginV1(
 			plugincoremetadatarulesv1.NewPlugin,
@@ -55,7 +55,7 @@ func createDefaultSLOPlugins(logger log.Logger, disableRecordings, disableAlerts
 		if err != nil {
 			return nil, fmt.Errorf("could not create metadata rules plugin: %w", err)
 		}
-		metaRuleGen = metadataPlugin
+		metaRuleGen = generate.NewIDSLOProcessor(plugincoremetadatarulesv1.PluginID, metadataPlugin)
 	}
 
 	validatePlugin, err := generate.NewSLOProcessorFromSLOPluginV1(
@@ -78,11 +78,11 @@ func createDefaultSLOPlugins(logger log.Logger, disableRecordings, disableAlerts
 		if err != nil {
 			return nil, fmt.Errorf("could not create alert rules plugin: %w", err)
 		}
-		alertRuleGen = plugin
+		alertRuleGen = generate.NewIDSLOProcessor(plugincorealertrulesv1.PluginID, plugin)
 	}
 
 	return []generate.SLOProcessor{
-		validatePlugin,
+		generate.NewIDSLOProcessor(plugincorevalidatev1.PluginID, validatePlugin),
 		sliRuleGen,
 		metaRuleGen,
 		alertRuleGen,
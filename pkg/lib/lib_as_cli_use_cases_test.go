@@ -70,6 +70,32 @@ func TestLibAsCLIIntegration(t *testing.T) {
 			},
 		},
 
+		"Kubernetes with SLO templates case.": {
+			config: func() lib.PrometheusSLOGeneratorConfig {
+				return lib.PrometheusSLOGeneratorConfig{
+					CallerAgent:    lib.CallerAgentCLI,
+					SLOTemplatesFS: os.DirFS("../../test/integration/prometheus/templates"),
+				}
+			},
+			inFilePath:     "../../test/integration/prometheus/testdata/in-templates-k8s.yaml",
+			expOutFilePath: "../../test/integration/prometheus/testdata/out-base-k8s.yaml.tpl",
+			resultFormatter: func(t *testing.T, gen *lib.PrometheusSLOGenerator, result model.PromSLOGroupResult) []byte {
+				var b bytes.Buffer
+				kmeta := model.K8sMeta{Name: "svc", Namespace: "test-ns"}
+				err := gen.WriteResultAsK8sPrometheusOperator(t.Context(), kmeta, result, &b)
+				require.NoError(t, err)
+				return b.Bytes()
+			},
+		},
+
+		"Kubernetes with SLO templates without templates case.": {
+			config: func() lib.PrometheusSLOGeneratorConfig {
+				return lib.PrometheusSLOGeneratorConfig{CallerAgent: lib.CallerAgentCLI}
+			},
+			inFilePath: "../../test/integration/prometheus/testdata/in-templates-k8s.yaml",
+			expGenErr:  true,
+		},
+
 		"OpenSLO case.": {
 			config: func() lib.PrometheusSLOGeneratorConfig {
 				return lib.PrometheusSLOGeneratorConfig{CallerAgent: lib.CallerAgentCLI}
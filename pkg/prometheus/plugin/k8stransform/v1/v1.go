@@ -21,6 +21,15 @@ type PluginID = string
 
 const PluginIDName = "PluginID"
 
+// Optional plugin metadata names. The metadata must be declared using literals, so it can be read without
+// loading the plugin (e.g: PluginModes = []string{"cli-gen-k8s"}).
+const (
+	// PluginDescriptionName is the name of the plugin description string.
+	PluginDescriptionName = "PluginDescription"
+	// PluginModesName is the name of the plugin supported Sloth modes string slice.
+	PluginModesName = "PluginModes"
+)
+
 type K8sObjects struct {
 	Items []*unstructured.Unstructured
 }
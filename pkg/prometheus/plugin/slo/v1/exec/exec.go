@@ -0,0 +1,152 @@
+// Package exec has the protocol and helpers to create SLO plugins that run as an external executable (exec plugins).
+//
+// Exec plugins are discovered by a manifest file (`sloth-plugin.json`) placed in the plugins path, Sloth will
+// execute the manifest command on every SLO that uses the plugin, sending the SLO plugin request, the current
+// result and the plugin configuration as JSON (`Input`) through the standard input, and will read the new
+// result (JSON `pluginslov1.Result`) from the standard output. The standard error will be logged by Sloth.
+//
+// Any language can be used to create exec plugins, for Go plugins, the `Serve` helper can be used to serve a
+// regular SLO plugin (`pluginslov1.PluginFactory`) as an exec plugin without the Yaegi import limitations.
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/slok/sloth/internal/log"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+// ManifestFileName is the name of the exec plugin manifest file.
+const ManifestFileName = "sloth-plugin.json"
+
+// ManifestEngine is the engine name that exec plugin manifests must use.
+const ManifestEngine = "exec"
+
+// Manifest is the exec plugin manifest.
+type Manifest struct {
+	// Engine is the plugin engine, must be `exec`.
+	Engine string `json:"engine"`
+	// Version is the SLO plugin version, must be `prometheus/slo/v1`.
+	Version pluginslov1.PluginVersion `json:"version"`
+	// ID is the ID of the plugin (e.g: example.com/my-plugin/v1).
+	ID pluginslov1.PluginID `json:"id"`
+	// Command is the command and its arguments that will be executed, relative paths are
+	// resolved from the manifest directory (e.g: `["./my-plugin", "--debug"]`).
+	Command []string `json:"command"`
+	// Timeout is the max duration of a plugin execution (e.g: `5s`, by default `30s`).
+	Timeout string `json:"timeout,omitempty"`
+	// MaxOutputMB is the max size in MiB of the plugin standard output (by default 32).
+	MaxOutputMB int `json:"max_output_mb,omitempty"`
+
+	ManifestMetadata
+}
+
+// ManifestMetadata is the optional metadata of the plugin manifests.
+type ManifestMetadata struct {
+	// Description is the plugin description.
+	Description string `json:"description,omitempty"`
+	// ConfigSchema is the JSON schema of the plugin configuration.
+	ConfigSchema json.RawMessage `json:"config_schema,omitempty"`
+	// ConfigExample is an example of the plugin configuration.
+	ConfigExample json.RawMessage `json:"config_example,omitempty"`
+	// Modes are the Sloth modes supported by the plugin (e.g: `cli-gen-prom`), by default all.
+	Modes []string `json:"modes,omitempty"`
+}
+
+// Input is the data the exec plugins receive on the standard input.
+type Input struct {
+	// Config is the plugin configuration.
+	Config json.RawMessage `json:"config,omitempty"`
+	// Request is the SLO plugin request.
+	Request pluginslov1.Request `json:"request"`
+	// Result is the SLO plugin current result, the plugin will return the new result on the standard output.
+	Result pluginslov1.Result `json:"result"`
+}
+
+// Serve serves a Go SLO plugin as an exec plugin using the standard input, output and error. It will exit
+// the process with a non zero code if the plugin fails.
+func Serve(factory pluginslov1.PluginFactory) {
+	err := ServeIO(context.Background(), os.Stdin, os.Stdout, os.Stderr, factory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// ServeIO serves a Go SLO plugin as an exec plugin using the received input, output and error streams.
+func ServeIO(ctx context.Context, in io.Reader, out io.Writer, errOut io.Writer, factory pluginslov1.PluginFactory) error {
+	input := Input{}
+	err := json.NewDecoder(in).Decode(&input)
+	if err != nil {
+		return fmt.Errorf("could not decode plugin input: %w", err)
+	}
+
+	plugin, err := factory(input.Config, pluginslov1.AppUtils{Logger: newWriterLogger(errOut)})
+	if err != nil {
+		return fmt.Errorf("could not create plugin: %w", err)
+	}
+
+	err = plugin.ProcessSLO(ctx, &input.Request, &input.Result)
+	if err != nil {
+		return fmt.Errorf("plugin failed processing SLO: %w", err)
+	}
+
+	err = json.NewEncoder(out).Encode(input.Result)
+	if err != nil {
+		return fmt.Errorf("could not encode plugin result: %w", err)
+	}
+
+	return nil
+}
+
+// writerLogger is a simple logger that writes one line per log entry, Sloth will
+// log each of these lines with its own logger.
+type writerLogger struct {
+	w      io.Writer
+	values log.Kv
+}
+
+func newWriterLogger(w io.Writer) log.Logger {
+	return writerLogger{w: w, values: log.Kv{}}
+}
+
+func (l writerLogger) Infof(format string, args ...interface{})  { l.log("info", format, args...) }
+func (l writerLogger) Debugf(format string, args ...interface{}) { l.log("debug", format, args...) }
+func (l writerLogger) Errorf(format string, args ...interface{}) { l.log("error", format, args...) }
+func (l writerLogger) Warningf(format string, args ...interface{}) {
+	l.log("warning", format, args...)
+}
+
+func (l writerLogger) WithValues(values map[string]interface{}) log.Logger {
+	kv := log.Kv{}
+	for k, v := range l.values {
+		kv[k] = v
+	}
+	for k, v := range values {
+		kv[k] = v
+	}
+	return writerLogger{w: l.w, values: kv}
+}
+
+func (l writerLogger) WithCtxValues(ctx context.Context) log.Logger {
+	return l.WithValues(log.ValuesFromCtx(ctx))
+}
+
+func (l writerLogger) SetValuesOnCtx(parent context.Context, values map[string]interface{}) context.Context {
+	return log.CtxWithValues(parent, values)
+}
+
+func (l writerLogger) log(level, format string, args ...interface{}) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s", level, fmt.Sprintf(format, args...))
+	for k, v := range l.values {
+		fmt.Fprintf(&sb, " %s=%v", k, v)
+	}
+	sb.WriteString("\n")
+	_, _ = io.WriteString(l.w, sb.String())
+}
@@ -0,0 +1,45 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+const (
+	PluginVersion      = "prometheus/slo/v1"
+	PluginID           = "sloth.dev/test/page-windows/v1"
+	PluginConfigSchema = `{"type": "object", "properties": {"severity": {"type": "string"}}, "additionalProperties": false}`
+)
+
+var PluginModes = []string{"cli-gen-prom"}
+
+type Config struct {
+	Severity string `json:"severity"`
+}
+
+func NewPlugin(configData json.RawMessage, _ pluginslov1.AppUtils) (pluginslov1.Plugin, error) {
+	config := Config{Severity: "page"}
+	if err := json.Unmarshal(configData, &config); err != nil {
+		return nil, err
+	}
+	return plugin{config: config}, nil
+}
+
+type plugin struct {
+	config Config
+}
+
+func (p plugin) ProcessSLO(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) error {
+	result.SLORules.AlertRules.Name = "sloth-slo-alerts-" + request.SLO.ID
+	result.SLORules.AlertRules.Rules = append(result.SLORules.AlertRules.Rules, rulefmt.Rule{
+		Alert:  request.SLO.PageAlertMeta.Name,
+		Expr:   fmt.Sprintf("slo:sli_error:ratio_rate%s > %v", request.MWMBAlertGroup.PageQuick.ShortWindow, request.MWMBAlertGroup.PageQuick.BurnRateFactor),
+		Labels: map[string]string{"severity": p.config.Severity},
+	})
+	return nil
+}
@@ -0,0 +1,220 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slok/sloth/internal/alert"
+	"github.com/slok/sloth/internal/app/generate"
+	"github.com/slok/sloth/internal/info"
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/internal/pluginengine"
+	pluginenginesli "github.com/slok/sloth/internal/pluginengine/sli"
+	storageio "github.com/slok/sloth/internal/storage/io"
+	commonerrors "github.com/slok/sloth/pkg/common/errors"
+	"github.com/slok/sloth/pkg/common/model"
+	prometheusv1 "github.com/slok/sloth/pkg/prometheus/api/v1"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+)
+
+// UpdateGoldenEnv is the env var that when set to `true` will make the golden assertions
+// update the golden files with the current plugin output instead of asserting them.
+const UpdateGoldenEnv = "SLOTH_UPDATE_GOLDEN"
+
+// PluginTester is a helper util to test SLO plugins the same way Sloth runs them: the plugin is loaded
+// from source with the Sloth plugin engine, the config is validated against the plugin config schema
+// and the plugin results are rendered as the Prometheus rules Sloth would write.
+type PluginTester struct {
+	plugin   pluginslov1.Plugin
+	id       string
+	metadata pluginengine.Metadata
+}
+
+// NewPluginTester returns a new PluginTester for the plugin of the configuration.
+func NewPluginTester(ctx context.Context, config TestPluginConfig) (*PluginTester, error) {
+	err := config.defaults()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	plugin, err := loadPluginSource(ctx, config.PluginFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	err = plugin.Metadata.ValidateConfig(config.PluginConfiguration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin config: %w", err)
+	}
+
+	p, err := plugin.PluginV1Factory(config.PluginConfiguration, pluginslov1.AppUtils{Logger: log.Noop})
+	if err != nil {
+		return nil, fmt.Errorf("could not create plugin instance: %w", err)
+	}
+
+	return &PluginTester{
+		plugin:   p,
+		id:       plugin.ID,
+		metadata: plugin.Metadata,
+	}, nil
+}
+
+// ProcessSLO executes the plugin, checking the plugin supports the request mode like Sloth does.
+func (p *PluginTester) ProcessSLO(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) error {
+	if !p.metadata.SupportsMode(request.Info.Mode) {
+		return fmt.Errorf("SLO plugin %q doesn't support %q mode", p.id, request.Info.Mode)
+	}
+
+	return p.plugin.ProcessSLO(ctx, request, result)
+}
+
+// AssertYAML asserts that the SLO rules after executing the plugin with the request and the
+// result (can be nil) are the expected Prometheus rules YAML.
+func (p *PluginTester) AssertYAML(t *testing.T, expYAML string, request *pluginslov1.Request, result *pluginslov1.Result) {
+	t.Helper()
+
+	gotYAML, err := p.processYAML(t.Context(), request, result)
+	if err != nil {
+		assert.NoError(t, err)
+		return
+	}
+
+	assert.Equal(t, expYAML, gotYAML)
+}
+
+// AssertGoldenYAML asserts that the SLO rules after executing the plugin with the request and the
+// result (can be nil) are the Prometheus rules YAML of the golden file. If the `SLOTH_UPDATE_GOLDEN`
+// env var is `true`, the golden file will be updated instead.
+func (p *PluginTester) AssertGoldenYAML(t *testing.T, goldenPath string, request *pluginslov1.Request, result *pluginslov1.Result) {
+	t.Helper()
+
+	gotYAML, err := p.processYAML(t.Context(), request, result)
+	if err != nil {
+		assert.NoError(t, err)
+		return
+	}
+
+	if os.Getenv(UpdateGoldenEnv) == "true" {
+		err := os.MkdirAll(filepath.Dir(goldenPath), 0o755)
+		if err == nil {
+			err = os.WriteFile(goldenPath, []byte(gotYAML), 0o644)
+		}
+		assert.NoError(t, err, "could not update golden file")
+		return
+	}
+
+	expYAML, err := os.ReadFile(goldenPath)
+	if err != nil {
+		assert.NoError(t, err, "could not read golden file, use %s=true to create it", UpdateGoldenEnv)
+		return
+	}
+
+	assert.Equal(t, string(expYAML), gotYAML, "golden file %q doesn't match, use %s=true to update it", goldenPath, UpdateGoldenEnv)
+}
+
+func (p *PluginTester) processYAML(ctx context.Context, request *pluginslov1.Request, result *pluginslov1.Result) (string, error) {
+	if result == nil {
+		result = &pluginslov1.Result{}
+	}
+
+	err := p.ProcessSLO(ctx, request, result)
+	if err != nil {
+		return "", fmt.Errorf("plugin execution failed: %w", err)
+	}
+
+	return RulesYAML(ctx, request.SLO, result.SLORules)
+}
+
+// RulesYAML renders the SLO rules as the Prometheus rules YAML Sloth would write (including the
+// default rule group names), without rules the YAML will be empty.
+func RulesYAML(ctx context.Context, slo model.PromSLO, rules model.PromSLORules) (string, error) {
+	generate.SetDefaultsPromSLORulesResult(slo, &rules)
+
+	var b bytes.Buffer
+	repo := storageio.NewStdPrometheusGroupedRulesYAMLRepo(&b, log.Noop)
+	err := repo.StoreSLOs(ctx, model.PromSLOGroupResult{
+		SLOResults: []model.PromSLOResult{{SLO: slo, PrometheusRules: rules}},
+	})
+	if err != nil {
+		if errors.Is(err, storageio.ErrNoSLORules) {
+			return "", nil
+		}
+		return "", fmt.Errorf("could not render rules: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// NewRequestFromSpec returns a plugin request for an SLO of a Sloth `prometheus/v1` spec, like the
+// one Sloth would create for the plugin, including the real multiwindow-multiburn alert group of the
+// SLO period. If the SLO name is empty the spec must have a single SLO. The SLO period is 30 days and
+// the mode `cli-gen-prom`, SLI plugins are not supported.
+func NewRequestFromSpec(ctx context.Context, spec []byte, sloName string) (*pluginslov1.Request, error) {
+	const sloPeriod = 30 * 24 * time.Hour
+
+	loader := storageio.NewSlothPrometheusYAMLSpecLoader(noSLIPluginRepo{}, sloPeriod)
+	apiSpec, err := loader.LoadAPI(ctx, spec)
+	if err != nil {
+		return nil, fmt.Errorf("could not load spec: %w", err)
+	}
+	sloGroup, err := loader.MapSpecToModel(ctx, *apiSpec)
+	if err != nil {
+		return nil, fmt.Errorf("could not map spec to model: %w", err)
+	}
+
+	var slo *model.PromSLO
+	switch {
+	case sloName == "" && len(sloGroup.SLOs) == 1:
+		slo = &sloGroup.SLOs[0]
+	case sloName == "":
+		return nil, fmt.Errorf("the SLO name is required on specs with multiple SLOs")
+	default:
+		for i := range sloGroup.SLOs {
+			if sloGroup.SLOs[i].Name == sloName {
+				slo = &sloGroup.SLOs[i]
+				break
+			}
+		}
+		if slo == nil {
+			return nil, fmt.Errorf("SLO %q missing on spec: %w", sloName, commonerrors.ErrNotFound)
+		}
+	}
+
+	windowsRepo, err := alert.NewFSWindowsRepo(alert.FSWindowsRepoConfig{})
+	if err != nil {
+		return nil, fmt.Errorf("could not load SLO period windows: %w", err)
+	}
+	alertGroup, err := alert.NewGenerator(windowsRepo).GenerateMWMBAlerts(ctx, alert.SLO{
+		ID:         slo.ID,
+		TimeWindow: slo.TimeWindow,
+		Objective:  slo.Objective,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not generate SLO alerts: %w", err)
+	}
+
+	return &pluginslov1.Request{
+		Info: model.Info{
+			Version: info.Version,
+			Mode:    model.ModeCLIGenPrometheus,
+			Spec:    prometheusv1.Version,
+		},
+		OriginalSource: sloGroup.OriginalSource,
+		SLO:            *slo,
+		MWMBAlertGroup: *alertGroup,
+	}, nil
+}
+
+type noSLIPluginRepo struct{}
+
+func (noSLIPluginRepo) GetSLIPlugin(ctx context.Context, id string) (*pluginenginesli.SLIPlugin, error) {
+	return nil, fmt.Errorf("SLI plugins are not supported on plugin tests: %w", commonerrors.ErrNotFound)
+}
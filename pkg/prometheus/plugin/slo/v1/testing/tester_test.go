@@ -0,0 +1,135 @@
+package testing_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/slok/sloth/pkg/common/model"
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+	pluginslov1testing "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1/testing"
+)
+
+func TestNewRequestFromSpec(t *testing.T) {
+	tests := map[string]struct {
+		sloName     string
+		expSLOID    string
+		expAlertSLO string
+		expErr      bool
+	}{
+		"Missing SLO name on a multiple SLO spec should fail.": {
+			sloName: "",
+			expErr:  true,
+		},
+
+		"A missing SLO should fail.": {
+			sloName: "missing",
+			expErr:  true,
+		},
+
+		"The SLO of the spec should be used with the real alert windows.": {
+			sloName:     "requests-latency",
+			expSLOID:    "myservice-requests-latency",
+			expAlertSLO: "MyServiceHighLatency",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			spec, err := os.ReadFile("testdata/spec.yml")
+			require.NoError(err)
+
+			gotReq, err := pluginslov1testing.NewRequestFromSpec(t.Context(), spec, test.sloName)
+			if test.expErr {
+				assert.Error(err)
+			} else if assert.NoError(err) {
+				assert.Equal(test.expSLOID, gotReq.SLO.ID)
+				assert.Equal(test.expAlertSLO, gotReq.SLO.PageAlertMeta.Name)
+				assert.Equal(model.Mode(model.ModeCLIGenPrometheus), gotReq.Info.Mode)
+				assert.NotNil(gotReq.OriginalSource.SlothV1)
+				assert.Equal(model.PageAlertSeverity, gotReq.MWMBAlertGroup.PageQuick.Severity)
+				assert.NotZero(gotReq.MWMBAlertGroup.PageQuick.ShortWindow)
+			}
+		})
+	}
+}
+
+func TestPluginTester(t *testing.T) {
+	tests := map[string]struct {
+		config  json.RawMessage
+		mode    model.Mode
+		golden  string
+		expYAML string
+		expErr  bool
+	}{
+		"A config that doesn't match the plugin config schema should fail.": {
+			config: json.RawMessage(`{"unknown": true}`),
+			expErr: true,
+		},
+
+		"A mode not supported by the plugin should fail.": {
+			mode:   model.ModeCLIGenKubernetes,
+			expErr: true,
+		},
+
+		"The plugin should render the expected rules.": {
+			config: json.RawMessage(`{"severity": "critical"}`),
+			expYAML: `
+---
+# Code generated by Sloth (dev): https://github.com/slok/sloth.
+# DO NOT EDIT.
+
+groups:
+- name: sloth-slo-alerts-myservice-requests-availability
+  rules:
+  - alert: MyServiceHighErrorRate
+    expr: slo:sli_error:ratio_rate5m0s > 14.4
+    labels:
+      severity: critical
+`,
+		},
+
+		"The plugin should render the golden file rules.": {
+			golden: "testdata/golden/page-windows.yml",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			spec, err := os.ReadFile("testdata/spec.yml")
+			require.NoError(err)
+			req, err := pluginslov1testing.NewRequestFromSpec(t.Context(), spec, "requests-availability")
+			require.NoError(err)
+			if test.mode != "" {
+				req.Info.Mode = test.mode
+			}
+
+			pt, err := pluginslov1testing.NewPluginTester(t.Context(), pluginslov1testing.TestPluginConfig{
+				PluginFilePath:      "testdata/plugin.go",
+				PluginConfiguration: test.config,
+			})
+			if err == nil && test.expErr {
+				err = pt.ProcessSLO(t.Context(), req, &pluginslov1.Result{})
+			}
+			if test.expErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(err)
+
+			if test.golden != "" {
+				pt.AssertGoldenYAML(t, test.golden, req, nil)
+				return
+			}
+			pt.AssertYAML(t, test.expYAML, req, nil)
+		})
+	}
+}
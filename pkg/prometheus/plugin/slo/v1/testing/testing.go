@@ -41,7 +41,19 @@ func NewTestPlugin(ctx context.Context, config TestPluginConfig) (pluginslov1.Pl
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	pluginSource, err := os.ReadFile(config.PluginFilePath)
+	plugin, err := loadPluginSource(ctx, config.PluginFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return plugin.PluginV1Factory(config.PluginConfiguration, pluginslov1.AppUtils{
+		Logger: log.Noop,
+	})
+}
+
+// loadPluginSource loads the plugin source file with the plugin engine that will use Sloth.
+func loadPluginSource(ctx context.Context, path string) (*pluginengineslo.Plugin, error) {
+	pluginSource, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("could not read plugin source code: %w", err)
 	}
@@ -50,7 +62,9 @@ func NewTestPlugin(ctx context.Context, config TestPluginConfig) (pluginslov1.Pl
 		return nil, fmt.Errorf("could not load plugin source code: %w", err)
 	}
 
-	return plugin.PluginV1Factory(config.PluginConfiguration, pluginslov1.AppUtils{
-		Logger: log.Noop,
-	})
+	if plugin.PluginV1Factory == nil {
+		return nil, fmt.Errorf("plugin %q is not a %q plugin", plugin.ID, pluginslov1.Version)
+	}
+
+	return plugin, nil
 }
@@ -21,6 +21,21 @@ type PluginID = string
 
 const PluginIDName = "PluginID"
 
+// Optional plugin metadata names. The metadata must be declared using literals, so it can be read without
+// loading the plugin (e.g: PluginModes = []string{"cli-gen-prom"}).
+const (
+	// PluginDescriptionName is the name of the plugin description string.
+	PluginDescriptionName = "PluginDescription"
+	// PluginConfigSchemaName is the name of the plugin config JSON schema string, the SLO
+	// plugin configs will be validated against it before running the plugins.
+	PluginConfigSchemaName = "PluginConfigSchema"
+	// PluginConfigExampleName is the name of the plugin config JSON example string.
+	PluginConfigExampleName = "PluginConfigExample"
+	// PluginModesName is the name of the plugin supported Sloth modes string slice, if
+	// declared, the plugin will fail on the not supported modes.
+	PluginModesName = "PluginModes"
+)
+
 // AppUtils are app utils plugins can use in their logic.
 type AppUtils struct {
 	Logger log.Logger
@@ -0,0 +1,60 @@
+// Package wasm has the manifest of the SLO plugins that run sandboxed as WebAssembly modules (WASM plugins).
+//
+// WASM plugins are discovered by the same manifest file as the exec plugins (`sloth-plugin.json`) using the
+// `wasm` engine. The module must be a WASI (preview 1) command, Sloth will run it on every SLO that uses the
+// plugin using the same protocol as the exec plugins: the plugin config, SLO plugin request and current result
+// are sent as JSON (`exec.Input`) through the standard input and the new result (JSON `pluginslov1.Result`) is
+// read from the standard output, the standard error will be logged by Sloth.
+//
+// Any language that compiles to WASI preview 1 can be used (e.g: Rust `wasm32-wasip1` or Go `GOOS=wasip1 GOARCH=wasm`).
+//
+// WASM plugins are sandboxed, they don't have access to the file system, the environment variables nor
+// the network. They have memory and execution time limits, and only the capabilities explicitly granted on
+// the manifest (environment variables and read only directories) will be available. The network is never available.
+package wasm
+
+import (
+	pluginslov1 "github.com/slok/sloth/pkg/prometheus/plugin/slo/v1"
+	"github.com/slok/sloth/pkg/prometheus/plugin/slo/v1/exec"
+)
+
+// ManifestEngine is the engine name that WASM plugin manifests must use.
+const ManifestEngine = "wasm"
+
+// Manifest is the WASM plugin manifest.
+type Manifest struct {
+	// Engine is the plugin engine, must be `wasm`.
+	Engine string `json:"engine"`
+	// Version is the SLO plugin version, must be `prometheus/slo/v1`.
+	Version pluginslov1.PluginVersion `json:"version"`
+	// ID is the ID of the plugin (e.g: example.com/my-plugin/v1).
+	ID pluginslov1.PluginID `json:"id"`
+	// Module is the path to the WASM module, relative paths are resolved from
+	// the manifest directory (e.g: `./plugin.wasm`).
+	Module string `json:"module"`
+	// Timeout is the max duration of a plugin execution (e.g: `5s`, by default `30s`).
+	Timeout string `json:"timeout,omitempty"`
+	// MemoryLimitMB is the max memory in MiB the plugin can use (by default 128), it's also the max
+	// size of the plugin output (the standard error is limited to 1MiB).
+	MemoryLimitMB int `json:"memory_limit_mb,omitempty"`
+	// Capabilities are the capabilities granted to the plugin, by default none.
+	Capabilities Capabilities `json:"capabilities,omitempty"`
+
+	exec.ManifestMetadata
+}
+
+// Capabilities are the capabilities that can be granted to a WASM plugin.
+type Capabilities struct {
+	// Env are the names of the Sloth environment variables that will be available to the plugin.
+	Env []string `json:"env,omitempty"`
+	// Dirs are the directories that will be available to the plugin in read only mode.
+	Dirs []Dir `json:"dirs,omitempty"`
+}
+
+// Dir is a directory mounted on a WASM plugin.
+type Dir struct {
+	// Path is the directory path, relative paths are resolved from the manifest directory (e.g: `./data`).
+	Path string `json:"path"`
+	// GuestPath is the path where the directory will be mounted on the plugin (e.g: `/data`).
+	GuestPath string `json:"guest_path"`
+}
@@ -0,0 +1,100 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/slok/sloth/internal/log"
+	"github.com/slok/sloth/pkg/common/model"
+)
+
+// Version is this plugin type version.
+const Version = "prometheus/slo/v2"
+
+// PluginVersion is the version of the plugin (e.g: `prometheus/slo/v2`).
+type PluginVersion = string
+
+const PluginVersionName = "PluginVersion"
+
+// PluginID is the ID of the plugin (e.g: sloth.dev/my-test-plugin/v1).
+type PluginID = string
+
+const PluginIDName = "PluginID"
+
+// Optional plugin metadata names. The metadata must be declared using literals, so it can be read without
+// loading the plugin (e.g: PluginModes = []string{"cli-gen-prom"}).
+const (
+	// PluginDescriptionName is the name of the plugin description string.
+	PluginDescriptionName = "PluginDescription"
+	// PluginConfigSchemaName is the name of the plugin config JSON schema string, the SLO
+	// plugin configs will be validated against it before running the plugins.
+	PluginConfigSchemaName = "PluginConfigSchema"
+	// PluginConfigExampleName is the name of the plugin config JSON example string.
+	PluginConfigExampleName = "PluginConfigExample"
+	// PluginModesName is the name of the plugin supported Sloth modes string slice, if
+	// declared, the plugin will fail on the not supported modes.
+	PluginModesName = "PluginModes"
+)
+
+// AppUtils are app utils plugins can use in their logic.
+type AppUtils struct {
+	Logger log.Logger
+}
+
+type Request struct {
+	// Info about the application and execution, normally used as metadata.
+	Info model.Info
+	// OriginalSource is the original specification of the SLO came from, this is informative data that
+	// can be used to make decision on plugins, it should be used only as RO.
+	// The information used on the generation is the SLO model itself not this one.
+	OriginalSource model.PromSLOGroupSource
+	// The SLO to process and generate the final Prom rules.
+	SLO model.PromSLO
+	// The SLO MWMBAlertGroup selected.
+	MWMBAlertGroup model.MWMBAlertGroup
+}
+
+type Result struct {
+	SLORules model.PromSLORules
+}
+
+type GroupRequest struct {
+	// Info about the application and execution, normally used as metadata.
+	Info model.Info
+	// OriginalSource is the original specification of the SLO group came from, it should be used only as RO.
+	OriginalSource model.PromSLOGroupSource
+	// SLOs are all the SLOs of the group, these should be used only as RO.
+	SLOs []model.PromSLO
+	// SLOResults are the generated results of all the SLOs of the group, these should be used only as RO.
+	// Only set on the post SLO group processing.
+	SLOResults []model.PromSLOResult
+}
+
+type GroupResult struct {
+	// ExtraRules are the SLO group level rule groups (e.g: service rollups), the rule group names are required.
+	ExtraRules []model.PromRuleGroup
+}
+
+// PluginFactoryName is the required name for the plugin factory.
+const PluginFactoryName = "NewPlugin"
+
+type PluginFactory = func(config json.RawMessage, appUtils AppUtils) (Plugin, error)
+
+// Plugin knows how to process SLO groups and SLOs in a chain of plugins. The processing happens in three phases:
+//
+//  1. PreProcessSLOGroup: Executed once per SLO group before processing the SLOs, with all the SLOs of the group.
+//  2. ProcessSLO: Executed on each SLO chain, same as the `prometheus/slo/v1` plugins.
+//  3. PostProcessSLOGroup: Executed once per SLO group after processing the SLOs, with all the SLO results.
+//
+// The SLO group phases are executed once per distinct plugin declaration (ID and config) of the group SLOs on the
+// same plugin instance, the SLO processing uses a different plugin instance per SLO (SLOs can be processed concurrently).
+// Plugins that don't need a phase can implement it as a no-op.
+//
+// The SLO group processors can add SLO group level rule groups to the result.
+//
+// This is the type the SLO v2 plugins need to implement.
+type Plugin interface {
+	PreProcessSLOGroup(ctx context.Context, request *GroupRequest, result *GroupResult) error
+	ProcessSLO(ctx context.Context, request *Request, result *Result) error
+	PostProcessSLOGroup(ctx context.Context, request *GroupRequest, result *GroupResult) error
+}
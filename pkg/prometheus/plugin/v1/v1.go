@@ -18,6 +18,20 @@ type SLIPluginVersion = string
 // SLIPluginID is the ID of the plugin.
 type SLIPluginID = string
 
+// Optional plugin metadata names. The metadata must be declared using literals, so it can be read without
+// loading the plugin (e.g: SLIPluginConfigSchema = `{"type": "object"}`).
+const (
+	// SLIPluginDescriptionName is the name of the plugin description string.
+	SLIPluginDescriptionName = "SLIPluginDescription"
+	// SLIPluginConfigSchemaName is the name of the plugin options JSON schema string, the SLI
+	// plugin options will be validated against it before running the plugin.
+	SLIPluginConfigSchemaName = "SLIPluginConfigSchema"
+	// SLIPluginConfigExampleName is the name of the plugin options JSON example string.
+	SLIPluginConfigExampleName = "SLIPluginConfigExample"
+	// SLIPluginModesName is the name of the plugin supported Sloth modes string slice.
+	SLIPluginModesName = "SLIPluginModes"
+)
+
 // Metada keys.
 const (
 	SLIPluginMetaService   = "service"
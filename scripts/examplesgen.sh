@@ -19,4 +19,4 @@ mkdir -p "${GEN_PATH}"
 
 # We already know that we are building sloth for each SLO, good enough, this way we can check
 # the current development version.
-go run ./cmd/sloth/ generate -i "${SLOS_PATH}" -o "${GEN_PATH}" -p "${SLOS_PATH}" --extra-labels "cmd=examplesgen.sh" -e "_gen|windows"
+go run ./cmd/sloth/ generate -i "${SLOS_PATH}" -o "${GEN_PATH}" -p "${SLOS_PATH}" --slo-templates-path "${SLOS_PATH}/templates" --extra-labels "cmd=examplesgen.sh" -e "_gen|windows|templates"
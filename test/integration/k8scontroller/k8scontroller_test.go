@@ -33,10 +33,22 @@ func sanitizePrometheusRule(pr *monitoringv1.PrometheusRule) *monitoringv1.Prome
 	for i := range pr.OwnerReferences {
 		pr.OwnerReferences[i].UID = ""
 	}
+	delete(pr.Labels, "sloth.dev/owner-uid")
 
 	return pr
 }
 
+func sanitizePrometheusServiceLevelStatus(status slothv1.PrometheusServiceLevelStatus) slothv1.PrometheusServiceLevelStatus {
+	status = *status.DeepCopy()
+
+	status.LastPromOpRulesSuccessfulGenerated = nil
+	for i := range status.Conditions {
+		status.Conditions[i].LastTransitionTime = metav1.Time{}
+	}
+
+	return status
+}
+
 func TestKubernetesControllerPromOperatorGenerate(t *testing.T) {
 	// Tests config.
 	config := k8scontroller.NewConfig(t)
@@ -160,15 +172,25 @@ func TestKubernetesControllerPromOperatorGenerate(t *testing.T) {
 				gotSLOs, err := kubeClis.Sloth.SlothV1().PrometheusServiceLevels(ns).Get(ctx, SLOs.Name, metav1.GetOptions{})
 				require.NoError(t, err)
 
+				gen := newSLOs.Generation
 				expStatus := slothv1.PrometheusServiceLevelStatus{
 					ProcessedSLOs:            2,
 					PromOpRulesGeneratedSLOs: 2,
 					PromOpRulesGenerated:     true,
-					ObservedGeneration:       newSLOs.Generation,
+					ObservedGeneration:       gen,
+					Conditions: []metav1.Condition{
+						{Type: "SpecValid", Status: "True", ObservedGeneration: gen, Reason: "ValidSpec", Message: "The spec is valid"},
+						{Type: "RulesApplied", Status: "True", ObservedGeneration: gen, Reason: "RulesApplied", Message: "The rules of 2 SLOs have been applied"},
+						{Type: "Ready", Status: "True", ObservedGeneration: gen, Reason: "Ready", Message: "All the SLOs are ready"},
+					},
+					SLOs: []slothv1.SLOStatus{
+						{Name: "slo01", RuleGroups: []string{"sloth-slo-sli-recordings-svc01-slo01", "sloth-slo-meta-recordings-svc01-slo01", "sloth-slo-alerts-svc01-slo01"}},
+						{Name: "slo02", RuleGroups: []string{"sloth-slo-sli-recordings-svc01-slo02", "sloth-slo-meta-recordings-svc01-slo02"}},
+					},
 				}
-				gotSLOs.Status.LastPromOpRulesSuccessfulGenerated = nil // Remove variations.
+				gotStatus := sanitizePrometheusServiceLevelStatus(gotSLOs.Status) // Remove variations.
 
-				assert.Equal(t, expStatus, gotSLOs.Status)
+				assert.Equal(t, expStatus, gotStatus)
 			},
 		},
 
@@ -188,15 +210,35 @@ func TestKubernetesControllerPromOperatorGenerate(t *testing.T) {
 				gotSLOs, err := kubeClis.Sloth.SlothV1().PrometheusServiceLevels(ns).Get(ctx, SLOs.Name, metav1.GetOptions{})
 				require.NoError(t, err)
 
+				gen := newSLOs.Generation
 				expStatus := slothv1.PrometheusServiceLevelStatus{
 					ProcessedSLOs:            2,
 					PromOpRulesGeneratedSLOs: 0,
 					PromOpRulesGenerated:     false,
-					ObservedGeneration:       newSLOs.Generation,
+					ObservedGeneration:       gen,
+					Conditions: []metav1.Condition{
+						{Type: "SpecValid", Status: "False", ObservedGeneration: gen, Reason: "InvalidSLO"},
+						{Type: "RulesApplied", Status: "False", ObservedGeneration: gen, Reason: "InvalidSLO"},
+						{Type: "Ready", Status: "False", ObservedGeneration: gen, Reason: "InvalidSLO"},
+					},
+					SLOs: []slothv1.SLOStatus{
+						{Name: "slo01"},
+						{Name: "slo02"},
+					},
+				}
+				gotStatus := sanitizePrometheusServiceLevelStatus(gotSLOs.Status) // Remove variations.
+
+				// The error messages come from the validation, we only check they are set.
+				for i, c := range gotStatus.Conditions {
+					assert.NotEmpty(t, c.Message)
+					gotStatus.Conditions[i].Message = ""
+				}
+				if assert.Len(t, gotStatus.SLOs, 2) {
+					assert.Contains(t, gotStatus.SLOs[0].Error, "invalid slo")
+					gotStatus.SLOs[0].Error = ""
 				}
-				gotSLOs.Status.LastPromOpRulesSuccessfulGenerated = nil // Remove variations.
 
-				assert.Equal(t, expStatus, gotSLOs.Status)
+				assert.Equal(t, expStatus, gotStatus)
 			},
 		},
 
@@ -115,6 +115,26 @@ func TestPrometheusGenerate(t *testing.T) {
 			expOut:     expectLoader.mustLoadExp("./testdata/out-base-custom-windows-7d.yaml.tpl"),
 		},
 
+		"Generate with SLO templates should generate the correct rules for the declared and rendered SLOs (Kubernetes).": {
+			genCmdArgs: "--input ./testdata/in-templates-k8s.yaml --slo-templates-path ./templates",
+			expOut:     expectLoader.mustLoadExp("./testdata/out-base-k8s.yaml.tpl"),
+		},
+
+		"Generate with SLO templates without the SLO templates path should fail (Kubernetes).": {
+			genCmdArgs: "--input ./testdata/in-templates-k8s.yaml",
+			expErr:     true,
+		},
+
+		"Generate with shared SLI rules should share the SLI recording rules of the SLOs with identical SLIs.": {
+			genCmdArgs: "--input ./testdata/in-multifile.yaml --share-sli-rules",
+			expOut:     expectLoader.mustLoadExp("./testdata/out-multifile-shared-sli-rules.yaml.tpl"),
+		},
+
+		"Generate with shared SLI rules in watch mode should fail.": {
+			genCmdArgs: "--input ./testdata/in-base.yaml --share-sli-rules --watch",
+			expErr:     true,
+		},
+
 		"Generate using invalid version should fail.": {
 			genCmdArgs: "--input ./testdata/in-invalid-version.yaml",
 			expErr:     true,